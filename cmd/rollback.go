@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/watchtower/internal/actions"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/events"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/rollback"
+	appConfig "github.com/nicholas-fedor/watchtower/internal/config"
+	"github.com/nicholas-fedor/watchtower/internal/flags"
+	"github.com/nicholas-fedor/watchtower/internal/hostlock"
+	"github.com/nicholas-fedor/watchtower/internal/logging"
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/notifications"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// unpinFlag is the name of the rollback subcommand flag that unpins a rolled back container.
+const unpinFlag = "unpin"
+
+// init registers the rollback command with the root command.
+func init() {
+	rollbackCmd := &cobra.Command{
+		Use:   "rollback <container>",
+		Short: "Roll a container back to the image it ran before its last update",
+		Long: "Recreates the container from the image it ran before its last update and pins it, " +
+			"so later updates skip the image it was rolled back from until it is unpinned with --unpin.",
+		Run:  runRollback,
+		Args: cobra.ExactArgs(1),
+	}
+
+	rollbackCmd.Flags().Bool(unpinFlag, false, "Unpin a rolled back container so it can be updated again")
+
+	rootCmd.AddCommand(rollbackCmd)
+}
+
+// runRollback executes the rollback command, exiting with a non-zero status on failure.
+func runRollback(cmd *cobra.Command, args []string) {
+	log := logging.New(os.Stderr, logging.InfoLevel)
+
+	log, err := runRollbackE(cmd, args, log)
+	if err != nil {
+		log.Error().Err(err).Msg("Rollback failed")
+		os.Exit(1)
+	}
+}
+
+// runRollbackE performs the core logic for the `rollback` subcommand.
+//
+// Configuration is loaded like for the root command, so the rollback runs with
+// the same filters, lifecycle hooks, and notifications as an update.
+//
+// Parameters:
+//   - cmd: The *cobra.Command instance representing the `rollback` subcommand.
+//   - args: The container name.
+//   - log: Process logger, reconfigured from --log-format / --log-level.
+//
+// Returns:
+//   - *zerolog.Logger: Logger after SetupLogging for outer error reporting.
+//   - error: Non-nil if configuration fails or the container cannot be rolled back or unpinned.
+func runRollbackE(cmd *cobra.Command, args []string, log *zerolog.Logger) (*zerolog.Logger, error) {
+	flagSet := cmd.Root().PersistentFlags()
+
+	err := flags.ApplyEnvToFlags(flagSet, flags.AllSpecs())
+	if err != nil {
+		return log, fmt.Errorf("apply environment configuration: %w", err)
+	}
+
+	log, err = flags.SetupLogging(log, flagSet)
+	if err != nil {
+		return log, fmt.Errorf("setup logging: %w", err)
+	}
+
+	flags.ProcessFlagAliases(log, flagSet)
+
+	log, err = flags.SetupLogging(log, flagSet)
+	if err != nil {
+		return log, fmt.Errorf("setup logging: %w", err)
+	}
+
+	flags.GetSecretsFromFiles(log, cmd.Root())
+
+	err = flags.EnvConfig(log, cmd.Root())
+	if err != nil {
+		return log, fmt.Errorf("configure Docker environment: %w", err)
+	}
+
+	cfg, err := appConfig.Load(log, cmd.Root(), nil)
+	if err != nil {
+		return log, fmt.Errorf("load configuration: %w", err)
+	}
+
+	unpin, err := cmd.Flags().GetBool(unpinFlag)
+	if err != nil {
+		return log, fmt.Errorf("read --%s: %w", unpinFlag, err)
+	}
+
+	rollbackNotifier := notifications.NewNotifier(log, cfg.Notify)
+	rollbackNotifier.RegisterHook(log)
+
+	defer rollbackNotifier.Close()
+
+	ctx, stop := createSignalContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Wait for a scan or rollback of a running Watchtower to finish instead of racing it.
+	lockPath := hostlock.Path(cfg.Update.LockDir, cfg.Filter.Scope)
+	log.Debug().Str("path", lockPath).Msg("Taking the host update lock")
+
+	lock, err := hostlock.Acquire(ctx, lockPath)
+	if err != nil {
+		return log, fmt.Errorf("take update lock: %w", err)
+	}
+
+	defer lock.Release()
+
+	_, err = actions.RollbackWithNotifications(ctx, actions.RunUpdatesWithNotificationsParams{
+		Logger:                       log,
+		Client:                       container.NewClient(log, cfg.ClientOptions()),
+		Notifier:                     rollbackNotifier,
+		NotificationSplitByContainer: cfg.Notify.SplitByContainer,
+		NotificationReport:           cfg.Notify.Report,
+		Update:                       cfg.UpdateParams(appConfig.RunOverrides{}),
+	}, args[0], unpin)
+	if err != nil {
+		return log, fmt.Errorf("roll back %s: %w", args[0], err)
+	}
+
+	return log, nil
+}
+
+// apiRollback returns the rollback function of the HTTP API.
+//
+// Rollbacks publish events and use the digest like scans; the handler holds
+// the update lock while the function runs.
+//
+// Parameters:
+//   - broadcaster: Event broadcaster for container events.
+//   - digest: Notification digest, or nil to notify right away.
+//   - params: Update policy shared with API scans.
+//
+// Returns:
+//   - rollback.Func: Function that rolls back or unpins a container by name.
+func (p *process) apiRollback(
+	broadcaster *events.Broadcaster,
+	digest *notifications.Digest,
+	params types.UpdateParams,
+) rollback.Func {
+	return func(ctx context.Context, name string, unpin bool) (rollback.Result, error) {
+		if params.CurrentContainerID == "" {
+			params.CurrentContainerID = currentWatchtowerContainerID
+		}
+
+		defer p.holdHostLock(ctx)()
+
+		result, err := actions.RollbackWithNotifications(ctx, actions.RunUpdatesWithNotificationsParams{
+			Logger:                       p.log,
+			Client:                       client,
+			Notifier:                     notifier,
+			NotificationSplitByContainer: appCfg.Notify.SplitByContainer,
+			NotificationReport:           appCfg.Notify.Report,
+			EventBroadcaster:             broadcaster,
+			Digest:                       digest,
+			Update:                       params,
+		}, name, unpin)
+
+		switch {
+		case errors.Is(err, actions.ErrRollbackContainerNotFound):
+			return rollback.Result{}, fmt.Errorf("%w: %w", rollback.ErrContainerNotFound, err)
+		case errors.Is(err, actions.ErrNoPreviousImage), errors.Is(err, actions.ErrNotPinned):
+			return rollback.Result{}, fmt.Errorf("%w: %w", rollback.ErrNotApplicable, err)
+		case err != nil:
+			return rollback.Result{}, err
+		}
+
+		return rollback.Result{
+			Container:     result.Container,
+			ContainerID:   string(result.ContainerID),
+			FromImageID:   string(result.FromImage),
+			ToImageID:     string(result.ToImage),
+			PinnedImageID: string(result.PinnedImage),
+		}, nil
+	}
+}
@@ -3,8 +3,10 @@ package cmd
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
 	"os/signal"
+	"regexp"
 	"syscall"
 	"time"
 
@@ -15,12 +17,17 @@ import (
 	"github.com/nicholas-fedor/watchtower/internal/api"
 	"github.com/nicholas-fedor/watchtower/internal/api/config"
 	"github.com/nicholas-fedor/watchtower/internal/api/handlers/events"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/quarantine"
 	appConfig "github.com/nicholas-fedor/watchtower/internal/config"
 	"github.com/nicholas-fedor/watchtower/internal/flags"
+	"github.com/nicholas-fedor/watchtower/internal/heartbeat"
+	"github.com/nicholas-fedor/watchtower/internal/hostlock"
 	"github.com/nicholas-fedor/watchtower/internal/logging"
 	"github.com/nicholas-fedor/watchtower/internal/meta"
 	"github.com/nicholas-fedor/watchtower/internal/metrics"
+	"github.com/nicholas-fedor/watchtower/internal/mqtt"
 	"github.com/nicholas-fedor/watchtower/internal/scheduling"
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/container"
 	"github.com/nicholas-fedor/watchtower/pkg/filters"
 	"github.com/nicholas-fedor/watchtower/pkg/notifications"
@@ -43,6 +50,10 @@ const (
 	// so startup cannot hang indefinitely if the daemon or container runtime
 	// metadata is unreachable.
 	containerLookupTimeout = 5 * time.Second
+
+	// tracingShutdownTimeout bounds the final span flush so an unreachable
+	// collector cannot delay process exit.
+	tracingShutdownTimeout = 5 * time.Second
 )
 
 var (
@@ -111,6 +122,8 @@ var (
 // parsed. It is never stored as a package-level global.
 type process struct {
 	log *zerolog.Logger
+	// logOutputs are the opened syslog, GELF, and file log outputs.
+	logOutputs []io.Closer
 }
 
 // init registers command-line flags for the root command during package initialization.
@@ -214,6 +227,9 @@ func (p *process) preRun(cmd *cobra.Command, _ []string) {
 		p.log.Fatal().Err(err).Msg("Failed to load configuration")
 	}
 
+	// Tee logs to the configured syslog, GELF, and file outputs.
+	p.setupLogOutputs()
+
 	p.log.Debug().
 		Str("scheduleSpec", appCfg.Schedule.Spec).
 		Msg("Retrieved cron schedule specification from configuration")
@@ -465,6 +481,9 @@ func (p *process) run(command *cobra.Command, args []string) {
 
 	// Execute core logic and exit with the returned status code (0 for success, 1 for failure).
 	exitCode := p.runMain(cfg)
+
+	p.closeLogOutputs()
+
 	if exitCode != 0 {
 		p.log.Debug().
 			Int("exit_code", exitCode).
@@ -514,10 +533,32 @@ func (p *process) runMain(cfg types.RunConfig) int {
 	// Ensure the Docker client is fully initialized before proceeding.
 	awaitDockerClient(p.log)
 
+	// Apply per-container metric cardinality before the first scan records series.
+	metrics.Default().SetContainerLabels(appCfg.Metrics.ContainerLabels)
+
+	// Install the span exporter before the first scan; spans are no-ops when disabled.
+	shutdownTracing := p.setupTracing()
+	defer shutdownTracing()
+
 	// Initialize the event broadcaster for SSE subscribers.
 	// Declared before runUpdatesWithNotifications so the closure can capture it.
 	eventsBroadcaster := events.NewBroadcaster()
 
+	// Ping the dead-man's-switch monitor around every scan when configured.
+	scanHeartbeat := p.newHeartbeat()
+
+	// Collect scans into a scheduled digest instead of notifying after each one.
+	digest := p.newDigest(cfg.RunOnce)
+
+	// Keep previous images under a retention policy instead of removing them right away.
+	imageRetention := p.newImageRetention()
+
+	// Hold back images that keep failing to update instead of retrying them every scan.
+	imageQuarantine := p.newQuarantine()
+
+	// Publish container states to MQTT; set up once the update lock exists.
+	var mqttPublisher *mqtt.Publisher
+
 	// runUpdatesWithNotifications performs container updates and sends notifications about the results.
 	//
 	// It executes the update action with configured parameters, batches notifications, and returns a metric
@@ -540,6 +581,8 @@ func (p *process) runMain(cfg types.RunConfig) int {
 			update.CurrentContainerID = currentWatchtowerContainerID
 		}
 
+		defer p.holdHostLock(ctx)()
+
 		return actions.RunUpdatesWithNotifications(ctx, actions.RunUpdatesWithNotificationsParams{
 			Logger:                       p.log,
 			Client:                       client,
@@ -547,6 +590,11 @@ func (p *process) runMain(cfg types.RunConfig) int {
 			NotificationSplitByContainer: appCfg.Notify.SplitByContainer,
 			NotificationReport:           appCfg.Notify.Report,
 			EventBroadcaster:             eventsBroadcaster,
+			Heartbeat:                    scanHeartbeat,
+			Digest:                       digest,
+			MQTT:                         mqttPublisher,
+			ImageRetention:               imageRetention,
+			Quarantine:                   imageQuarantine,
 			Update:                       update,
 		})
 	}
@@ -562,6 +610,13 @@ func (p *process) runMain(cfg types.RunConfig) int {
 	)
 	defer stop()
 
+	// Forward events to configured CloudEvents sinks for the life of the process.
+	eventSink := p.startEventSink(ctx, eventsBroadcaster)
+
+	if digest != nil {
+		go digest.Run(ctx)
+	}
+
 	// If rolling restarts are enabled, validate that the containers being monitored for
 	// updates do not have linked dependencies.
 	if appCfg.Update.RollingRestart {
@@ -610,11 +665,24 @@ func (p *process) runMain(cfg types.RunConfig) int {
 		startup.Logger = p.log
 		logging.WriteStartupMessage(startup)
 
+		if appCfg.MQTT.BrokerURL != "" {
+			p.log.Warn().Msg("MQTT publishing is not used in run-once mode")
+		}
+
 		params := baseParams
 		params.RunOnce = true
 
+		runStart := time.Now()
 		metric := runUpdatesWithNotifications(ctx, cfg.Filter, params)
 		metrics.Default().RegisterScan(metric)
+
+		// Push before exiting since nothing can scrape a run-once process.
+		p.pushMetrics(ctx, metric, time.Since(runStart))
+
+		if eventSink != nil {
+			eventSink.Close()
+		}
+
 		notifier.Close()
 
 		// Update current Watchtower container's restart policy to "no" to prevent unwanted restarts.
@@ -669,6 +737,20 @@ func (p *process) runMain(cfg types.RunConfig) int {
 			Msg("Cleaned up orphaned orchestrator containers")
 	}
 
+	// Lifecycle hook sidecars are removed after each hook, so any found here
+	// were left behind by a crash during a previous run.
+	removedHookCount, hookErr := container.RemoveOrphanedHookContainers(p.log, ctx, client, appCfg.Filter.Scope)
+	if hookErr != nil {
+		p.log.Warn().
+			Err(hookErr).
+			Int("removed_hook_containers", removedHookCount).
+			Msg("Failed to clean up orphaned lifecycle hook containers, continuing anyway")
+	} else if removedHookCount > 0 {
+		p.log.Debug().
+			Int("removed_hook_containers", removedHookCount).
+			Msg("Cleaned up orphaned lifecycle hook containers")
+	}
+
 	// Track whether cleanup occurred to prevent redundant updates after self-update.
 	cleanupOccurred := totalRemovedInstances > 0
 	// Disable update-on-start if cleanup occurred to prevent redundant updates after self-update.
@@ -700,6 +782,14 @@ func (p *process) runMain(cfg types.RunConfig) int {
 		sharedBase.SkipSelfUpdate = true
 	}
 
+	// Connect to the MQTT broker; Home Assistant installs run the targeted update path.
+	mqttPublisher = p.newMQTTPublisher(func(installCtx context.Context, name string) {
+		p.installContainer(installCtx, name, updateLock, cfg.Filter, sharedBase)
+	})
+	if mqttPublisher != nil {
+		go mqttPublisher.Run(ctx)
+	}
+
 	// Startup messaging snapshot. Sched and UpdateOnStart are filled by schedule or API
 	// callers. Populate the rest here so scheduling does not re-derive them from scalar deps.
 	startupBase := appCfg.StartupParams(cfg)
@@ -730,6 +820,8 @@ func (p *process) runMain(cfg types.RunConfig) int {
 			EnableHistoryAPI:             cfg.EnableHistoryAPI,
 			EnableImagesAPI:              cfg.EnableImagesAPI,
 			EnableMetricsAPI:             cfg.EnableMetricsAPI,
+			EnableOutboxAPI:              cfg.EnableOutboxAPI,
+			EnableQuarantineAPI:          cfg.EnableQuarantineAPI,
 			EnableSwaggerAPI:             cfg.EnableSwaggerAPI,
 			EnableUpdateAPI:              cfg.EnableUpdateAPI,
 			CheckTimeout:                 cfg.CheckAPITimeout,
@@ -755,12 +847,17 @@ func (p *process) runMain(cfg types.RunConfig) int {
 			Version:                      meta.Version,
 			Startup:                      startupBase,
 			RunUpdatesWithNotifications:  runUpdatesWithNotifications,
+			Rollback:                     p.apiRollback(eventsBroadcaster, digest, sharedBase),
+			QuarantineList:               apiQuarantineList(imageQuarantine),
+			QuarantineClear:              apiQuarantineClear(imageQuarantine),
 			FilterByImage: func(images []string, base types.Filter) types.Filter {
 				return filters.FilterByImage(p.log, images, base)
 			},
 			DefaultMetrics:      metrics.Default,
 			WriteStartupMessage: logging.WriteStartupMessage,
 			EventBroadcaster:    eventsBroadcaster,
+			EventsFormat:        appCfg.API.EventsFormat,
+			EventsSource:        eventsSource(),
 			OnUnexpectedServerStop: func(listenErr error) {
 				p.log.Error().
 					Err(listenErr).
@@ -825,6 +922,421 @@ func (p *process) runMain(cfg types.RunConfig) int {
 	return 0 // Default to success if execution completes without errors.
 }
 
+// startEventSink subscribes the outbound CloudEvents sink when sink URLs are configured.
+//
+// Parameters:
+//   - ctx: Process context; cancellation stops delivery.
+//   - broadcaster: The event broadcaster to forward from.
+//
+// Returns:
+//   - *events.Sink: The running sink, or nil when no sink is configured or setup fails.
+func (p *process) startEventSink(ctx context.Context, broadcaster *events.Broadcaster) *events.Sink {
+	if len(appCfg.Events.SinkURLs) == 0 {
+		return nil
+	}
+
+	sinkLog := p.log.With().Str("notify", "no").Logger()
+
+	sink, err := events.NewSink(
+		&sinkLog,
+		broadcaster,
+		appCfg.Events.SinkURLs,
+		appCfg.Events.SinkMode,
+		eventsSource(),
+		appCfg.Events.SinkTimeout,
+	)
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to start event sink")
+
+		return nil
+	}
+
+	go sink.Run(ctx)
+
+	return sink
+}
+
+// newImageRetention creates the image retention tracker when a retention policy is configured.
+//
+// Returns:
+//   - *actions.ImageRetention: The tracker, or nil when previous images are removed right after the update.
+func (p *process) newImageRetention() *actions.ImageRetention {
+	policy := actions.RetentionPolicy{
+		Keep:      appCfg.Update.CleanupKeep,
+		MaxAge:    appCfg.Update.CleanupKeepDuration,
+		StateFile: appCfg.Update.CleanupStateFile,
+		Dangling:  appCfg.Update.CleanupDangling,
+	}
+
+	if policy.Keep == 0 && policy.MaxAge == 0 && !policy.Dangling {
+		return nil
+	}
+
+	if !appCfg.Update.Cleanup {
+		p.log.Warn().Msg("Image retention settings have no effect without --cleanup")
+
+		return nil
+	}
+
+	if policy.StateFile == "" && (policy.Keep > 0 || policy.MaxAge > 0) {
+		p.log.Info().Msg("Retained images are tracked in memory only and are not cleaned up after a restart; set --cleanup-state-file to persist them")
+	}
+
+	return actions.NewImageRetention(p.log, policy)
+}
+
+// newQuarantine creates the image quarantine when quarantine-after is set.
+//
+// Returns:
+//   - *actions.Quarantine: The quarantine, or nil when failed updates are retried every scan.
+func (p *process) newQuarantine() *actions.Quarantine {
+	if appCfg.Update.QuarantineAfter == 0 {
+		return nil
+	}
+
+	return actions.NewQuarantine(p.log, actions.QuarantinePolicy{
+		After:      appCfg.Update.QuarantineAfter,
+		Backoff:    appCfg.Update.QuarantineBackoff,
+		MaxBackoff: appCfg.Update.QuarantineMaxBackoff,
+	})
+}
+
+// apiQuarantineList adapts the image quarantine to the quarantine API.
+//
+// Parameters:
+//   - imageQuarantine: The quarantine, or nil when it is disabled.
+//
+// Returns:
+//   - quarantine.ListFunc: Function listing the quarantined images.
+func apiQuarantineList(imageQuarantine *actions.Quarantine) quarantine.ListFunc {
+	return func() []quarantine.Entry {
+		entries := []quarantine.Entry{}
+		if imageQuarantine == nil {
+			return entries
+		}
+
+		for _, entry := range imageQuarantine.Entries() {
+			entries = append(entries, quarantine.Entry{
+				Container:   entry.ContainerName,
+				Image:       entry.ImageName,
+				ImageID:     string(entry.ImageID),
+				Failures:    entry.Failures,
+				LastError:   entry.LastError,
+				LastFailure: entry.LastFailure,
+				Until:       entry.Until,
+			})
+		}
+
+		return entries
+	}
+}
+
+// apiQuarantineClear adapts the image quarantine to the quarantine API.
+//
+// Parameters:
+//   - imageQuarantine: The quarantine, or nil when it is disabled.
+//
+// Returns:
+//   - quarantine.ClearFunc: Function clearing the quarantine of a container.
+func apiQuarantineClear(imageQuarantine *actions.Quarantine) quarantine.ClearFunc {
+	return func(name string) bool {
+		return imageQuarantine != nil && imageQuarantine.Clear(name)
+	}
+}
+
+// newHeartbeat creates the scan heartbeat when a heartbeat URL is configured.
+//
+// Returns:
+//   - *heartbeat.Heartbeat: The heartbeat, or nil when none is configured or the URL is invalid.
+func (p *process) newHeartbeat() *heartbeat.Heartbeat {
+	if appCfg.Heartbeat.URL == "" {
+		return nil
+	}
+
+	// Ping failures must not trigger notifications about themselves.
+	heartbeatLog := p.log.With().Str("notify", "no").Logger()
+
+	hb, err := heartbeat.New(
+		&heartbeatLog,
+		appCfg.Heartbeat.URL,
+		appCfg.Heartbeat.Format,
+		appCfg.Heartbeat.Timeout,
+	)
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to set up heartbeat")
+
+		return nil
+	}
+
+	return hb
+}
+
+// setupLogOutputs adds the configured log outputs to the process logger.
+//
+// The console keeps its format and level; each output filters at its own level.
+func (p *process) setupLogOutputs() {
+	if len(appCfg.Logging.Outputs) == 0 {
+		return
+	}
+
+	outputs := make([]logging.Output, 0, len(appCfg.Logging.Outputs))
+
+	for _, raw := range appCfg.Logging.Outputs {
+		output, err := logging.ParseOutput(raw)
+		if err != nil {
+			p.log.Fatal().Err(err).Msg("Invalid log output")
+		}
+
+		outputs = append(outputs, output)
+	}
+
+	console, err := logging.ConfigureWriter(appCfg.Logging.Format, appCfg.Logging.NoColor)
+	if err != nil {
+		p.log.Fatal().Err(err).Msg("Failed to initialize logging")
+	}
+
+	p.log, p.logOutputs, err = logging.WithOutputs(p.log, console, outputs)
+	if err != nil {
+		p.log.Fatal().Err(err).Msg("Failed to open log output")
+	}
+
+	p.log.Debug().Int("outputs", len(outputs)).Msg("Configured log outputs")
+}
+
+// closeLogOutputs closes the log outputs opened by setupLogOutputs.
+func (p *process) closeLogOutputs() {
+	for _, output := range p.logOutputs {
+		_ = output.Close()
+	}
+
+	p.logOutputs = nil
+}
+
+// newMQTTPublisher creates the MQTT publisher when a broker URL is configured.
+//
+// Parameters:
+//   - install: Runs a targeted update for a container named in a Home Assistant install command.
+//
+// Returns:
+//   - *mqtt.Publisher: The publisher, or nil when none is configured or the settings are invalid.
+func (p *process) newMQTTPublisher(install mqtt.InstallFunc) *mqtt.Publisher {
+	if appCfg.MQTT.BrokerURL == "" {
+		return nil
+	}
+
+	clientID := appCfg.MQTT.ClientID
+	if clientID == "" {
+		clientID = hostname()
+	}
+
+	// Broker failures must not trigger notifications about themselves.
+	mqttLog := p.log.With().Str("notify", "no").Logger()
+
+	publisher, err := mqtt.New(&mqttLog, mqtt.Options{
+		BrokerURL:       appCfg.MQTT.BrokerURL,
+		Username:        appCfg.MQTT.Username,
+		Password:        appCfg.MQTT.Password,
+		ClientID:        clientID,
+		TopicPrefix:     appCfg.MQTT.TopicPrefix,
+		Discovery:       appCfg.MQTT.Discovery,
+		DiscoveryPrefix: appCfg.MQTT.DiscoveryPrefix,
+		CAFile:          appCfg.MQTT.CAFile,
+		TLSSkipVerify:   appCfg.MQTT.TLSSkipVerify,
+		Version:         meta.Version,
+	}, install)
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to set up MQTT publisher")
+
+		return nil
+	}
+
+	return publisher
+}
+
+// installContainer runs a targeted update of one container, waiting for any
+// running update to finish first.
+//
+// Parameters:
+//   - ctx: Process context.
+//   - name: The container name.
+//   - updateLock: The update lock shared with the scheduler and HTTP API.
+//   - baseFilter: The configured container filter the update is narrowed from.
+//   - params: The shared update parameters.
+func (p *process) installContainer(
+	ctx context.Context,
+	name string,
+	updateLock chan bool,
+	baseFilter types.Filter,
+	params types.UpdateParams,
+) {
+	select {
+	case token := <-updateLock:
+		defer func() { updateLock <- token }()
+	case <-ctx.Done():
+		return
+	}
+
+	filter := filters.FilterByNames(p.log, []string{regexp.QuoteMeta(name)}, baseFilter)
+	params.Targeted = true
+
+	metric := runUpdatesWithNotifications(ctx, filter, params)
+	metrics.Default().RegisterScan(metric)
+}
+
+// holdHostLock takes the host update lock shared with the rollback subcommand.
+//
+// Callers already hold the in-process update lock, so only subcommands run in
+// other processes contend for it. A lock that cannot be taken is logged and
+// the update runs anyway, as it did before the host lock existed.
+//
+// Parameters:
+//   - ctx: Context ending the wait for a running subcommand.
+//
+// Returns:
+//   - func(): Releases the lock; a no-op if it was not taken.
+func (p *process) holdHostLock(ctx context.Context) func() {
+	lock, err := hostlock.Acquire(ctx, hostlock.Path(appCfg.Update.LockDir, appCfg.Filter.Scope))
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to take the host update lock")
+
+		return func() {}
+	}
+
+	return lock.Release
+}
+
+// newDigest creates the notification digest when a digest schedule is configured.
+//
+// Run-once mode never reaches a digest schedule, so it keeps per-scan notifications.
+//
+// Parameters:
+//   - runOnce: True when Watchtower exits after a single scan.
+//
+// Returns:
+//   - *notifications.Digest: The digest, or nil when disabled or misconfigured.
+func (p *process) newDigest(runOnce bool) *notifications.Digest {
+	if appCfg.Notify.Digest.Schedule == "" {
+		return nil
+	}
+
+	if runOnce {
+		p.log.Warn().Msg("Notification digest is not used in run-once mode")
+
+		return nil
+	}
+
+	// Digest persistence errors must not trigger notifications about themselves.
+	digestLog := p.log.With().Str("notify", "no").Logger()
+
+	digest, err := notifications.NewDigest(&digestLog, notifier, appCfg.Notify.Digest)
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to set up notification digest, sending notifications per scan")
+
+		return nil
+	}
+
+	return digest
+}
+
+// pushMetrics sends the run's metrics to the configured Pushgateway or remote-write endpoint.
+//
+// Push failures are logged and do not change the exit code.
+//
+// Parameters:
+//   - ctx: Process context.
+//   - metric: The run's scan metric.
+//   - duration: Wall-clock duration of the run.
+func (p *process) pushMetrics(ctx context.Context, metric *metrics.Metric, duration time.Duration) {
+	if appCfg.Metrics.PushURL == "" {
+		return
+	}
+
+	instance := appCfg.Metrics.PushInstance
+	if instance == "" {
+		instance = hostname()
+	}
+
+	err := metrics.Default().Push(ctx, metrics.PushOptions{
+		URL:      appCfg.Metrics.PushURL,
+		Format:   appCfg.Metrics.PushFormat,
+		Job:      appCfg.Metrics.PushJob,
+		Instance: instance,
+		Timeout:  appCfg.Metrics.PushTimeout,
+	}, metric, duration)
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to push metrics")
+
+		return
+	}
+
+	p.log.Debug().
+		Str("format", appCfg.Metrics.PushFormat).
+		Str("instance", instance).
+		Msg("Pushed run metrics")
+}
+
+// setupTracing installs the configured OpenTelemetry exporter.
+//
+// When tracing is enabled, the default HTTP transport is instrumented so
+// notification services propagate the trace context of the scan.
+//
+// Returns:
+//   - func(): Flushes buffered spans; safe to call when tracing is disabled.
+func (p *process) setupTracing() func() {
+	shutdown, err := tracing.Setup(context.Background(), tracing.Options{
+		Exporter:       appCfg.Tracing.Exporter,
+		Endpoint:       appCfg.Tracing.Endpoint,
+		Insecure:       appCfg.Tracing.Insecure,
+		SampleRatio:    appCfg.Tracing.SampleRatio,
+		ServiceVersion: meta.Version,
+	})
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to set up tracing")
+
+		return func() {}
+	}
+
+	if appCfg.Tracing.Exporter == "" || appCfg.Tracing.Exporter == tracing.ExporterNone {
+		return func() {}
+	}
+
+	p.log.Debug().
+		Str("exporter", appCfg.Tracing.Exporter).
+		Float64("sample_ratio", appCfg.Tracing.SampleRatio).
+		Msg("Tracing enabled")
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+
+		err := shutdown(ctx)
+		if err != nil {
+			p.log.Debug().Err(err).Msg("Failed to flush tracing spans")
+		}
+	}
+}
+
+// eventsSource returns the CloudEvents source for this Watchtower instance.
+//
+// Returns:
+//   - string: A URI built from the notification hostname (or system hostname) and scope.
+func eventsSource() string {
+	return events.CloudEventSource(hostname(), appCfg.Filter.Scope)
+}
+
+// hostname returns the host name used to identify this Watchtower instance.
+//
+// Returns:
+//   - string: The notification hostname when set, otherwise the system hostname.
+func hostname() string {
+	host := appCfg.Notify.Hostname
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	return host
+}
+
 // logNotify logs an error message and ensures notifications are sent before returning control.
 //
 // It uses a specific message if provided, falling back to a generic one, and includes the error in fields.
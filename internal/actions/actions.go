@@ -6,10 +6,16 @@ import (
 	"time"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/nicholas-fedor/watchtower/internal/api/handlers/events"
+	"github.com/nicholas-fedor/watchtower/internal/heartbeat"
 	"github.com/nicholas-fedor/watchtower/internal/metrics"
+	"github.com/nicholas-fedor/watchtower/internal/mqtt"
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/lifecycle"
+	"github.com/nicholas-fedor/watchtower/pkg/notifications"
 	"github.com/nicholas-fedor/watchtower/pkg/session"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
@@ -50,6 +56,16 @@ type RunUpdatesWithNotificationsParams struct {
 	NotificationReport bool
 	// EventBroadcaster publishes SSE events during the update session.
 	EventBroadcaster *events.Broadcaster
+	// Heartbeat pings an external monitor at scan start and completion; nil disables pings.
+	Heartbeat *heartbeat.Heartbeat
+	// Digest collects scan results for a scheduled digest in place of per-scan notifications; nil disables it.
+	Digest *notifications.Digest
+	// MQTT publishes container states and the scan summary to an MQTT broker; nil disables publishing.
+	MQTT *mqtt.Publisher
+	// ImageRetention keeps previous images under a retention policy when cleaning up; nil removes them right away.
+	ImageRetention *ImageRetention
+	// Quarantine holds back images that repeatedly failed to update a container; nil disables quarantine.
+	Quarantine *Quarantine
 	// Update is the complete update policy for this invocation (filter, cleanup, timeouts, etc.).
 	Update types.UpdateParams
 }
@@ -74,6 +90,18 @@ func RunUpdatesWithNotifications(
 
 	log.Debug().Msg("Starting RunUpdatesWithNotifications")
 
+	scanStart := time.Now()
+
+	// Root span for the scan; per-container spans are opened by Update.
+	ctx, span := tracing.Start(ctx, "watchtower.scan")
+	defer span.End()
+
+	// Parent notification delivery spans on the scan span.
+	setter, ok := params.Notifier.(traceContextSetter)
+	if ok {
+		setter.SetTraceContext(ctx)
+	}
+
 	// Initiate notification batching.
 	startNotifications(log, params.Notifier, params.NotificationSplitByContainer)
 
@@ -85,18 +113,35 @@ func RunUpdatesWithNotifications(
 			Type:      "scan_started",
 			Timestamp: time.Now().UTC(),
 			Data:      events.NewScanStartedData(updateConfig),
-		})
+		}.WithTraceContext(ctx))
 	}
 
+	if params.Heartbeat != nil {
+		params.Heartbeat.Start(ctx)
+	}
+
+	// Halt the remaining updates of the scan once too many of them failed.
+	updateCtx, breaker := withCircuitBreaker(ctx, updateConfig)
+
 	// Execute the container update operation
 	result, cleanupImageInfosPtr, err := executeUpdate(log,
-		ctx,
+		withQuarantine(updateCtx, params.Quarantine),
 		params.Client,
 		updateConfig,
 	)
+	// In digest mode a failed scan only notifies now when immediate failures are enabled.
+	resultNotifier := params.Notifier
+	if err != nil && params.Digest != nil && !params.Digest.Record(nil, 0, err) {
+		params.Digest.Discard()
+
+		resultNotifier = nil
+	}
+
 	// Process update result, return metric on failure
-	metric := handleUpdateResult(log, result, err, params.Notifier)
+	metric := handleUpdateResult(log, result, err, resultNotifier)
 	if metric != nil {
+		tracing.RecordError(span, err)
+
 		if params.EventBroadcaster != nil {
 			errMsg := "unknown error"
 			if err != nil {
@@ -109,39 +154,90 @@ func RunUpdatesWithNotifications(
 				Data: events.ScanFailedData{
 					Error: errMsg,
 				},
+			}.WithTraceContext(ctx))
+		}
+
+		if params.Heartbeat != nil {
+			params.Heartbeat.Finish(ctx, heartbeat.Result{
+				Failed:   metric.Failed,
+				Duration: time.Since(scanStart),
+				Err:      err,
+			})
+		}
+
+		if params.MQTT != nil {
+			params.MQTT.PublishScan(nil, mqtt.Summary{
+				Failed:   metric.Failed,
+				Duration: time.Since(scanStart),
+				Err:      err,
 			})
 		}
 
 		return metric
 	}
 
-	// Perform image cleanup if enabled.
-	cleanedImages := performImageCleanup(log,
-		ctx,
-		params.Client,
-		updateConfig.Cleanup,
-		cleanupImageInfosPtr,
-	)
+	// Count failed updates towards quarantining their images.
+	if params.Quarantine != nil {
+		params.Quarantine.Record(result)
+	}
+
+	// Publish a halted scan before the outcomes of its containers.
+	if aborted, ok := breaker.aborted(); ok && params.EventBroadcaster != nil {
+		params.EventBroadcaster.Publish(events.Event{
+			Type:      "scan_aborted",
+			Timestamp: time.Now().UTC(),
+			Data:      aborted,
+		}.WithTraceContext(ctx))
+	}
+
+	// Publish per-container outcome events and labelled metrics.
+	publishContainerEvents(ctx, params.EventBroadcaster, result)
+	metrics.Default().RecordContainers(result, !updateConfig.Targeted)
+	lifecycle.RecordLastRuns(result)
+
+	// Perform image cleanup if enabled, keeping previous images under a retention policy.
+	var cleanedImages []types.RemovedImageInfo
+	if params.ImageRetention != nil && updateConfig.Cleanup {
+		cleanedImages = params.ImageRetention.Cleanup(ctx, params.Client, cleanupImageInfosPtr, result)
+	} else {
+		cleanedImages = performImageCleanup(log,
+			ctx,
+			params.Client,
+			updateConfig.Cleanup,
+			cleanupImageInfosPtr,
+		)
+	}
 
 	// Publish image cleanup event
 	if params.EventBroadcaster != nil && len(cleanedImages) > 0 {
 		entries := make([]events.ImageCleanupEntry, len(cleanedImages))
+		reclaimed := make(map[types.ImageID]int64, len(cleanedImages))
+
 		for i, img := range cleanedImages {
 			entries[i] = events.ImageCleanupEntry{
 				ImageID:       string(img.ImageID),
 				ImageName:     img.ImageName,
 				ContainerID:   string(img.ContainerID),
 				ContainerName: img.ContainerName,
+				Size:          img.Size,
 			}
+			// Images shared by several containers are listed once per container but removed once.
+			reclaimed[img.ImageID] = img.Size
+		}
+
+		var reclaimedBytes int64
+		for _, size := range reclaimed {
+			reclaimedBytes += size
 		}
 
 		params.EventBroadcaster.Publish(events.Event{
 			Type:      "image_cleanup",
 			Timestamp: time.Now().UTC(),
 			Data: events.ImageCleanupData{
-				Images: entries,
+				Images:         entries,
+				ReclaimedBytes: reclaimedBytes,
 			},
-		})
+		}.WithTraceContext(ctx))
 	}
 
 	// Log update report details for debugging
@@ -153,23 +249,34 @@ func RunUpdatesWithNotifications(
 		Bool("notifier_present", params.Notifier != nil).
 		Msg("About to send notifications")
 
-	// Send notifications about update results
-	sendNotifications(log,
-		params.Notifier,
-		params.NotificationSplitByContainer,
-		params.NotificationReport,
-		result,
-		cleanedImages,
+	// Send notifications about update results, unless the digest holds them.
+	if params.Digest != nil && !params.Digest.Record(result, len(cleanedImages), nil) {
+		params.Digest.Discard()
+	} else {
+		sendNotifications(log,
+			params.Notifier,
+			params.NotificationSplitByContainer,
+			params.NotificationReport,
+			result,
+			cleanedImages,
+		)
+	}
+
+	scanned, updated, failed := 0, 0, 0
+	if result != nil {
+		scanned = len(result.Scanned())
+		updated = len(result.Updated())
+		failed = len(result.Failed())
+	}
+
+	span.SetAttributes(
+		attribute.Int("watchtower.scan.scanned", scanned),
+		attribute.Int("watchtower.scan.updated", updated),
+		attribute.Int("watchtower.scan.failed", failed),
 	)
 
 	// Publish scan completed event
 	if params.EventBroadcaster != nil {
-		scanned, updated, failed := 0, 0, 0
-		if result != nil {
-			scanned = len(result.Scanned())
-			updated = len(result.Updated())
-			failed = len(result.Failed())
-		}
 
 		params.EventBroadcaster.Publish(events.Event{
 			Type:      "scan_completed",
@@ -179,6 +286,24 @@ func RunUpdatesWithNotifications(
 				Updated: updated,
 				Failed:  failed,
 			},
+		}.WithTraceContext(ctx))
+	}
+
+	if params.Heartbeat != nil {
+		params.Heartbeat.Finish(ctx, heartbeat.Result{
+			Scanned:  scanned,
+			Updated:  updated,
+			Failed:   failed,
+			Duration: time.Since(scanStart),
+		})
+	}
+
+	if params.MQTT != nil {
+		params.MQTT.PublishScan(result, mqtt.Summary{
+			Scanned:  scanned,
+			Updated:  updated,
+			Failed:   failed,
+			Duration: time.Since(scanStart),
 		})
 	}
 
@@ -186,6 +311,38 @@ func RunUpdatesWithNotifications(
 	return generateAndLogMetric(log, result)
 }
 
+// publishContainerEvents publishes one event per updated, restarted, or failed
+// container in the session report.
+//
+// Parameters:
+//   - ctx: Scan context whose trace context is attached to each event.
+//   - broadcaster: The event broadcaster; nil disables publishing.
+//   - result: The session report.
+func publishContainerEvents(ctx context.Context, broadcaster *events.Broadcaster, result types.Report) {
+	if broadcaster == nil || result == nil {
+		return
+	}
+
+	groups := []struct {
+		eventType string
+		reports   []types.ContainerReport
+	}{
+		{eventType: "container_updated", reports: result.Updated()},
+		{eventType: "container_restarted", reports: result.Restarted()},
+		{eventType: "container_failed", reports: result.Failed()},
+	}
+
+	for _, group := range groups {
+		for _, report := range group.reports {
+			broadcaster.Publish(events.Event{
+				Type:      group.eventType,
+				Timestamp: time.Now().UTC(),
+				Data:      events.NewContainerEventData(report),
+			}.WithTraceContext(ctx))
+		}
+	}
+}
+
 // emptyReport is a non-nil empty report used when sending notifications about errors.
 // It prevents panics in notifier implementations that may dereference the report.
 type emptyReport struct{}
@@ -344,10 +501,8 @@ func executeUpdate(log *zerolog.Logger, ctx context.Context,
 
 // performImageCleanup executes image cleanup if enabled.
 //
-// It removes old images after updates if the cleanup flag is set.
-// When multiple containers share the same old image, the image is only removed once
-// (preventing duplicate "Removing image" log entries), but the returned slice includes
-// all container associations so that split-by-container notifications report correctly.
+// It removes old images after updates if the cleanup flag is set, recording
+// the size of each removed image for reporting.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
@@ -366,6 +521,38 @@ func performImageCleanup(log *zerolog.Logger, ctx context.Context,
 		return []types.RemovedImageInfo{}
 	}
 
+	// Sizes are only known before removal; a failed listing just leaves them unset.
+	images, err := client.ListImages(ctx)
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Msg("Failed to list images, image sizes will not be reported")
+	}
+
+	return setImageSizes(removePreviousImages(log, ctx, client, cleanupImageInfos), images)
+}
+
+// removePreviousImages removes the previous images of updated containers.
+//
+// When multiple containers share the same old image, the image is only removed once
+// (preventing duplicate "Removing image" log entries), but the returned slice includes
+// all container associations so that split-by-container notifications report correctly.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: The Docker client instance used for container operations.
+//   - cleanupImageInfos: Slice of cleaned image info to be removed.
+//
+// Returns:
+//   - []types.RemovedImageInfo: Slice of successfully cleaned image info.
+func removePreviousImages(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	cleanupImageInfos []types.RemovedImageInfo,
+) []types.RemovedImageInfo {
+	if len(cleanupImageInfos) == 0 {
+		return []types.RemovedImageInfo{}
+	}
+
 	// Deduplicate by ImageID so each image is only removed once.
 	// This prevents duplicate "Removing image" log entries in non-split notifications
 	// when multiple containers share the same old image.
@@ -17,6 +17,7 @@ import (
 	dockerNetwork "github.com/moby/moby/api/types/network"
 
 	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/events"
 	"github.com/nicholas-fedor/watchtower/internal/metrics"
 	"github.com/nicholas-fedor/watchtower/pkg/filters"
 	"github.com/nicholas-fedor/watchtower/pkg/session"
@@ -3160,3 +3161,48 @@ var _ = ginkgo.Describe("isPinned", func() {
 		gomega.Expect(pinned).To(gomega.BeFalse())
 	})
 })
+
+var _ = ginkgo.Describe("publishContainerEvents", func() {
+	ginkgo.It("publishes one event per updated, restarted, and failed container", func() {
+		log := testLogger()
+		updated := mockActions.CreateMockContainer("updated-id", "/updated", "app:latest", time.Now())
+		restarted := mockActions.CreateMockContainer("restarted-id", "/restarted", "db:latest", time.Now())
+		failed := mockActions.CreateMockContainer("failed-id", "/failed", "cache:latest", time.Now())
+
+		progress := session.Progress{}
+		progress.AddScanned(log, updated, "sha256:new", types.UpdateParams{})
+		progress.AddScanned(log, restarted, restarted.ImageID(), types.UpdateParams{})
+		progress.AddScanned(log, failed, "sha256:other", types.UpdateParams{})
+		progress.MarkForUpdate(log, updated.ID())
+		progress.MarkRestarted(log, restarted.ID())
+		progress.UpdateFailed(log, map[types.ContainerID]error{failed.ID(): errors.New("pull failed")})
+
+		broadcaster := events.NewBroadcaster()
+		subCh := broadcaster.Subscribe()
+
+		publishContainerEvents(context.Background(), broadcaster, progress.Report(log))
+
+		received := map[string]events.ContainerEventData{}
+
+		for range 3 {
+			event := <-subCh
+			data, ok := event.Data.(events.ContainerEventData)
+			gomega.Expect(ok).To(gomega.BeTrue())
+
+			received[event.Type] = data
+		}
+
+		gomega.Expect(received["container_updated"].ContainerName).To(gomega.Equal("updated"))
+		gomega.Expect(received["container_restarted"].ContainerName).To(gomega.Equal("restarted"))
+		gomega.Expect(received["container_failed"].ContainerName).To(gomega.Equal("failed"))
+		gomega.Expect(received["container_failed"].Error).To(gomega.Equal("pull failed"))
+		gomega.Expect(subCh).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("is a no-op without a broadcaster or report", func() {
+		gomega.Expect(func() {
+			publishContainerEvents(context.Background(), nil, emptyReport{})
+			publishContainerEvents(context.Background(), events.NewBroadcaster(), nil)
+		}).NotTo(gomega.Panic())
+	})
+})
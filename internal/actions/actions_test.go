@@ -912,6 +912,21 @@ var _ = ginkgo.Describe("Actions", func() {
 			gomega.Expect(cleanedImages[0].ContainerName).To(gomega.Equal("test-container"))
 		})
 
+		ginkgo.It("should report the size of each removed image", func() {
+			client := mockActions.CreateMockClient(&mockActions.TestData{
+				Images: []types.ImageSummary{{ID: "sha256:123", Size: 4096}},
+			}, false, false)
+			cleanedImages := performImageCleanup(testLogger(), context.Background(), client, true, []types.RemovedImageInfo{
+				{ContainerName: "a", ImageName: "test-image:v1.0", ImageID: types.ImageID("sha256:123")},
+				{ContainerName: "b", ImageName: "test-image:v1.0", ImageID: types.ImageID("sha256:123")},
+			})
+
+			gomega.Expect(cleanedImages).To(gomega.HaveLen(2))
+			gomega.Expect(cleanedImages[0].Size).To(gomega.Equal(int64(4096)))
+			gomega.Expect(cleanedImages[1].Size).To(gomega.Equal(int64(4096)))
+			gomega.Expect(client.TestData.RemovedImageIDs).To(gomega.Equal([]types.ImageID{"sha256:123"}))
+		})
+
 		ginkgo.It("should return a valid slice when cleanup input is empty", func() {
 			client := mockActions.CreateMockClient(&mockActions.TestData{}, false, false)
 			cleanedImages := performImageCleanup(testLogger(), context.Background(), client, true, []types.RemovedImageInfo{})
@@ -0,0 +1,146 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// volumeBackups collects the volume backups taken during one scan, keyed by container ID.
+type volumeBackups struct {
+	mu    sync.Mutex
+	paths map[types.ContainerID]string
+}
+
+// volumeBackupsKey is the context key for a *volumeBackups.
+type volumeBackupsKey struct{}
+
+// withVolumeBackups returns a context that records volume backups in a new collection.
+//
+// Parameters:
+//   - ctx: Parent context.
+//
+// Returns:
+//   - context.Context: Context carrying the collection.
+//   - *volumeBackups: Empty collection.
+func withVolumeBackups(ctx context.Context) (context.Context, *volumeBackups) {
+	backups := &volumeBackups{paths: map[types.ContainerID]string{}}
+
+	return context.WithValue(ctx, volumeBackupsKey{}, backups), backups
+}
+
+// recordVolumeBackup stores a container's backup path in the collection carried by ctx, if any.
+//
+// Parameters:
+//   - ctx: Context that may carry a collection.
+//   - id: ID of the backed-up container.
+//   - path: Backup directory.
+func recordVolumeBackup(ctx context.Context, id types.ContainerID, path string) {
+	backups, ok := ctx.Value(volumeBackupsKey{}).(*volumeBackups)
+	if !ok {
+		return
+	}
+
+	backups.mu.Lock()
+	defer backups.mu.Unlock()
+
+	backups.paths[id] = path
+}
+
+// attachVolumeBackups sets the backup paths recorded during a scan on each container's status.
+//
+// Parameters:
+//   - progress: Scan progress.
+//   - backups: Collection holding the scan's backups.
+func attachVolumeBackups(progress session.Progress, backups *volumeBackups) {
+	backups.mu.Lock()
+	defer backups.mu.Unlock()
+
+	for id, path := range backups.paths {
+		if status, ok := progress[id]; ok {
+			status.SetBackupPath(path)
+		}
+	}
+}
+
+// backupContainerVolumes archives the volumes of a stale container labeled for backup.
+//
+// The container is stopped first so the archive is consistent, but only
+// removed by the caller once the backup succeeded. If the backup fails, a
+// previously running container is started again and the update is aborted.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - cont: Container about to be recreated.
+//   - client: Container client for Docker operations.
+//   - config: Update options with the backup settings and stop timeout.
+//
+// Returns:
+//   - error: Non-nil if the container cannot be stopped or backed up.
+func backupContainerVolumes(log *zerolog.Logger, ctx context.Context,
+	cont types.Container,
+	client container.Client,
+	config types.UpdateParams,
+) error {
+	if config.VolumeBackupDir == "" || !cont.IsStale() || !container.VolumeBackupEnabled(cont) {
+		return nil
+	}
+
+	fields := map[string]any{
+		"container": cont.Name(),
+		"image":     cont.ImageName(),
+	}
+
+	// Docker deletes an auto-remove container as soon as it stops, so it
+	// could not be restarted if the backup failed.
+	if info := cont.ContainerInfo(); info != nil && info.HostConfig != nil && info.HostConfig.AutoRemove {
+		log.Error().
+			Fields(fields).
+			Msg("Volume backups are not supported for auto-remove containers, skipping update")
+
+		return fmt.Errorf("%w: %w", errVolumeBackupFailed, errAutoRemoveBackup)
+	}
+
+	err := client.StopContainer(ctx, cont, config.Timeout)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Fields(fields).
+			Msg("Failed to stop container for volume backup")
+
+		return fmt.Errorf("%w: %w", errStopContainerFailed, err)
+	}
+
+	backupPath, err := client.BackupVolumes(ctx, cont, config)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Fields(fields).
+			Msg("Failed to back up container volumes, keeping the current container")
+
+		if cont.IsRunning() {
+			// Restart even if the scan was canceled, so the service is not left down.
+			startErr := client.StartContainerByID(context.WithoutCancel(ctx), cont.ID())
+			if startErr != nil {
+				log.Error().
+					Err(startErr).
+					Fields(fields).
+					Msg("Failed to restart container after volume backup failure")
+			}
+		}
+
+		return fmt.Errorf("%w: %w", errVolumeBackupFailed, err)
+	}
+
+	if backupPath != "" {
+		recordVolumeBackup(ctx, cont.ID(), backupPath)
+	}
+
+	return nil
+}
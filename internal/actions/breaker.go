@@ -0,0 +1,284 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/events"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// circuitBreaker halts the remaining updates of a scan once too many of them failed.
+//
+// It trips after BreakerFailures consecutive failures, or once BreakerPercent
+// percent of the updates planned for the scan failed. Containers reached after
+// it tripped are left on their current images.
+type circuitBreaker struct {
+	consecutiveLimit int
+	percentLimit     int
+
+	mutex       sync.Mutex
+	planned     int
+	failed      int
+	consecutive int
+	reason      string
+	halted      []types.Container
+}
+
+// circuitBreakerKey is the context key for a *circuitBreaker.
+type circuitBreakerKey struct{}
+
+// withCircuitBreaker returns a context that halts the updates of a scan under the given policy.
+//
+// Parameters:
+//   - ctx: Parent context.
+//   - params: Update parameters holding the breaker limits.
+//
+// Returns:
+//   - context.Context: Context carrying the breaker.
+//   - *circuitBreaker: The breaker, closed.
+func withCircuitBreaker(ctx context.Context, params types.UpdateParams) (context.Context, *circuitBreaker) {
+	breaker := &circuitBreaker{
+		consecutiveLimit: params.BreakerFailures,
+		percentLimit:     params.BreakerPercent,
+	}
+
+	return context.WithValue(ctx, circuitBreakerKey{}, breaker), breaker
+}
+
+// circuitBreakerFrom returns the breaker carried by ctx.
+//
+// Parameters:
+//   - ctx: Context that may carry a breaker.
+//
+// Returns:
+//   - *circuitBreaker: The breaker, or nil if ctx carries none.
+func circuitBreakerFrom(ctx context.Context) *circuitBreaker {
+	breaker, _ := ctx.Value(circuitBreakerKey{}).(*circuitBreaker)
+
+	return breaker
+}
+
+// enabled reports whether a failure limit is set.
+//
+// Returns:
+//   - bool: True if the breaker can trip.
+func (b *circuitBreaker) enabled() bool {
+	return b != nil && (b.consecutiveLimit > 0 || b.percentLimit > 0)
+}
+
+// plan sets the number of updates the scan is about to perform.
+//
+// Parameters:
+//   - updates: Stale containers that will be recreated.
+func (b *circuitBreaker) plan(updates int) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.planned = updates
+}
+
+// record counts the outcome of an update and trips the breaker when a limit is reached.
+//
+// Parameters:
+//   - err: Error of the update, nil on success. Updates skipped by a pre-update hook are not counted.
+func (b *circuitBreaker) record(err error) {
+	if b == nil || errors.Is(err, errSkipUpdate) {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err == nil {
+		b.consecutive = 0
+
+		return
+	}
+
+	b.failed++
+	b.consecutive++
+
+	if b.reason != "" {
+		return
+	}
+
+	switch {
+	case b.consecutiveLimit > 0 && b.consecutive >= b.consecutiveLimit:
+		b.reason = fmt.Sprintf("%d consecutive update failures", b.consecutive)
+	case b.percentLimit > 0 && b.planned > 0 && b.failed*100 >= b.percentLimit*b.planned:
+		b.reason = fmt.Sprintf("%d of %d planned updates failed", b.failed, b.planned)
+	}
+}
+
+// halt reports whether the breaker tripped and, if so, leaves the container untouched.
+//
+// The first halted container logs the reason at error level, which reaches the notifiers.
+//
+// Parameters:
+//   - log: Logger for the halt.
+//   - cont: Container about to be stopped.
+//
+// Returns:
+//   - bool: True if the container must not be stopped or updated.
+func (b *circuitBreaker) halt(log *zerolog.Logger, cont types.Container) bool {
+	if b == nil {
+		return false
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.reason == "" {
+		return false
+	}
+
+	if len(b.halted) == 0 {
+		log.Error().
+			Str("reason", b.reason).
+			Int("failed", b.failed).
+			Msg("Halted remaining updates after repeated failures")
+	}
+
+	b.halted = append(b.halted, cont)
+
+	log.Debug().
+		Str("container", cont.Name()).
+		Msg("Skipping container update (scan aborted)")
+
+	return true
+}
+
+// isHalted reports whether a container was left untouched by the breaker.
+//
+// Parameters:
+//   - id: Container ID.
+//
+// Returns:
+//   - bool: True if the container was halted.
+func (b *circuitBreaker) isHalted(id types.ContainerID) bool {
+	if b == nil {
+		return false
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, cont := range b.halted {
+		if cont.ID() == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reportHalted marks the halted containers as skipped.
+//
+// Parameters:
+//   - log: Logger for progress updates.
+//   - progress: Scan progress.
+//   - params: Update parameters for monitor-only checks.
+func (b *circuitBreaker) reportHalted(log *zerolog.Logger, progress session.Progress, params types.UpdateParams) {
+	if b == nil {
+		return
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, cont := range b.halted {
+		progress.AddSkipped(log, cont, fmt.Errorf("%w: %s", errScanAborted, b.reason), params)
+	}
+}
+
+// aborted returns the scan_aborted event payload if the breaker halted any container.
+//
+// Returns:
+//   - events.ScanAbortedData: Reason, failure count, and halted container names.
+//   - bool: True if the scan was aborted.
+func (b *circuitBreaker) aborted() (events.ScanAbortedData, bool) {
+	if b == nil {
+		return events.ScanAbortedData{}, false
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.halted) == 0 {
+		return events.ScanAbortedData{}, false
+	}
+
+	names := make([]string, len(b.halted))
+	for i, cont := range b.halted {
+		names[i] = cont.Name()
+	}
+
+	return events.ScanAbortedData{
+		Reason: b.reason,
+		Failed: b.failed,
+		Halted: names,
+	}, true
+}
+
+// deferExcessUpdates keeps updates within the per-scan limit.
+//
+// Stale containers beyond the first MaxUpdates, in dependency order, are no
+// longer marked stale and are reported as skipped, so the next scan updates them.
+//
+// Parameters:
+//   - log: Logger for the deferral.
+//   - containers: Monitored containers sorted by dependencies.
+//   - containerByID: All containers by ID, whose stale status is cleared too.
+//   - progress: Scan progress.
+//   - params: Update parameters holding the limit.
+func deferExcessUpdates(log *zerolog.Logger,
+	containers []types.Container,
+	containerByID map[types.ContainerID]types.Container,
+	progress session.Progress,
+	params types.UpdateParams,
+) {
+	if params.MaxUpdates <= 0 {
+		return
+	}
+
+	planned, deferred := 0, 0
+
+	for _, cont := range containers {
+		if !cont.IsStale() {
+			continue
+		}
+
+		if planned < params.MaxUpdates {
+			planned++
+
+			continue
+		}
+
+		cont.SetStale(false)
+
+		if ac, ok := containerByID[cont.ID()]; ok {
+			ac.SetStale(false)
+		}
+
+		progress.AddSkipped(log, cont, errUpdateLimitReached, params)
+
+		deferred++
+	}
+
+	if deferred > 0 {
+		log.Info().
+			Int("limit", params.MaxUpdates).
+			Int("deferred", deferred).
+			Msg("Deferred updates beyond the per-run limit to the next run")
+	}
+}
@@ -0,0 +1,201 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/events"
+	"github.com/nicholas-fedor/watchtower/pkg/filters"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+var errStopRefused = errors.New("stop refused")
+
+var _ = ginkgo.Describe("blast-radius limits", func() {
+	var client mockActions.MockClient
+
+	// webContainer returns a running, unlinked container.
+	webContainer := func(name string) types.Container {
+		return mockActions.CreateMockContainerWithConfig(
+			name,
+			name,
+			"fake-"+name+":latest",
+			true,
+			false,
+			time.Now(),
+			&dockerContainer.Config{Image: "fake-" + name + ":latest"},
+		)
+	}
+
+	// skipReasons maps skipped container names to their skip reasons.
+	skipReasons := func(report types.Report) map[string]string {
+		reasons := map[string]string{}
+		for _, skipped := range report.Skipped() {
+			reasons[skipped.Name()] = skipped.Error()
+		}
+
+		return reasons
+	}
+
+	ginkgo.BeforeEach(func() {
+		client = mockActions.CreateMockClient(&mockActions.TestData{
+			Containers: []types.Container{
+				webContainer("web-1"),
+				webContainer("web-2"),
+				webContainer("web-3"),
+				webContainer("web-4"),
+			},
+		}, false, false)
+	})
+
+	ginkgo.It("should defer updates beyond the per-run limit", func() {
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.MaxUpdates = 1
+
+		report, _, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(report.Updated()).To(gomega.HaveLen(1))
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(1)))
+		gomega.Expect(skipReasons(report)).To(gomega.HaveLen(3))
+
+		for _, reason := range skipReasons(report) {
+			gomega.Expect(reason).To(gomega.Equal(errUpdateLimitReached.Error()))
+		}
+	})
+
+	ginkgo.It("should halt a rolling restart after consecutive failures", func() {
+		client.TestData.StartContainerByIDError = errCrashOnStart
+
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.RollingRestart = true
+		params.BreakerFailures = 2
+
+		report, _, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(report.Failed()).To(gomega.HaveLen(2))
+		gomega.Expect(client.TestData.StopAndRemoveContainerCount.Load()).To(gomega.Equal(int32(2)))
+
+		reasons := skipReasons(report)
+		gomega.Expect(reasons).To(gomega.HaveLen(2))
+
+		for _, reason := range reasons {
+			gomega.Expect(reason).To(gomega.Equal("scan aborted: 2 consecutive update failures"))
+		}
+	})
+
+	ginkgo.It("should halt once the failure percentage is reached", func() {
+		client.TestData.StartContainerByIDError = errCrashOnStart
+
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.RollingRestart = true
+		params.BreakerPercent = 25
+
+		report, _, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(report.Failed()).To(gomega.HaveLen(1))
+		gomega.Expect(skipReasons(report)).To(gomega.HaveLen(3))
+
+		for _, reason := range skipReasons(report) {
+			gomega.Expect(reason).To(gomega.Equal("scan aborted: 1 of 4 planned updates failed"))
+		}
+	})
+
+	ginkgo.It("should halt batch restarts after consecutive start failures", func() {
+		client.TestData.StartContainerByIDError = errCrashOnStart
+
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.BreakerFailures = 2
+
+		report, _, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(report.Failed()).To(gomega.HaveLen(2))
+		gomega.Expect(client.TestData.StopAndRemoveContainerCount.Load()).To(gomega.Equal(int32(2)))
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(2)))
+
+		reasons := skipReasons(report)
+		gomega.Expect(reasons).To(gomega.HaveLen(2))
+
+		for _, reason := range reasons {
+			gomega.Expect(reason).To(gomega.Equal("scan aborted: 2 consecutive update failures"))
+		}
+	})
+
+	ginkgo.It("should leave the remaining containers running when stops fail", func() {
+		client.TestData.StopContainerError = errStopRefused
+		client.TestData.StopContainerFailCount = 4
+
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.BreakerFailures = 1
+
+		report, _, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(client.TestData.StopContainerCount.Load()).To(gomega.Equal(int32(1)))
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.BeZero())
+		gomega.Expect(report.Failed()).To(gomega.HaveLen(1))
+		gomega.Expect(skipReasons(report)).To(gomega.HaveLen(3))
+	})
+
+	ginkgo.It("should publish a scan_aborted event", func() {
+		client.TestData.StartContainerByIDError = errCrashOnStart
+
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.RollingRestart = true
+		params.BreakerFailures = 3
+
+		broadcaster := events.NewBroadcaster()
+		subCh := broadcaster.Subscribe()
+
+		RunUpdatesWithNotifications(context.Background(), RunUpdatesWithNotificationsParams{
+			Logger:           testLogger(),
+			Client:           client,
+			EventBroadcaster: broadcaster,
+			Update:           params,
+		})
+
+		var aborted []events.ScanAbortedData
+
+		for len(subCh) > 0 {
+			event := <-subCh
+			if event.Type == "scan_aborted" {
+				data, ok := event.Data.(events.ScanAbortedData)
+				gomega.Expect(ok).To(gomega.BeTrue())
+
+				aborted = append(aborted, data)
+			}
+		}
+
+		gomega.Expect(aborted).To(gomega.HaveLen(1))
+		gomega.Expect(aborted[0].Reason).To(gomega.Equal("3 consecutive update failures"))
+		gomega.Expect(aborted[0].Failed).To(gomega.Equal(3))
+		gomega.Expect(aborted[0].Halted).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should not abort a scan whose failures left nothing to halt", func() {
+		client.TestData.StartContainerByIDError = errCrashOnStart
+
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.RollingRestart = true
+		params.BreakerFailures = 4
+
+		ctx, breaker := withCircuitBreaker(context.Background(), params)
+
+		report, _, err := Update(testLogger(), ctx, client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(report.Failed()).To(gomega.HaveLen(4))
+
+		_, ok := breaker.aborted()
+		gomega.Expect(ok).To(gomega.BeFalse())
+	})
+})
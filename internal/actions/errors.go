@@ -28,6 +28,10 @@ var (
 	)
 	// errStopContainerFailed indicates a failure to stop a container during the update process.
 	errStopContainerFailed = errors.New("failed to stop container")
+	// errVolumeBackupFailed indicates a failure to back up a container's volumes before recreating it.
+	errVolumeBackupFailed = errors.New("failed to back up container volumes")
+	// errAutoRemoveBackup indicates a volume backup was requested for an auto-remove container.
+	errAutoRemoveBackup = errors.New("auto-remove containers cannot be stopped without being deleted")
 	// errStartContainerFailed indicates a failure to start a container after an update.
 	errStartContainerFailed = errors.New("failed to start container")
 	// errCreateContainerFailed indicates a failure to create a container during the update process.
@@ -40,6 +44,14 @@ var (
 	errCircularDependency = errors.New("circular dependency detected")
 	// errSelfDependency indicates a container has a self-dependency.
 	errSelfDependency = errors.New("container has self-dependency")
+	// errUpdateLimitReached indicates a stale container was deferred by the per-run update limit.
+	errUpdateLimitReached = errors.New("update limit reached, deferred to next run")
+	// errScanAborted indicates the circuit breaker halted a container's update.
+	errScanAborted = errors.New("scan aborted")
+	// errCrashReverted indicates a container that crashed after its update was recreated from its previous image.
+	errCrashReverted = errors.New("reverted to the previous image")
+	// errCrashRevertFailed indicates a container that crashed after its update could not be reverted.
+	errCrashRevertFailed = errors.New("failed to revert to the previous image")
 )
 
 // Errors for Watchtower self-update operations.
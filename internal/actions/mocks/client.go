@@ -11,6 +11,7 @@ import (
 
 	dockerContainer "github.com/moby/moby/api/types/container"
 
+	"github.com/nicholas-fedor/watchtower/pkg/container"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
 
@@ -47,6 +48,8 @@ type TestData struct {
 	Containers                   []types.Container                     // List of mock containers.
 	ContainersByID               map[types.ContainerID]types.Container // Map of containers by ID.
 	Staleness                    map[string]bool                       // Map of container names to staleness status.
+	LatestImageMetadata          map[string]types.ImageMetadata        // Map of container names to latest image metadata.
+	LatestImageIDs               map[string]types.ImageID              // Map of container names to the latest image IDs IsContainerStale reports.
 	IsContainerStaleError        error                                 // Error to return from IsContainerStale (for testing).
 	ListContainersError          error                                 // Error to return from ListContainers (for testing).
 	ListContainersFailCount      int                                   // Number of times ListContainers should fail before succeeding.
@@ -77,6 +80,17 @@ type TestData struct {
 	StartContainerByIDCtx       context.Context               // Last context passed to StartContainerByID.
 	GetContainerCtx             context.Context               // Last context passed to GetContainer.
 	StopAndRemoveContainerCtx   context.Context               // Last context passed to StopAndRemoveContainer.
+	BackupVolumesCount          atomic.Int32                  // Number of times BackupVolumes was called.
+	BackupVolumesError          error                         // Error to return from BackupVolumes (for testing).
+	BackupPath                  string                        // Path returned by BackupVolumes.
+	Images                      []types.ImageSummary          // Images returned by ListImages.
+	ListImagesError             error                         // Error to return from ListImages (for testing).
+	RemovedImageIDs             []types.ImageID               // IDs passed to successful RemoveImageByID calls, in order.
+	HasImageError               error                         // Error to return from HasImage (for testing).
+	MissingImages               []types.ImageID               // Images HasImage reports as absent.
+	CreatedImages               []string                      // Images of the create configs of containers passed to CreateContainer, in order.
+	ObserveContainerCount       atomic.Int32                  // Number of times ObserveContainer was called.
+	ObserveErrors               map[types.ContainerID]error   // Errors returned by ObserveContainer, by observed container ID.
 }
 
 // recordOperation appends an operation name to OperationOrder for sequencing tests.
@@ -258,6 +272,10 @@ func (client MockClient) CreateContainer(ctx context.Context, c types.Container)
 
 	client.TestData.CreateOrder = append(client.TestData.CreateOrder, c.Name())
 
+	if concrete, ok := c.(*container.Container); ok {
+		client.TestData.CreatedImages = append(client.TestData.CreatedImages, concrete.GetCreateConfig().Image)
+	}
+
 	newID := types.ContainerID(string(c.ID()) + "-recreated")
 	client.TestData.LastCreatedContainerID = newID
 
@@ -410,6 +428,8 @@ func (client MockClient) RemoveImageByID(ctx context.Context, imageID types.Imag
 		return client.TestData.RemoveImageError
 	}
 
+	client.TestData.RemovedImageIDs = append(client.TestData.RemovedImageIDs, imageID)
+
 	return nil
 }
 
@@ -502,7 +522,7 @@ func (client MockClient) IsContainerStale(
 		stale = true // Default to stale if not specified.
 	}
 
-	return stale, "", "", nil
+	return stale, client.TestData.LatestImageIDs[container.Name()], "", nil
 }
 
 // CheckContainerUpdate reports update availability using the same staleness map as IsContainerStale.
@@ -514,6 +534,15 @@ func (client MockClient) CheckContainerUpdate(
 	return client.IsContainerStale(ctx, container, params)
 }
 
+// LatestImageMetadata returns the metadata configured for the container in TestData.
+func (client MockClient) LatestImageMetadata(
+	_ context.Context,
+	container types.Container,
+	_ types.ImageID,
+) (types.ImageMetadata, error) {
+	return client.TestData.LatestImageMetadata[container.Name()], nil
+}
+
 // WarnOnHeadPullFailed always returns true for the mock client.
 // It simulates a warning condition for HEAD pull failures in tests.
 func (client MockClient) WarnOnHeadPullFailed(_ types.Container) bool {
@@ -583,3 +612,67 @@ func (client MockClient) GetInfo(ctx context.Context) (map[string]any, error) {
 func (client MockClient) Ping(ctx context.Context) error {
 	return client.checkContextCancellation(ctx)
 }
+
+// BackupVolumes simulates archiving a container's volumes.
+// It returns the configured BackupVolumesError if set, and BackupPath otherwise.
+func (client MockClient) BackupVolumes(ctx context.Context, _ types.Container, _ types.UpdateParams) (string, error) {
+	client.TestData.BackupVolumesCount.Add(1)
+	client.TestData.recordOperation("BackupVolumes")
+
+	if err := client.checkContextCancellation(ctx); err != nil {
+		return "", err
+	}
+
+	if client.TestData.BackupVolumesError != nil {
+		return "", client.TestData.BackupVolumesError
+	}
+
+	return client.TestData.BackupPath, nil
+}
+
+// ObserveContainer simulates watching a started container for crashes.
+// It returns the error configured in ObserveErrors for the container, nil otherwise.
+func (client MockClient) ObserveContainer(
+	ctx context.Context,
+	containerID types.ContainerID,
+	_ time.Duration,
+	_ types.UpdateParams,
+) error {
+	client.TestData.ObserveContainerCount.Add(1)
+
+	if err := client.checkContextCancellation(ctx); err != nil {
+		return err
+	}
+
+	return client.TestData.ObserveErrors[containerID]
+}
+
+// ListImages returns the images from TestData.
+// It returns the configured ListImagesError if set.
+func (client MockClient) ListImages(ctx context.Context) ([]types.ImageSummary, error) {
+	if err := client.checkContextCancellation(ctx); err != nil {
+		return nil, err
+	}
+
+	if client.TestData.ListImagesError != nil {
+		return nil, client.TestData.ListImagesError
+	}
+
+	return client.TestData.Images, nil
+}
+
+// HasImage reports images as present unless listed in MissingImages.
+// It returns the configured HasImageError if set.
+func (client MockClient) HasImage(ctx context.Context, imageID types.ImageID) (bool, error) {
+	client.TestData.recordOperation("HasImage")
+
+	if err := client.checkContextCancellation(ctx); err != nil {
+		return false, err
+	}
+
+	if client.TestData.HasImageError != nil {
+		return false, client.TestData.HasImageError
+	}
+
+	return !slices.Contains(client.TestData.MissingImages, imageID), nil
+}
@@ -0,0 +1,237 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// restartedContainer is a stale container recreated from its new image.
+type restartedContainer struct {
+	cont types.Container
+	id   types.ContainerID
+}
+
+// observeUpdatedContainer watches a container recreated from a new image for crashes and restart loops.
+//
+// Containers that were not started, the Watchtower container, and containers
+// whose observation period is zero are not observed. Failures to observe are
+// logged and do not fail the update.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - client: Container client for Docker operations.
+//   - cont: Container that was updated.
+//   - newID: ID of the recreated container.
+//   - config: Update options with the observation settings.
+//
+// Returns:
+//   - *container.CrashError: The crash, or nil if the container stayed up or was not observed.
+func observeUpdatedContainer(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	cont types.Container,
+	newID types.ContainerID,
+	config types.UpdateParams,
+) *container.CrashError {
+	if !cont.IsStale() || cont.IsWatchtower() || newID == "" {
+		return nil
+	}
+
+	// The recreated container is only started if the old one ran or stopped containers are revived.
+	if config.NoRestart || (!cont.IsRunning() && !config.ReviveStopped) {
+		return nil
+	}
+
+	period, err := container.ObservePeriod(cont, config)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("container", cont.Name()).
+			Msg("Failed to parse observe-period label, using global value")
+	}
+
+	if period <= 0 {
+		return nil
+	}
+
+	log.Debug().
+		Str("container", cont.Name()).
+		Dur("period", period).
+		Msg("Observing updated container")
+
+	err = client.ObserveContainer(containerContext(ctx, cont), newID, period, config)
+	if err == nil {
+		return nil
+	}
+
+	crash, ok := errors.AsType[*container.CrashError](err)
+	if !ok {
+		log.Warn().
+			Err(err).
+			Str("container", cont.Name()).
+			Msg("Failed to observe updated container")
+
+		return nil
+	}
+
+	return crash
+}
+
+// observeUpdatedContainers observes several updated containers at once, so their observation periods overlap.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - client: Container client for Docker operations.
+//   - restarted: Updated containers and the IDs of their recreated containers.
+//   - config: Update options with the observation settings.
+//
+// Returns:
+//   - map[types.ContainerID]*container.CrashError: Crashes by ID of the updated container.
+func observeUpdatedContainers(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	restarted []restartedContainer,
+	config types.UpdateParams,
+) map[types.ContainerID]*container.CrashError {
+	var (
+		mutex sync.Mutex
+		group sync.WaitGroup
+	)
+
+	crashes := make(map[types.ContainerID]*container.CrashError)
+
+	for _, r := range restarted {
+		group.Go(func() {
+			crash := observeUpdatedContainer(log, ctx, client, r.cont, r.id, config)
+			if crash == nil {
+				return
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			crashes[r.cont.ID()] = crash
+		})
+	}
+
+	group.Wait()
+
+	return crashes
+}
+
+// handleCrash reports an updated container that crashed and, if enabled, reverts it.
+//
+// The crash logs are attached to the container's report. With RevertOnCrash,
+// the container is recreated from its previous image and pinned against the
+// crashing one, as by a rollback.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - cont: Container that was updated.
+//   - newID: ID of the crashed container.
+//   - crash: The crash.
+//   - config: Update options controlling the revert.
+//   - progress: Scan progress receiving the crash logs; may be nil.
+//
+// Returns:
+//   - error: Error the update fails with, wrapping the crash and the outcome of the revert.
+func handleCrash(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	cont types.Container,
+	newID types.ContainerID,
+	crash *container.CrashError,
+	config types.UpdateParams,
+	progress *session.Progress,
+) error {
+	log.Warn().
+		Str("container", cont.Name()).
+		Str("image", cont.ImageName()).
+		Bool("exited", crash.Exited).
+		Int("exit_code", crash.ExitCode).
+		Int("restarts", crash.Restarts).
+		Msg("Container crashed after update")
+
+	var status *session.ContainerStatus
+	if progress != nil {
+		status = (*progress)[cont.ID()]
+	}
+
+	if status != nil {
+		status.SetCrashLogs(crash.Logs)
+	}
+
+	if !config.RevertOnCrash {
+		return crash
+	}
+
+	revertedID, err := revertCrashedContainer(log, ctx, client, cont, newID, config)
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("container", cont.Name()).
+			Msg("Failed to revert crashed container")
+
+		return fmt.Errorf("%w; %w: %w", crash, errCrashRevertFailed, err)
+	}
+
+	if status != nil {
+		status.SetNewContainerID(revertedID)
+	}
+
+	log.Info().
+		Str("container", cont.Name()).
+		Str("image_id", cont.ImageID().ShortID()).
+		Msg("Reverted crashed container to its previous image")
+
+	return fmt.Errorf("%w; %w", crash, errCrashReverted)
+}
+
+// revertCrashedContainer recreates a crashed container from the image it ran before the update.
+//
+// Lifecycle hooks and volume backups are skipped, as the crashed container
+// cannot run hooks and its volumes were backed up before the update.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - cont: Container that was updated; its image is the one reverted to.
+//   - newID: ID of the crashed container.
+//   - config: Update options controlling stop timeouts.
+//
+// Returns:
+//   - types.ContainerID: ID of the reverted container.
+//   - error: Non-nil if the crashed container cannot be found or recreated.
+func revertCrashedContainer(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	cont types.Container,
+	newID types.ContainerID,
+	config types.UpdateParams,
+) (types.ContainerID, error) {
+	crashed, err := client.GetContainer(ctx, newID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrRollbackContainerNotFound, err)
+	}
+
+	// Pin the reverted container against the crashing image so later scans skip it.
+	container.SetLabel(crashed, container.RolledBackFromLabel, string(crashed.ImageID()))
+	container.SetLabel(crashed, container.PreviousImageLabel, "")
+
+	config.LifecycleHooks = false
+	config.VolumeBackupDir = ""
+	config.NoRestart = false
+	config.ReviveStopped = true
+
+	_, revertedID, err := recreateFromImage(log, ctx, client, crashed, cont.ImageID(), true, config)
+	if err != nil {
+		return "", err
+	}
+
+	return revertedID, nil
+}
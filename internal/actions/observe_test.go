@@ -0,0 +1,128 @@
+package actions
+
+import (
+	"context"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/filters"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+var _ = ginkgo.Describe("crash detection after updates", func() {
+	var client mockActions.MockClient
+
+	// appContainer returns a running, unlinked container.
+	appContainer := func(name string) types.Container {
+		return mockActions.CreateMockContainerWithConfig(
+			name,
+			name,
+			"fake-"+name+":latest",
+			true,
+			false,
+			time.Now(),
+			&dockerContainer.Config{Image: "fake-" + name + ":latest"},
+		)
+	}
+
+	// failedByName maps failed container names to their reports.
+	failedByName := func(report types.Report) map[string]types.ContainerReport {
+		failed := map[string]types.ContainerReport{}
+		for _, cont := range report.Failed() {
+			failed[cont.Name()] = cont
+		}
+
+		return failed
+	}
+
+	crash := &container.CrashError{
+		Exited:   true,
+		ExitCode: 1,
+		Logs:     []string{"loading config", "panic: missing DATABASE_URL"},
+	}
+
+	ginkgo.BeforeEach(func() {
+		client = mockActions.CreateMockClient(&mockActions.TestData{
+			Containers: []types.Container{
+				appContainer("api"),
+				appContainer("worker"),
+			},
+			ObserveErrors: map[types.ContainerID]error{
+				"worker-recreated": crash,
+			},
+		}, false, false)
+	})
+
+	ginkgo.It("should not observe containers without an observation period", func() {
+		report, _, err := Update(testLogger(), context.Background(), client, defaultTestUpdateParams(filters.NoFilter))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(report.Updated()).To(gomega.HaveLen(2))
+		gomega.Expect(client.TestData.ObserveContainerCount.Load()).To(gomega.BeZero())
+	})
+
+	ginkgo.It("should fail a crashed container, attach its logs, and keep its old image", func() {
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.ObservePeriod = time.Minute
+
+		report, cleanupImages, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(client.TestData.ObserveContainerCount.Load()).To(gomega.Equal(int32(2)))
+		gomega.Expect(report.Updated()).To(gomega.HaveLen(1))
+
+		failed := failedByName(report)
+		gomega.Expect(failed).To(gomega.HaveKey("worker"))
+		gomega.Expect(failed["worker"].Error()).To(gomega.Equal("container crashed after update: exited with code 1"))
+		gomega.Expect(failed["worker"].CrashLogs()).To(gomega.Equal(crash.Logs))
+
+		gomega.Expect(cleanupImages).To(gomega.HaveLen(1))
+		gomega.Expect(cleanupImages[0].ContainerName).To(gomega.Equal("api"))
+	})
+
+	ginkgo.It("should revert a crashed container to its previous image during a rolling restart", func() {
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.ObservePeriod = time.Minute
+		params.RollingRestart = true
+		params.RevertOnCrash = true
+
+		report, _, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		failed := failedByName(report)
+		gomega.Expect(failed).To(gomega.HaveKey("worker"))
+		gomega.Expect(failed["worker"].Error()).To(gomega.HaveSuffix("; reverted to the previous image"))
+		gomega.Expect(failed["worker"].NewContainerID()).To(gomega.Equal(types.ContainerID("worker-recreated-recreated")))
+
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(3)))
+		gomega.Expect(client.TestData.CreatedImages).To(
+			gomega.ConsistOf("fake-api:latest", "fake-worker:latest", "fake-worker:latest"))
+
+		crashed := client.TestData.ContainersByID["worker-recreated"]
+		gomega.Expect(crashed.ImageName()).To(gomega.Equal("fake-worker:latest"))
+		gomega.Expect(container.RolledBackFrom(crashed)).To(gomega.Equal(crashed.ImageID()))
+		gomega.Expect(container.PreviousImage(crashed)).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should report a failed revert", func() {
+		client.TestData.HasImageError = errStopRefused
+
+		params := defaultTestUpdateParams(filters.NoFilter)
+		params.ObservePeriod = time.Minute
+		params.RevertOnCrash = true
+
+		report, _, err := Update(testLogger(), context.Background(), client, params)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		failed := failedByName(report)
+		gomega.Expect(failed).To(gomega.HaveKey("worker"))
+		gomega.Expect(failed["worker"].Error()).To(gomega.ContainSubstring("; failed to revert to the previous image: "))
+		gomega.Expect(failed["worker"].CrashLogs()).To(gomega.Equal(crash.Logs))
+	})
+})
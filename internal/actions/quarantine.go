@@ -0,0 +1,254 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// ErrQuarantinedDigest indicates a container was skipped because updates to its latest image kept failing.
+var ErrQuarantinedDigest = errors.New("quarantined digest")
+
+// QuarantinePolicy configures when images that fail to update are quarantined.
+type QuarantinePolicy struct {
+	// After is the number of failed updates to the same image before it is quarantined; 0 disables quarantine.
+	After int
+	// Backoff is how long an image is first quarantined; it doubles with every further failure.
+	Backoff time.Duration
+	// MaxBackoff caps the quarantine period; 0 does not cap it.
+	MaxBackoff time.Duration
+}
+
+// QuarantinedDigest is an image that failed to update a container.
+type QuarantinedDigest struct {
+	ContainerName string        `json:"container"`
+	ImageName     string        `json:"image"`
+	ImageID       types.ImageID `json:"image_id"`
+	Failures      int           `json:"failures"`
+	LastError     string        `json:"last_error"`
+	LastFailure   time.Time     `json:"last_failure"`
+	Until         time.Time     `json:"until"`
+}
+
+// Quarantine remembers images that failed to update a container and holds them back.
+//
+// Once updates of a container to the same image failed often enough, scans
+// skip that image until its quarantine expires. Every failed retry doubles the
+// quarantine. A newer image is not affected, and a successful update or
+// Clear forgets the failures.
+type Quarantine struct {
+	log    *zerolog.Logger
+	policy QuarantinePolicy
+	now    func() time.Time
+
+	mutex   sync.Mutex
+	entries map[string]*QuarantinedDigest
+}
+
+// quarantineKey is the context key for a *Quarantine.
+type quarantineKey struct{}
+
+// NewQuarantine creates an image quarantine.
+//
+// Parameters:
+//   - log: Logger for quarantine decisions.
+//   - policy: Quarantine policy.
+//
+// Returns:
+//   - *Quarantine: The quarantine.
+func NewQuarantine(log *zerolog.Logger, policy QuarantinePolicy) *Quarantine {
+	return &Quarantine{
+		log:     log,
+		policy:  policy,
+		now:     time.Now,
+		entries: make(map[string]*QuarantinedDigest),
+	}
+}
+
+// Record counts the failed updates of a scan and forgets containers that updated.
+//
+// Only failures to move a container to a different image are counted; a
+// failed restart of a linked container is not held against any image.
+//
+// Parameters:
+//   - report: Scan report.
+func (q *Quarantine) Record(report types.Report) {
+	if report == nil {
+		return
+	}
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, cont := range report.Updated() {
+		if _, ok := q.entries[cont.Name()]; ok {
+			delete(q.entries, cont.Name())
+			q.log.Debug().Str("container", cont.Name()).Msg("Released image quarantine after successful update")
+		}
+	}
+
+	now := q.now()
+
+	for _, cont := range report.Failed() {
+		target := cont.LatestImageID()
+		if target == "" || target == cont.CurrentImageID() {
+			continue
+		}
+
+		entry, ok := q.entries[cont.Name()]
+		if !ok || entry.ImageID != target {
+			entry = &QuarantinedDigest{ContainerName: cont.Name(), ImageID: target}
+			q.entries[cont.Name()] = entry
+		}
+
+		entry.ImageName = cont.ImageName()
+		entry.Failures++
+		entry.LastError = cont.Error()
+		entry.LastFailure = now
+
+		if entry.Failures < q.policy.After {
+			continue
+		}
+
+		entry.Until = now.Add(q.backoff(entry.Failures))
+
+		q.log.Warn().
+			Str("container", cont.Name()).
+			Str("image", cont.ImageName()).
+			Str("image_id", target.ShortID()).
+			Int("failures", entry.Failures).
+			Time("until", entry.Until).
+			Msg("Quarantined image after repeated update failures")
+	}
+}
+
+// Entries returns the quarantined images, ordered by container name.
+//
+// Returns:
+//   - []QuarantinedDigest: Images whose failures reached the policy's limit, including expired quarantines awaiting a retry.
+func (q *Quarantine) Entries() []QuarantinedDigest {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entries := []QuarantinedDigest{}
+
+	for _, name := range slices.Sorted(maps.Keys(q.entries)) {
+		if entry := q.entries[name]; entry.Failures >= q.policy.After {
+			entries = append(entries, *entry)
+		}
+	}
+
+	return entries
+}
+
+// Clear forgets the failed updates of a container, so the next scan updates it again.
+//
+// Parameters:
+//   - name: Container name, with or without a leading slash.
+//
+// Returns:
+//   - bool: True if the container had failed updates.
+func (q *Quarantine) Clear(name string) bool {
+	name = strings.TrimPrefix(name, "/")
+
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if _, ok := q.entries[name]; !ok {
+		return false
+	}
+
+	delete(q.entries, name)
+
+	q.log.Info().Str("container", name).Msg("Cleared image quarantine")
+
+	return true
+}
+
+// check reports whether updating a container to an image is held back.
+//
+// Parameters:
+//   - cont: Container about to be updated.
+//   - imageID: Image the container would be updated to.
+//
+// Returns:
+//   - error: ErrQuarantinedDigest if the image is quarantined for the container, nil otherwise.
+func (q *Quarantine) check(cont types.Container, imageID types.ImageID) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	entry, ok := q.entries[cont.Name()]
+	if !ok || entry.ImageID != imageID || entry.Failures < q.policy.After || !q.now().Before(entry.Until) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s until %s", ErrQuarantinedDigest, imageID.ShortID(), entry.Until.UTC().Format(time.RFC3339))
+}
+
+// backoff returns the quarantine period after a number of failures.
+//
+// Parameters:
+//   - failures: Failed updates to the image, at least the policy's limit.
+//
+// Returns:
+//   - time.Duration: Backoff doubled for every failure beyond the limit, capped at MaxBackoff.
+func (q *Quarantine) backoff(failures int) time.Duration {
+	period := q.policy.Backoff
+
+	for range failures - q.policy.After {
+		if q.policy.MaxBackoff > 0 && period >= q.policy.MaxBackoff {
+			break
+		}
+
+		period *= 2
+	}
+
+	if q.policy.MaxBackoff > 0 && period > q.policy.MaxBackoff {
+		return q.policy.MaxBackoff
+	}
+
+	return period
+}
+
+// withQuarantine returns a context that makes Update skip quarantined images.
+//
+// Parameters:
+//   - ctx: Parent context.
+//   - quarantine: Quarantine to consult, or nil to update regardless.
+//
+// Returns:
+//   - context.Context: Context carrying the quarantine.
+func withQuarantine(ctx context.Context, quarantine *Quarantine) context.Context {
+	if quarantine == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, quarantineKey{}, quarantine)
+}
+
+// checkQuarantine reports whether the quarantine carried by ctx holds back an update.
+//
+// Parameters:
+//   - ctx: Context that may carry a quarantine.
+//   - cont: Container about to be updated.
+//   - imageID: Image the container would be updated to.
+//
+// Returns:
+//   - error: ErrQuarantinedDigest if the image is quarantined for the container, nil otherwise.
+func checkQuarantine(ctx context.Context, cont types.Container, imageID types.ImageID) error {
+	quarantine, ok := ctx.Value(quarantineKey{}).(*Quarantine)
+	if !ok {
+		return nil
+	}
+
+	return quarantine.check(cont, imageID)
+}
@@ -0,0 +1,129 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/filters"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+var errCrashOnStart = errors.New("container exited on start")
+
+var _ = ginkgo.Describe("image quarantine", func() {
+	var (
+		client     mockActions.MockClient
+		quarantine *Quarantine
+		now        time.Time
+	)
+
+	// scan runs one update session against the quarantine.
+	scan := func() {
+		RunUpdatesWithNotifications(context.Background(), RunUpdatesWithNotificationsParams{
+			Logger:     testLogger(),
+			Client:     client,
+			Quarantine: quarantine,
+			Update:     defaultTestUpdateParams(filters.NoFilter),
+		})
+	}
+
+	ginkgo.BeforeEach(func() {
+		client = mockActions.CreateMockClient(&mockActions.TestData{
+			Containers: []types.Container{
+				mockActions.CreateMockContainerWithConfig(
+					"test-container-web",
+					"test-container-web",
+					"fake-web:latest",
+					true,
+					false,
+					time.Now(),
+					&dockerContainer.Config{Image: "fake-web:latest"},
+				),
+			},
+			LatestImageIDs:          map[string]types.ImageID{"test-container-web": "sha256:bad"},
+			StartContainerByIDError: errCrashOnStart,
+		}, false, false)
+
+		now = time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+		quarantine = NewQuarantine(testLogger(), QuarantinePolicy{
+			After:      2,
+			Backoff:    time.Hour,
+			MaxBackoff: 3 * time.Hour,
+		})
+		quarantine.now = func() time.Time { return now }
+	})
+
+	ginkgo.It("should retry until the failures reach the limit", func() {
+		scan()
+		gomega.Expect(quarantine.Entries()).To(gomega.BeEmpty())
+
+		scan()
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(2)))
+
+		entries := quarantine.Entries()
+		gomega.Expect(entries).To(gomega.HaveLen(1))
+		gomega.Expect(entries[0].ContainerName).To(gomega.Equal("test-container-web"))
+		gomega.Expect(entries[0].ImageID).To(gomega.Equal(types.ImageID("sha256:bad")))
+		gomega.Expect(entries[0].Failures).To(gomega.Equal(2))
+		gomega.Expect(entries[0].LastError).To(gomega.ContainSubstring(errCrashOnStart.Error()))
+		gomega.Expect(entries[0].Until).To(gomega.Equal(now.Add(time.Hour)))
+	})
+
+	ginkgo.It("should skip the quarantined image until the quarantine expires", func() {
+		scan()
+		scan()
+
+		report, _, err := Update(testLogger(), withQuarantine(context.Background(), quarantine),
+			client, defaultTestUpdateParams(filters.NoFilter))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(2)))
+		gomega.Expect(report.Skipped()).To(gomega.HaveLen(1))
+		gomega.Expect(report.Skipped()[0].Error()).To(gomega.ContainSubstring(ErrQuarantinedDigest.Error()))
+
+		now = now.Add(time.Hour)
+		scan()
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(3)))
+	})
+
+	ginkgo.It("should double the quarantine with every failed retry up to the maximum", func() {
+		for range 2 {
+			scan()
+		}
+
+		for _, backoff := range []time.Duration{2 * time.Hour, 3 * time.Hour, 3 * time.Hour} {
+			now = quarantine.Entries()[0].Until
+			scan()
+			gomega.Expect(quarantine.Entries()[0].Until).To(gomega.Equal(now.Add(backoff)))
+		}
+	})
+
+	ginkgo.It("should update to a newer image", func() {
+		scan()
+		scan()
+
+		client.TestData.LatestImageIDs["test-container-web"] = "sha256:fixed"
+		client.TestData.StartContainerByIDError = nil
+		scan()
+
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(3)))
+		gomega.Expect(quarantine.Entries()).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should update again once the quarantine is cleared", func() {
+		scan()
+		scan()
+
+		gomega.Expect(quarantine.Clear("/test-container-web")).To(gomega.BeTrue())
+		gomega.Expect(quarantine.Clear("test-container-web")).To(gomega.BeFalse())
+
+		scan()
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(3)))
+	})
+})
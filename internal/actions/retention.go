@@ -0,0 +1,434 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/distribution/reference"
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// retentionStateFileMode is the permission mode of the retained image state file.
+const retentionStateFileMode = 0o600
+
+// RetentionPolicy configures which previous images ImageRetention keeps after updates.
+//
+// When both Keep and MaxAge are set, an image is removed once it exceeds either limit.
+type RetentionPolicy struct {
+	// Keep is the number of previous images kept per repository; 0 does not limit by count.
+	Keep int
+	// MaxAge is how long previous images are kept; 0 does not limit by age.
+	MaxAge time.Duration
+	// StateFile persists retained images across restarts; empty keeps them in memory.
+	StateFile string
+	// Dangling also removes untagged images of monitored repositories.
+	Dangling bool
+}
+
+// retains reports whether the policy keeps previous images instead of removing them right away.
+//
+// Returns:
+//   - bool: True if a count or age limit is set.
+func (p RetentionPolicy) retains() bool {
+	return p.Keep > 0 || p.MaxAge > 0
+}
+
+// RetainedImage is a previous image kept after its container was updated.
+type RetainedImage struct {
+	ImageID       types.ImageID     `json:"image_id"`
+	ImageName     string            `json:"image_name"`
+	Repository    string            `json:"repository"`
+	ContainerID   types.ContainerID `json:"container_id"`
+	ContainerName string            `json:"container_name"`
+	RetiredAt     time.Time         `json:"retired_at"`
+}
+
+// ImageRetention replaces immediate image cleanup with a retention policy.
+//
+// Previous images of updated containers are tracked instead of removed, so a
+// container can be rolled back without pulling again, and are removed by a
+// later scan once they fall outside the policy.
+type ImageRetention struct {
+	log    *zerolog.Logger
+	policy RetentionPolicy
+	now    func() time.Time
+
+	mutex  sync.Mutex
+	images map[types.ImageID]*RetainedImage
+}
+
+// NewImageRetention creates an image retention tracker, restoring images persisted in the policy's state file.
+//
+// Parameters:
+//   - log: Logger for cleanup and persistence diagnostics.
+//   - policy: Retention policy.
+//
+// Returns:
+//   - *ImageRetention: The tracker.
+func NewImageRetention(log *zerolog.Logger, policy RetentionPolicy) *ImageRetention {
+	retention := &ImageRetention{
+		log:    log,
+		policy: policy,
+		now:    time.Now,
+		images: make(map[types.ImageID]*RetainedImage),
+	}
+
+	if policy.StateFile == "" {
+		return retention
+	}
+
+	content, err := os.ReadFile(policy.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return retention
+	}
+
+	var retained []*RetainedImage
+	if err == nil {
+		err = json.Unmarshal(content, &retained)
+	}
+
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("file", policy.StateFile).
+			Msg("Failed to read retained images, previous images will not be cleaned up")
+
+		return retention
+	}
+
+	for _, image := range retained {
+		if image != nil && image.ImageID != "" {
+			retention.images[image.ImageID] = image
+		}
+	}
+
+	log.Debug().
+		Str("file", policy.StateFile).
+		Int("count", len(retention.images)).
+		Msg("Restored retained images")
+
+	return retention
+}
+
+// Cleanup tracks the previous images of a scan and removes images outside the policy.
+//
+// Without a count or age limit, previous images are removed right away as with
+// plain cleanup. Retained images that no longer exist on the host are forgotten.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - previous: Previous images of the containers updated in this scan.
+//   - report: Scan report, used to find the monitored repositories.
+//
+// Returns:
+//   - []types.RemovedImageInfo: Removed images with their sizes when known.
+func (r *ImageRetention) Cleanup(
+	ctx context.Context,
+	client container.Client,
+	previous []types.RemovedImageInfo,
+	report types.Report,
+) []types.RemovedImageInfo {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	images, err := client.ListImages(ctx)
+	if err != nil {
+		r.log.Warn().Err(err).Msg("Failed to list images, skipping removal of untracked images")
+	}
+
+	removed := []types.RemovedImageInfo{}
+
+	if r.policy.retains() {
+		r.retireLocked(previous)
+	} else {
+		removed = append(removed, removePreviousImages(r.log, ctx, client, previous)...)
+	}
+
+	if images != nil {
+		r.forgetMissingLocked(images)
+	}
+
+	removed = append(removed, r.pruneLocked(ctx, client)...)
+
+	if r.policy.Dangling && images != nil {
+		removed = append(removed, r.pruneDanglingLocked(ctx, client, images, report, removed)...)
+	}
+
+	r.saveLocked()
+
+	return setImageSizes(removed, images)
+}
+
+// retireLocked starts tracking previous images. Callers hold mutex.
+//
+// Parameters:
+//   - previous: Previous images of updated containers; several containers may share one image.
+func (r *ImageRetention) retireLocked(previous []types.RemovedImageInfo) {
+	now := r.now()
+
+	for _, image := range previous {
+		if image.ImageID == "" {
+			continue
+		}
+
+		r.images[image.ImageID] = &RetainedImage{
+			ImageID:       image.ImageID,
+			ImageName:     image.ImageName,
+			Repository:    imageRepository(image.ImageName),
+			ContainerID:   image.ContainerID,
+			ContainerName: image.ContainerName,
+			RetiredAt:     now,
+		}
+
+		r.log.Debug().
+			Str("image_id", image.ImageID.ShortID()).
+			Str("image_name", image.ImageName).
+			Str("container", image.ContainerName).
+			Msg("Retaining previous image")
+	}
+}
+
+// forgetMissingLocked stops tracking retained images that were removed outside Watchtower. Callers hold mutex.
+//
+// Parameters:
+//   - images: Images currently on the host.
+func (r *ImageRetention) forgetMissingLocked(images []types.ImageSummary) {
+	present := make(map[types.ImageID]bool, len(images))
+	for _, image := range images {
+		present[image.ID] = true
+	}
+
+	for id := range r.images {
+		if !present[id] {
+			delete(r.images, id)
+		}
+	}
+}
+
+// pruneLocked removes retained images beyond the policy's count or age limit. Callers hold mutex.
+//
+// Images that cannot be removed, for example because a container uses them
+// again, stay tracked and are retried by the next scan.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//
+// Returns:
+//   - []types.RemovedImageInfo: Removed images.
+func (r *ImageRetention) pruneLocked(ctx context.Context, client container.Client) []types.RemovedImageInfo {
+	byRepository := make(map[string][]*RetainedImage)
+	for _, image := range r.images {
+		byRepository[image.Repository] = append(byRepository[image.Repository], image)
+	}
+
+	now := r.now()
+
+	var expired []types.RemovedImageInfo
+
+	for _, repository := range slices.Sorted(maps.Keys(byRepository)) {
+		group := byRepository[repository]
+		sort.Slice(group, func(i, j int) bool {
+			return group[i].RetiredAt.After(group[j].RetiredAt)
+		})
+
+		for i, image := range group {
+			overCount := r.policy.Keep > 0 && i >= r.policy.Keep
+			overAge := r.policy.MaxAge > 0 && now.Sub(image.RetiredAt) > r.policy.MaxAge
+
+			if overCount || overAge {
+				expired = append(expired, types.RemovedImageInfo{
+					ImageID:       image.ImageID,
+					ContainerID:   image.ContainerID,
+					ImageName:     image.ImageName,
+					ContainerName: image.ContainerName,
+				})
+			}
+		}
+	}
+
+	if len(expired) == 0 {
+		return nil
+	}
+
+	removed, err := RemoveImages(r.log, ctx, client, expired)
+	if err != nil {
+		r.log.Warn().Err(err).Msg("Failed to remove some retained images")
+	}
+
+	for _, image := range removed {
+		delete(r.images, image.ImageID)
+	}
+
+	return removed
+}
+
+// pruneDanglingLocked removes untagged images of monitored repositories. Callers hold mutex.
+//
+// An image qualifies when it was pulled by digest from the repository of a
+// scanned container's image and is neither retained nor used by a scanned
+// container. Untagged images from local builds have no registry digest and
+// are never removed.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - images: Images on the host, listed before this cleanup.
+//   - report: Scan report.
+//   - removed: Images already removed by this cleanup.
+//
+// Returns:
+//   - []types.RemovedImageInfo: Removed images.
+func (r *ImageRetention) pruneDanglingLocked(
+	ctx context.Context,
+	client container.Client,
+	images []types.ImageSummary,
+	report types.Report,
+	removed []types.RemovedImageInfo,
+) []types.RemovedImageInfo {
+	if report == nil {
+		return nil
+	}
+
+	repositories := make(map[string]bool)
+	skip := make(map[types.ImageID]bool)
+
+	for _, cont := range report.All() {
+		if repository := imageRepository(cont.ImageName()); repository != "" {
+			repositories[repository] = true
+		}
+
+		skip[cont.CurrentImageID()] = true
+		skip[cont.LatestImageID()] = true
+	}
+
+	for _, image := range removed {
+		skip[image.ImageID] = true
+	}
+
+	var dangling []types.RemovedImageInfo
+
+	for _, image := range images {
+		if !image.Dangling() || skip[image.ID] || r.images[image.ID] != nil {
+			continue
+		}
+
+		for _, repoDigest := range image.RepoDigests {
+			if repositories[imageRepository(repoDigest)] {
+				dangling = append(dangling, types.RemovedImageInfo{
+					ImageID:   image.ID,
+					ImageName: repoDigest,
+				})
+
+				break
+			}
+		}
+	}
+
+	if len(dangling) == 0 {
+		return nil
+	}
+
+	pruned, err := RemoveImages(r.log, ctx, client, dangling)
+	if err != nil {
+		r.log.Warn().Err(err).Msg("Failed to remove some dangling images")
+	}
+
+	return pruned
+}
+
+// saveLocked writes the retained images to the state file, if any. Callers hold mutex.
+//
+// The file is replaced atomically so a crash mid-write keeps the previous contents.
+func (r *ImageRetention) saveLocked() {
+	if r.policy.StateFile == "" {
+		return
+	}
+
+	retained := slices.SortedFunc(maps.Values(r.images), func(a, b *RetainedImage) int {
+		return a.RetiredAt.Compare(b.RetiredAt)
+	})
+
+	err := writeRetentionState(r.policy.StateFile, retained)
+	if err != nil {
+		r.log.Warn().Err(err).Str("file", r.policy.StateFile).Msg("Failed to save retained images")
+	}
+}
+
+// writeRetentionState writes retained images as indented JSON to path.
+//
+// Parameters:
+//   - path: Destination file.
+//   - retained: Images to persist.
+//
+// Returns:
+//   - error: Non-nil if encoding or writing fails.
+func writeRetentionState(path string, retained []*RetainedImage) error {
+	content, err := json.MarshalIndent(retained, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+
+	err = os.WriteFile(tmp, content, retentionStateFileMode)
+	if err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("replace state: %w", err)
+	}
+
+	return nil
+}
+
+// imageRepository returns the normalized repository of an image reference.
+//
+// Parameters:
+//   - imageName: Image reference with an optional tag or digest, e.g. "nginx:latest" or "nginx@sha256:...".
+//
+// Returns:
+//   - string: Repository such as "docker.io/library/nginx", or empty if the reference cannot be parsed.
+func imageRepository(imageName string) string {
+	named, err := reference.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return ""
+	}
+
+	return named.Name()
+}
+
+// setImageSizes fills in the sizes of removed images from an image listing taken before removal.
+//
+// Parameters:
+//   - removed: Removed images.
+//   - images: Images listed before removal, or nil if unknown.
+//
+// Returns:
+//   - []types.RemovedImageInfo: The removed images with sizes set where known.
+func setImageSizes(removed []types.RemovedImageInfo, images []types.ImageSummary) []types.RemovedImageInfo {
+	sizes := make(map[types.ImageID]int64, len(images))
+	for _, image := range images {
+		sizes[image.ID] = image.Size
+	}
+
+	for i := range removed {
+		removed[i].Size = sizes[removed[i].ImageID]
+	}
+
+	return removed
+}
@@ -0,0 +1,201 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+	mockTypes "github.com/nicholas-fedor/watchtower/pkg/types/mocks"
+)
+
+var _ = ginkgo.Describe("ImageRetention", func() {
+	var (
+		client mockActions.MockClient
+		clock  time.Time
+	)
+
+	// newRetention creates a tracker whose clock is controlled by the test.
+	newRetention := func(policy RetentionPolicy) *ImageRetention {
+		retention := NewImageRetention(testLogger(), policy)
+		retention.now = func() time.Time { return clock }
+
+		return retention
+	}
+
+	// previous describes the old image of an updated container.
+	previous := func(id types.ImageID, name, containerName string) []types.RemovedImageInfo {
+		return []types.RemovedImageInfo{{ImageID: id, ImageName: name, ContainerName: containerName}}
+	}
+
+	// scanReport returns a report whose only container runs image.
+	scanReport := func(image string, current types.ImageID) types.Report {
+		cont := mockTypes.NewMockContainerReport(ginkgo.GinkgoT())
+		cont.EXPECT().ImageName().Return(image).Maybe()
+		cont.EXPECT().CurrentImageID().Return(current).Maybe()
+		cont.EXPECT().LatestImageID().Return(current).Maybe()
+
+		report := mockTypes.NewMockReport(ginkgo.GinkgoT())
+		report.EXPECT().All().Return([]types.ContainerReport{cont}).Maybe()
+
+		return report
+	}
+
+	ginkgo.BeforeEach(func() {
+		clock = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		client = mockActions.CreateMockClient(&mockActions.TestData{
+			Images: []types.ImageSummary{
+				{ID: "sha256:v1", Size: 100},
+				{ID: "sha256:v2", Size: 200},
+				{ID: "sha256:v3", Size: 300},
+				{ID: "sha256:redis", Size: 50},
+			},
+		}, false, false)
+	})
+
+	ginkgo.It("should keep the newest images of each repository", func() {
+		retention := newRetention(RetentionPolicy{Keep: 1})
+		report := scanReport("nginx:latest", "sha256:v4")
+
+		gomega.Expect(retention.Cleanup(context.Background(), client,
+			previous("sha256:v1", "nginx:latest", "web"), report)).To(gomega.BeEmpty())
+
+		clock = clock.Add(time.Hour)
+		gomega.Expect(retention.Cleanup(context.Background(), client,
+			previous("sha256:redis", "redis:7", "cache"), report)).To(gomega.BeEmpty())
+
+		clock = clock.Add(time.Hour)
+		removed := retention.Cleanup(context.Background(), client,
+			previous("sha256:v2", "docker.io/library/nginx:latest", "web"), report)
+
+		gomega.Expect(removed).To(gomega.Equal([]types.RemovedImageInfo{
+			{ImageID: "sha256:v1", ImageName: "nginx:latest", ContainerName: "web", Size: 100},
+		}))
+		gomega.Expect(client.TestData.RemovedImageIDs).To(gomega.Equal([]types.ImageID{"sha256:v1"}))
+	})
+
+	ginkgo.It("should remove images retained longer than the maximum age", func() {
+		retention := newRetention(RetentionPolicy{MaxAge: 24 * time.Hour})
+		report := scanReport("nginx:latest", "sha256:v3")
+
+		retention.Cleanup(context.Background(), client, previous("sha256:v1", "nginx:latest", "web"), report)
+
+		clock = clock.Add(23 * time.Hour)
+		retention.Cleanup(context.Background(), client, previous("sha256:v2", "nginx:latest", "web"), report)
+		gomega.Expect(client.TestData.RemovedImageIDs).To(gomega.BeEmpty())
+
+		clock = clock.Add(2 * time.Hour)
+		removed := retention.Cleanup(context.Background(), client, nil, report)
+
+		gomega.Expect(removed).To(gomega.HaveLen(1))
+		gomega.Expect(removed[0].ImageID).To(gomega.Equal(types.ImageID("sha256:v1")))
+	})
+
+	ginkgo.It("should keep images that cannot be removed for the next scan", func() {
+		client.TestData.FailedImageIDs = []types.ImageID{"sha256:v1"}
+		client.TestData.RemoveImageError = errImageRemovalFailed
+
+		retention := newRetention(RetentionPolicy{MaxAge: time.Hour})
+		report := scanReport("nginx:latest", "sha256:v3")
+
+		retention.Cleanup(context.Background(), client, previous("sha256:v1", "nginx:latest", "web"), report)
+
+		clock = clock.Add(2 * time.Hour)
+		gomega.Expect(retention.Cleanup(context.Background(), client, nil, report)).To(gomega.BeEmpty())
+
+		client.TestData.FailedImageIDs = nil
+		gomega.Expect(retention.Cleanup(context.Background(), client, nil, report)).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should forget retained images removed outside Watchtower", func() {
+		retention := newRetention(RetentionPolicy{Keep: 1})
+		report := scanReport("nginx:latest", "sha256:v3")
+
+		retention.Cleanup(context.Background(), client, previous("sha256:gone", "nginx:latest", "web"), report)
+		retention.Cleanup(context.Background(), client, previous("sha256:v1", "nginx:latest", "web"), report)
+
+		gomega.Expect(client.TestData.RemovedImageIDs).To(gomega.BeEmpty())
+		gomega.Expect(retention.images).To(gomega.HaveKey(types.ImageID("sha256:v1")))
+		gomega.Expect(retention.images).NotTo(gomega.HaveKey(types.ImageID("sha256:gone")))
+	})
+
+	ginkgo.It("should restore retained images from the state file", func() {
+		stateFile := filepath.Join(ginkgo.GinkgoT().TempDir(), "retained-images.json")
+		report := scanReport("nginx:latest", "sha256:v3")
+
+		first := newRetention(RetentionPolicy{Keep: 1, StateFile: stateFile})
+		first.Cleanup(context.Background(), client, previous("sha256:v1", "nginx:latest", "web"), report)
+		gomega.Expect(stateFile).To(gomega.BeAnExistingFile())
+
+		clock = clock.Add(time.Hour)
+		restarted := newRetention(RetentionPolicy{Keep: 1, StateFile: stateFile})
+		removed := restarted.Cleanup(context.Background(), client, previous("sha256:v2", "nginx:latest", "web"), report)
+
+		gomega.Expect(removed).To(gomega.HaveLen(1))
+		gomega.Expect(removed[0].ImageID).To(gomega.Equal(types.ImageID("sha256:v1")))
+	})
+
+	ginkgo.It("should start empty when the state file is unreadable", func() {
+		stateFile := filepath.Join(ginkgo.GinkgoT().TempDir(), "retained-images.json")
+		gomega.Expect(os.WriteFile(stateFile, []byte("{"), 0o600)).To(gomega.Succeed())
+
+		retention := newRetention(RetentionPolicy{Keep: 1, StateFile: stateFile})
+
+		gomega.Expect(retention.images).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should remove previous images right away without a count or age limit", func() {
+		retention := newRetention(RetentionPolicy{Dangling: true})
+
+		removed := retention.Cleanup(context.Background(), client,
+			previous("sha256:v1", "nginx:latest", "web"), scanReport("nginx:latest", "sha256:v3"))
+
+		gomega.Expect(removed).To(gomega.Equal([]types.RemovedImageInfo{
+			{ImageID: "sha256:v1", ImageName: "nginx:latest", ContainerName: "web", Size: 100},
+		}))
+	})
+
+	ginkgo.Describe("dangling images", func() {
+		// digest returns a registry digest suffix filled with c.
+		digest := func(c string) string {
+			return "@sha256:" + strings.Repeat(c, 64)
+		}
+
+		ginkgo.BeforeEach(func() {
+			client.TestData.Images = []types.ImageSummary{
+				{ID: "sha256:current", RepoTags: []string{"nginx:latest"}, RepoDigests: []string{"nginx" + digest("a")}},
+				{ID: "sha256:pulled", RepoTags: []string{}, RepoDigests: []string{"nginx" + digest("b")}, Size: 700},
+				{ID: "sha256:retained", RepoDigests: []string{"nginx" + digest("c")}},
+				{ID: "sha256:built", RepoTags: []string{"<none>:<none>"}},
+				{ID: "sha256:other", RepoDigests: []string{"postgres" + digest("d")}},
+			}
+		})
+
+		ginkgo.It("should remove untagged images pulled for monitored repositories", func() {
+			retention := newRetention(RetentionPolicy{Keep: 3, Dangling: true})
+
+			removed := retention.Cleanup(context.Background(), client,
+				previous("sha256:retained", "nginx:latest", "web"), scanReport("nginx:latest", "sha256:current"))
+
+			gomega.Expect(removed).To(gomega.Equal([]types.RemovedImageInfo{
+				{ImageID: "sha256:pulled", ImageName: "nginx" + digest("b"), Size: 700},
+			}))
+			gomega.Expect(client.TestData.RemovedImageIDs).To(gomega.Equal([]types.ImageID{"sha256:pulled"}))
+		})
+
+		ginkgo.It("should leave dangling images alone unless enabled", func() {
+			retention := newRetention(RetentionPolicy{Keep: 3})
+
+			removed := retention.Cleanup(context.Background(), client,
+				previous("sha256:retained", "nginx:latest", "web"), scanReport("nginx:latest", "sha256:current"))
+
+			gomega.Expect(removed).To(gomega.BeEmpty())
+		})
+	})
+})
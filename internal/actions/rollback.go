@@ -0,0 +1,357 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/internal/metrics"
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/lifecycle"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Errors for rollbacks.
+var (
+	// ErrRollbackContainerNotFound indicates no monitored container has the requested name.
+	ErrRollbackContainerNotFound = errors.New("container not found")
+	// ErrNoPreviousImage indicates the container has no recorded previous image to roll back to.
+	ErrNoPreviousImage = errors.New("no previous image recorded for container")
+	// ErrNotPinned indicates the container was not rolled back, so there is nothing to unpin.
+	ErrNotPinned = errors.New("container is not pinned")
+	// errRollbackWatchtower indicates a rollback of the Watchtower container itself was requested.
+	errRollbackWatchtower = errors.New("cannot roll back the Watchtower container")
+	// errRollbackFailed indicates the container could not be recreated from the previous image.
+	errRollbackFailed = errors.New("failed to roll back container")
+	// errRecreateImageUnavailable indicates the image to recreate a container from is no longer on the host.
+	errRecreateImageUnavailable = errors.New("image to recreate the container from is not available")
+	// errRecreateFromImageUnsupported indicates the container cannot be recreated from an image ID.
+	errRecreateFromImageUnsupported = errors.New("container cannot be recreated from an image ID")
+)
+
+// createImageSetter is a container that can be recreated from an image ID instead of its image name.
+type createImageSetter interface {
+	SetCreateImage(imageID types.ImageID)
+}
+
+// RollbackResult describes a rolled back or unpinned container.
+type RollbackResult struct {
+	// Container is the container name.
+	Container string
+	// ContainerID is the ID of the recreated container.
+	ContainerID types.ContainerID
+	// FromImage is the image the container ran before.
+	FromImage types.ImageID
+	// ToImage is the image the container runs now.
+	ToImage types.ImageID
+	// PinnedImage is the image updates skip, empty after an unpin.
+	PinnedImage types.ImageID
+}
+
+// Rollback recreates a container from the image it ran before its last update.
+//
+// The container is pinned against the image it is rolled back from: later
+// scans skip updating to it until the container is unpinned. The previous
+// image must still be on the host, so it should be kept with an image
+// retention policy when cleanup is enabled.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - name: Name of the container to roll back.
+//   - config: Update options; Filter limits which containers can be rolled back.
+//
+// Returns:
+//   - RollbackResult: The rolled back container.
+//   - types.Report: Report with the container as updated or failed, or nil if it was not touched.
+//   - error: Non-nil if the container cannot be found or rolled back.
+func Rollback(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	name string,
+	config types.UpdateParams,
+) (RollbackResult, types.Report, error) {
+	cont, err := findRollbackContainer(ctx, client, name, config.Filter)
+	if err != nil {
+		return RollbackResult{}, nil, err
+	}
+
+	previous := container.PreviousImage(cont)
+	if previous == "" {
+		return RollbackResult{}, nil, fmt.Errorf("%w: %s", ErrNoPreviousImage, cont.Name())
+	}
+
+	result := RollbackResult{
+		Container:   cont.Name(),
+		FromImage:   cont.ImageID(),
+		ToImage:     previous,
+		PinnedImage: cont.ImageID(),
+	}
+
+	log.Info().
+		Str("container", cont.Name()).
+		Str("image", cont.ImageName()).
+		Str("from_image_id", result.FromImage.ShortID()).
+		Str("to_image_id", result.ToImage.ShortID()).
+		Msg("Rolling back container")
+
+	// The rolled back container has nothing left to roll back to.
+	container.SetLabel(cont, container.RolledBackFromLabel, string(result.PinnedImage))
+	container.SetLabel(cont, container.PreviousImageLabel, "")
+
+	report, newID, err := recreateFromImage(log, ctx, client, cont, previous, true, config)
+	if err != nil {
+		return RollbackResult{}, report, fmt.Errorf("%w: %w", errRollbackFailed, err)
+	}
+
+	result.ContainerID = newID
+
+	log.Info().
+		Str("container", cont.Name()).
+		Str("pinned_image_id", result.PinnedImage.ShortID()).
+		Msg("Rolled back container")
+
+	return result, report, nil
+}
+
+// Unpin lets a rolled back container be updated to the image it was rolled back from again.
+//
+// Docker cannot change the labels of an existing container, so the container
+// is recreated from the image it runs, without the pin.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - name: Name of the container to unpin.
+//   - config: Update options; Filter limits which containers can be unpinned.
+//
+// Returns:
+//   - RollbackResult: The unpinned container.
+//   - types.Report: Report with the container as restarted or failed, or nil if it was not touched.
+//   - error: Non-nil if the container cannot be found, is not pinned, or cannot be recreated.
+func Unpin(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	name string,
+	config types.UpdateParams,
+) (RollbackResult, types.Report, error) {
+	cont, err := findRollbackContainer(ctx, client, name, config.Filter)
+	if err != nil {
+		return RollbackResult{}, nil, err
+	}
+
+	pinned := container.RolledBackFrom(cont)
+	if pinned == "" {
+		return RollbackResult{}, nil, fmt.Errorf("%w: %s", ErrNotPinned, cont.Name())
+	}
+
+	container.SetLabel(cont, container.RolledBackFromLabel, "")
+
+	report, newID, err := recreateFromImage(log, ctx, client, cont, cont.ImageID(), false, config)
+	if err != nil {
+		return RollbackResult{}, report, err
+	}
+
+	log.Info().
+		Str("container", cont.Name()).
+		Str("image_id", pinned.ShortID()).
+		Msg("Unpinned container")
+
+	return RollbackResult{
+		Container:   cont.Name(),
+		ContainerID: newID,
+		FromImage:   cont.ImageID(),
+		ToImage:     cont.ImageID(),
+	}, report, nil
+}
+
+// RollbackWithNotifications rolls back or unpins a container and sends notifications about the result.
+//
+// Like a scan, the outcome is published as container events and recorded in
+// the metrics and, in digest mode, in the digest instead of notified right away.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - params: Runtime dependencies and update policy, as for RunUpdatesWithNotifications.
+//   - name: Name of the container.
+//   - unpin: Unpin the container instead of rolling it back.
+//
+// Returns:
+//   - RollbackResult: The rolled back or unpinned container.
+//   - error: Non-nil if the container cannot be found, rolled back, or unpinned.
+func RollbackWithNotifications(
+	ctx context.Context,
+	params RunUpdatesWithNotificationsParams,
+	name string,
+	unpin bool,
+) (RollbackResult, error) {
+	log := params.Logger
+
+	startNotifications(log, params.Notifier, params.NotificationSplitByContainer)
+
+	action := Rollback
+	if unpin {
+		action = Unpin
+	}
+
+	result, report, err := action(log, ctx, params.Client, name, params.Update)
+	if report == nil {
+		// Flush the queued entries, as for a failed scan.
+		if params.Notifier != nil {
+			params.Notifier.SendNotification(emptyReport{})
+		}
+
+		return result, err
+	}
+
+	publishContainerEvents(ctx, params.EventBroadcaster, report)
+	metrics.Default().RecordContainers(report, false)
+	lifecycle.RecordLastRuns(report)
+
+	if params.Digest != nil && !params.Digest.Record(report, 0, nil) {
+		params.Digest.Discard()
+	} else {
+		sendNotifications(log,
+			params.Notifier,
+			params.NotificationSplitByContainer,
+			params.NotificationReport,
+			report,
+			nil,
+		)
+	}
+
+	return result, err
+}
+
+// findRollbackContainer returns the monitored container with the given name.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - name: Container name, with or without a leading slash.
+//   - filter: Filter the container must also match; nil matches every container.
+//
+// Returns:
+//   - types.Container: The container.
+//   - error: Non-nil if listing fails or no monitored container has the name.
+func findRollbackContainer(ctx context.Context,
+	client container.Client,
+	name string,
+	filter types.Filter,
+) (types.Container, error) {
+	name = strings.TrimPrefix(name, "/")
+
+	containers, err := client.ListContainers(ctx, func(c types.FilterableContainer) bool {
+		return c.Name() == name && (filter == nil || filter(c))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errListContainersFailed, err)
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRollbackContainerNotFound, name)
+	}
+
+	if containers[0].IsWatchtower() {
+		return nil, errRollbackWatchtower
+	}
+
+	return containers[0], nil
+}
+
+// recreateFromImage recreates a container from an image on the update path.
+//
+// The container is created from the image ID through CreateContainer like any
+// update, with its lifecycle hooks and volume backup. Its image name is kept in
+// a label rather than retagged, so other containers using the name are not
+// affected and the next scan pulls the image name again.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - cont: Container to recreate.
+//   - imageID: Image to recreate the container from.
+//   - changesImage: Whether imageID differs from the container's image; the container is reported as updated if so and as restarted otherwise.
+//   - config: Update options controlling stop timeouts, hooks, and restarts.
+//
+// Returns:
+//   - types.Report: Report with the container as updated, restarted, or failed.
+//   - types.ContainerID: ID of the new container, empty if it was not started.
+//   - error: Non-nil if the container cannot be recreated.
+func recreateFromImage(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	cont types.Container,
+	imageID types.ImageID,
+	changesImage bool,
+	config types.UpdateParams,
+) (types.Report, types.ContainerID, error) {
+	ctx, span := tracing.StartContainer(ctx, "watchtower.rollback", cont)
+	defer span.End()
+
+	// Identify the rollback to lifecycle webhooks and record hook runs for the report.
+	config.ScanID = newScanID()
+	hookRuns := lifecycle.NewRecorder(config)
+	ctx = lifecycle.WithRecorder(ctx, hookRuns)
+
+	ctx, backups := withVolumeBackups(ctx)
+
+	progress := &session.Progress{}
+	progress.AddScanned(log, cont, imageID, config)
+
+	defer attachHookRuns(*progress, hookRuns)
+	defer attachVolumeBackups(*progress, backups)
+
+	// fail marks the container as failed in the report.
+	fail := func(err error) (types.Report, types.ContainerID, error) {
+		tracing.RecordError(span, err)
+		progress.UpdateFailed(log, map[types.ContainerID]error{cont.ID(): err})
+
+		return progress.Report(log), "", err
+	}
+
+	setter, ok := cont.(createImageSetter)
+	if !ok {
+		return fail(errRecreateFromImageUnsupported)
+	}
+
+	available, err := client.HasImage(ctx, imageID)
+	if err != nil {
+		return fail(fmt.Errorf("%w: %w", errRecreateImageUnavailable, err))
+	}
+
+	if !available {
+		return fail(fmt.Errorf("%w: %s", errRecreateImageUnavailable, imageID.ShortID()))
+	}
+
+	setter.SetCreateImage(imageID)
+
+	if changesImage {
+		cont.SetStale(true)
+		progress.MarkForUpdate(log, cont.ID())
+	} else {
+		cont.SetLinkedToRestarting(true)
+	}
+
+	err = stopStaleContainer(log, ctx, cont, client, config)
+	if err != nil {
+		return fail(err)
+	}
+
+	newID, _, err := restartStaleContainer(log, ctx, cont, client, config)
+	if err != nil {
+		return fail(err)
+	}
+
+	if status, ok := (*progress)[cont.ID()]; ok {
+		status.SetNewContainerID(newID)
+	}
+
+	if !changesImage {
+		progress.MarkRestarted(log, cont.ID())
+	}
+
+	return progress.Report(log), newID, nil
+}
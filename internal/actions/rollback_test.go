@@ -0,0 +1,148 @@
+package actions_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerNetwork "github.com/moby/moby/api/types/network"
+
+	"github.com/nicholas-fedor/watchtower/internal/actions"
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+var errImageGone = errors.New("no such image")
+
+var _ = ginkgo.Describe("rollbacks", func() {
+	var client mockActions.MockClient
+
+	// labeledContainer returns the running test-container-web with the given labels.
+	labeledContainer := func(labels map[string]string) types.Container {
+		return mockActions.CreateMockContainerWithConfig(
+			"test-container-web",
+			"test-container-web",
+			"fake-web:latest",
+			true,
+			false,
+			time.Now(),
+			&dockerContainer.Config{
+				Image:        "fake-web:latest",
+				Labels:       labels,
+				ExposedPorts: dockerNetwork.PortSet{},
+			})
+	}
+
+	// labels returns the labels of the only test container.
+	labels := func() map[string]string {
+		return client.TestData.Containers[0].ContainerInfo().Config.Labels
+	}
+
+	ginkgo.BeforeEach(func() {
+		client = mockActions.CreateMockClient(&mockActions.TestData{
+			Containers: []types.Container{
+				labeledContainer(map[string]string{container.PreviousImageLabel: "sha256:previous"}),
+			},
+		}, false, false)
+	})
+
+	ginkgo.It("should record the previous image when updating a container", func() {
+		client.TestData.Containers[0] = labeledContainer(map[string]string{
+			container.RolledBackFromLabel: "sha256:bad",
+		})
+
+		_, _, err := actions.Update(testLogger(), context.Background(), client, types.UpdateParams{CPUCopyMode: "auto"})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(labels()).To(gomega.HaveKeyWithValue(container.PreviousImageLabel, "fake-web:latest"))
+		gomega.Expect(labels()).NotTo(gomega.HaveKey(container.RolledBackFromLabel))
+	})
+
+	ginkgo.It("should recreate the container from the previous image and pin it", func() {
+		result, report, err := actions.Rollback(testLogger(),
+			context.Background(), client, "/test-container-web", types.UpdateParams{CPUCopyMode: "auto"})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		gomega.Expect(result.FromImage).To(gomega.Equal(types.ImageID("fake-web:latest")))
+		gomega.Expect(result.ToImage).To(gomega.Equal(types.ImageID("sha256:previous")))
+		gomega.Expect(result.PinnedImage).To(gomega.Equal(types.ImageID("fake-web:latest")))
+		gomega.Expect(result.ContainerID).To(gomega.Equal(client.TestData.LastCreatedContainerID))
+
+		gomega.Expect(client.TestData.OperationOrder[:2]).To(gomega.Equal([]string{"HasImage", "StopAndRemoveContainer"}))
+		gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(1)))
+		gomega.Expect(client.TestData.CreatedImages).To(gomega.Equal([]string{"sha256:previous"}))
+		gomega.Expect(client.TestData.Containers[0].ImageName()).To(gomega.Equal("fake-web:latest"))
+
+		gomega.Expect(labels()).To(gomega.HaveKeyWithValue(container.RolledBackFromLabel, "fake-web:latest"))
+		gomega.Expect(labels()).NotTo(gomega.HaveKey(container.PreviousImageLabel))
+		gomega.Expect(report.Updated()).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should reject containers without a previous image", func() {
+		client.TestData.Containers[0] = labeledContainer(map[string]string{})
+
+		_, report, err := actions.Rollback(testLogger(),
+			context.Background(), client, "test-container-web", types.UpdateParams{})
+		gomega.Expect(err).To(gomega.MatchError(actions.ErrNoPreviousImage))
+		gomega.Expect(report).To(gomega.BeNil())
+		gomega.Expect(client.TestData.OperationOrder).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("should reject containers that are not monitored", func() {
+		_, _, err := actions.Rollback(testLogger(),
+			context.Background(), client, "test-container-web", types.UpdateParams{
+				Filter: func(types.FilterableContainer) bool { return false },
+			})
+		gomega.Expect(err).To(gomega.MatchError(actions.ErrRollbackContainerNotFound))
+	})
+
+	ginkgo.It("should keep the container running when the previous image is gone", func() {
+		client.TestData.MissingImages = []types.ImageID{"sha256:previous"}
+
+		_, report, err := actions.Rollback(testLogger(),
+			context.Background(), client, "test-container-web", types.UpdateParams{})
+		gomega.Expect(err).To(gomega.HaveOccurred())
+		gomega.Expect(client.TestData.StopContainerCount.Load()).To(gomega.BeZero())
+		gomega.Expect(report.Failed()).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("should keep the container running when the image cannot be inspected", func() {
+		client.TestData.HasImageError = errImageGone
+
+		_, report, err := actions.Rollback(testLogger(),
+			context.Background(), client, "test-container-web", types.UpdateParams{})
+		gomega.Expect(err).To(gomega.MatchError(errImageGone))
+		gomega.Expect(client.TestData.StopContainerCount.Load()).To(gomega.BeZero())
+		gomega.Expect(report.Failed()).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.Describe("unpinning", func() {
+		ginkgo.It("should recreate a pinned container from its image without the pin", func() {
+			client.TestData.Containers[0] = labeledContainer(map[string]string{
+				container.RolledBackFromLabel: "sha256:bad",
+			})
+
+			result, report, err := actions.Unpin(testLogger(),
+				context.Background(), client, "test-container-web", types.UpdateParams{CPUCopyMode: "auto"})
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			gomega.Expect(result.ToImage).To(gomega.Equal(types.ImageID("fake-web:latest")))
+			gomega.Expect(result.PinnedImage).To(gomega.BeEmpty())
+			gomega.Expect(client.TestData.CreatedImages).To(gomega.Equal([]string{"fake-web:latest"}))
+			gomega.Expect(labels()).NotTo(gomega.HaveKey(container.RolledBackFromLabel))
+			gomega.Expect(report.Restarted()).To(gomega.HaveLen(1))
+		})
+
+		ginkgo.It("should reject containers that are not pinned", func() {
+			_, report, err := actions.Unpin(testLogger(),
+				context.Background(), client, "test-container-web", types.UpdateParams{})
+			gomega.Expect(err).To(gomega.MatchError(actions.ErrNotPinned))
+			gomega.Expect(report).To(gomega.BeNil())
+		})
+	})
+})
@@ -0,0 +1,161 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// containerStateKey is the span attribute holding the container's final scan state.
+const containerStateKey = attribute.Key("watchtower.container.state")
+
+// containerSpansKey is the context key for the per-container spans of a scan.
+type containerSpansKey struct{}
+
+// traceContextSetter is implemented by notifiers that parent their delivery
+// spans on the scan span.
+type traceContextSetter interface {
+	SetTraceContext(ctx context.Context)
+}
+
+// containerSpans holds one span per container processed during a scan so the
+// stop, create, start, and health wait phases nest under the container that
+// triggered them.
+type containerSpans struct {
+	mu    sync.Mutex
+	spans map[types.ContainerID]trace.Span
+}
+
+// withContainerSpans attaches an empty per-container span set to the context.
+//
+// Parameters:
+//   - ctx: The scan context.
+//
+// Returns:
+//   - context.Context: Context carrying the span set.
+//   - *containerSpans: The span set; call end once the scan report is known.
+func withContainerSpans(ctx context.Context) (context.Context, *containerSpans) {
+	spans := &containerSpans{spans: make(map[types.ContainerID]trace.Span)}
+
+	return context.WithValue(ctx, containerSpansKey{}, spans), spans
+}
+
+// start opens the span for a container as a child of the scan span.
+//
+// Parameters:
+//   - ctx: The scan context.
+//   - c: The container being processed.
+func (s *containerSpans) start(ctx context.Context, c types.Container) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.spans[c.ID()]; ok {
+		return
+	}
+
+	_, span := tracing.StartContainer(ctx, "watchtower.container", c)
+	s.spans[c.ID()] = span
+}
+
+// finish records a container's final state and ends its span.
+//
+// Parameters:
+//   - id: Source container ID.
+//   - progress: The scan progress keyed by source container ID.
+//   - err: Error of the container's update, applied when the progress does not hold it yet.
+func (s *containerSpans) finish(id types.ContainerID, progress session.Progress, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	span, ok := s.spans[id]
+	if !ok {
+		return
+	}
+
+	endContainerSpan(span, progress[id], err)
+	delete(s.spans, id)
+}
+
+// end records each remaining container's final state from the scan progress and ends its span.
+//
+// Parameters:
+//   - progress: The scan progress keyed by source container ID.
+func (s *containerSpans) end(progress session.Progress) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, span := range s.spans {
+		endContainerSpan(span, progress[id], nil)
+		delete(s.spans, id)
+	}
+}
+
+// endContainerSpan sets the state attribute of a container span and ends it.
+//
+// Parameters:
+//   - span: The container span.
+//   - status: The container's scan status; nil when the container has none.
+//   - err: Error of the container's update; overrides the status when non-nil.
+func endContainerSpan(span trace.Span, status *session.ContainerStatus, err error) {
+	switch {
+	case err != nil:
+		span.SetAttributes(containerStateKey.String(session.FailedStateString))
+		tracing.RecordError(span, err)
+	case status != nil:
+		span.SetAttributes(containerStateKey.String(status.State()))
+
+		if status.Error() != "" {
+			tracing.RecordError(span, errors.New(status.Error())) //nolint:err113 // Status errors are strings.
+		}
+	}
+
+	span.End()
+}
+
+// finishContainer ends the span of a container whose processing in the scan is complete.
+//
+// Parameters:
+//   - ctx: A context derived from withContainerSpans.
+//   - c: The container.
+//   - progress: The scan progress keyed by source container ID.
+//   - err: Error of the container's update, applied when the progress does not hold it yet.
+func finishContainer(ctx context.Context, c types.Container, progress session.Progress, err error) {
+	spans, ok := ctx.Value(containerSpansKey{}).(*containerSpans)
+	if !ok {
+		return
+	}
+
+	spans.finish(c.ID(), progress, err)
+}
+
+// containerContext returns ctx with the container's span as the active span.
+//
+// Parameters:
+//   - ctx: A context derived from withContainerSpans.
+//   - c: The container.
+//
+// Returns:
+//   - context.Context: ctx with the container span, or ctx unchanged when none was started.
+func containerContext(ctx context.Context, c types.Container) context.Context {
+	spans, ok := ctx.Value(containerSpansKey{}).(*containerSpans)
+	if !ok {
+		return ctx
+	}
+
+	spans.mu.Lock()
+	span, ok := spans.spans[c.ID()]
+	spans.mu.Unlock()
+
+	if !ok {
+		return ctx
+	}
+
+	return trace.ContextWithSpan(ctx, span)
+}
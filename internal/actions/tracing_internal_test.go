@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+)
+
+var _ = ginkgo.Describe("containerSpans", func() {
+	var exporter *tracetest.InMemoryExporter
+
+	ginkgo.BeforeEach(func() {
+		exporter = tracetest.NewInMemoryExporter()
+		previous := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+		ginkgo.DeferCleanup(func() { otel.SetTracerProvider(previous) })
+	})
+
+	ginkgo.It("ends each container span when that container finishes", func() {
+		web := mockActions.CreateMockContainer("web-id", "web", "web:latest", time.Now())
+		db := mockActions.CreateMockContainer("db-id", "db", "db:latest", time.Now())
+
+		ctx, spans := withContainerSpans(context.Background())
+		spans.start(ctx, web)
+		spans.start(ctx, db)
+
+		progress := session.Progress{}
+		finishContainer(ctx, web, progress, errors.New("start failed"))
+
+		gomega.Expect(exporter.GetSpans()).To(gomega.HaveLen(1))
+		gomega.Expect(exporter.GetSpans()[0].Attributes).To(gomega.ContainElement(
+			containerStateKey.String(session.FailedStateString),
+		))
+
+		// Finishing twice leaves the span ended once; the scan end covers the rest.
+		finishContainer(ctx, web, progress, nil)
+		spans.end(progress)
+
+		gomega.Expect(exporter.GetSpans()).To(gomega.HaveLen(2))
+	})
+
+	ginkgo.It("ignores contexts without container spans", func() {
+		web := mockActions.CreateMockContainer("web-id", "web", "web:latest", time.Now())
+
+		finishContainer(context.Background(), web, session.Progress{}, nil)
+
+		gomega.Expect(exporter.GetSpans()).To(gomega.BeEmpty())
+	})
+})
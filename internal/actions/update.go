@@ -2,8 +2,11 @@ package actions
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"sort"
 	"strings"
@@ -16,6 +19,7 @@ import (
 
 	cerrdefs "github.com/containerd/errdefs"
 
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/compose"
 	"github.com/nicholas-fedor/watchtower/pkg/container"
 	"github.com/nicholas-fedor/watchtower/pkg/filters"
@@ -26,6 +30,9 @@ import (
 )
 
 const (
+	// scanIDBytes is the number of random bytes in a scan ID.
+	scanIDBytes = 8
+
 	// defaultPullFailureDelay defines the default delay duration for failed Watchtower self-update pulls.
 	defaultPullFailureDelay = 5 * time.Minute
 
@@ -187,8 +194,13 @@ func Update(log *zerolog.Logger, ctx context.Context,
 	default:
 	}
 
+	// Identify this scan to lifecycle webhooks.
+	if config.ScanID == "" {
+		config.ScanID = newScanID()
+	}
+
 	// Initialize logging for the update process start.
-	log.Debug().Msg("Starting container update check")
+	log.Debug().Str("scan_id", config.ScanID).Msg("Starting container update check")
 
 	// Fetch all containers for monitoring
 	allContainers, err := client.ListContainers(
@@ -287,6 +299,28 @@ func Update(log *zerolog.Logger, ctx context.Context,
 
 	// Create a progress tracker for reporting scanned, updated, and skipped containers.
 	progress := &session.Progress{}
+
+	// Open one span per container so registry, pull, and restart work nests
+	// under the container that triggered it.
+	ctx, spans := withContainerSpans(ctx)
+	defer func() { spans.end(*progress) }()
+
+	// Record lifecycle hook runs and attach them to the report when the scan ends.
+	hookRuns := lifecycle.NewRecorder(config)
+	ctx = lifecycle.WithRecorder(ctx, hookRuns)
+
+	defer attachHookRuns(*progress, hookRuns)
+
+	// Record volume backups and attach their paths to the report when the scan ends.
+	ctx, backups := withVolumeBackups(ctx)
+	defer attachVolumeBackups(*progress, backups)
+
+	// Halt the remaining updates once too many failed; callers may supply the breaker.
+	breaker := circuitBreakerFrom(ctx)
+	if breaker == nil {
+		ctx, breaker = withCircuitBreaker(ctx, config)
+	}
+
 	// Track the number of stale containers for logging.
 	var staleCount int
 	// Track if Watchtower self-update pull failed to add safeguard delay.
@@ -300,9 +334,17 @@ func Update(log *zerolog.Logger, ctx context.Context,
 	for _, c := range allContainers {
 		if config.Filter == nil || config.Filter(c) {
 			filteredContainers = append(filteredContainers, c)
+			spans.start(ctx, c)
 		}
 	}
 
+	// Run the global pre-scan hook; a failure aborts the scan.
+	err = lifecycle.ExecuteScanHook(log, ctx, config,
+		scanHookPayload(lifecycle.PreScanPhase, config.ScanID, filteredContainers, nil))
+	if err != nil {
+		return progress.Report(log), cleanupImageInfos, err
+	}
+
 	// Run pre-check lifecycle hooks if enabled to validate the environment before updates.
 	if config.LifecycleHooks {
 		log.Debug().Msg("Executing pre-check lifecycle hooks")
@@ -472,7 +514,7 @@ func Update(log *zerolog.Logger, ctx context.Context,
 				newestImage = sourceContainer.ImageID()
 			} else {
 				stale, newestImage, _, checkErr = client.IsContainerStale(
-					ctx,
+					containerContext(ctx, sourceContainer),
 					sourceContainer,
 					config,
 				)
@@ -480,6 +522,11 @@ func Update(log *zerolog.Logger, ctx context.Context,
 				if checkErr != nil && (errors.Is(checkErr, context.Canceled) || errors.Is(checkErr, context.DeadlineExceeded)) {
 					return fmt.Errorf("staleness check canceled: %w", checkErr)
 				}
+
+				// Hold back an image that kept failing to update the container.
+				if checkErr == nil && stale {
+					checkErr = checkQuarantine(ctx, sourceContainer, newestImage)
+				}
 			}
 
 			// Determine if the container should be updated based on staleness and config.
@@ -516,7 +563,7 @@ func Update(log *zerolog.Logger, ctx context.Context,
 			switch {
 			case checkErr != nil:
 				// Skip containers with staleness check errors, marking them as skipped.
-				if !errors.Is(checkErr, container.ErrImageCooldown) {
+				if !errors.Is(checkErr, container.ErrImageCooldown) && !errors.Is(checkErr, ErrQuarantinedDigest) {
 					parallelStaleCheckFailed++
 				}
 
@@ -547,7 +594,8 @@ func Update(log *zerolog.Logger, ctx context.Context,
 				// failure, not a cooldown deferral.
 				if sourceContainer.IsWatchtower() &&
 					!config.SkipSelfUpdate &&
-					!errors.Is(checkErr, container.ErrImageCooldown) {
+					!errors.Is(checkErr, container.ErrImageCooldown) &&
+					!errors.Is(checkErr, ErrQuarantinedDigest) {
 					parallelWatchtowerPullFailed = true
 				}
 			case verifyErr != nil:
@@ -592,6 +640,12 @@ func Update(log *zerolog.Logger, ctx context.Context,
 			// Only mark as stale if the container should actually be updated.
 			filteredContainers[task.index].SetStale(stale && shouldUpdate && checkErr == nil && verifyErr == nil)
 
+			// Record the image being replaced on the new container so the update can be rolled back.
+			if filteredContainers[task.index].IsStale() {
+				container.SetLabel(sourceContainer, container.PreviousImageLabel, string(sourceContainer.ImageID()))
+				container.SetLabel(sourceContainer, container.RolledBackFromLabel, "")
+			}
+
 			// Increment stale count for logging summary.
 			if stale {
 				parallelStaleCount++
@@ -647,45 +701,48 @@ func Update(log *zerolog.Logger, ctx context.Context,
 		filteredContainers,
 		config.UseComposeDependsOn,
 	)
-	if err != nil {
-		if errors.Is(err, sorter.ErrCircularReference) {
-			circularErr, ok := errors.AsType[sorter.CircularReferenceError](err)
-			if ok {
-				circularName := circularErr.ContainerName
-				// Find the container and mark as skipped.
-				for _, c := range filteredContainers {
-					if c.Name() == circularName {
-						// Only add if not already skipped (e.g., from initial cycle detection)
-						_, exists := (*progress)[c.ID()]
-						if !exists {
-							progress.AddSkipped(log,
-								c,
-								errCircularDependency,
-								config,
-							)
-							log.Warn().
-								Str("container", c.Name()).
-								Str("id", c.ID().ShortID()).
-								Msg("Skipping container update (circular dependency)")
-						}
+	if err != nil && !errors.Is(err, sorter.ErrCircularReference) {
+		// Log and return an error if dependency sorting fails for other reasons.
+		log.Debug().
+			Err(err).
+			Msg("Failed to sort containers by dependencies")
 
-						break
+		return nil, []types.RemovedImageInfo{}, fmt.Errorf(
+			"%w: %w",
+			errSortDependenciesFailed,
+			err,
+		)
+	}
+
+	// Defer updates beyond the per-run limit before linked restarts are derived.
+	deferExcessUpdates(log, filteredContainers, containerByID, *progress, config)
+
+	if err != nil {
+		circularErr, ok := errors.AsType[sorter.CircularReferenceError](err)
+		if ok {
+			circularName := circularErr.ContainerName
+			// Find the container and mark as skipped.
+			for _, c := range filteredContainers {
+				if c.Name() == circularName {
+					// Only add if not already skipped (e.g., from initial cycle detection)
+					_, exists := (*progress)[c.ID()]
+					if !exists {
+						progress.AddSkipped(log,
+							c,
+							errCircularDependency,
+							config,
+						)
+						log.Warn().
+							Str("container", c.Name()).
+							Str("id", c.ID().ShortID()).
+							Msg("Skipping container update (circular dependency)")
 					}
+
+					break
 				}
 			}
-			// Skip UpdateImplicitRestart to avoid potential issues with circular dependencies.
-		} else {
-			// Log and return an error if dependency sorting fails for other reasons.
-			log.Debug().
-				Err(err).
-				Msg("Failed to sort containers by dependencies")
-
-			return nil, []types.RemovedImageInfo{}, fmt.Errorf(
-				"%w: %w",
-				errSortDependenciesFailed,
-				err,
-			)
 		}
+		// Skip UpdateImplicitRestart to avoid potential issues with circular dependencies.
 	} else {
 		// Mark containers linked to restarting ones for restart without updating.
 		UpdateImplicitRestart(log,
@@ -726,13 +783,60 @@ func Update(log *zerolog.Logger, ctx context.Context,
 		Int("restart_count", len(allContainersToRestart)).
 		Msg("Prepared containers for restart")
 
-	// Perform updates and restarts, either with rolling restarts or in batches.
-	var (
-		failedStop    map[types.ContainerID]error
-		stoppedImages []types.RemovedImageInfo
-		failedStart   map[types.ContainerID]error
-	)
+	// Size the failure percentage of the circuit breaker by the planned updates.
+	plannedUpdates := 0
+
+	for _, c := range allContainersToRestart {
+		if c.IsStale() {
+			plannedUpdates++
+		}
+	}
+
+	breaker.plan(plannedUpdates)
+
+	// Run the global pre-restart-batch hook before the first stop. A failure
+	// aborts the restarts, marks the planned updates as failed, and reports
+	// the linked containers that were only to be restarted as skipped.
+	restartBatch := len(allContainersToRestart) > 0
+	if restartBatch {
+		hookErr := lifecycle.ExecuteScanHook(log, ctx, config,
+			scanHookPayload(lifecycle.PreRestartBatchPhase, config.ScanID, allContainersToRestart, nil))
+		if hookErr != nil {
+			log.Error().
+				Err(hookErr).
+				Int("restart_count", len(allContainersToRestart)).
+				Msg("Pre-restart-batch hook failed, skipping container restarts")
+
+			aborted := make(map[types.ContainerID]error, len(allContainersToRestart))
+
+			for _, c := range allContainersToRestart {
+				if c.IsStale() {
+					aborted[c.ID()] = hookErr
+				} else {
+					progress.AddSkipped(log, c, hookErr, config)
+				}
+			}
+
+			progress.UpdateFailed(log, aborted)
+
+			allContainersToRestart = nil
+			restartBatch = false
+		}
+	}
+
+	// End the spans of containers that are not restarted; their processing is complete.
+	restarting := make(map[types.ContainerID]bool, len(allContainersToRestart))
+	for _, c := range allContainersToRestart {
+		restarting[c.ID()] = true
+	}
 
+	for _, c := range filteredContainers {
+		if !restarting[c.ID()] {
+			finishContainer(ctx, c, *progress, nil)
+		}
+	}
+
+	// Perform updates and restarts, either with rolling restarts or in batches.
 	if config.RollingRestart {
 		// Apply rolling restarts for all containers in dependency order.
 		rollingFailed, rollingErr := performRollingRestart(log,
@@ -756,25 +860,78 @@ func Update(log *zerolog.Logger, ctx context.Context,
 			}
 		}
 
-		// Stop and restart containers in batches, respecting dependency order.
-		failedStop, stoppedImages = stopContainersInReversedOrder(log,
-			ctx,
-			allContainersToRestart,
-			client,
-			config,
-		)
-		progress.UpdateFailed(log, failedStop)
+		// Stop and restart containers in batches. With a circuit breaker or an
+		// update limit, linked containers are stopped and started one group at a
+		// time, so failed starts can halt the groups that were not stopped yet.
+		groups := [][]types.Container{allContainersToRestart}
+		if breaker.enabled() || config.MaxUpdates > 0 {
+			groups = sorter.GroupByDependencies(log, allContainersToRestart, config.UseComposeDependsOn)
+		}
 
-		failedStart = restartContainersInSortedOrder(log,
-			ctx,
-			allContainersToRestart,
-			client,
-			config,
-			stoppedImages,
-			&cleanupImageInfos,
-			progress,
+		var (
+			restarted []restartedContainer
+			started   []types.ContainerID
 		)
-		progress.UpdateFailed(log, failedStart)
+
+		for _, group := range groups {
+			failedStop, stoppedImages := stopContainersInReversedOrder(log,
+				ctx,
+				group,
+				client,
+				config,
+			)
+			progress.UpdateFailed(log, failedStop)
+
+			failedStart, groupRestarted, updating := startContainersInSortedOrder(log,
+				ctx,
+				group,
+				client,
+				config,
+				stoppedImages,
+				progress,
+			)
+			progress.UpdateFailed(log, failedStart)
+
+			// Count failed starts right away; started updates count once observed.
+			for _, id := range updating {
+				if err, ok := failedStart[id]; ok {
+					breaker.record(err)
+				} else {
+					started = append(started, id)
+				}
+			}
+
+			restarted = append(restarted, groupRestarted...)
+		}
+
+		// Observe all updated containers once, after the last start.
+		failedObserve := completeRestarts(log, ctx, client, restarted, config, &cleanupImageInfos, progress)
+		progress.UpdateFailed(log, failedObserve)
+
+		for _, id := range started {
+			breaker.record(failedObserve[id])
+		}
+
+		// End the spans of restarted containers; halted ones end with the scan once reported.
+		for _, c := range allContainersToRestart {
+			if !breaker.isHalted(c.ID()) {
+				finishContainer(ctx, c, *progress, nil)
+			}
+		}
+	}
+
+	// Report containers left on their current images by the circuit breaker.
+	breaker.reportHalted(log, *progress, config)
+
+	// Run the global post-restart-batch hook after the last start.
+	if restartBatch {
+		hookErr := lifecycle.ExecuteScanHook(log, ctx, config,
+			scanHookPayload(lifecycle.PostRestartBatchPhase, config.ScanID, allContainersToRestart, *progress))
+		if hookErr != nil {
+			log.Warn().
+				Err(hookErr).
+				Msg("Post-restart-batch hook failed")
+		}
 	}
 
 	// Run post-check lifecycle hooks if enabled to finalize the update process.
@@ -783,6 +940,15 @@ func Update(log *zerolog.Logger, ctx context.Context,
 		lifecycle.ExecutePostChecks(log, ctx, client, config, filteredContainers)
 	}
 
+	// Run the global post-scan hook.
+	err = lifecycle.ExecuteScanHook(log, ctx, config,
+		scanHookPayload(lifecycle.PostScanPhase, config.ScanID, filteredContainers, *progress))
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Msg("Post-scan hook failed")
+	}
+
 	// Add safeguard delay if Watchtower self-update pull failed
 	// to prevent rapid restarts.
 	if watchtowerPullFailed {
@@ -1454,6 +1620,13 @@ func performRollingRestart(log *zerolog.Logger, ctx context.Context,
 	progress *session.Progress,
 ) (map[types.ContainerID]error, error) {
 	failed := make(map[types.ContainerID]error, len(containers))
+	breaker := circuitBreakerFrom(ctx)
+
+	// Progress read when ending container spans; nil when the caller tracks none.
+	var current session.Progress
+	if progress != nil {
+		current = *progress
+	}
 
 	containerNames := make([]string, len(containers))
 	for i, c := range containers {
@@ -1498,6 +1671,11 @@ func performRollingRestart(log *zerolog.Logger, ctx context.Context,
 			continue
 		}
 
+		// Leave the remaining containers untouched once the circuit breaker tripped.
+		if breaker.halt(log, c) {
+			continue
+		}
+
 		fields := map[string]any{
 			"container": c.Name(),
 			"image":     c.ImageName(),
@@ -1540,7 +1718,7 @@ func performRollingRestart(log *zerolog.Logger, ctx context.Context,
 
 				// Wait for the container to become healthy if it has a health check
 				waitErr := client.WaitForContainerHealthy(
-					ctx,
+					containerContext(ctx, c),
 					newContainerID,
 					defaultHealthCheckTimeout,
 				)
@@ -1553,7 +1731,11 @@ func performRollingRestart(log *zerolog.Logger, ctx context.Context,
 					// Don't fail the update, just log the warning
 				}
 
-				if c.IsStale() && !renamed {
+				// Watch the updated container for crashes and restart loops.
+				crash := observeUpdatedContainer(log, ctx, client, c, newContainerID, config)
+				if crash != nil {
+					failed[c.ID()] = handleCrash(log, ctx, client, c, newContainerID, crash, config, progress)
+				} else if c.IsStale() && !renamed {
 					// Only collect cleaned image info for stale containers that were not renamed, as renamed
 					// containers (Watchtower self-updates) are cleaned up by CheckForMultipleWatchtowerInstances
 					// in the new container.
@@ -1571,6 +1753,14 @@ func performRollingRestart(log *zerolog.Logger, ctx context.Context,
 				}
 			}
 		}
+
+		// Count the update towards the circuit breaker.
+		if c.IsStale() {
+			breaker.record(failed[c.ID()])
+		}
+
+		// End the container's span now that its update is complete.
+		finishContainer(ctx, c, current, failed[c.ID()])
 	}
 
 	return failed, nil
@@ -1597,6 +1787,7 @@ func stopContainersInReversedOrder(log *zerolog.Logger, ctx context.Context,
 ) (map[types.ContainerID]error, []types.RemovedImageInfo) {
 	failed := make(map[types.ContainerID]error, len(containers))
 	stopped := make([]types.RemovedImageInfo, 0, len(containers))
+	breaker := circuitBreakerFrom(ctx)
 
 	// Stop containers in reverse order to avoid breaking dependencies.
 	for i, v := range slices.Backward(containers) {
@@ -1632,7 +1823,18 @@ func stopContainersInReversedOrder(log *zerolog.Logger, ctx context.Context,
 			"image":     c.ImageName(),
 		}
 
+		// Leave the remaining containers running once the circuit breaker tripped.
+		if c.ToRestart() && breaker.halt(log, c) {
+			continue
+		}
+
 		err := stopStaleContainer(log, ctx, c, client, config)
+
+		// Count a failed stop towards the circuit breaker; stopped containers count once started.
+		if err != nil && c.IsStale() && !c.IsWatchtower() {
+			breaker.record(err)
+		}
+
 		if err != nil {
 			failed[c.ID()] = err
 		} else {
@@ -1673,6 +1875,8 @@ func stopStaleContainer(log *zerolog.Logger, ctx context.Context,
 	client container.Client,
 	config types.UpdateParams,
 ) error {
+	ctx = containerContext(ctx, container)
+
 	fields := map[string]any{
 		"container": container.Name(),
 		"image":     container.ImageName(),
@@ -1715,8 +1919,7 @@ func stopStaleContainer(log *zerolog.Logger, ctx context.Context,
 			ctx,
 			client,
 			container,
-			config.LifecycleUID,
-			config.LifecycleGID,
+			config,
 		)
 		if err != nil {
 			log.Debug().
@@ -1730,14 +1933,20 @@ func stopStaleContainer(log *zerolog.Logger, ctx context.Context,
 		if skipUpdate {
 			log.Debug().
 				Fields(fields).
-				Msg("Skipping container due to pre-update exit code 75")
+				Msg("Skipping container due to pre-update exit code 75 or webhook skip status")
 
 			return errSkipUpdate
 		}
 	}
 
+	// Archive the volumes of containers labeled for backup before they are removed.
+	err := backupContainerVolumes(log, ctx, container, client, config)
+	if err != nil {
+		return err
+	}
+
 	// Stop the container with the configured timeout.
-	err := client.StopAndRemoveContainer(
+	err = client.StopAndRemoveContainer(
 		ctx,
 		container,
 		config.Timeout,
@@ -1767,9 +1976,9 @@ func stopStaleContainer(log *zerolog.Logger, ctx context.Context,
 
 // restartContainersInSortedOrder restarts stopped containers.
 //
-// It restarts containers in dependency order, collecting cleaned image info
-// for stale containers that were not renamed during a self-update, and
-// tracking any restart failures.
+// It restarts containers in dependency order, observes the updated ones for
+// crashes, collects cleaned image info for stale containers that were not
+// renamed during a self-update, and tracks any restart failures.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeouts.
@@ -1790,15 +1999,63 @@ func restartContainersInSortedOrder(log *zerolog.Logger, ctx context.Context,
 	cleanupImageInfos *[]types.RemovedImageInfo,
 	progress *session.Progress,
 ) map[types.ContainerID]error {
+	failed, restarted, updating := startContainersInSortedOrder(log,
+		ctx,
+		containers,
+		client,
+		config,
+		stoppedImages,
+		progress,
+	)
+
+	maps.Copy(failed, completeRestarts(log, ctx, client, restarted, config, cleanupImageInfos, progress))
+
+	// Count the updates of the stopped containers towards the circuit breaker.
+	breaker := circuitBreakerFrom(ctx)
+	for _, id := range updating {
+		breaker.record(failed[id])
+	}
+
+	return failed
+}
+
+// startContainersInSortedOrder recreates and starts stopped containers in dependency order.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - containers: List of containers to restart.
+//   - client: Container client for Docker operations.
+//   - config: Update options controlling restart behavior.
+//   - stoppedImages: Slice of cleaned image info for previously stopped containers.
+//   - progress: Progress tracker to update with new container IDs.
+//
+// Returns:
+//   - map[types.ContainerID]error: Map of container IDs to errors for failed restarts.
+//   - []restartedContainer: Updated containers to observe before their old images are cleaned up.
+//   - []types.ContainerID: Stopped stale containers whose outcome counts towards the circuit breaker.
+func startContainersInSortedOrder(log *zerolog.Logger, ctx context.Context,
+	containers []types.Container,
+	client container.Client,
+	config types.UpdateParams,
+	stoppedImages []types.RemovedImageInfo,
+	progress *session.Progress,
+) (map[types.ContainerID]error, []restartedContainer, []types.ContainerID) {
 	failed := make(map[types.ContainerID]error, len(containers))
 	// Track renamed containers to skip cleanup.
 	renamedContainers := make(map[types.ContainerID]bool)
+	// Track updated containers to observe before their old images are cleaned up.
+	var restarted []restartedContainer
+	// Track stopped stale containers whose outcome counts towards the circuit breaker.
+	var updating []types.ContainerID
+
+	breaker := circuitBreakerFrom(ctx)
 
 	// Restart containers in sorted order to respect dependency chains.
 	for i := range containers {
 		c := containers[i]
 
-		if !c.ToRestart() {
+		// Skip containers the circuit breaker left untouched.
+		if !c.ToRestart() || breaker.isHalted(c.ID()) {
 			continue
 		}
 
@@ -1818,6 +2075,10 @@ func restartContainersInSortedOrder(log *zerolog.Logger, ctx context.Context,
 			}
 		}
 
+		if wasStopped && c.IsStale() && !c.IsWatchtower() {
+			updating = append(updating, c.ID())
+		}
+
 		// Skip other Watchtower containers from self-updates
 		if c.IsWatchtower() && config.CurrentContainerID != "" &&
 			c.ID() != config.CurrentContainerID {
@@ -1882,17 +2143,56 @@ func restartContainersInSortedOrder(log *zerolog.Logger, ctx context.Context,
 				// are cleaned up by CheckForMultipleWatchtowerInstances
 				// in the new container.
 				if c.IsStale() && !renamedContainers[c.ID()] {
-					addCleanupImageInfo(
-						cleanupImageInfos,
-						c.ImageID(),
-						c.ImageName(),
-						c.Name(),
-						c.ID())
+					restarted = append(restarted, restartedContainer{cont: c, id: newContainerID})
 				}
 			}
 		}
 	}
 
+	return failed, restarted, updating
+}
+
+// completeRestarts observes updated containers for crashes and queues the old images of healthy ones for cleanup.
+//
+// Crashed containers keep their old image for a revert or rollback.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeouts.
+//   - client: Container client for Docker operations.
+//   - restarted: Updated containers with their new IDs.
+//   - config: Update options controlling observation and crash handling.
+//   - cleanupImageInfos: Pointer to slice to collect cleaned image info for deferred cleanup.
+//   - progress: Progress tracker for crash outcomes.
+//
+// Returns:
+//   - map[types.ContainerID]error: Map of container IDs to errors for crashed containers.
+func completeRestarts(log *zerolog.Logger, ctx context.Context,
+	client container.Client,
+	restarted []restartedContainer,
+	config types.UpdateParams,
+	cleanupImageInfos *[]types.RemovedImageInfo,
+	progress *session.Progress,
+) map[types.ContainerID]error {
+	failed := make(map[types.ContainerID]error)
+
+	// Watch the updated containers for crashes and restart loops in one window.
+	crashes := observeUpdatedContainers(log, ctx, client, restarted, config)
+
+	for _, r := range restarted {
+		if crash, ok := crashes[r.cont.ID()]; ok {
+			failed[r.cont.ID()] = handleCrash(log, ctx, client, r.cont, r.id, crash, config, progress)
+
+			continue
+		}
+
+		addCleanupImageInfo(
+			cleanupImageInfos,
+			r.cont.ImageID(),
+			r.cont.ImageName(),
+			r.cont.Name(),
+			r.cont.ID())
+	}
+
 	return failed
 }
 
@@ -1948,6 +2248,8 @@ func restartStaleContainer(log *zerolog.Logger, ctx context.Context,
 	client container.Client,
 	config types.UpdateParams,
 ) (types.ContainerID, bool, error) {
+	ctx = containerContext(ctx, sourceContainer)
+
 	// Create a detached context to survive parent context cancellation.
 	// This ensures container cleanup and update operations complete even if the
 	// parent context is canceled during the restart process.
@@ -1956,8 +2258,10 @@ func restartStaleContainer(log *zerolog.Logger, ctx context.Context,
 	// slow hosts.
 	detachedTimeout := max(restartPolicyTimeout(config.Timeout), defaultCreateStartTimeout)
 
+	// The detached context keeps the container span so create/start remain traced,
+	// and the hook recorder so post-update runs reach the report.
 	detachedCtx, cancelDetached := context.WithTimeout(
-		context.Background(),
+		lifecycle.WithRecorder(tracing.Detach(ctx), lifecycle.RecorderFrom(ctx)),
 		detachedTimeout,
 	)
 
@@ -2210,9 +2514,9 @@ func restartStaleContainer(log *zerolog.Logger, ctx context.Context,
 			lifecycle.ExecutePostUpdateCommand(log,
 				detachedCtx,
 				client,
+				sourceContainer,
 				newContainerID,
-				config.LifecycleUID,
-				config.LifecycleGID,
+				config,
 			)
 		}
 	}
@@ -2266,3 +2570,58 @@ func restartPolicyTimeout(timeout time.Duration) time.Duration {
 
 	return defaultRestartPolicyTimeout
 }
+
+// newScanID returns a random identifier for one update scan.
+//
+// Returns:
+//   - string: 16 hex characters.
+func newScanID() string {
+	id := make([]byte, scanIDBytes)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
+
+// attachHookRuns sets the lifecycle hook runs recorded during a scan on each container's status.
+//
+// Statuses are shared with reports already generated from progress, so this
+// also updates a report returned by Update.
+//
+// Parameters:
+//   - progress: Scan progress.
+//   - recorder: Recorder holding the scan's hook runs.
+func attachHookRuns(progress session.Progress, recorder *lifecycle.Recorder) {
+	for _, status := range progress {
+		status.SetHookRuns(recorder.Runs(status.Name()))
+	}
+}
+
+// scanHookPayload builds the payload for a global scan hook.
+//
+// Parameters:
+//   - phase: Scan hook phase.
+//   - scanID: Identifier of the scan.
+//   - containers: Containers the phase concerns.
+//   - progress: Scan progress whose errors are added, or nil before any container ran.
+//
+// Returns:
+//   - lifecycle.ScanHookPayload: Payload for lifecycle.ExecuteScanHook.
+func scanHookPayload(
+	phase, scanID string,
+	containers []types.Container,
+	progress session.Progress,
+) lifecycle.ScanHookPayload {
+	described := lifecycle.NewScanHookContainers(containers)
+
+	for i, c := range containers {
+		if status, ok := progress[c.ID()]; ok {
+			described[i].Error = status.Error()
+		}
+	}
+
+	return lifecycle.ScanHookPayload{
+		Phase:      phase,
+		ScanID:     scanID,
+		Containers: described,
+	}
+}
@@ -0,0 +1,104 @@
+package actions_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerNetwork "github.com/moby/moby/api/types/network"
+
+	"github.com/nicholas-fedor/watchtower/internal/actions"
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+var errBackupFailed = errors.New("no space left on device")
+
+var _ = ginkgo.Describe("the update action", func() {
+	ginkgo.When("a stale container is labeled for volume backups", func() {
+		var client mockActions.MockClient
+
+		ginkgo.BeforeEach(func() {
+			client = mockActions.CreateMockClient(
+				&mockActions.TestData{
+					Containers: []types.Container{
+						mockActions.CreateMockContainerWithConfig(
+							"test-container-db",
+							"test-container-db",
+							"fake-db:latest",
+							true,
+							false,
+							time.Now(),
+							&dockerContainer.Config{
+								Labels: map[string]string{
+									"com.centurylinklabs.watchtower.backup-volumes": "true",
+								},
+								ExposedPorts: dockerNetwork.PortSet{},
+							}),
+					},
+					BackupPath: "/srv/backups/test-container-db/20240101T120000Z",
+				},
+				false,
+				false,
+			)
+			client.TestData.Staleness = map[string]bool{
+				"test-container-db": true,
+			}
+		})
+
+		ginkgo.It("should back up the volumes and report the backup path", func() {
+			report, _, err := actions.Update(testLogger(),
+				context.Background(),
+				client,
+				types.UpdateParams{
+					VolumeBackupDir: "/srv/backups",
+					CPUCopyMode:     "auto",
+				},
+			)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(client.TestData.BackupVolumesCount.Load()).To(gomega.Equal(int32(1)))
+			gomega.Expect(client.TestData.OperationOrder[:3]).To(gomega.Equal([]string{
+				"StopContainer", "BackupVolumes", "StopAndRemoveContainer",
+			}))
+			gomega.Expect(report.Updated()).To(gomega.HaveLen(1))
+			gomega.Expect(report.Updated()[0].BackupPath()).
+				To(gomega.Equal("/srv/backups/test-container-db/20240101T120000Z"))
+		})
+
+		ginkgo.It("should keep the current container when the backup fails", func() {
+			client.TestData.BackupVolumesError = errBackupFailed
+
+			report, _, err := actions.Update(testLogger(),
+				context.Background(),
+				client,
+				types.UpdateParams{
+					VolumeBackupDir: "/srv/backups",
+					CPUCopyMode:     "auto",
+				},
+			)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(report.Updated()).To(gomega.BeEmpty())
+			gomega.Expect(report.Failed()).To(gomega.HaveLen(1))
+			gomega.Expect(report.Failed()[0].Error()).To(gomega.ContainSubstring("no space left on device"))
+			gomega.Expect(client.TestData.StopAndRemoveContainerCount.Load()).To(gomega.Equal(int32(0)))
+			gomega.Expect(client.TestData.CreateContainerCount.Load()).To(gomega.Equal(int32(0)))
+			gomega.Expect(client.TestData.OperationOrder).To(gomega.ContainElement("StartContainerByID"))
+		})
+
+		ginkgo.It("should not back up when no backup directory is configured", func() {
+			report, _, err := actions.Update(testLogger(),
+				context.Background(),
+				client,
+				types.UpdateParams{CPUCopyMode: "auto"},
+			)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(client.TestData.BackupVolumesCount.Load()).To(gomega.BeZero())
+			gomega.Expect(report.Updated()).To(gomega.HaveLen(1))
+			gomega.Expect(report.Updated()[0].BackupPath()).To(gomega.BeEmpty())
+		})
+	})
+})
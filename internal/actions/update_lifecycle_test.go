@@ -439,4 +439,125 @@ var _ = ginkgo.Describe("the update action", func() {
 			})
 		})
 	})
+
+	ginkgo.When("watchtower has been instructed to run scan hooks", func() {
+		// staleClient returns a client with one stale container.
+		staleClient := func() mockActions.MockClient {
+			client := mockActions.CreateMockClient(
+				&mockActions.TestData{
+					Containers: []types.Container{
+						mockActions.CreateMockContainerWithConfig(
+							"test-container-scan",
+							"test-container-scan",
+							"fake-image:latest",
+							true,
+							false,
+							time.Now(),
+							&dockerContainer.Config{
+								Labels:       map[string]string{},
+								ExposedPorts: dockerNetwork.PortSet{},
+							}),
+					},
+				},
+				false,
+				false,
+			)
+			client.TestData.Staleness = map[string]bool{
+				"test-container-scan": true,
+			}
+
+			return client
+		}
+
+		ginkgo.When("the pre-scan hook fails", func() {
+			ginkgo.It("should abort the scan without stopping any container", func() {
+				client := staleClient()
+				_, _, err := actions.Update(testLogger(),
+					context.Background(),
+					client,
+					types.UpdateParams{
+						PreScanHook:     "exit 1",
+						ScanHookTimeout: time.Minute,
+						CPUCopyMode:     "auto",
+					},
+				)
+				gomega.Expect(err).To(gomega.HaveOccurred())
+				gomega.Expect(client.TestData.StopContainerCount.Load()).To(gomega.Equal(int32(0)))
+			})
+		})
+
+		ginkgo.When("the pre-restart-batch hook fails", func() {
+			ginkgo.It("should mark the planned containers failed and restart none", func() {
+				client := staleClient()
+				report, _, err := actions.Update(testLogger(),
+					context.Background(),
+					client,
+					types.UpdateParams{
+						PreRestartBatchHook: "exit 1",
+						ScanHookTimeout:     time.Minute,
+						CPUCopyMode:         "auto",
+					},
+				)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(report.Updated()).To(gomega.BeEmpty())
+				gomega.Expect(report.Failed()).To(gomega.HaveLen(1))
+				gomega.Expect(client.TestData.StopContainerCount.Load()).To(gomega.Equal(int32(0)))
+			})
+
+			ginkgo.It("should mark linked containers that were only restarting as skipped", func() {
+				client := staleClient()
+				client.TestData.Containers = append(client.TestData.Containers,
+					mockActions.CreateMockContainerWithConfig(
+						"test-container-linked",
+						"test-container-linked",
+						"fake-image2:latest",
+						true,
+						false,
+						time.Now(),
+						&dockerContainer.Config{
+							Labels: map[string]string{
+								"com.centurylinklabs.watchtower.depends-on": "test-container-scan",
+							},
+							ExposedPorts: dockerNetwork.PortSet{},
+						}),
+				)
+				client.TestData.Staleness["test-container-linked"] = false
+
+				report, _, err := actions.Update(testLogger(),
+					context.Background(),
+					client,
+					types.UpdateParams{
+						PreRestartBatchHook: "exit 1",
+						ScanHookTimeout:     time.Minute,
+						CPUCopyMode:         "auto",
+					},
+				)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(report.Failed()).To(gomega.ConsistOf(
+					gomega.HaveField("Name()", "test-container-scan"),
+				))
+				gomega.Expect(report.Skipped()).To(gomega.ConsistOf(
+					gomega.HaveField("Name()", "test-container-linked"),
+				))
+				gomega.Expect(client.TestData.StopContainerCount.Load()).To(gomega.Equal(int32(0)))
+			})
+		})
+
+		ginkgo.When("the post-scan hook fails", func() {
+			ginkgo.It("should still report the updated containers", func() {
+				client := staleClient()
+				report, _, err := actions.Update(testLogger(),
+					context.Background(),
+					client,
+					types.UpdateParams{
+						PostScanHook:    "exit 1",
+						ScanHookTimeout: time.Minute,
+						CPUCopyMode:     "auto",
+					},
+				)
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+				gomega.Expect(report.Updated()).To(gomega.HaveLen(1))
+			})
+		})
+	})
 })
@@ -13,6 +13,8 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/nicholas-fedor/watchtower/internal/api/handlers/events"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/quarantine"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/rollback"
 	"github.com/nicholas-fedor/watchtower/internal/logging"
 	mt "github.com/nicholas-fedor/watchtower/internal/metrics"
 	"github.com/nicholas-fedor/watchtower/pkg/container"
@@ -92,6 +94,10 @@ type Options struct {
 	EnableConfigAPI bool
 	// EnableEventsAPI enables the events SSE endpoint.
 	EnableEventsAPI bool
+	// EnableOutboxAPI enables the notification outbox endpoint.
+	EnableOutboxAPI bool
+	// EnableQuarantineAPI enables the image quarantine endpoints.
+	EnableQuarantineAPI bool
 	// UnblockHTTPAPI keeps scheduled polls running when the HTTP API is enabled.
 	UnblockHTTPAPI bool
 	// NoStartupMessage suppresses startup logs and notifications.
@@ -134,6 +140,12 @@ type Options struct {
 	Startup logging.StartupParams
 	// RunUpdatesWithNotifications runs the scan-and-update pipeline for HTTP update requests.
 	RunUpdatesWithNotifications func(context.Context, types.Filter, types.UpdateParams) *mt.Metric
+	// Rollback rolls back or unpins a container for HTTP rollback requests; nil disables the rollback endpoints.
+	Rollback rollback.Func
+	// QuarantineList lists quarantined images for HTTP quarantine requests.
+	QuarantineList quarantine.ListFunc
+	// QuarantineClear clears the quarantine of a container for HTTP quarantine requests.
+	QuarantineClear quarantine.ClearFunc
 	// FilterByImage builds an image-scoped filter for update and check requests.
 	FilterByImage func([]string, types.Filter) types.Filter
 	// DefaultMetrics returns the process metrics store.
@@ -142,6 +154,10 @@ type Options struct {
 	WriteStartupMessage func(logging.StartupParams)
 	// EventBroadcaster publishes action events to SSE subscribers.
 	EventBroadcaster *events.Broadcaster
+	// EventsFormat selects the SSE encoding ("native" or "cloudevents").
+	EventsFormat string
+	// EventsSource is the CloudEvents source attribute for the events stream.
+	EventsSource string
 	// OnUnexpectedServerStop is invoked when the HTTP server exits with an
 	// unexpected error while running in non-blocking mode. Callers typically
 	// cancel the process context so scheduling shuts down with the API.
@@ -21,6 +21,8 @@ const (
 	EndpointImages     = "images"
 	EndpointConfig     = "config"
 	EndpointEvents     = "events"
+	EndpointOutbox     = "outbox"
+	EndpointQuarantine = "quarantine"
 	EndpointSwagger    = "swagger"
 )
 
@@ -35,6 +37,8 @@ var AllEndpointNames = []string{
 	EndpointImages,
 	EndpointConfig,
 	EndpointEvents,
+	EndpointOutbox,
+	EndpointQuarantine,
 	EndpointSwagger,
 }
 
@@ -263,6 +267,8 @@ func SetEndpointConfig(endpointMap EnabledEndpointsMap, cfg *types.RunConfig) {
 	cfg.EnableImagesAPI = endpointMap.Contains(EndpointImages)
 	cfg.EnableConfigAPI = endpointMap.Contains(EndpointConfig)
 	cfg.EnableEventsAPI = endpointMap.Contains(EndpointEvents)
+	cfg.EnableOutboxAPI = endpointMap.Contains(EndpointOutbox)
+	cfg.EnableQuarantineAPI = endpointMap.Contains(EndpointQuarantine)
 	cfg.EnableSwaggerAPI = endpointMap.Contains(EndpointSwagger)
 }
 
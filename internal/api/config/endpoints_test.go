@@ -209,6 +209,8 @@ func TestPopulateRouteFlags(t *testing.T) {
 	assert.True(t, cfg.EnableImagesAPI)
 	assert.True(t, cfg.EnableConfigAPI)
 	assert.True(t, cfg.EnableEventsAPI)
+	assert.True(t, cfg.EnableOutboxAPI)
+	assert.True(t, cfg.EnableQuarantineAPI)
 	assert.True(t, cfg.EnableSwaggerAPI)
 
 	var empty types.RunConfig
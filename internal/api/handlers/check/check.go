@@ -15,15 +15,17 @@ import (
 
 // ContainerCheck holds the update availability result for a single container.
 type ContainerCheck struct {
-	Name            string    `json:"name"`
-	Image           string    `json:"image"`
-	ImageID         string    `json:"image_id"`
-	Digest          string    `json:"digest"`
-	UpdateAvailable bool      `json:"update_available"`
-	LatestImageID   string    `json:"latest_image_id"`
-	LatestDigest    string    `json:"latest_digest"`
-	Error           string    `json:"error,omitempty"`
-	Timestamp       time.Time `json:"timestamp"`
+	Name            string               `json:"name"`
+	Image           string               `json:"image"`
+	ImageID         string               `json:"image_id"`
+	Digest          string               `json:"digest"`
+	UpdateAvailable bool                 `json:"update_available"`
+	LatestImageID   string               `json:"latest_image_id"`
+	LatestDigest    string               `json:"latest_digest"`
+	CurrentImage    *types.ImageMetadata `json:"current_image,omitempty"`
+	LatestImage     *types.ImageMetadata `json:"latest_image,omitempty"`
+	Error           string               `json:"error,omitempty"`
+	Timestamp       time.Time            `json:"timestamp"`
 }
 
 // CheckFunc performs the update availability check for all watched containers.
@@ -100,6 +102,10 @@ func CheckForUpdates(log *zerolog.Logger,
 				info.RepoDigests,
 				c.ImageName(),
 			)
+
+			if info.Config != nil {
+				result.CurrentImage = metadataOrNil(types.ImageMetadataFromLabels(info.Config.Labels))
+			}
 		}
 
 		available, latestID, latestDigest, err := client.CheckContainerUpdate(
@@ -126,6 +132,10 @@ func CheckForUpdates(log *zerolog.Logger,
 			if latestID != "" {
 				result.LatestImageID = string(latestID)
 			}
+
+			if available {
+				result.LatestImage = latestImageMetadata(log, ctx, client, c, latestID)
+			}
 		}
 
 		results = append(results, result)
@@ -133,3 +143,45 @@ func CheckForUpdates(log *zerolog.Logger,
 
 	return results, nil
 }
+
+// latestImageMetadata looks up the OCI metadata of a container's newest image.
+//
+// Failures are logged and leave the metadata out of the result.
+//
+// Parameters:
+//   - ctx: Context for the lookup.
+//   - client: Docker client.
+//   - c: Container with an available update.
+//   - latestID: Latest local image ID, or empty if only the registry has it.
+//
+// Returns:
+//   - *types.ImageMetadata: Metadata, or nil if it is unknown or empty.
+func latestImageMetadata(log *zerolog.Logger,
+	ctx context.Context,
+	client container.Client,
+	c types.Container,
+	latestID types.ImageID,
+) *types.ImageMetadata {
+	metadata, err := client.LatestImageMetadata(ctx, c, latestID)
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Str("container", c.Name()).
+			Str("image", c.ImageName()).
+			Str("notify", "no").
+			Msg("Failed to read latest image metadata")
+
+		return nil
+	}
+
+	return metadataOrNil(metadata)
+}
+
+// metadataOrNil returns a pointer to metadata, or nil if it is empty.
+func metadataOrNil(metadata types.ImageMetadata) *types.ImageMetadata {
+	if metadata.IsZero() {
+		return nil
+	}
+
+	return &metadata
+}
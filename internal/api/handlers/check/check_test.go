@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v3"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
 	"github.com/moby/moby/api/types/image"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -42,6 +44,8 @@ func TestCheckForUpdates(t *testing.T) {
 				c.EXPECT().ListContainers(mock.Anything, mock.Anything).Return([]types.Container{container}, nil)
 				c.EXPECT().CheckContainerUpdate(mock.Anything, mock.Anything, mock.Anything).
 					Return(true, types.ImageID("sha256:def"), "", nil)
+				c.EXPECT().LatestImageMetadata(mock.Anything, mock.Anything, types.ImageID("sha256:def")).
+					Return(types.ImageMetadata{}, nil)
 
 				return c
 			},
@@ -261,6 +265,8 @@ func TestCheckForUpdates_ParamsPropagation(t *testing.T) {
 	client.EXPECT().CheckContainerUpdate(mock.Anything, mock.Anything, mock.MatchedBy(func(p types.UpdateParams) bool {
 		return p.MonitorOnly && p.NoPull && p.LabelPrecedence && p.CooldownDelay == 5*time.Minute
 	})).Return(true, types.ImageID("sha256:new"), "sha256:newdigest", nil)
+	client.EXPECT().LatestImageMetadata(mock.Anything, mock.Anything, types.ImageID("sha256:new")).
+		Return(types.ImageMetadata{}, nil)
 
 	params := types.UpdateParams{
 		MonitorOnly:     true,
@@ -293,6 +299,8 @@ func TestCheckForUpdates_MixedStaleResults(t *testing.T) {
 	client.EXPECT().ListContainers(mock.Anything, mock.Anything).Return([]types.Container{container1, container2}, nil)
 	client.EXPECT().CheckContainerUpdate(mock.Anything, container1, mock.Anything).
 		Return(true, types.ImageID("sha256:new1"), "sha256:digest1", nil)
+	client.EXPECT().LatestImageMetadata(mock.Anything, container1, types.ImageID("sha256:new1")).
+		Return(types.ImageMetadata{}, nil)
 	client.EXPECT().CheckContainerUpdate(mock.Anything, container2, mock.Anything).
 		Return(false, types.ImageID("sha256:def"), "", nil)
 
@@ -307,6 +315,53 @@ func TestCheckForUpdates_MixedStaleResults(t *testing.T) {
 	assert.Empty(t, results[1].LatestDigest)
 }
 
+func TestCheckForUpdates_ImageMetadata(t *testing.T) {
+	client := mockContainer.NewMockClient(t)
+	container := mockTypes.NewMockContainer(t)
+	container.EXPECT().Name().Return("my-app")
+	container.EXPECT().ImageName().Return("nginx:latest")
+	container.EXPECT().ImageID().Return(types.ImageID("sha256:abc"))
+	container.EXPECT().ImageInfo().Return(&image.InspectResponse{
+		Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{Labels: map[string]string{
+			types.ImageVersionLabel:  "1.4.2",
+			types.ImageRevisionLabel: "abc1234",
+		}}},
+	})
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything).Return([]types.Container{container}, nil)
+	client.EXPECT().CheckContainerUpdate(mock.Anything, mock.Anything, mock.Anything).
+		Return(true, types.ImageID(""), "sha256:newdigest", nil)
+	client.EXPECT().LatestImageMetadata(mock.Anything, container, types.ImageID("")).
+		Return(types.ImageMetadata{Version: "1.5.0", Revision: "def5678"}, nil)
+
+	results, err := CheckForUpdates(testLogger(), t.Context(), client, nil, types.UpdateParams{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, &types.ImageMetadata{Version: "1.4.2", Revision: "abc1234"}, results[0].CurrentImage)
+	assert.Equal(t, &types.ImageMetadata{Version: "1.5.0", Revision: "def5678"}, results[0].LatestImage)
+}
+
+func TestCheckForUpdates_ImageMetadataUnavailable(t *testing.T) {
+	client := mockContainer.NewMockClient(t)
+	container := mockTypes.NewMockContainer(t)
+	container.EXPECT().Name().Return("my-app")
+	container.EXPECT().ImageName().Return("nginx:latest")
+	container.EXPECT().ImageID().Return(types.ImageID("sha256:abc"))
+	container.EXPECT().ImageInfo().Return(&image.InspectResponse{})
+	client.EXPECT().ListContainers(mock.Anything, mock.Anything).Return([]types.Container{container}, nil)
+	client.EXPECT().CheckContainerUpdate(mock.Anything, mock.Anything, mock.Anything).
+		Return(true, types.ImageID(""), "sha256:newdigest", nil)
+	client.EXPECT().LatestImageMetadata(mock.Anything, container, types.ImageID("")).
+		Return(types.ImageMetadata{}, errors.New("registry unavailable"))
+
+	results, err := CheckForUpdates(testLogger(), t.Context(), client, nil, types.UpdateParams{})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].UpdateAvailable)
+	assert.Nil(t, results[0].CurrentImage)
+	assert.Nil(t, results[0].LatestImage)
+	assert.Empty(t, results[0].Error)
+}
+
 func TestExtractFilterParams(t *testing.T) {
 	tests := []struct {
 		name      string
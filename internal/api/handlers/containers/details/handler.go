@@ -10,6 +10,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/lifecycle"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
 
@@ -37,6 +38,8 @@ type ContainerDetails struct {
 	Stale bool `json:"stale"`
 	// Scope is the monitoring scope of the container.
 	Scope string `json:"scope"`
+	// HookRuns are the lifecycle hook runs of the container's most recent scan.
+	HookRuns []types.HookRun `json:"hook_runs,omitempty"`
 }
 
 // GetFunc returns detailed container status for all watched containers.
@@ -189,6 +192,7 @@ func GetContainerDetails(
 			Enabled:     enabled,
 			Stale:       c.IsStale(),
 			Scope:       scope,
+			HookRuns:    lifecycle.LastRuns(c.Name()),
 		}
 
 		info := c.ImageInfo()
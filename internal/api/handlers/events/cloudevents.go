@@ -0,0 +1,171 @@
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CloudEvents encoding constants.
+const (
+	// CloudEventsSpecVersion is the CloudEvents specification version emitted.
+	CloudEventsSpecVersion = "1.0"
+	// CloudEventsTypePrefix namespaces every Watchtower event type.
+	CloudEventsTypePrefix = "dev.watchtower."
+	// CloudEventsContentType is the media type for structured-mode events.
+	CloudEventsContentType = "application/cloudevents+json"
+	// cloudEventsDataContentType is the media type of the event data payload.
+	cloudEventsDataContentType = "application/json"
+	// cloudEventIDBytes is the number of random bytes in a generated event ID.
+	cloudEventIDBytes = 16
+)
+
+// Event stream formats accepted by the SSE endpoint.
+const (
+	// FormatNative streams events using the Watchtower Event envelope.
+	FormatNative = "native"
+	// FormatCloudEvents streams events as structured-mode CloudEvents 1.0.
+	FormatCloudEvents = "cloudevents"
+)
+
+// CloudEvent is a structured-mode CloudEvents 1.0 representation of an Event.
+type CloudEvent struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject,omitempty"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            any       `json:"data,omitempty"`
+	// TraceParent and TraceState are the Distributed Tracing extension attributes.
+	TraceParent string `json:"traceparent,omitempty"`
+	TraceState  string `json:"tracestate,omitempty"`
+}
+
+// NewCloudEvent converts a Watchtower event into a CloudEvent.
+//
+// Parameters:
+//   - event: The Watchtower event to convert.
+//   - source: The CloudEvents source attribute (see CloudEventSource).
+//
+// Returns:
+//   - CloudEvent: The converted event, keeping the event's ID or generating one if it has none.
+func NewCloudEvent(event Event, source string) CloudEvent {
+	id := event.ID
+	if id == "" {
+		id = newCloudEventID()
+	}
+
+	return CloudEvent{
+		SpecVersion:     CloudEventsSpecVersion,
+		ID:              id,
+		Source:          source,
+		Type:            CloudEventType(event.Type),
+		Subject:         cloudEventSubject(event.Data),
+		Time:            event.Timestamp,
+		DataContentType: cloudEventsDataContentType,
+		Data:            event.Data,
+		TraceParent:     event.TraceParent,
+		TraceState:      event.TraceState,
+	}
+}
+
+// CloudEventType maps a Watchtower event type onto the dev.watchtower namespace.
+//
+// Underscores become dots so "scan_started" maps to "dev.watchtower.scan.started"
+// and "container_updated" maps to "dev.watchtower.container.updated".
+//
+// Parameters:
+//   - eventType: The Watchtower event type.
+//
+// Returns:
+//   - string: The namespaced CloudEvents type.
+func CloudEventType(eventType string) string {
+	return CloudEventsTypePrefix + strings.ReplaceAll(eventType, "_", ".")
+}
+
+// CloudEventSource builds the CloudEvents source attribute from the host and scope.
+//
+// Parameters:
+//   - host: The Watchtower host name.
+//   - scope: The Watchtower scope, or empty when unscoped.
+//
+// Returns:
+//   - string: A URI reference such as "watchtower://host/scope".
+func CloudEventSource(host, scope string) string {
+	source := url.URL{Scheme: "watchtower", Host: host}
+	if scope != "" {
+		source.Path = "/" + scope
+	}
+
+	return source.String()
+}
+
+// BinaryHeaders returns the binary-mode HTTP headers for the event attributes.
+//
+// The data payload is carried in the request body with the data content type.
+//
+// Returns:
+//   - http.Header: The ce-* attribute headers and Content-Type.
+func (ce CloudEvent) BinaryHeaders() http.Header {
+	header := http.Header{}
+	header.Set("Ce-Specversion", ce.SpecVersion)
+	header.Set("Ce-Id", ce.ID)
+	header.Set("Ce-Source", ce.Source)
+	header.Set("Ce-Type", ce.Type)
+	header.Set("Ce-Time", ce.Time.UTC().Format(time.RFC3339Nano))
+	header.Set("Content-Type", ce.DataContentType)
+
+	if ce.Subject != "" {
+		header.Set("Ce-Subject", ce.Subject)
+	}
+
+	if ce.TraceParent != "" {
+		header.Set("Ce-Traceparent", ce.TraceParent)
+	}
+
+	if ce.TraceState != "" {
+		header.Set("Ce-Tracestate", ce.TraceState)
+	}
+
+	return header
+}
+
+// cloudEventSubject returns the container name for container-scoped event data.
+//
+// Parameters:
+//   - data: The event data payload.
+//
+// Returns:
+//   - string: The container name, or empty for scan-level events.
+func cloudEventSubject(data any) string {
+	switch d := data.(type) {
+	case ContainerEventData:
+		return d.ContainerName
+	case *ContainerEventData:
+		if d != nil {
+			return d.ContainerName
+		}
+	}
+
+	return ""
+}
+
+// newCloudEventID returns a random hex identifier for a CloudEvent.
+//
+// Returns:
+//   - string: A 32-character hex string, or a timestamp-based fallback.
+func newCloudEventID() string {
+	buf := make([]byte, cloudEventIDBytes)
+
+	_, err := rand.Read(buf)
+	if err != nil {
+		return time.Now().UTC().Format("20060102150405.000000000")
+	}
+
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEventType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		eventType string
+		want      string
+	}{
+		{eventType: "scan_started", want: "dev.watchtower.scan.started"},
+		{eventType: "image_cleanup", want: "dev.watchtower.image.cleanup"},
+		{eventType: "container_updated", want: "dev.watchtower.container.updated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, CloudEventType(tt.eventType))
+		})
+	}
+}
+
+func TestCloudEventSource(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "watchtower://docker-01", CloudEventSource("docker-01", ""))
+	assert.Equal(t, "watchtower://docker-01/prod", CloudEventSource("docker-01", "prod"))
+}
+
+func TestNewCloudEvent_ContainerSubject(t *testing.T) {
+	t.Parallel()
+
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := Event{
+		Type:      "container_updated",
+		Timestamp: timestamp,
+		Data:      ContainerEventData{ContainerID: "abc", ContainerName: "web", ImageName: "nginx:latest"},
+	}
+
+	cloudEvent := NewCloudEvent(event, "watchtower://host")
+
+	assert.Equal(t, CloudEventsSpecVersion, cloudEvent.SpecVersion)
+	assert.Equal(t, "dev.watchtower.container.updated", cloudEvent.Type)
+	assert.Equal(t, "watchtower://host", cloudEvent.Source)
+	assert.Equal(t, "web", cloudEvent.Subject)
+	assert.Len(t, cloudEvent.ID, 2*cloudEventIDBytes)
+	assert.True(t, cloudEvent.Time.Equal(timestamp))
+
+	raw, err := json.Marshal(cloudEvent)
+	require.NoError(t, err)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	assert.Equal(t, "1.0", decoded["specversion"])
+	assert.Equal(t, "application/json", decoded["datacontenttype"])
+	assert.Equal(t, "web", decoded["subject"])
+
+	data, ok := decoded["data"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "nginx:latest", data["image_name"])
+}
+
+func TestNewCloudEvent_ScanEventHasNoSubject(t *testing.T) {
+	t.Parallel()
+
+	cloudEvent := NewCloudEvent(Event{Type: "scan_completed", Data: ScanCompletedData{Scanned: 1}}, "src")
+
+	assert.Empty(t, cloudEvent.Subject)
+
+	raw, err := json.Marshal(cloudEvent)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), `"subject"`)
+}
+
+func TestNewCloudEvent_UniqueIDs(t *testing.T) {
+	t.Parallel()
+
+	first := NewCloudEvent(Event{Type: "scan_started"}, "src")
+	second := NewCloudEvent(Event{Type: "scan_started"}, "src")
+
+	assert.NotEqual(t, first.ID, second.ID)
+}
+
+func TestCloudEvent_BinaryHeaders(t *testing.T) {
+	t.Parallel()
+
+	cloudEvent := NewCloudEvent(Event{
+		Type:      "container_failed",
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Data:      ContainerEventData{ContainerName: "db"},
+	}, "watchtower://host")
+
+	header := cloudEvent.BinaryHeaders()
+
+	assert.Equal(t, "1.0", header.Get("ce-specversion"))
+	assert.Equal(t, cloudEvent.ID, header.Get("ce-id"))
+	assert.Equal(t, "watchtower://host", header.Get("ce-source"))
+	assert.Equal(t, "dev.watchtower.container.failed", header.Get("ce-type"))
+	assert.Equal(t, "db", header.Get("ce-subject"))
+	assert.Equal(t, "2025-01-02T03:04:05Z", header.Get("ce-time"))
+	assert.Equal(t, "application/json", header.Get("Content-Type"))
+}
+
+func TestNewCloudEvent_TraceContext(t *testing.T) {
+	t.Parallel()
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	cloudEvent := NewCloudEvent(Event{
+		Type:        "scan_started",
+		TraceParent: traceParent,
+		TraceState:  "vendor=value",
+	}, "watchtower://host")
+
+	assert.Equal(t, traceParent, cloudEvent.TraceParent)
+	assert.Equal(t, traceParent, cloudEvent.BinaryHeaders().Get("ce-traceparent"))
+	assert.Equal(t, "vendor=value", cloudEvent.BinaryHeaders().Get("ce-tracestate"))
+
+	encoded, err := json.Marshal(cloudEvent)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"traceparent":"`+traceParent+`"`)
+
+	native, err := json.Marshal(Event{Type: "scan_started", TraceParent: traceParent})
+	require.NoError(t, err)
+	assert.NotContains(t, string(native), traceParent)
+}
+
+func TestHandler_EncodeEvent(t *testing.T) {
+	t.Parallel()
+
+	event := Event{Type: "scan_started", Data: ScanStartedData{Cleanup: true}}
+
+	native := NewHandler(testLogger(), NewBroadcaster(), nil)
+	name, data, err := native.encodeEvent(event)
+	require.NoError(t, err)
+	assert.Equal(t, "scan_started", name)
+	assert.Contains(t, string(data), `"type":"scan_started"`)
+
+	cloud := NewHandler(testLogger(), NewBroadcaster(), nil)
+	cloud.Format = FormatCloudEvents
+	cloud.Source = "watchtower://host"
+	name, data, err = cloud.encodeEvent(event)
+	require.NoError(t, err)
+	assert.Equal(t, "dev.watchtower.scan.started", name)
+	assert.Contains(t, string(data), `"specversion":"1.0"`)
+	assert.Contains(t, string(data), `"source":"watchtower://host"`)
+}
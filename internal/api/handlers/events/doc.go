@@ -1,6 +1,8 @@
 // Package events provides the /v1/events HTTP API endpoint for real-time
 // Server-Sent Events (SSE). It exposes a Broadcaster that manages subscriber
-// registration and event distribution, and a Handler that streams Watchtower
-// operational events (scan_started, scan_failed, image_cleanup, scan_completed)
-// to connected clients.
+// registration and event distribution, a Handler that streams Watchtower
+// operational events (scan_started, scan_failed, scan_aborted, container_updated,
+// container_restarted, container_failed, image_cleanup, scan_completed) to
+// connected clients, and a Sink that forwards the same events as CloudEvents
+// to outbound HTTP endpoints.
 package events
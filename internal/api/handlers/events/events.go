@@ -1,9 +1,11 @@
 package events
 
 import (
+	"context"
 	"sync"
 	"time"
 
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
 
@@ -19,6 +21,28 @@ type Event struct {
 	Type      string    `json:"type"`
 	Timestamp time.Time `json:"timestamp"`
 	Data      any       `json:"data"`
+
+	// ID identifies the event in CloudEvents encodings. Publish assigns it once
+	// so every subscriber, the SSE stream and the sinks alike, sees the same ID.
+	ID string `json:"-"`
+
+	// TraceParent and TraceState carry the W3C trace context of the operation
+	// that emitted the event. They are forwarded by CloudEvents encodings only.
+	TraceParent string `json:"-"`
+	TraceState  string `json:"-"`
+}
+
+// WithTraceContext returns a copy of the event carrying the trace context of ctx.
+//
+// Parameters:
+//   - ctx: Context holding the active span.
+//
+// Returns:
+//   - Event: The event with TraceParent and TraceState set, or unchanged when ctx has no span.
+func (e Event) WithTraceContext(ctx context.Context) Event {
+	e.TraceParent, e.TraceState = tracing.TraceParent(ctx)
+
+	return e
 }
 
 // ScanStartedData carries redacted scan policy flags for SSE subscribers.
@@ -71,9 +95,22 @@ type ScanFailedData struct {
 	Error string `json:"error"`
 }
 
+// ScanAbortedData carries details about a scan whose circuit breaker halted the remaining updates.
+type ScanAbortedData struct {
+	// Reason describes the limit that was exceeded.
+	Reason string `json:"reason"`
+	// Failed is the number of failed updates before the scan halted.
+	Failed int `json:"failed"`
+	// Halted lists the containers left on their current images.
+	Halted []string `json:"halted"`
+}
+
 // ImageCleanupData carries details about images cleaned up after a scan.
 type ImageCleanupData struct {
 	Images []ImageCleanupEntry `json:"images"`
+	// ReclaimedBytes is the combined size of the removed images. Layers shared
+	// with remaining images are counted too, so less space may be freed.
+	ReclaimedBytes int64 `json:"reclaimed_bytes"`
 }
 
 // ImageCleanupEntry represents a single cleaned-up image in an event.
@@ -82,6 +119,36 @@ type ImageCleanupEntry struct {
 	ImageName     string `json:"image_name"`
 	ContainerID   string `json:"container_id"`
 	ContainerName string `json:"container_name"`
+	Size          int64  `json:"size,omitempty"`
+}
+
+// ContainerEventData carries the outcome of a single container within a scan.
+type ContainerEventData struct {
+	ContainerID   string `json:"container_id"`
+	ContainerName string `json:"container_name"`
+	ImageName     string `json:"image_name"`
+	OldImageID    string `json:"old_image_id,omitempty"`
+	NewImageID    string `json:"new_image_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// NewContainerEventData creates a container event payload from a session
+// container report.
+//
+// Parameters:
+//   - report: The container report from the scan session.
+//
+// Returns:
+//   - ContainerEventData: The event payload with short image IDs.
+func NewContainerEventData(report types.ContainerReport) ContainerEventData {
+	return ContainerEventData{
+		ContainerID:   string(report.ID()),
+		ContainerName: report.Name(),
+		ImageName:     report.ImageName(),
+		OldImageID:    report.CurrentImageID().ShortID(),
+		NewImageID:    report.LatestImageID().ShortID(),
+		Error:         report.Error(),
+	}
 }
 
 // subscriber represents a single SSE subscriber with an event channel and a
@@ -131,7 +198,14 @@ func (b *Broadcaster) Subscribe() <-chan Event {
 // allowing the receiver to detect unsubscription even if no more events are sent.
 // Returns nil, nil if the maximum number of subscribers has been reached.
 func (b *Broadcaster) SubscribeWithDone() (<-chan Event, <-chan struct{}) {
-	subCh := make(chan Event, subscriberChannelSize)
+	return b.subscribeWithSize(subscriberChannelSize)
+}
+
+// subscribeWithSize registers a new subscriber whose event channel holds up to
+// size events before Publish starts dropping them for that subscriber.
+// Returns nil, nil if the maximum number of subscribers has been reached.
+func (b *Broadcaster) subscribeWithSize(size int) (<-chan Event, <-chan struct{}) {
+	subCh := make(chan Event, size)
 	done := make(chan struct{})
 
 	b.mu.Lock()
@@ -173,8 +247,12 @@ func (b *Broadcaster) Unsubscribe(ch <-chan Event) bool {
 
 // Publish sends an event to all registered subscribers.
 // Subscribers that are full (backpressure) or have been unsubscribed have their
-// event dropped.
+// event dropped. Events without an ID are assigned one before they are sent.
 func (b *Broadcaster) Publish(event Event) {
+	if event.ID == "" {
+		event.ID = newCloudEventID()
+	}
+
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 
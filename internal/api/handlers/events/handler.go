@@ -21,6 +21,11 @@ type Handler struct {
 	Path           string
 	Broadcaster    *Broadcaster
 	AllowedOrigins []string
+	// Format selects the stream encoding: FormatNative (default) or
+	// FormatCloudEvents.
+	Format string
+	// Source is the CloudEvents source attribute used in FormatCloudEvents.
+	Source string
 }
 
 // NewHandler creates a new events handler backed by the given broadcaster.
@@ -43,6 +48,7 @@ func NewHandler(log *zerolog.Logger, b *Broadcaster, allowedOrigins []string) *H
 		Path:           "/v1/events",
 		Broadcaster:    b,
 		AllowedOrigins: allowedOrigins,
+		Format:         FormatNative,
 	}
 }
 
@@ -53,7 +59,7 @@ func NewHandler(log *zerolog.Logger, b *Broadcaster, allowedOrigins []string) *H
 //   - fiber.Handler: The registered route handler for SSE streaming.
 //
 //     @Summary		Real-time events stream
-//     @Description	Streams Watchtower operational events (scan started/completed, update started/completed/failed) via Server-Sent Events (SSE).
+//     @Description	Streams Watchtower operational events (scan started/completed/failed, container updated/restarted/failed, image cleanup) via Server-Sent Events (SSE), optionally encoded as CloudEvents 1.0.
 //     @Description
 //     @Description	**SSE is not supported by "Try it out"**.
 //     @Tags			events
@@ -180,7 +186,7 @@ func (h *Handler) dispatchEvents(stream *sse.Stream, subCh <-chan Event, done <-
 				return nil
 			}
 
-			data, err := json.Marshal(event)
+			name, data, err := h.encodeEvent(event)
 			if err != nil {
 				h.log.Warn().
 					Err(err).
@@ -191,7 +197,7 @@ func (h *Handler) dispatchEvents(stream *sse.Stream, subCh <-chan Event, done <-
 			}
 
 			err = stream.Event(sse.Event{
-				Name: event.Type,
+				Name: name,
 				Data: string(data),
 			})
 			if err != nil {
@@ -205,6 +211,35 @@ func (h *Handler) dispatchEvents(stream *sse.Stream, subCh <-chan Event, done <-
 	}
 }
 
+// encodeEvent serializes an event in the handler's configured format.
+//
+// Parameters:
+//   - event: The event to encode.
+//
+// Returns:
+//   - string: The SSE event name (the native type or the CloudEvents type).
+//   - []byte: The JSON-encoded event.
+//   - error: Non-nil if JSON marshaling fails.
+func (h *Handler) encodeEvent(event Event) (string, []byte, error) {
+	if h.Format == FormatCloudEvents {
+		cloudEvent := NewCloudEvent(event, h.Source)
+
+		data, err := json.Marshal(cloudEvent)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to marshal cloud event: %w", err)
+		}
+
+		return cloudEvent.Type, data, nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return event.Type, data, nil
+}
+
 // isOriginAllowed reports whether origin is allowed for the SSE endpoint.
 //
 // Same-origin (with/without scheme), "null", and origins in allowedOrigins (or "*")
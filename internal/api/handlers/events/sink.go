@@ -0,0 +1,285 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
+)
+
+// Event sink delivery modes.
+const (
+	// SinkModeStructured posts the full CloudEvent as application/cloudevents+json.
+	SinkModeStructured = "structured"
+	// SinkModeBinary posts the event data as the body with ce-* attribute headers.
+	SinkModeBinary = "binary"
+)
+
+// sinkChannelSize is the subscriber buffer for outbound sinks, larger than
+// the SSE buffer so slow endpoints do not drop bursts of container events.
+const sinkChannelSize = 256
+
+// defaultSinkTimeout bounds each delivery attempt when no timeout is configured.
+const defaultSinkTimeout = 10 * time.Second
+
+// Errors returned by the event sink.
+var (
+	// ErrInvalidSinkMode indicates an unsupported CloudEvents delivery mode.
+	ErrInvalidSinkMode = errors.New("invalid event sink mode")
+	// ErrSinkSubscribe indicates the sink could not subscribe to the broadcaster.
+	ErrSinkSubscribe = errors.New("failed to subscribe event sink")
+	// ErrSinkDelivery indicates the sink endpoint returned a non-2xx status.
+	ErrSinkDelivery = errors.New("event sink rejected event")
+)
+
+// Sink forwards broadcaster events as CloudEvents to one or more HTTP endpoints.
+type Sink struct {
+	log *zerolog.Logger
+
+	urls        []string
+	mode        string
+	source      string
+	client      *http.Client
+	broadcaster *Broadcaster
+	events      <-chan Event
+	done        <-chan struct{}
+	finished    chan struct{}
+
+	mu        sync.Mutex
+	running   bool
+	closed    bool
+	closeOnce sync.Once
+}
+
+// NewSink subscribes a new outbound CloudEvents sink to the broadcaster.
+//
+// Parameters:
+//   - log: Logger for delivery failures; nil disables logging.
+//   - b: The broadcaster to subscribe to.
+//   - urls: HTTP endpoints receiving every event.
+//   - mode: SinkModeStructured or SinkModeBinary.
+//   - source: The CloudEvents source attribute.
+//   - timeout: Per-request timeout; zero uses a 10 second default.
+//
+// Returns:
+//   - *Sink: The subscribed sink; call Run to start delivery.
+//   - error: Non-nil if the mode is invalid or the subscriber cap is reached.
+func NewSink(
+	log *zerolog.Logger,
+	b *Broadcaster,
+	urls []string,
+	mode, source string,
+	timeout time.Duration,
+) (*Sink, error) {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	if mode == "" {
+		mode = SinkModeStructured
+	}
+
+	if mode != SinkModeStructured && mode != SinkModeBinary {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSinkMode, mode)
+	}
+
+	if timeout <= 0 {
+		timeout = defaultSinkTimeout
+	}
+
+	if b == nil {
+		return nil, ErrSinkSubscribe
+	}
+
+	subCh, done := b.subscribeWithSize(sinkChannelSize)
+	if subCh == nil {
+		return nil, ErrSinkSubscribe
+	}
+
+	return &Sink{
+		log:         log,
+		urls:        urls,
+		mode:        mode,
+		source:      source,
+		client:      &http.Client{Timeout: timeout, Transport: tracing.NewTransport(nil)},
+		broadcaster: b,
+		events:      subCh,
+		done:        done,
+		finished:    make(chan struct{}),
+	}, nil
+}
+
+// Run delivers events until the context is cancelled or the sink is closed.
+//
+// Events still buffered when the sink is closed are delivered before Run
+// returns so run-once invocations do not lose the final scan events.
+//
+// Parameters:
+//   - ctx: Context controlling the delivery loop.
+func (s *Sink) Run(ctx context.Context) {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.running = true
+	s.mu.Unlock()
+
+	defer close(s.finished)
+
+	for {
+		select {
+		case event := <-s.events:
+			s.deliver(ctx, event)
+		case <-s.done:
+			s.drain(ctx)
+
+			return
+		case <-ctx.Done():
+			s.broadcaster.Unsubscribe(s.events)
+
+			return
+		}
+	}
+}
+
+// Close unsubscribes the sink and waits for buffered events to be delivered.
+//
+// If Run was never started, Close delivers the buffered events itself and
+// any later call to Run returns immediately. Calling Close more than once is
+// a no-op.
+func (s *Sink) Close() {
+	s.closeOnce.Do(func() {
+		s.mu.Lock()
+		running := s.running
+		s.closed = true
+		s.mu.Unlock()
+
+		s.broadcaster.Unsubscribe(s.events)
+
+		if !running {
+			s.drain(context.Background())
+
+			return
+		}
+
+		<-s.finished
+	})
+}
+
+// drain delivers any events left in the subscriber buffer.
+//
+// Parameters:
+//   - ctx: Context for the outbound requests.
+func (s *Sink) drain(ctx context.Context) {
+	for {
+		select {
+		case event := <-s.events:
+			s.deliver(ctx, event)
+		default:
+			return
+		}
+	}
+}
+
+// deliver posts a single event to every configured endpoint.
+//
+// Parameters:
+//   - ctx: Context for the outbound requests.
+//   - event: The event to deliver.
+func (s *Sink) deliver(ctx context.Context, event Event) {
+	cloudEvent := NewCloudEvent(event, s.source)
+
+	ctx, span := tracing.Start(
+		tracing.ContextFromTraceParent(ctx, event.TraceParent, event.TraceState),
+		"events.sink.deliver",
+		attribute.String("cloudevents.event_type", cloudEvent.Type),
+	)
+	defer span.End()
+
+	for _, target := range s.urls {
+		err := s.post(ctx, target, cloudEvent)
+		if err != nil {
+			tracing.RecordError(span, err)
+
+			s.log.Warn().
+				Err(err).
+				Str("type", cloudEvent.Type).
+				Str("notify", "no").
+				Msg("Failed to deliver event to sink")
+		}
+	}
+}
+
+// post sends a CloudEvent to a single endpoint in the configured mode.
+//
+// Parameters:
+//   - ctx: Context for the outbound request.
+//   - target: The endpoint URL.
+//   - cloudEvent: The event to send.
+//
+// Returns:
+//   - error: Non-nil if encoding, the request, or the response status fails.
+func (s *Sink) post(ctx context.Context, target string, cloudEvent CloudEvent) error {
+	var (
+		body   []byte
+		header http.Header
+		err    error
+	)
+
+	if s.mode == SinkModeBinary {
+		body, err = json.Marshal(cloudEvent.Data)
+		header = cloudEvent.BinaryHeaders()
+	} else {
+		body, err = json.Marshal(cloudEvent)
+		header = http.Header{"Content-Type": []string{CloudEventsContentType}}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create sink request: %w", err)
+	}
+
+	req.Header = header
+
+	// Propagate the delivery span's trace context to the receiver.
+	traceParent, traceState := tracing.TraceParent(ctx)
+	if traceParent != "" {
+		req.Header.Set("Traceparent", traceParent)
+
+		if traceState != "" {
+			req.Header.Set("Tracestate", traceState)
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: status %d", ErrSinkDelivery, resp.StatusCode)
+	}
+
+	return nil
+}
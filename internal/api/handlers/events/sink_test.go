@@ -0,0 +1,227 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, func() []capturedRequest) {
+	t.Helper()
+
+	var (
+		mu       sync.Mutex
+		captured []capturedRequest
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		captured = append(captured, capturedRequest{header: r.Header.Clone(), body: body})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []capturedRequest {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]capturedRequest(nil), captured...)
+	}
+}
+
+func TestNewSink_InvalidMode(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSink(testLogger(), NewBroadcaster(), []string{"http://localhost"}, "batch", "src", 0)
+	require.ErrorIs(t, err, ErrInvalidSinkMode)
+}
+
+func TestNewSink_NilBroadcaster(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSink(testLogger(), nil, []string{"http://localhost"}, SinkModeStructured, "src", 0)
+	require.ErrorIs(t, err, ErrSinkSubscribe)
+}
+
+func TestSink_StructuredMode(t *testing.T) {
+	t.Parallel()
+
+	server, captured := newCaptureServer(t, http.StatusAccepted)
+	b := NewBroadcaster()
+
+	sink, err := NewSink(testLogger(), b, []string{server.URL}, SinkModeStructured, "watchtower://host", time.Second)
+	require.NoError(t, err)
+
+	go sink.Run(t.Context())
+
+	b.Publish(Event{
+		Type:      "container_updated",
+		Timestamp: time.Now().UTC(),
+		Data:      ContainerEventData{ContainerName: "web"},
+	})
+	sink.Close()
+
+	requests := captured()
+	require.Len(t, requests, 1)
+	assert.Equal(t, CloudEventsContentType, requests[0].header.Get("Content-Type"))
+
+	var decoded CloudEvent
+	require.NoError(t, json.Unmarshal(requests[0].body, &decoded))
+	assert.Equal(t, "dev.watchtower.container.updated", decoded.Type)
+	assert.Equal(t, "watchtower://host", decoded.Source)
+	assert.Equal(t, "web", decoded.Subject)
+	assert.Equal(t, 0, b.SubscriberCount())
+}
+
+func TestSink_BinaryMode(t *testing.T) {
+	t.Parallel()
+
+	server, captured := newCaptureServer(t, http.StatusOK)
+	b := NewBroadcaster()
+
+	sink, err := NewSink(testLogger(), b, []string{server.URL}, SinkModeBinary, "watchtower://host", time.Second)
+	require.NoError(t, err)
+
+	go sink.Run(t.Context())
+
+	b.Publish(Event{Type: "scan_completed", Data: ScanCompletedData{Scanned: 3, Updated: 1}})
+	sink.Close()
+
+	requests := captured()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "dev.watchtower.scan.completed", requests[0].header.Get("Ce-Type"))
+	assert.Equal(t, "watchtower://host", requests[0].header.Get("Ce-Source"))
+	assert.Equal(t, "application/json", requests[0].header.Get("Content-Type"))
+	assert.JSONEq(t, `{"scanned":3,"updated":1,"failed":0}`, string(requests[0].body))
+}
+
+func TestSink_SharesEventIDWithStream(t *testing.T) {
+	t.Parallel()
+
+	server, captured := newCaptureServer(t, http.StatusOK)
+	b := NewBroadcaster()
+
+	sink, err := NewSink(testLogger(), b, []string{server.URL}, SinkModeStructured, "watchtower://host", time.Second)
+	require.NoError(t, err)
+
+	stream := b.Subscribe()
+	require.NotNil(t, stream)
+
+	go sink.Run(t.Context())
+
+	b.Publish(Event{Type: "container_updated", Data: ContainerEventData{ContainerName: "web"}})
+	sink.Close()
+
+	handler := NewHandler(testLogger(), b, nil)
+	handler.Format = FormatCloudEvents
+	handler.Source = "watchtower://host"
+
+	_, data, err := handler.encodeEvent(<-stream)
+	require.NoError(t, err)
+
+	var streamed, delivered CloudEvent
+	require.NoError(t, json.Unmarshal(data, &streamed))
+
+	requests := captured()
+	require.Len(t, requests, 1)
+	require.NoError(t, json.Unmarshal(requests[0].body, &delivered))
+
+	assert.NotEmpty(t, streamed.ID)
+	assert.Equal(t, streamed.ID, delivered.ID)
+
+	// Encoding the same event again keeps its ID.
+	_, again, err := handler.encodeEvent(Event{ID: streamed.ID, Type: "container_updated"})
+	require.NoError(t, err)
+	assert.Contains(t, string(again), `"id":"`+streamed.ID+`"`)
+}
+
+func TestSink_DeliversToEveryURLDespiteErrors(t *testing.T) {
+	t.Parallel()
+
+	failing, failed := newCaptureServer(t, http.StatusInternalServerError)
+	working, delivered := newCaptureServer(t, http.StatusNoContent)
+	b := NewBroadcaster()
+
+	sink, err := NewSink(testLogger(), b, []string{failing.URL, working.URL}, "", "src", time.Second)
+	require.NoError(t, err)
+
+	go sink.Run(t.Context())
+
+	b.Publish(Event{Type: "scan_started"})
+	b.Publish(Event{Type: "scan_completed"})
+	sink.Close()
+
+	assert.Len(t, failed(), 2)
+	assert.Len(t, delivered(), 2)
+}
+
+func TestSink_PropagatesTraceContext(t *testing.T) {
+	t.Parallel()
+
+	const traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	server, captured := newCaptureServer(t, http.StatusOK)
+	b := NewBroadcaster()
+
+	sink, err := NewSink(testLogger(), b, []string{server.URL}, SinkModeStructured, "src", time.Second)
+	require.NoError(t, err)
+
+	go sink.Run(t.Context())
+
+	b.Publish(Event{Type: "scan_started", TraceParent: traceParent})
+	b.Publish(Event{Type: "scan_completed"})
+	sink.Close()
+
+	requests := captured()
+	require.Len(t, requests, 2)
+	assert.Equal(t, traceParent, requests[0].header.Get("Traceparent"))
+	assert.Empty(t, requests[1].header.Get("Traceparent"))
+}
+
+func TestSink_CloseWithoutRun(t *testing.T) {
+	t.Parallel()
+
+	server, captured := newCaptureServer(t, http.StatusOK)
+	b := NewBroadcaster()
+
+	sink, err := NewSink(testLogger(), b, []string{server.URL}, SinkModeStructured, "src", time.Second)
+	require.NoError(t, err)
+
+	b.Publish(Event{Type: "scan_started"})
+
+	closed := make(chan struct{})
+
+	go func() {
+		sink.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked without a running sink")
+	}
+
+	assert.Len(t, captured(), 1)
+	assert.Equal(t, 0, b.SubscriberCount())
+
+	// Run after Close must return immediately, and a second Close is a no-op.
+	sink.Run(t.Context())
+	sink.Close()
+}
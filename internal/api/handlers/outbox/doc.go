@@ -0,0 +1,4 @@
+// Package outbox provides the /v1/notifications/outbox HTTP API endpoint,
+// exposing notifications held in the outbox after a service URL failed to
+// accept them.
+package outbox
@@ -0,0 +1,69 @@
+package outbox
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/notifications"
+)
+
+// Handler serves the /v1/notifications/outbox endpoint.
+type Handler struct {
+	log *zerolog.Logger
+
+	Path        string
+	getMessages func() []notifications.OutboxMessage
+}
+
+// New creates a new outbox handler backed by the given function.
+//
+// Parameters:
+//   - getMessages: Function that returns the notifications held for retry.
+func New(log *zerolog.Logger, getMessages func() []notifications.OutboxMessage) *Handler {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	return &Handler{
+		log:         log,
+		Path:        "/v1/notifications/outbox",
+		getMessages: getMessages,
+	}
+}
+
+// Handle responds with the notifications waiting in the outbox as JSON.
+//
+//	@Summary		Notification outbox
+//	@Description	Returns notifications that a service URL failed to accept and that are held for retry. Service URLs are reduced to their scheme and host.
+//	@Tags			notifications
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}	"Outbox messages with count and timestamp"
+//	@Failure		401	{string}	string					"Missing or invalid API token"
+//	@Security		BearerAuth
+//	@Router			/v1/notifications/outbox [get]
+func (h *Handler) Handle(c fiber.Ctx) error {
+	h.log.Debug().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("notify", "no").
+		Msg("Received HTTP API notification outbox request")
+
+	messages := h.getMessages()
+
+	err := c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"messages":    messages,
+		"count":       len(messages),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"api_version": "v1",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send JSON response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package outbox
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nicholas-fedor/watchtower/pkg/notifications"
+)
+
+func testLogger() *zerolog.Logger {
+	n := zerolog.Nop()
+
+	return &n
+}
+
+func TestNew(t *testing.T) {
+	h := New(testLogger(), func() []notifications.OutboxMessage { return nil })
+	require.NotNil(t, h)
+	assert.Equal(t, "/v1/notifications/outbox", h.Path)
+}
+
+func TestHandler_Handle(t *testing.T) {
+	queued := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	messages := []notifications.OutboxMessage{
+		{
+			Service:     "smtp",
+			URL:         "smtp://mail.example.com",
+			Message:     "web updated",
+			Queued:      queued,
+			Attempts:    2,
+			NextAttempt: queued.Add(2 * time.Minute),
+			LastError:   "connection refused",
+		},
+	}
+
+	h := New(testLogger(), func() []notifications.OutboxMessage { return messages })
+
+	app := fiber.New(fiber.Config{})
+	app.Get("/v1/notifications/outbox", h.Handle)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/notifications/outbox", nil)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Messages []notifications.OutboxMessage `json:"messages"`
+		Count    int                           `json:"count"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 1, body.Count)
+	assert.Equal(t, messages, body.Messages)
+}
@@ -0,0 +1,4 @@
+// Package quarantine provides the /v1/quarantine HTTP API endpoints, listing
+// images held back after repeated update failures and clearing the quarantine
+// of a container so the next scan updates it again.
+package quarantine
@@ -0,0 +1,140 @@
+package quarantine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog"
+)
+
+// Entry describes an image quarantined for a container.
+type Entry struct {
+	// Container is the container name.
+	Container string `json:"container"`
+	// Image is the image name the container runs.
+	Image string `json:"image"`
+	// ImageID is the quarantined image.
+	ImageID string `json:"image_id"`
+	// Failures is the number of failed updates to the image.
+	Failures int `json:"failures"`
+	// LastError is the error of the most recent failed update.
+	LastError string `json:"last_error"`
+	// LastFailure is when the most recent update failed.
+	LastFailure time.Time `json:"last_failure"`
+	// Until is when the image is retried; in the past if the retry is pending.
+	Until time.Time `json:"until"`
+}
+
+// ListFunc returns the quarantined images.
+type ListFunc func() []Entry
+
+// ClearFunc clears the quarantine of a container and reports whether it had one.
+type ClearFunc func(name string) bool
+
+// Handler serves the /v1/quarantine and /v1/quarantine/{name} endpoints.
+type Handler struct {
+	log *zerolog.Logger
+
+	list      ListFunc
+	clearFunc ClearFunc
+	Path      string
+	ClearPath string
+}
+
+// New creates a new quarantine handler.
+//
+// Parameters:
+//   - list: Function that returns the quarantined images.
+//   - clearFunc: Function that clears the quarantine of a container.
+func New(log *zerolog.Logger, list ListFunc, clearFunc ClearFunc) *Handler {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	return &Handler{
+		log:       log,
+		list:      list,
+		clearFunc: clearFunc,
+		Path:      "/v1/quarantine",
+		ClearPath: "/v1/quarantine/:name",
+	}
+}
+
+// HandleList responds with the quarantined images as JSON.
+//
+//	@Summary		Quarantined images
+//	@Description	Lists images held back after updating a container to them failed repeatedly, with the time each is retried.
+//	@Tags			containers
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	map[string]interface{}	"Quarantined images with count and timestamp"
+//	@Failure		401	{string}	string					"Missing or invalid API token"
+//	@Security		BearerAuth
+//	@Router			/v1/quarantine [get]
+func (h *Handler) HandleList(c fiber.Ctx) error {
+	h.log.Debug().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("notify", "no").
+		Msg("Received HTTP API quarantine request")
+
+	entries := h.list()
+
+	err := c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"quarantined": entries,
+		"count":       len(entries),
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"api_version": "v1",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send JSON response: %w", err)
+	}
+
+	return nil
+}
+
+// HandleClear clears the quarantine of a container, so the next scan updates it again.
+//
+//	@Summary		Clear a container's quarantine
+//	@Description	Forgets the failed updates of a container, so the next scan updates it to the quarantined image again.
+//	@Tags			containers
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string					true	"Container name"
+//	@Success		200		{object}	map[string]interface{}	"Cleared container"
+//	@Failure		401		{string}	string					"Missing or invalid API token"
+//	@Failure		404		{string}	string					"Container has no quarantined image"
+//	@Security		BearerAuth
+//	@Router			/v1/quarantine/{name} [delete]
+func (h *Handler) HandleClear(c fiber.Ctx) error {
+	name := c.Params("name")
+
+	h.log.Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("container", name).
+		Str("notify", "no").
+		Msg("Received HTTP API quarantine clear request")
+
+	if !h.clearFunc(name) {
+		err := c.Status(fiber.StatusNotFound).SendString("no quarantined image for container " + name)
+		if err != nil {
+			return fmt.Errorf("failed to send error response: %w", err)
+		}
+
+		return nil
+	}
+
+	err := c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"cleared":     name,
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"api_version": "v1",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send JSON response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package quarantine
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testLogger() *zerolog.Logger {
+	n := zerolog.Nop()
+
+	return &n
+}
+
+// newTestApp serves both quarantine endpoints of h.
+func newTestApp(h *Handler) *fiber.App {
+	app := fiber.New(fiber.Config{})
+	app.Get(h.Path, h.HandleList)
+	app.Delete(h.ClearPath, h.HandleClear)
+
+	return app
+}
+
+func TestNew(t *testing.T) {
+	h := New(nil, nil, nil)
+	require.NotNil(t, h)
+	assert.Equal(t, "/v1/quarantine", h.Path)
+	assert.Equal(t, "/v1/quarantine/:name", h.ClearPath)
+}
+
+func TestHandler_HandleList(t *testing.T) {
+	until := time.Date(2025, 1, 20, 12, 0, 0, 0, time.UTC)
+
+	h := New(testLogger(), func() []Entry {
+		return []Entry{{
+			Container: "web",
+			Image:     "nginx:latest",
+			ImageID:   "sha256:bad",
+			Failures:  3,
+			LastError: "container exited",
+			Until:     until,
+		}}
+	}, nil)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/quarantine", nil)
+	resp, err := newTestApp(h).Test(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Quarantined []Entry `json:"quarantined"`
+		Count       int     `json:"count"`
+		APIVersion  string  `json:"api_version"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Len(t, body.Quarantined, 1)
+	assert.Equal(t, 1, body.Count)
+	assert.Equal(t, "v1", body.APIVersion)
+	assert.Equal(t, "sha256:bad", body.Quarantined[0].ImageID)
+	assert.Equal(t, 3, body.Quarantined[0].Failures)
+	assert.True(t, until.Equal(body.Quarantined[0].Until))
+}
+
+func TestHandler_HandleClear(t *testing.T) {
+	var gotName string
+
+	h := New(testLogger(), nil, func(name string) bool {
+		gotName = name
+
+		return true
+	})
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/v1/quarantine/web", nil)
+	resp, err := newTestApp(h).Test(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "web", gotName)
+
+	var body struct {
+		Cleared string `json:"cleared"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "web", body.Cleared)
+}
+
+func TestHandler_HandleClear_NotQuarantined(t *testing.T) {
+	h := New(testLogger(), nil, func(string) bool { return false })
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/v1/quarantine/web", nil)
+	resp, err := newTestApp(h).Test(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "web")
+}
@@ -0,0 +1,6 @@
+// Package rollback provides the HTTP API handlers that roll a container back
+// to the image it ran before its last update and unpin it again.
+//
+// Both handlers wait for the update lock, so a rollback never runs alongside
+// a scan, and return 503 when the request is cancelled while waiting.
+package rollback
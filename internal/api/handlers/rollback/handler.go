@@ -0,0 +1,169 @@
+package rollback
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrContainerNotFound indicates no monitored container has the requested name.
+	ErrContainerNotFound = errors.New("container not found")
+	// ErrNotApplicable indicates the container has no previous image to roll back to, or is not pinned.
+	ErrNotApplicable = errors.New("rollback not applicable")
+)
+
+// Result describes a rolled back or unpinned container.
+type Result struct {
+	// Container is the container name.
+	Container string `json:"container"`
+	// ContainerID is the ID of the recreated container.
+	ContainerID string `json:"container_id"`
+	// FromImageID is the image the container ran before.
+	FromImageID string `json:"from_image_id"`
+	// ToImageID is the image the container runs now.
+	ToImageID string `json:"to_image_id"`
+	// PinnedImageID is the image updates skip, empty after an unpin.
+	PinnedImageID string `json:"pinned_image_id,omitempty"`
+}
+
+// Func rolls back a container, or unpins it when unpin is true.
+type Func func(ctx context.Context, name string, unpin bool) (Result, error)
+
+// Handler serves the /v1/containers/{name}/rollback and /v1/containers/{name}/unpin endpoints.
+type Handler struct {
+	log *zerolog.Logger
+
+	fn        Func
+	lock      chan bool
+	Path      string
+	UnpinPath string
+}
+
+// New creates a new rollback handler.
+//
+// Parameters:
+//   - fn: Function that rolls back or unpins a container.
+//   - updateLock: Lock channel shared with update scans. If nil, a new channel is created.
+func New(log *zerolog.Logger, fn Func, updateLock chan bool) *Handler {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	if updateLock == nil {
+		updateLock = make(chan bool, 1)
+		updateLock <- true
+	}
+
+	return &Handler{
+		log:       log,
+		fn:        fn,
+		lock:      updateLock,
+		Path:      "/v1/containers/:name/rollback",
+		UnpinPath: "/v1/containers/:name/unpin",
+	}
+}
+
+// HandleRollback rolls a container back to the image it ran before its last update.
+//
+//	@Summary		Roll back a container
+//	@Description	Recreates the container from the image it ran before its last update and pins it, so later scans skip the image it was rolled back from until it is unpinned. Waits for a running scan to finish.
+//	@Tags			containers
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string					true	"Container name"
+//	@Success		200		{object}	map[string]interface{}	"Rolled back container with its images"
+//	@Failure		401		{string}	string					"Missing or invalid API token"
+//	@Failure		404		{string}	string					"Container not found"
+//	@Failure		409		{string}	string					"No previous image recorded for the container"
+//	@Failure		500		{string}	string					"Rollback failed"
+//	@Failure		503		{string}	string					"Request cancelled while waiting for lock"
+//	@Security		BearerAuth
+//	@Router			/v1/containers/{name}/rollback [post]
+func (h *Handler) HandleRollback(c fiber.Ctx) error {
+	return h.handle(c, false)
+}
+
+// HandleUnpin lets a rolled back container be updated to the image it was rolled back from again.
+//
+//	@Summary		Unpin a rolled back container
+//	@Description	Recreates a rolled back container from the image it runs without its pin, so the next scan may update it again. Waits for a running scan to finish.
+//	@Tags			containers
+//	@Accept			json
+//	@Produce		json
+//	@Param			name	path		string					true	"Container name"
+//	@Success		200		{object}	map[string]interface{}	"Unpinned container"
+//	@Failure		401		{string}	string					"Missing or invalid API token"
+//	@Failure		404		{string}	string					"Container not found"
+//	@Failure		409		{string}	string					"Container is not pinned"
+//	@Failure		500		{string}	string					"Unpin failed"
+//	@Failure		503		{string}	string					"Request cancelled while waiting for lock"
+//	@Security		BearerAuth
+//	@Router			/v1/containers/{name}/unpin [post]
+func (h *Handler) HandleUnpin(c fiber.Ctx) error {
+	return h.handle(c, true)
+}
+
+// handle waits for the update lock, runs the rollback function, and writes the result.
+func (h *Handler) handle(c fiber.Ctx, unpin bool) error {
+	name := c.Params("name")
+
+	h.log.Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("container", name).
+		Str("notify", "no").
+		Msg("Received HTTP API rollback request")
+
+	var token bool
+
+	select {
+	case token = <-h.lock:
+	case <-c.Context().Done():
+		return fiber.ErrServiceUnavailable
+	}
+
+	defer func() { h.lock <- token }()
+
+	result, err := h.fn(c.Context(), name, unpin)
+	if err != nil {
+		h.log.Error().
+			Err(err).
+			Str("container", name).
+			Bool("unpin", unpin).
+			Str("notify", "no").
+			Msg("HTTP API rollback request failed")
+
+		status := fiber.StatusInternalServerError
+
+		switch {
+		case errors.Is(err, ErrContainerNotFound):
+			status = fiber.StatusNotFound
+		case errors.Is(err, ErrNotApplicable):
+			status = fiber.StatusConflict
+		}
+
+		sendErr := c.Status(status).SendString(err.Error())
+		if sendErr != nil {
+			return fmt.Errorf("failed to send error response: %w", sendErr)
+		}
+
+		return nil
+	}
+
+	err = c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"rollback":    result,
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+		"api_version": "v1",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send JSON response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package rollback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errRecreateFailed = errors.New("failed to create container")
+
+func testLogger() *zerolog.Logger {
+	n := zerolog.Nop()
+
+	return &n
+}
+
+// newTestApp serves both rollback endpoints of h.
+func newTestApp(h *Handler) *fiber.App {
+	app := fiber.New(fiber.Config{})
+	app.Post(h.Path, h.HandleRollback)
+	app.Post(h.UnpinPath, h.HandleUnpin)
+
+	return app
+}
+
+func TestNew(t *testing.T) {
+	h := New(nil, nil, nil)
+	require.NotNil(t, h)
+	assert.Equal(t, "/v1/containers/:name/rollback", h.Path)
+	assert.Equal(t, "/v1/containers/:name/unpin", h.UnpinPath)
+	assert.Len(t, h.lock, 1)
+}
+
+func TestHandler_HandleRollback(t *testing.T) {
+	var (
+		gotName  string
+		gotUnpin bool
+	)
+
+	h := New(testLogger(), func(_ context.Context, name string, unpin bool) (Result, error) {
+		gotName, gotUnpin = name, unpin
+
+		return Result{
+			Container:     name,
+			ContainerID:   "new-id",
+			FromImageID:   "sha256:bad",
+			ToImageID:     "sha256:good",
+			PinnedImageID: "sha256:bad",
+		}, nil
+	}, nil)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/containers/web/rollback", nil)
+	resp, err := newTestApp(h).Test(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "web", gotName)
+	assert.False(t, gotUnpin)
+
+	var body struct {
+		Rollback Result `json:"rollback"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "sha256:good", body.Rollback.ToImageID)
+	assert.Equal(t, "sha256:bad", body.Rollback.PinnedImageID)
+	assert.Len(t, h.lock, 1, "lock should be released")
+}
+
+func TestHandler_HandleUnpin(t *testing.T) {
+	var gotUnpin bool
+
+	h := New(testLogger(), func(_ context.Context, name string, unpin bool) (Result, error) {
+		gotUnpin = unpin
+
+		return Result{Container: name}, nil
+	}, nil)
+
+	req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/containers/web/unpin", nil)
+	resp, err := newTestApp(h).Test(req)
+	require.NoError(t, err)
+
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.True(t, gotUnpin)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"container not found", fmt.Errorf("%w: web", ErrContainerNotFound), http.StatusNotFound},
+		{"not applicable", fmt.Errorf("%w: no previous image", ErrNotApplicable), http.StatusConflict},
+		{"rollback failed", errRecreateFailed, http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(testLogger(), func(context.Context, string, bool) (Result, error) {
+				return Result{}, tt.err
+			}, nil)
+
+			req := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/v1/containers/web/rollback", nil)
+			resp, err := newTestApp(h).Test(req)
+			require.NoError(t, err)
+
+			defer resp.Body.Close()
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.err.Error(), string(body))
+		})
+	}
+}
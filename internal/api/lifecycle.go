@@ -76,7 +76,9 @@ func SetupAndStartAPI(ctx context.Context, opts config.Options) error {
 		!opts.EnableHistoryAPI &&
 		!opts.EnableImagesAPI &&
 		!opts.EnableConfigAPI &&
-		!opts.EnableEventsAPI {
+		!opts.EnableEventsAPI &&
+		!opts.EnableOutboxAPI &&
+		!opts.EnableQuarantineAPI {
 		return nil
 	}
 
@@ -88,7 +90,9 @@ func SetupAndStartAPI(ctx context.Context, opts config.Options) error {
 		opts.EnableCheckAPI ||
 		opts.EnableHistoryAPI ||
 		opts.EnableImagesAPI ||
-		opts.EnableConfigAPI
+		opts.EnableConfigAPI ||
+		opts.EnableOutboxAPI ||
+		opts.EnableQuarantineAPI
 
 	shouldRequireEventsToken := opts.EnableEventsAPI
 
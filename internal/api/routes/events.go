@@ -15,6 +15,11 @@ import (
 
 func registerEventsRoute(app *fiber.App, opts config.Options) {
 	handler := events.NewHandler(opts.Logger, opts.EventBroadcaster, opts.CORSAllowedOrigins)
+	if opts.EventsFormat != "" {
+		handler.Format = opts.EventsFormat
+	}
+
+	handler.Source = opts.EventsSource
 
 	eventsToken := opts.EventsToken
 	expectedHash := sha256.Sum256([]byte(eventsToken))
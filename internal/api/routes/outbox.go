@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/nicholas-fedor/watchtower/internal/api/config"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/outbox"
+	"github.com/nicholas-fedor/watchtower/pkg/notifications"
+)
+
+func registerOutboxRoute(app *fiber.App, auth fiber.Handler, opts config.Options) {
+	handler := outbox.New(opts.Logger, func() []notifications.OutboxMessage {
+		return notifications.OutboxMessages(opts.Notifier)
+	})
+	app.Get(handler.Path, auth, config.TimeoutMiddleware(), handler.Handle)
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nicholas-fedor/watchtower/internal/api/config"
+)
+
+func TestRegisterOutboxRoute(t *testing.T) {
+	app := testApp()
+	auth := testAuthMiddleware()
+
+	opts := config.Options{
+		EnableOutboxAPI: true,
+	}
+
+	registerOutboxRoute(app, auth, opts)
+
+	routes := app.GetRoutes()
+	found := false
+
+	for _, r := range routes {
+		if r.Path == "/v1/notifications/outbox" && r.Method == http.MethodGet {
+			found = true
+
+			break
+		}
+	}
+
+	assert.True(t, found, "GET /v1/notifications/outbox should be registered")
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+
+	"github.com/nicholas-fedor/watchtower/internal/api/config"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/quarantine"
+)
+
+func registerQuarantineRoutes(app *fiber.App, auth fiber.Handler, opts config.Options) {
+	list := opts.QuarantineList
+	if list == nil {
+		list = func() []quarantine.Entry { return nil }
+	}
+
+	clearFunc := opts.QuarantineClear
+	if clearFunc == nil {
+		clearFunc = func(string) bool { return false }
+	}
+
+	handler := quarantine.New(opts.Logger, list, clearFunc)
+	app.Get(handler.Path, auth, config.TimeoutMiddleware(), handler.HandleList)
+	app.Delete(handler.ClearPath, auth, config.TimeoutMiddleware(), handler.HandleClear)
+}
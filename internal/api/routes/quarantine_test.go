@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nicholas-fedor/watchtower/internal/api/config"
+)
+
+func TestRegisterQuarantineRoutes(t *testing.T) {
+	app := testApp()
+
+	registerQuarantineRoutes(app, testAuthMiddleware(), config.Options{})
+
+	registered := map[string]bool{}
+
+	for _, r := range app.GetRoutes() {
+		registered[r.Method+" "+r.Path] = true
+	}
+
+	assert.True(t, registered[http.MethodGet+" /v1/quarantine"], "GET /v1/quarantine should be registered")
+	assert.True(t, registered[http.MethodDelete+" /v1/quarantine/:name"], "DELETE /v1/quarantine/:name should be registered")
+}
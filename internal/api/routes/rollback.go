@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"github.com/gofiber/fiber/v3/middleware/timeout"
+
+	"github.com/nicholas-fedor/watchtower/internal/api/config"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/rollback"
+)
+
+func registerRollbackRoutes(app *fiber.App, auth fiber.Handler, opts config.Options) {
+	if opts.Rollback == nil {
+		return
+	}
+
+	updateTimeout := opts.UpdateTimeout
+	if updateTimeout <= 0 {
+		updateTimeout = config.DefaultUpdateTimeout
+	}
+
+	handler := rollback.New(opts.Logger, opts.Rollback, opts.UpdateLock)
+	timeoutConfig := timeout.Config{Timeout: updateTimeout}
+
+	app.Post(handler.Path, auth, timeout.New(handler.HandleRollback, timeoutConfig))
+	app.Post(handler.UnpinPath, auth, timeout.New(handler.HandleUnpin, timeoutConfig))
+}
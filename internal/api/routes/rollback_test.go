@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/nicholas-fedor/watchtower/internal/api/config"
+	"github.com/nicholas-fedor/watchtower/internal/api/handlers/rollback"
+)
+
+func TestRegisterRollbackRoutes(t *testing.T) {
+	app := testApp()
+	auth := testAuthMiddleware()
+
+	opts := config.Options{
+		Rollback: func(context.Context, string, bool) (rollback.Result, error) {
+			return rollback.Result{}, nil
+		},
+	}
+
+	registerRollbackRoutes(app, auth, opts)
+
+	registered := map[string]bool{}
+
+	for _, r := range app.GetRoutes() {
+		if r.Method == http.MethodPost {
+			registered[r.Path] = true
+		}
+	}
+
+	assert.True(t, registered["/v1/containers/:name/rollback"], "POST /v1/containers/:name/rollback should be registered")
+	assert.True(t, registered["/v1/containers/:name/unpin"], "POST /v1/containers/:name/unpin should be registered")
+}
+
+func TestRegisterRollbackRoutes_Disabled(t *testing.T) {
+	app := testApp()
+
+	registerRollbackRoutes(app, testAuthMiddleware(), config.Options{})
+
+	for _, r := range app.GetRoutes() {
+		assert.NotContains(t, r.Path, "/rollback")
+	}
+}
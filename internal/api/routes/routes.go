@@ -97,6 +97,7 @@ func Register(
 
 	if opts.EnableUpdateAPI {
 		registerUpdateRoute(ctx, app, auth, opts)
+		registerRollbackRoutes(app, auth, opts)
 	}
 
 	if opts.EnableMetricsAPI {
@@ -128,6 +129,14 @@ func Register(
 		registerEventsRoute(app, opts)
 	}
 
+	if opts.EnableOutboxAPI {
+		registerOutboxRoute(app, auth, opts)
+	}
+
+	if opts.EnableQuarantineAPI {
+		registerQuarantineRoutes(app, auth, opts)
+	}
+
 	if opts.EnableSwaggerAPI {
 		registerSwaggerRoute(app, opts)
 	}
@@ -21,6 +21,7 @@ func registerUpdateRoute(ctx context.Context, app *fiber.App, auth fiber.Handler
 
 	handler := update.NewWithTimeout(opts.Logger, func(updateCtx context.Context, images, containers []string) *mt.Metric {
 		params := config.BuildUpdateParams(opts)
+		params.Targeted = len(images) > 0 || len(containers) > 0
 
 		imageFilter := opts.FilterByImage(images, opts.Filter)
 
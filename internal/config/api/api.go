@@ -25,6 +25,8 @@ type API struct {
 	Token string
 	// EventsToken is the events SSE authentication token.
 	EventsToken string
+	// EventsFormat is the events SSE encoding ("native" or "cloudevents").
+	EventsFormat string
 	// PeriodicPolls keeps scheduled polls when the HTTP API is enabled.
 	PeriodicPolls bool
 	// RateLimit is max auth requests per minute per IP.
@@ -5,13 +5,18 @@ import (
 	"github.com/nicholas-fedor/watchtower/internal/config/client"
 	"github.com/nicholas-fedor/watchtower/internal/config/compatibility"
 	"github.com/nicholas-fedor/watchtower/internal/config/docker"
+	"github.com/nicholas-fedor/watchtower/internal/config/events"
 	"github.com/nicholas-fedor/watchtower/internal/config/filter"
+	"github.com/nicholas-fedor/watchtower/internal/config/heartbeat"
 	"github.com/nicholas-fedor/watchtower/internal/config/lifecycle"
 	"github.com/nicholas-fedor/watchtower/internal/config/logging"
+	"github.com/nicholas-fedor/watchtower/internal/config/metrics"
 	"github.com/nicholas-fedor/watchtower/internal/config/mode"
+	"github.com/nicholas-fedor/watchtower/internal/config/mqtt"
 	"github.com/nicholas-fedor/watchtower/internal/config/notify"
 	"github.com/nicholas-fedor/watchtower/internal/config/registry"
 	"github.com/nicholas-fedor/watchtower/internal/config/schedule"
+	"github.com/nicholas-fedor/watchtower/internal/config/tracing"
 	"github.com/nicholas-fedor/watchtower/internal/config/update"
 )
 
@@ -43,6 +48,16 @@ type Config struct {
 	API api.API
 	// Notify holds notification URLs and related options for notifications.NewNotifier.
 	Notify notify.Notify
+	// Events holds outbound CloudEvents sink settings.
+	Events events.Events
+	// Metrics holds Prometheus metric cardinality settings.
+	Metrics metrics.Metrics
+	// Tracing holds OpenTelemetry exporter and sampling settings.
+	Tracing tracing.Tracing
+	// Heartbeat holds dead-man's-switch ping settings.
+	Heartbeat heartbeat.Heartbeat
+	// MQTT holds MQTT publisher and Home Assistant discovery settings.
+	MQTT mqtt.MQTT
 	// Logging holds console log format and level settings.
 	Logging logging.Logging
 }
@@ -0,0 +1,14 @@
+// Package events holds outbound event delivery settings.
+package events
+
+import "time"
+
+// Events holds CloudEvents sink configuration.
+type Events struct {
+	// SinkURLs lists HTTP endpoints that receive every event.
+	SinkURLs []string
+	// SinkMode is the CloudEvents content mode ("structured" or "binary").
+	SinkMode string
+	// SinkTimeout bounds each sink request.
+	SinkTimeout time.Duration
+}
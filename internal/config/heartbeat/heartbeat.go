@@ -0,0 +1,14 @@
+// Package heartbeat holds dead-man's-switch heartbeat settings.
+package heartbeat
+
+import "time"
+
+// Heartbeat holds heartbeat ping configuration.
+type Heartbeat struct {
+	// URL is the monitor's ping URL; empty disables heartbeats.
+	URL string
+	// Format is the ping format ("healthchecks" or "uptime-kuma").
+	Format string
+	// Timeout bounds each ping.
+	Timeout time.Duration
+}
@@ -1,6 +1,8 @@
 // Package lifecycle holds pre/post update lifecycle hook settings.
 package lifecycle
 
+import "time"
+
 // Lifecycle holds lifecycle hook configuration.
 type Lifecycle struct {
 	// Enabled turns on pre- and post-update lifecycle hooks.
@@ -9,4 +11,18 @@ type Lifecycle struct {
 	UID int
 	// GID is the default GID for lifecycle hook commands.
 	GID int
+	// OutputLimit is the maximum bytes of hook output kept in reports.
+	OutputLimit int
+	// Redact is an extra regular expression redacted from hook output.
+	Redact string
+	// PreScanHook is the command or URL run by Watchtower before a scan.
+	PreScanHook string
+	// PreRestartBatchHook is the command or URL run before the first container is stopped.
+	PreRestartBatchHook string
+	// PostRestartBatchHook is the command or URL run after the last container is started.
+	PostRestartBatchHook string
+	// PostScanHook is the command or URL run by Watchtower after a scan.
+	PostScanHook string
+	// ScanHookTimeout bounds each scan hook.
+	ScanHookTimeout time.Duration
 }
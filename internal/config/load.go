@@ -1,8 +1,11 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,16 +18,22 @@ import (
 	"github.com/nicholas-fedor/watchtower/internal/config/client"
 	"github.com/nicholas-fedor/watchtower/internal/config/compatibility"
 	"github.com/nicholas-fedor/watchtower/internal/config/docker"
+	"github.com/nicholas-fedor/watchtower/internal/config/events"
 	"github.com/nicholas-fedor/watchtower/internal/config/filter"
+	"github.com/nicholas-fedor/watchtower/internal/config/heartbeat"
 	"github.com/nicholas-fedor/watchtower/internal/config/lifecycle"
 	"github.com/nicholas-fedor/watchtower/internal/config/logging"
+	"github.com/nicholas-fedor/watchtower/internal/config/metrics"
 	"github.com/nicholas-fedor/watchtower/internal/config/mode"
+	"github.com/nicholas-fedor/watchtower/internal/config/mqtt"
 	"github.com/nicholas-fedor/watchtower/internal/config/notify"
 	"github.com/nicholas-fedor/watchtower/internal/config/registry"
 	"github.com/nicholas-fedor/watchtower/internal/config/schedule"
+	"github.com/nicholas-fedor/watchtower/internal/config/tracing"
 	"github.com/nicholas-fedor/watchtower/internal/config/update"
 	"github.com/nicholas-fedor/watchtower/internal/flags"
 	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
+	applog "github.com/nicholas-fedor/watchtower/internal/logging"
 	"github.com/nicholas-fedor/watchtower/internal/util"
 	"github.com/nicholas-fedor/watchtower/pkg/filters"
 )
@@ -34,10 +43,70 @@ var (
 	ErrNegativeStopTimeout = errors.New("stop-timeout must be non-negative")
 	// ErrNegativeCooldownDelay indicates cooldown-delay was set to a negative duration.
 	ErrNegativeCooldownDelay = errors.New("cooldown-delay must be non-negative")
+	// ErrNegativeVolumeBackupKeep indicates volume-backup-keep was set to a negative count.
+	ErrNegativeVolumeBackupKeep = errors.New("volume-backup-keep must be non-negative")
+	// ErrNegativeCleanupKeep indicates cleanup-keep was set to a negative count.
+	ErrNegativeCleanupKeep = errors.New("cleanup-keep must be non-negative")
+	// ErrNegativeCleanupKeepDuration indicates cleanup-keep-duration was set to a negative duration.
+	ErrNegativeCleanupKeepDuration = errors.New("cleanup-keep-duration must be non-negative")
+	// ErrNegativeQuarantineAfter indicates quarantine-after was set to a negative count.
+	ErrNegativeQuarantineAfter = errors.New("quarantine-after must be non-negative")
+	// ErrInvalidQuarantineBackoff indicates quarantine-backoff or quarantine-max-backoff was not a positive duration.
+	ErrInvalidQuarantineBackoff = errors.New("quarantine backoff must be positive")
+	// ErrNegativeMaxUpdates indicates max-updates was set to a negative count.
+	ErrNegativeMaxUpdates = errors.New("max-updates must be non-negative")
+	// ErrNegativeCircuitBreakerFailures indicates circuit-breaker-failures was set to a negative count.
+	ErrNegativeCircuitBreakerFailures = errors.New("circuit-breaker-failures must be non-negative")
+	// ErrInvalidCircuitBreakerPercent indicates circuit-breaker-percent was outside 0-100.
+	ErrInvalidCircuitBreakerPercent = errors.New("circuit-breaker-percent must be between 0 and 100")
+	// ErrNegativeObservePeriod indicates observe-period was set to a negative duration.
+	ErrNegativeObservePeriod = errors.New("observe-period must be non-negative")
+	// ErrNegativeObserveMaxRestarts indicates observe-max-restarts was set to a negative count.
+	ErrNegativeObserveMaxRestarts = errors.New("observe-max-restarts must be non-negative")
+	// ErrNegativeObserveLogLines indicates observe-log-lines was set to a negative count.
+	ErrNegativeObserveLogLines = errors.New("observe-log-lines must be non-negative")
 	// ErrRollingRestartWithMonitorOnly indicates incompatible rolling-restart and monitor-only flags.
 	ErrRollingRestartWithMonitorOnly = errors.New(
 		"rolling-restart and monitor-only cannot both be enabled",
 	)
+	// ErrInvalidEventsFormat indicates an unsupported http-api-events-format value.
+	ErrInvalidEventsFormat = errors.New("http-api-events-format must be native or cloudevents")
+	// ErrInvalidMetricsContainerLabel indicates an unsupported metrics-container-labels value.
+	ErrInvalidMetricsContainerLabel = errors.New(
+		"metrics-container-labels accepts container, image, or none",
+	)
+	// ErrInvalidMetricsPushFormat indicates an unsupported metrics-push-format value.
+	ErrInvalidMetricsPushFormat = errors.New("metrics-push-format must be pushgateway or remote-write")
+	// ErrEmptyMetricsPushJob indicates metrics-push-url was set without a job label.
+	ErrEmptyMetricsPushJob = errors.New("metrics-push-job must not be empty")
+	// ErrInvalidEventsSinkMode indicates an unsupported events-sink-mode value.
+	ErrInvalidEventsSinkMode = errors.New("events-sink-mode must be structured or binary")
+	// ErrInvalidHeartbeatFormat indicates an unsupported heartbeat-format value.
+	ErrInvalidHeartbeatFormat = errors.New("heartbeat-format must be healthchecks or uptime-kuma")
+	// ErrNegativeNotificationReminderInterval indicates notification-reminder-interval was negative.
+	ErrNegativeNotificationReminderInterval = errors.New("notification-reminder-interval must be non-negative")
+	// ErrNegativeNotificationOutboxMaxAge indicates notification-outbox-max-age was negative.
+	ErrNegativeNotificationOutboxMaxAge = errors.New("notification-outbox-max-age must be non-negative")
+	// ErrInvalidNotificationOutboxRetryInterval indicates notification-outbox-retry-interval was not positive.
+	ErrInvalidNotificationOutboxRetryInterval = errors.New("notification-outbox-retry-interval must be positive")
+	// ErrInvalidNotificationQuietHours indicates a notification-quiet-hours range that is not HH:MM-HH:MM.
+	ErrInvalidNotificationQuietHours = errors.New("notification-quiet-hours ranges must be HH:MM-HH:MM")
+	// ErrInvalidNotificationQuietHoursTimezone indicates an unknown notification-quiet-hours-timezone.
+	ErrInvalidNotificationQuietHoursTimezone = errors.New("invalid notification-quiet-hours-timezone")
+	// ErrInvalidNotificationRoutes indicates notification-routes is not a valid JSON array of routes.
+	ErrInvalidNotificationRoutes = errors.New("notification-routes must be a JSON array of routes")
+	// ErrInvalidNotificationRoute indicates a notification route with no URLs or an unknown category.
+	ErrInvalidNotificationRoute = errors.New("invalid notification route")
+	// ErrInvalidTracingExporter indicates an unsupported tracing-exporter value.
+	ErrInvalidTracingExporter = errors.New("tracing-exporter must be none, otlp-grpc, or otlp-http")
+	// ErrInvalidTracingSampleRatio indicates a tracing-sample-ratio outside 0 to 1.
+	ErrInvalidTracingSampleRatio = errors.New("tracing-sample-ratio must be a number between 0 and 1")
+	// ErrNegativeLifecycleOutputLimit indicates lifecycle-output-limit was negative.
+	ErrNegativeLifecycleOutputLimit = errors.New("lifecycle-output-limit must be non-negative")
+	// ErrInvalidScanHookTimeout indicates scan-hook-timeout was not positive.
+	ErrInvalidScanHookTimeout = errors.New("scan-hook-timeout must be positive")
+	// ErrInvalidLifecycleRedact indicates lifecycle-redact is not a valid regular expression.
+	ErrInvalidLifecycleRedact = errors.New("lifecycle-redact must be a valid regular expression")
 )
 
 // Load reads resolved settings from a parsed Cobra command into Config.
@@ -99,7 +168,28 @@ func Load(log *zerolog.Logger, cmd *cobra.Command, args []string) (Config, error
 	cfg.Registry = loadRegistry(vip)
 	cfg.API = loadAPI(vip, flagSet)
 	cfg.Notify = loadNotify(vip, flagSet)
-	cfg.Logging = loadLogging(vip)
+
+	cfg.Notify.Routes, err = loadNotificationRoutes(vip)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg.Notify.QuietHours, err = loadQuietHours(vip)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg.Events = loadEvents(vip, flagSet)
+	cfg.Metrics = loadMetrics(vip, flagSet)
+
+	cfg.Tracing, err = loadTracing(vip)
+	if err != nil {
+		return Config{}, err
+	}
+
+	cfg.Heartbeat = loadHeartbeat(vip, flagSet)
+	cfg.MQTT = loadMQTT(vip)
+	cfg.Logging = loadLogging(vip, flagSet)
 
 	err = validate(log, cfg)
 	if err != nil {
@@ -189,26 +279,164 @@ func loadUpdate(log *zerolog.Logger, vip *viper.Viper, flagSet *pflag.FlagSet) (
 		cooldown = parsed
 	}
 
+	cleanupKeep := vip.GetInt("cleanup-keep")
+	if cleanupKeep < 0 {
+		return update.Update{}, ErrNegativeCleanupKeep
+	}
+
+	cleanupKeepStr := strings.TrimSpace(vip.GetString("cleanup-keep-duration"))
+
+	var cleanupKeepDuration time.Duration
+
+	if cleanupKeepStr != "" {
+		parsed, err := util.ParseDuration(cleanupKeepStr)
+		if err != nil {
+			return update.Update{}, fmt.Errorf("cleanup-keep-duration: %w", err)
+		}
+
+		if parsed < 0 {
+			return update.Update{}, ErrNegativeCleanupKeepDuration
+		}
+
+		cleanupKeepDuration = parsed
+	}
+
+	backupKeep := vip.GetInt("volume-backup-keep")
+	if backupKeep < 0 {
+		return update.Update{}, ErrNegativeVolumeBackupKeep
+	}
+
+	quarantineAfter := vip.GetInt("quarantine-after")
+	if quarantineAfter < 0 {
+		return update.Update{}, ErrNegativeQuarantineAfter
+	}
+
+	var (
+		quarantineBackoff, quarantineMaxBackoff time.Duration
+		err                                     error
+	)
+
+	if quarantineAfter > 0 {
+		quarantineBackoff, err = loadQuarantineBackoff(vip, "quarantine-backoff")
+		if err != nil {
+			return update.Update{}, err
+		}
+
+		quarantineMaxBackoff, err = loadQuarantineBackoff(vip, "quarantine-max-backoff")
+		if err != nil {
+			return update.Update{}, err
+		}
+	}
+
+	maxUpdates := vip.GetInt("max-updates")
+	if maxUpdates < 0 {
+		return update.Update{}, ErrNegativeMaxUpdates
+	}
+
+	breakerFailures := vip.GetInt("circuit-breaker-failures")
+	if breakerFailures < 0 {
+		return update.Update{}, ErrNegativeCircuitBreakerFailures
+	}
+
+	breakerPercent := vip.GetInt("circuit-breaker-percent")
+	if breakerPercent < 0 || breakerPercent > 100 {
+		return update.Update{}, ErrInvalidCircuitBreakerPercent
+	}
+
+	observePeriodStr := strings.TrimSpace(vip.GetString("observe-period"))
+
+	var observePeriod time.Duration
+
+	if observePeriodStr != "" {
+		parsed, err := util.ParseDuration(observePeriodStr)
+		if err != nil {
+			return update.Update{}, fmt.Errorf("observe-period: %w", err)
+		}
+
+		if parsed < 0 {
+			return update.Update{}, ErrNegativeObservePeriod
+		}
+
+		observePeriod = parsed
+	}
+
+	observeMaxRestarts := vip.GetInt("observe-max-restarts")
+	if observeMaxRestarts < 0 {
+		return update.Update{}, ErrNegativeObserveMaxRestarts
+	}
+
+	observeLogLines := vip.GetInt("observe-log-lines")
+	if observeLogLines < 0 {
+		return update.Update{}, ErrNegativeObserveLogLines
+	}
+
 	return update.Update{
-		Cleanup:             vip.GetBool("cleanup"),
-		NoPull:              vip.GetBool("no-pull"),
-		NoRestart:           vip.GetBool("no-restart"),
-		MonitorOnly:         vip.GetBool("monitor-only"),
-		RollingRestart:      vip.GetBool("rolling-restart"),
-		StopTimeout:         stopTimeout,
-		CooldownDelay:       cooldown,
-		UseComposeDependsOn: vip.GetBool("use-compose-depends-on"),
-		LabelPrecedence:     vip.GetBool("label-take-precedence"),
-		EphemeralSelfUpdate: vip.GetBool("ephemeral-self-update"),
+		Cleanup:                vip.GetBool("cleanup"),
+		CleanupKeep:            cleanupKeep,
+		CleanupKeepDuration:    cleanupKeepDuration,
+		CleanupStateFile:       strings.TrimSpace(vip.GetString("cleanup-state-file")),
+		CleanupDangling:        vip.GetBool("cleanup-dangling"),
+		NoPull:                 vip.GetBool("no-pull"),
+		NoRestart:              vip.GetBool("no-restart"),
+		MonitorOnly:            vip.GetBool("monitor-only"),
+		RollingRestart:         vip.GetBool("rolling-restart"),
+		StopTimeout:            stopTimeout,
+		CooldownDelay:          cooldown,
+		UseComposeDependsOn:    vip.GetBool("use-compose-depends-on"),
+		LabelPrecedence:        vip.GetBool("label-take-precedence"),
+		EphemeralSelfUpdate:    vip.GetBool("ephemeral-self-update"),
+		VolumeBackupDir:        strings.TrimSpace(vip.GetString("volume-backup-dir")),
+		VolumeBackupImage:      strings.TrimSpace(vip.GetString("volume-backup-image")),
+		VolumeBackupKeep:       backupKeep,
+		QuarantineAfter:        quarantineAfter,
+		QuarantineBackoff:      quarantineBackoff,
+		QuarantineMaxBackoff:   quarantineMaxBackoff,
+		MaxUpdates:             maxUpdates,
+		CircuitBreakerFailures: breakerFailures,
+		CircuitBreakerPercent:  breakerPercent,
+		ObservePeriod:          observePeriod,
+		ObserveMaxRestarts:     observeMaxRestarts,
+		ObserveLogLines:        observeLogLines,
+		RevertOnCrash:          vip.GetBool("revert-on-crash"),
+		LockDir:                strings.TrimSpace(vip.GetString("lock-dir")),
 	}, nil
 }
 
+// loadQuarantineBackoff reads a quarantine period from Viper.
+//
+// Parameters:
+//   - vip: Viper instance bound to the command flags.
+//   - key: Setting name, quarantine-backoff or quarantine-max-backoff.
+//
+// Returns:
+//   - time.Duration: The period.
+//   - error: Non-nil if the value is not a positive duration.
+func loadQuarantineBackoff(vip *viper.Viper, key string) (time.Duration, error) {
+	parsed, err := util.ParseDuration(strings.TrimSpace(vip.GetString(key)))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+
+	if parsed <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidQuarantineBackoff, key)
+	}
+
+	return parsed, nil
+}
+
 // loadLifecycle reads lifecycle hook settings from Viper.
 func loadLifecycle(vip *viper.Viper) lifecycle.Lifecycle {
 	return lifecycle.Lifecycle{
-		Enabled: vip.GetBool("enable-lifecycle-hooks"),
-		UID:     vip.GetInt("lifecycle-uid"),
-		GID:     vip.GetInt("lifecycle-gid"),
+		Enabled:              vip.GetBool("enable-lifecycle-hooks"),
+		UID:                  vip.GetInt("lifecycle-uid"),
+		GID:                  vip.GetInt("lifecycle-gid"),
+		OutputLimit:          vip.GetInt("lifecycle-output-limit"),
+		Redact:               vip.GetString("lifecycle-redact"),
+		PreScanHook:          vip.GetString("pre-scan-hook"),
+		PreRestartBatchHook:  vip.GetString("pre-restart-batch-hook"),
+		PostRestartBatchHook: vip.GetString("post-restart-batch-hook"),
+		PostScanHook:         vip.GetString("post-scan-hook"),
+		ScanHookTimeout:      vip.GetDuration("scan-hook-timeout"),
 	}
 }
 
@@ -338,6 +566,7 @@ func loadAPI(vip *viper.Viper, flagSet *pflag.FlagSet) api.API {
 		PortChanged:      flagChanged(flagSet, "http-api-port"),
 		Token:            vip.GetString("http-api-token"),
 		EventsToken:      vip.GetString("http-api-events-token"),
+		EventsFormat:     strings.ToLower(vip.GetString("http-api-events-format")),
 		PeriodicPolls:    vip.GetBool("http-api-periodic-polls"),
 		RateLimit:        vip.GetInt("http-api-rate-limit"),
 		RateLimitChanged: flagChanged(flagSet, "http-api-rate-limit"),
@@ -380,17 +609,47 @@ func loadNotify(vip *viper.Viper, flagSet *pflag.FlagSet) notify.Notify {
 			[]string{"WATCHTOWER_NOTIFICATIONS"},
 			spec.ListCommaOrSpace,
 		),
-		Level:            vip.GetString("notifications-level"),
-		Template:         vip.GetString("notification-template"),
-		TemplateFile:     vip.GetString("notification-template-file"),
-		Report:           vip.GetBool("notification-report"),
-		SplitByContainer: vip.GetBool("notification-split-by-container"),
-		SkipTitle:        vip.GetBool("notification-skip-title"),
-		LogStdout:        vip.GetBool("notification-log-stdout"),
-		DelaySeconds:     vip.GetInt("notifications-delay"),
-		Hostname:         vip.GetString("notifications-hostname"),
-		TitleTag:         vip.GetString("notification-title-tag"),
-		EmailSubjectTag:  vip.GetString("notification-email-subjecttag"),
+		Level:               vip.GetString("notifications-level"),
+		Template:            vip.GetString("notification-template"),
+		TemplateFile:        vip.GetString("notification-template-file"),
+		TemplateDir:         vip.GetString("notification-template-dir"),
+		Report:              vip.GetBool("notification-report"),
+		SplitByContainer:    vip.GetBool("notification-split-by-container"),
+		SkipTitle:           vip.GetBool("notification-skip-title"),
+		LogStdout:           vip.GetBool("notification-log-stdout"),
+		DelaySeconds:        vip.GetInt("notifications-delay"),
+		Hostname:            vip.GetString("notifications-hostname"),
+		TitleTag:            vip.GetString("notification-title-tag"),
+		EmailSubjectTag:     vip.GetString("notification-email-subjecttag"),
+		SuppressRepeats:     vip.GetBool("notification-suppress-repeats"),
+		SuppressRepeatsFile: vip.GetString("notification-suppress-repeats-file"),
+		ReminderInterval: durationValue(
+			vip, flagSet, "notification-reminder-interval",
+			[]string{"WATCHTOWER_NOTIFICATION_REMINDER_INTERVAL"},
+		),
+		Digest: notify.Digest{
+			Schedule:          strings.TrimSpace(vip.GetString("notification-digest-schedule")),
+			File:              vip.GetString("notification-digest-file"),
+			Template:          vip.GetString("notification-digest-template"),
+			ImmediateFailures: vip.GetBool("notification-digest-immediate-failures"),
+		},
+		Outbox: notify.Outbox{
+			Dir: vip.GetString("notification-outbox-dir"),
+			MaxAge: durationValue(
+				vip, flagSet, "notification-outbox-max-age",
+				[]string{"WATCHTOWER_NOTIFICATION_OUTBOX_MAX_AGE"},
+			),
+			RetryInterval: durationValue(
+				vip, flagSet, "notification-outbox-retry-interval",
+				[]string{"WATCHTOWER_NOTIFICATION_OUTBOX_RETRY_INTERVAL"},
+			),
+		},
+		Email: notify.Email{
+			HTML:         vip.GetBool("notification-email-html"),
+			HTMLTemplate: vip.GetString("notification-email-html-template"),
+			TextTemplate: vip.GetString("notification-email-text-template"),
+			AttachReport: vip.GetBool("notification-email-attach-report"),
+		},
 		Legacy: notify.Legacy{
 			EmailFrom:           vip.GetString("notification-email-from"),
 			EmailTo:             vip.GetString("notification-email-to"),
@@ -413,8 +672,203 @@ func loadNotify(vip *viper.Viper, flagSet *pflag.FlagSet) notify.Notify {
 	}
 }
 
+// loadNotificationRoutes parses the notification-routes JSON array from Viper.
+func loadNotificationRoutes(vip *viper.Viper) ([]notify.Route, error) {
+	raw := strings.TrimSpace(vip.GetString("notification-routes"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var routes []notify.Route
+
+	err := json.Unmarshal([]byte(raw), &routes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidNotificationRoutes, err)
+	}
+
+	for i, route := range routes {
+		name := route.Name
+		if name == "" {
+			name = "#" + strconv.Itoa(i+1)
+		}
+
+		if len(route.URLs) == 0 {
+			return nil, fmt.Errorf("%w %s: no urls", ErrInvalidNotificationRoute, name)
+		}
+
+		for _, category := range route.Categories {
+			switch category {
+			case "scanned", "updated", "failed", "skipped", "stale", "fresh", "restarted":
+			default:
+				return nil, fmt.Errorf("%w %s: unknown category %q", ErrInvalidNotificationRoute, name, category)
+			}
+		}
+
+		if route.QuietHours == "" || strings.EqualFold(route.QuietHours, "off") {
+			continue
+		}
+
+		routes[i].QuietWindows, err = parseQuietWindows(route.QuietHours)
+		if err != nil {
+			return nil, fmt.Errorf("%w %s: %w", ErrInvalidNotificationRoute, name, err)
+		}
+	}
+
+	return routes, nil
+}
+
+// loadQuietHours reads the notification quiet hours settings from Viper.
+func loadQuietHours(vip *viper.Viper) (notify.QuietHours, error) {
+	windows, err := parseQuietWindows(vip.GetString("notification-quiet-hours"))
+	if err != nil {
+		return notify.QuietHours{}, err
+	}
+
+	location := time.Local
+
+	if zone := strings.TrimSpace(vip.GetString("notification-quiet-hours-timezone")); zone != "" {
+		location, err = time.LoadLocation(zone)
+		if err != nil {
+			return notify.QuietHours{}, fmt.Errorf("%w %q: %w", ErrInvalidNotificationQuietHoursTimezone, zone, err)
+		}
+	}
+
+	return notify.QuietHours{
+		Windows:       windows,
+		Location:      location,
+		AllowFailures: vip.GetBool("notification-quiet-hours-allow-failures"),
+		File:          vip.GetString("notification-quiet-hours-file"),
+	}, nil
+}
+
+// parseQuietWindows parses comma-separated HH:MM-HH:MM quiet hours ranges.
+//
+// Parameters:
+//   - raw: Ranges such as "22:00-07:00,12:00-13:00"; empty yields no windows.
+//
+// Returns:
+//   - []notify.QuietWindow: Parsed windows in the order given.
+//   - error: Non-nil if a range is malformed or empty.
+func parseQuietWindows(raw string) ([]notify.QuietWindow, error) {
+	var windows []notify.QuietWindow
+
+	for part := range strings.SplitSeq(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		rawStart, rawEnd, found := strings.Cut(part, "-")
+		if !found {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidNotificationQuietHours, part)
+		}
+
+		start, startErr := time.Parse("15:04", strings.TrimSpace(rawStart))
+		end, endErr := time.Parse("15:04", strings.TrimSpace(rawEnd))
+
+		if startErr != nil || endErr != nil || start.Equal(end) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidNotificationQuietHours, part)
+		}
+
+		windows = append(windows, notify.QuietWindow{
+			Start: time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+			End:   time.Duration(end.Hour())*time.Hour + time.Duration(end.Minute())*time.Minute,
+		})
+	}
+
+	return windows, nil
+}
+
+// loadEvents reads outbound event sink settings from Viper.
+func loadEvents(vip *viper.Viper, flagSet *pflag.FlagSet) events.Events {
+	return events.Events{
+		SinkURLs: stringSliceValue(
+			vip, flagSet, "events-sink-url",
+			[]string{"WATCHTOWER_EVENTS_SINK_URL"},
+			spec.ListCommaOrSpace,
+		),
+		SinkMode: strings.ToLower(vip.GetString("events-sink-mode")),
+		SinkTimeout: durationValue(
+			vip, flagSet, "events-sink-timeout",
+			[]string{"WATCHTOWER_EVENTS_SINK_TIMEOUT"},
+		),
+	}
+}
+
+// loadMetrics reads Prometheus metrics settings from Viper.
+func loadMetrics(vip *viper.Viper, flagSet *pflag.FlagSet) metrics.Metrics {
+	labels := stringSliceValue(
+		vip, flagSet, "metrics-container-labels",
+		[]string{"WATCHTOWER_METRICS_CONTAINER_LABELS"},
+		spec.ListCommaOrSpace,
+	)
+
+	for i, label := range labels {
+		labels[i] = strings.ToLower(label)
+	}
+
+	return metrics.Metrics{
+		ContainerLabels: labels,
+		PushURL:         vip.GetString("metrics-push-url"),
+		PushFormat:      strings.ToLower(vip.GetString("metrics-push-format")),
+		PushJob:         vip.GetString("metrics-push-job"),
+		PushInstance:    vip.GetString("metrics-push-instance"),
+		PushTimeout: durationValue(
+			vip, flagSet, "metrics-push-timeout",
+			[]string{"WATCHTOWER_METRICS_PUSH_TIMEOUT"},
+		),
+	}
+}
+
+// loadHeartbeat reads dead-man's-switch heartbeat settings from Viper.
+func loadHeartbeat(vip *viper.Viper, flagSet *pflag.FlagSet) heartbeat.Heartbeat {
+	return heartbeat.Heartbeat{
+		URL:    vip.GetString("heartbeat-url"),
+		Format: strings.ToLower(vip.GetString("heartbeat-format")),
+		Timeout: durationValue(
+			vip, flagSet, "heartbeat-timeout",
+			[]string{"WATCHTOWER_HEARTBEAT_TIMEOUT"},
+		),
+	}
+}
+
+// loadMQTT reads MQTT publisher and Home Assistant discovery settings from Viper.
+func loadMQTT(vip *viper.Viper) mqtt.MQTT {
+	return mqtt.MQTT{
+		BrokerURL:       vip.GetString("mqtt-broker-url"),
+		Username:        vip.GetString("mqtt-username"),
+		Password:        vip.GetString("mqtt-password"),
+		ClientID:        vip.GetString("mqtt-client-id"),
+		TopicPrefix:     vip.GetString("mqtt-topic-prefix"),
+		Discovery:       vip.GetBool("mqtt-discovery"),
+		DiscoveryPrefix: vip.GetString("mqtt-discovery-prefix"),
+		CAFile:          vip.GetString("mqtt-tls-ca-file"),
+		TLSSkipVerify:   vip.GetBool("mqtt-tls-skip-verify"),
+	}
+}
+
+// loadTracing reads OpenTelemetry tracing settings from Viper.
+func loadTracing(vip *viper.Viper) (tracing.Tracing, error) {
+	rawRatio := strings.TrimSpace(vip.GetString("tracing-sample-ratio"))
+	if rawRatio == "" {
+		rawRatio = "1"
+	}
+
+	ratio, err := strconv.ParseFloat(rawRatio, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return tracing.Tracing{}, fmt.Errorf("%w: %q", ErrInvalidTracingSampleRatio, rawRatio)
+	}
+
+	return tracing.Tracing{
+		Exporter:    strings.ToLower(vip.GetString("tracing-exporter")),
+		Endpoint:    vip.GetString("tracing-endpoint"),
+		Insecure:    vip.GetBool("tracing-insecure"),
+		SampleRatio: ratio,
+	}, nil
+}
+
 // loadLogging reads logging settings from Viper.
-func loadLogging(vip *viper.Viper) logging.Logging {
+func loadLogging(vip *viper.Viper, flagSet *pflag.FlagSet) logging.Logging {
 	format := vip.GetString("log-format")
 	if format == "" {
 		format = "auto"
@@ -426,6 +880,11 @@ func loadLogging(vip *viper.Viper) logging.Logging {
 		Debug:   vip.GetBool("debug"),
 		Trace:   vip.GetBool("trace"),
 		NoColor: vip.GetBool("no-color"),
+		Outputs: stringSliceValue(
+			vip, flagSet, "log-output",
+			[]string{"WATCHTOWER_LOG_OUTPUT"},
+			spec.ListCommaOrSpace,
+		),
 	}
 }
 
@@ -435,6 +894,82 @@ func validate(log *zerolog.Logger, cfg Config) error {
 		return ErrRollingRestartWithMonitorOnly
 	}
 
+	switch cfg.API.EventsFormat {
+	case "", "native", "cloudevents":
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidEventsFormat, cfg.API.EventsFormat)
+	}
+
+	for _, label := range cfg.Metrics.ContainerLabels {
+		switch label {
+		case "container", "image", "none":
+		default:
+			return fmt.Errorf("%w: %q", ErrInvalidMetricsContainerLabel, label)
+		}
+	}
+
+	if cfg.Lifecycle.OutputLimit < 0 {
+		return ErrNegativeLifecycleOutputLimit
+	}
+
+	if cfg.Lifecycle.ScanHookTimeout <= 0 {
+		return ErrInvalidScanHookTimeout
+	}
+
+	if cfg.Lifecycle.Redact != "" {
+		_, err := regexp.Compile(cfg.Lifecycle.Redact)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidLifecycleRedact, err)
+		}
+	}
+
+	if cfg.Notify.ReminderInterval < 0 {
+		return ErrNegativeNotificationReminderInterval
+	}
+
+	if cfg.Notify.Outbox.MaxAge < 0 {
+		return ErrNegativeNotificationOutboxMaxAge
+	}
+
+	if cfg.Notify.Outbox.Dir != "" && cfg.Notify.Outbox.RetryInterval <= 0 {
+		return ErrInvalidNotificationOutboxRetryInterval
+	}
+
+	switch cfg.Metrics.PushFormat {
+	case "", "pushgateway", "remote-write":
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidMetricsPushFormat, cfg.Metrics.PushFormat)
+	}
+
+	if cfg.Metrics.PushURL != "" && cfg.Metrics.PushJob == "" {
+		return ErrEmptyMetricsPushJob
+	}
+
+	switch cfg.Events.SinkMode {
+	case "", "structured", "binary":
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidEventsSinkMode, cfg.Events.SinkMode)
+	}
+
+	switch cfg.Heartbeat.Format {
+	case "", "healthchecks", "uptime-kuma":
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidHeartbeatFormat, cfg.Heartbeat.Format)
+	}
+
+	for _, output := range cfg.Logging.Outputs {
+		_, err := applog.ParseOutput(output)
+		if err != nil {
+			return fmt.Errorf("log-output %q: %w", output, err)
+		}
+	}
+
+	switch cfg.Tracing.Exporter {
+	case "", "none", "otlp-grpc", "otlp-http":
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidTracingExporter, cfg.Tracing.Exporter)
+	}
+
 	if cfg.Update.MonitorOnly && cfg.Update.NoPull {
 		log.Warn().
 			Bool("monitor_only", cfg.Update.MonitorOnly).
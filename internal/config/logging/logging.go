@@ -13,4 +13,6 @@ type Logging struct {
 	Trace bool
 	// NoColor disables ANSI color codes.
 	NoColor bool
+	// Outputs are additional log output URLs (syslog, GELF, or file), each with its own level and format.
+	Outputs []string
 }
@@ -0,0 +1,20 @@
+// Package metrics holds Prometheus metrics settings.
+package metrics
+
+import "time"
+
+// Metrics holds Prometheus metrics configuration.
+type Metrics struct {
+	// ContainerLabels lists the labels kept on per-container series; "none" drops all.
+	ContainerLabels []string
+	// PushURL is the Pushgateway or remote-write URL for run-once metrics; empty disables pushing.
+	PushURL string
+	// PushFormat is "pushgateway" or "remote-write".
+	PushFormat string
+	// PushJob is the job label of pushed metrics.
+	PushJob string
+	// PushInstance is the instance label of pushed metrics; empty uses the hostname.
+	PushInstance string
+	// PushTimeout bounds all push attempts together.
+	PushTimeout time.Duration
+}
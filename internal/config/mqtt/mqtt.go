@@ -0,0 +1,24 @@
+// Package mqtt holds MQTT and Home Assistant integration settings.
+package mqtt
+
+// MQTT holds MQTT publisher configuration.
+type MQTT struct {
+	// BrokerURL is the broker address (mqtt:// or mqtts://); empty disables MQTT.
+	BrokerURL string
+	// Username is the broker user.
+	Username string
+	// Password is the broker password.
+	Password string
+	// ClientID identifies the connection and the Home Assistant device; empty uses the hostname.
+	ClientID string
+	// TopicPrefix is the root of the state, summary, and command topics.
+	TopicPrefix string
+	// Discovery publishes Home Assistant MQTT discovery configs.
+	Discovery bool
+	// DiscoveryPrefix is Home Assistant's discovery prefix.
+	DiscoveryPrefix string
+	// CAFile is a PEM bundle used to verify the broker certificate.
+	CAFile string
+	// TLSSkipVerify disables broker certificate verification.
+	TLSSkipVerify bool
+}
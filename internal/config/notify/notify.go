@@ -1,6 +1,8 @@
 // Package notify holds notification settings for the config domain.
 package notify
 
+import "time"
+
 // Notify holds resolved notification configuration for the process.
 //
 // Values come from CLI flags and environment variables. Pass this value to
@@ -20,6 +22,9 @@ type Notify struct {
 	// TemplateFile is an optional path to a template file. When set it overrides Template
 	// (--notification-template-file / WATCHTOWER_NOTIFICATION_TEMPLATE_FILE).
 	TemplateFile string
+	// TemplateDir holds template files named by container notification-template labels
+	// (--notification-template-dir / WATCHTOWER_NOTIFICATION_TEMPLATE_DIR).
+	TemplateDir string
 	// Report enables report-based notification templates
 	// (--notification-report / WATCHTOWER_NOTIFICATION_REPORT).
 	Report bool
@@ -44,10 +49,133 @@ type Notify struct {
 	// EmailSubjectTag is the deprecated email subject tag fallback when TitleTag is empty
 	// (--notification-email-subjecttag / WATCHTOWER_NOTIFICATION_EMAIL_SUBJECTTAG).
 	EmailSubjectTag string
+	// Routes are notification routing rules parsed from a JSON array
+	// (--notification-routes / WATCHTOWER_NOTIFICATION_ROUTES).
+	Routes []Route
+	// SuppressRepeats announces an available update only once per container and image digest
+	// (--notification-suppress-repeats / WATCHTOWER_NOTIFICATION_SUPPRESS_REPEATS).
+	SuppressRepeats bool
+	// SuppressRepeatsFile persists the announced digests across restarts; empty keeps them in memory only
+	// (--notification-suppress-repeats-file / WATCHTOWER_NOTIFICATION_SUPPRESS_REPEATS_FILE).
+	SuppressRepeatsFile string
+	// ReminderInterval re-announces a still-available update after this long; zero never reminds
+	// (--notification-reminder-interval / WATCHTOWER_NOTIFICATION_REMINDER_INTERVAL).
+	ReminderInterval time.Duration
+	// Digest holds scheduled digest settings. Digest mode is off when Digest.Schedule is empty.
+	Digest Digest
+	// Outbox holds undeliverable message retry settings. The outbox is off when Outbox.Dir is empty.
+	Outbox Outbox
+	// QuietHours holds notifications produced during quiet windows. Off when QuietHours.Windows is empty.
+	QuietHours QuietHours
+	// Email holds HTML email settings for smtp:// URLs. Off when Email.HTML is false.
+	Email Email
 	// Legacy holds deprecated per-type notification settings used only when LegacyTypes is set.
 	Legacy Legacy
 }
 
+// Route sends the matching part of each notification to its own set of URLs.
+//
+// A container matches when every non-empty container criterion matches. The
+// matched report entries and log entries are rendered with the route's template
+// and sent to the route's URLs in addition to the default notification URLs,
+// unless Exclusive removes them from the default notification.
+type Route struct {
+	// Name identifies the route in logs.
+	Name string `json:"name"`
+	// Containers are container name glob patterns, matched with or without the leading slash.
+	Containers []string `json:"containers"`
+	// Labels are container labels that must be present; an empty value matches any value.
+	Labels map[string]string `json:"labels"`
+	// Projects are Docker Compose project names (com.docker.compose.project).
+	Projects []string `json:"projects"`
+	// Categories are report categories: scanned, updated, failed, skipped, stale, fresh, restarted.
+	Categories []string `json:"categories"`
+	// Level is the minimum log level of queued log entries sent to the route.
+	Level string `json:"level"`
+	// URLs are the Shoutrrr URLs the route sends to.
+	URLs []string `json:"urls"`
+	// Template is the route's text/template; empty uses the default template.
+	Template string `json:"template"`
+	// Exclusive removes matched containers and entries from the default notification.
+	Exclusive bool `json:"exclusive"`
+	// QuietHours overrides the quiet windows for the route: comma-separated HH:MM-HH:MM
+	// ranges, or "off". Empty uses the global quiet hours.
+	QuietHours string `json:"quiet_hours"`
+	// QuietWindows are the parsed QuietHours ranges, set by config.Load.
+	QuietWindows []QuietWindow `json:"-"`
+}
+
+// Digest accumulates scan reports and sends one summary notification on its own schedule.
+type Digest struct {
+	// Schedule is the cron expression for sending the digest; empty disables digest mode
+	// (--notification-digest-schedule / WATCHTOWER_NOTIFICATION_DIGEST_SCHEDULE).
+	Schedule string
+	// File persists the accumulated digest across restarts; empty keeps it in memory only
+	// (--notification-digest-file / WATCHTOWER_NOTIFICATION_DIGEST_FILE).
+	File string
+	// Template is the digest text/template or builtin name; empty uses the "digest" builtin
+	// (--notification-digest-template / WATCHTOWER_NOTIFICATION_DIGEST_TEMPLATE).
+	Template string
+	// ImmediateFailures still sends the regular notification for scans with failures
+	// (--notification-digest-immediate-failures / WATCHTOWER_NOTIFICATION_DIGEST_IMMEDIATE_FAILURES).
+	ImmediateFailures bool
+}
+
+// Outbox persists messages a service URL failed to accept and retries them with exponential backoff.
+type Outbox struct {
+	// Dir holds one backlog file per service URL; empty disables the outbox
+	// (--notification-outbox-dir / WATCHTOWER_NOTIFICATION_OUTBOX_DIR).
+	Dir string
+	// MaxAge drops undelivered messages older than this; zero keeps them until delivered
+	// (--notification-outbox-max-age / WATCHTOWER_NOTIFICATION_OUTBOX_MAX_AGE).
+	MaxAge time.Duration
+	// RetryInterval is the delay before the first retry, doubled after every failed retry
+	// (--notification-outbox-retry-interval / WATCHTOWER_NOTIFICATION_OUTBOX_RETRY_INTERVAL).
+	RetryInterval time.Duration
+}
+
+// QuietHours holds non-failure notifications produced inside a quiet window and
+// sends them as one combined message when the window ends.
+type QuietHours struct {
+	// Windows are the daily quiet windows; empty disables quiet hours
+	// (--notification-quiet-hours / WATCHTOWER_NOTIFICATION_QUIET_HOURS).
+	Windows []QuietWindow
+	// Location is the time zone the windows are read in
+	// (--notification-quiet-hours-timezone / WATCHTOWER_NOTIFICATION_QUIET_HOURS_TIMEZONE).
+	Location *time.Location
+	// AllowFailures sends notifications about failures right away during quiet hours
+	// (--notification-quiet-hours-allow-failures / WATCHTOWER_NOTIFICATION_QUIET_HOURS_ALLOW_FAILURES).
+	AllowFailures bool
+	// File persists notifications still held when Watchtower stops inside a quiet window;
+	// empty drops them (--notification-quiet-hours-file / WATCHTOWER_NOTIFICATION_QUIET_HOURS_FILE).
+	File string
+}
+
+// Email sends smtp:// notifications as multipart/alternative emails with a
+// plain-text and an HTML part instead of through Shoutrrr's plain-text smtp service.
+type Email struct {
+	// HTML enables multipart HTML email for smtp:// URLs
+	// (--notification-email-html / WATCHTOWER_NOTIFICATION_EMAIL_HTML).
+	HTML bool
+	// HTMLTemplate is the html/template for the HTML part; empty uses the default template
+	// (--notification-email-html-template / WATCHTOWER_NOTIFICATION_EMAIL_HTML_TEMPLATE).
+	HTMLTemplate string
+	// TextTemplate is the text/template for the plain-text part; empty uses the notification template
+	// (--notification-email-text-template / WATCHTOWER_NOTIFICATION_EMAIL_TEXT_TEMPLATE).
+	TextTemplate string
+	// AttachReport attaches the notification data as a JSON file
+	// (--notification-email-attach-report / WATCHTOWER_NOTIFICATION_EMAIL_ATTACH_REPORT).
+	AttachReport bool
+}
+
+// QuietWindow is a daily time range, as offsets from midnight.
+//
+// A window whose End is not after its Start spans midnight.
+type QuietWindow struct {
+	Start time.Duration
+	End   time.Duration
+}
+
 // Legacy holds deprecated notification-type-specific settings.
 //
 // These fields support the legacy email, Slack, MSTeams, and Gotify flag sets.
@@ -15,4 +15,6 @@ type RunOverrides struct {
 	SkipSelfUpdate bool
 	// CurrentContainerID is the running Watchtower container ID when known.
 	CurrentContainerID types.ContainerID
+	// Targeted marks a scan narrowed to named containers or images.
+	Targeted bool
 }
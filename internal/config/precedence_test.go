@@ -3,6 +3,7 @@ package config_test
 import (
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/nicholas-fedor/watchtower/internal/config"
+	"github.com/nicholas-fedor/watchtower/internal/config/notify"
 	"github.com/nicholas-fedor/watchtower/internal/flags"
 	"github.com/nicholas-fedor/watchtower/internal/logging"
 )
@@ -122,6 +124,375 @@ func TestLoad_CooldownExtendedUnits(t *testing.T) {
 	assert.Equal(t, 72*time.Hour, cfg.Update.CooldownDelay)
 }
 
+func TestLoad_CleanupRetention(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+
+	assert.Zero(t, cfg.Update.CleanupKeep)
+	assert.Zero(t, cfg.Update.CleanupKeepDuration)
+	assert.Empty(t, cfg.Update.CleanupStateFile)
+	assert.False(t, cfg.Update.CleanupDangling)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_CLEANUP_KEEP":          "2",
+		"WATCHTOWER_CLEANUP_KEEP_DURATION": "7d",
+		"WATCHTOWER_CLEANUP_STATE_FILE":    "/data/retained-images.json",
+		"WATCHTOWER_CLEANUP_DANGLING":      "true",
+	})
+
+	assert.Equal(t, 2, cfg.Update.CleanupKeep)
+	assert.Equal(t, 7*24*time.Hour, cfg.Update.CleanupKeepDuration)
+	assert.Equal(t, "/data/retained-images.json", cfg.Update.CleanupStateFile)
+	assert.True(t, cfg.Update.CleanupDangling)
+
+	cmd := &cobra.Command{Use: "watchtower"}
+
+	flags.SetDefaults()
+	flags.RegisterAll(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--cleanup-keep", "-1"}))
+
+	_, err := config.Load(testLogger(), cmd, nil)
+	require.ErrorIs(t, err, config.ErrNegativeCleanupKeep)
+}
+
+func TestLoad_LockDir(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+	assert.Empty(t, cfg.Update.LockDir)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_LOCK_DIR": "/var/run/watchtower",
+	})
+	assert.Equal(t, "/var/run/watchtower", cfg.Update.LockDir)
+}
+
+func TestLoad_Quarantine(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+
+	assert.Zero(t, cfg.Update.QuarantineAfter)
+	assert.Zero(t, cfg.Update.QuarantineBackoff)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_QUARANTINE_AFTER":       "3",
+		"WATCHTOWER_QUARANTINE_MAX_BACKOFF": "2w",
+	}, "--quarantine-backoff", "30m")
+
+	assert.Equal(t, 3, cfg.Update.QuarantineAfter)
+	assert.Equal(t, 30*time.Minute, cfg.Update.QuarantineBackoff)
+	assert.Equal(t, 14*24*time.Hour, cfg.Update.QuarantineMaxBackoff)
+
+	cmd := &cobra.Command{Use: "watchtower"}
+
+	flags.SetDefaults()
+	flags.RegisterAll(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--quarantine-after", "2", "--quarantine-backoff", "0s"}))
+
+	_, err := config.Load(testLogger(), cmd, nil)
+	require.ErrorIs(t, err, config.ErrInvalidQuarantineBackoff)
+}
+
+func TestLoad_BlastRadiusLimits(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+
+	assert.Zero(t, cfg.Update.MaxUpdates)
+	assert.Zero(t, cfg.Update.CircuitBreakerFailures)
+	assert.Zero(t, cfg.Update.CircuitBreakerPercent)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_MAX_UPDATES":              "5",
+		"WATCHTOWER_CIRCUIT_BREAKER_FAILURES": "3",
+	}, "--circuit-breaker-percent", "40")
+
+	assert.Equal(t, 5, cfg.Update.MaxUpdates)
+	assert.Equal(t, 3, cfg.Update.CircuitBreakerFailures)
+	assert.Equal(t, 40, cfg.Update.CircuitBreakerPercent)
+
+	cmd := &cobra.Command{Use: "watchtower"}
+
+	flags.SetDefaults()
+	flags.RegisterAll(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--circuit-breaker-percent", "150"}))
+
+	_, err := config.Load(testLogger(), cmd, nil)
+	require.ErrorIs(t, err, config.ErrInvalidCircuitBreakerPercent)
+}
+
+func TestLoad_CrashObservation(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+
+	assert.Zero(t, cfg.Update.ObservePeriod)
+	assert.Zero(t, cfg.Update.ObserveMaxRestarts)
+	assert.Equal(t, 20, cfg.Update.ObserveLogLines)
+	assert.False(t, cfg.Update.RevertOnCrash)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_OBSERVE_PERIOD":       "2m",
+		"WATCHTOWER_OBSERVE_MAX_RESTARTS": "1",
+	}, "--observe-log-lines", "50", "--revert-on-crash")
+
+	assert.Equal(t, 2*time.Minute, cfg.Update.ObservePeriod)
+	assert.Equal(t, 1, cfg.Update.ObserveMaxRestarts)
+	assert.Equal(t, 50, cfg.Update.ObserveLogLines)
+	assert.True(t, cfg.Update.RevertOnCrash)
+
+	cmd := &cobra.Command{Use: "watchtower"}
+
+	flags.SetDefaults()
+	flags.RegisterAll(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--observe-max-restarts", "-1"}))
+
+	_, err := config.Load(testLogger(), cmd, nil)
+	require.ErrorIs(t, err, config.ErrNegativeObserveMaxRestarts)
+}
+
+func TestLoad_Tracing(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+	assert.Equal(t, "none", cfg.Tracing.Exporter)
+	assert.InDelta(t, 1.0, cfg.Tracing.SampleRatio, 0)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_TRACING_EXPORTER":     "OTLP-HTTP",
+		"WATCHTOWER_TRACING_SAMPLE_RATIO": "0.25",
+	}, "--tracing-endpoint", "collector:4318", "--tracing-insecure")
+
+	assert.Equal(t, "otlp-http", cfg.Tracing.Exporter)
+	assert.Equal(t, "collector:4318", cfg.Tracing.Endpoint)
+	assert.True(t, cfg.Tracing.Insecure)
+	assert.InDelta(t, 0.25, cfg.Tracing.SampleRatio, 0)
+}
+
+func TestLoad_MetricsPush(t *testing.T) {
+	cfg := newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_METRICS_PUSH_URL":    "http://pushgateway:9091",
+		"WATCHTOWER_METRICS_PUSH_FORMAT": "Remote-Write",
+	}, "--metrics-push-timeout", "5s")
+
+	assert.Equal(t, "http://pushgateway:9091", cfg.Metrics.PushURL)
+	assert.Equal(t, "remote-write", cfg.Metrics.PushFormat)
+	assert.Equal(t, "watchtower", cfg.Metrics.PushJob)
+	assert.Empty(t, cfg.Metrics.PushInstance)
+	assert.Equal(t, 5*time.Second, cfg.Metrics.PushTimeout)
+}
+
+func TestLoad_Heartbeat(t *testing.T) {
+	cfg := newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_HEARTBEAT_URL":    "https://hc-ping.com/abc",
+		"WATCHTOWER_HEARTBEAT_FORMAT": "Uptime-Kuma",
+	})
+
+	assert.Equal(t, "https://hc-ping.com/abc", cfg.Heartbeat.URL)
+	assert.Equal(t, "uptime-kuma", cfg.Heartbeat.Format)
+	assert.Equal(t, 10*time.Second, cfg.Heartbeat.Timeout)
+}
+
+func TestLoad_MQTT(t *testing.T) {
+	cfg := newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_MQTT_BROKER_URL": "mqtts://broker.lan:8883",
+		"WATCHTOWER_MQTT_USERNAME":   "watchtower",
+		"WATCHTOWER_MQTT_DISCOVERY":  "false",
+	})
+
+	assert.Equal(t, "mqtts://broker.lan:8883", cfg.MQTT.BrokerURL)
+	assert.Equal(t, "watchtower", cfg.MQTT.Username)
+	assert.False(t, cfg.MQTT.Discovery)
+	assert.Equal(t, "watchtower", cfg.MQTT.TopicPrefix)
+	assert.Equal(t, "homeassistant", cfg.MQTT.DiscoveryPrefix)
+}
+
+func TestLoad_LogOutputs(t *testing.T) {
+	cfg := newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_LOG_OUTPUT": "syslog+tcp://logs.lan:601?level=warn file:///var/log/watchtower.log",
+	})
+
+	assert.Equal(t, []string{
+		"syslog+tcp://logs.lan:601?level=warn",
+		"file:///var/log/watchtower.log",
+	}, cfg.Logging.Outputs)
+
+	cmd := &cobra.Command{Use: "watchtower"}
+
+	flags.SetDefaults()
+	flags.RegisterAll(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--log-output", "kafka://broker:9092"}))
+
+	_, err := config.Load(testLogger(), cmd, nil)
+	require.ErrorIs(t, err, logging.ErrInvalidLogOutput)
+}
+
+func TestLoad_NotificationRoutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.json")
+	require.NoError(t, os.WriteFile(path, []byte(`[
+		{"name": "on-call", "categories": ["failed"], "urls": ["pagerduty://token"], "exclusive": true},
+		{"projects": ["shop"], "labels": {"tier": ""}, "urls": ["slack://hook"]}
+	]`), 0o600))
+
+	cfg := newLoadedCommand(t, map[string]string{"WATCHTOWER_NOTIFICATION_ROUTES": path})
+
+	require.Len(t, cfg.Notify.Routes, 2)
+	assert.Equal(t, "on-call", cfg.Notify.Routes[0].Name)
+	assert.Equal(t, []string{"failed"}, cfg.Notify.Routes[0].Categories)
+	assert.True(t, cfg.Notify.Routes[0].Exclusive)
+	assert.Equal(t, []string{"shop"}, cfg.Notify.Routes[1].Projects)
+	assert.Equal(t, map[string]string{"tier": ""}, cfg.Notify.Routes[1].Labels)
+
+	for name, tc := range map[string]struct {
+		routes string
+		want   error
+	}{
+		"not an array":     {`{"urls": ["slack://hook"]}`, config.ErrInvalidNotificationRoutes},
+		"no urls":          {`[{"name": "empty"}]`, config.ErrInvalidNotificationRoute},
+		"unknown category": {`[{"categories": ["broken"], "urls": ["slack://hook"]}]`, config.ErrInvalidNotificationRoute},
+	} {
+		t.Run(name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "watchtower"}
+
+			flags.SetDefaults()
+			flags.RegisterAll(cmd)
+			require.NoError(t, cmd.ParseFlags([]string{"--notification-routes", tc.routes}))
+
+			_, err := config.Load(testLogger(), cmd, nil)
+			require.ErrorIs(t, err, tc.want)
+		})
+	}
+}
+
+func TestLoad_NotificationSuppressRepeats(t *testing.T) {
+	cfg := newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_NOTIFICATION_SUPPRESS_REPEATS":      "true",
+		"WATCHTOWER_NOTIFICATION_SUPPRESS_REPEATS_FILE": "/data/announced.json",
+		"WATCHTOWER_NOTIFICATION_REMINDER_INTERVAL":     "72h",
+	})
+
+	assert.True(t, cfg.Notify.SuppressRepeats)
+	assert.Equal(t, "/data/announced.json", cfg.Notify.SuppressRepeatsFile)
+	assert.Equal(t, 72*time.Hour, cfg.Notify.ReminderInterval)
+
+	cmd := &cobra.Command{Use: "watchtower"}
+
+	flags.SetDefaults()
+	flags.RegisterAll(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{"--notification-reminder-interval", "-1h"}))
+
+	_, err := config.Load(testLogger(), cmd, nil)
+	require.ErrorIs(t, err, config.ErrNegativeNotificationReminderInterval)
+}
+
+func TestLoad_NotificationDigest(t *testing.T) {
+	cfg := newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_NOTIFICATION_DIGEST_SCHEDULE":           " 0 0 8 * * * ",
+		"WATCHTOWER_NOTIFICATION_DIGEST_FILE":               "/data/digest.json",
+		"WATCHTOWER_NOTIFICATION_DIGEST_IMMEDIATE_FAILURES": "true",
+	})
+
+	assert.Equal(t, "0 0 8 * * *", cfg.Notify.Digest.Schedule)
+	assert.Equal(t, "/data/digest.json", cfg.Notify.Digest.File)
+	assert.Empty(t, cfg.Notify.Digest.Template)
+	assert.True(t, cfg.Notify.Digest.ImmediateFailures)
+}
+
+func TestLoad_NotificationOutbox(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+
+	assert.Empty(t, cfg.Notify.Outbox.Dir)
+	assert.Equal(t, 24*time.Hour, cfg.Notify.Outbox.MaxAge)
+	assert.Equal(t, time.Minute, cfg.Notify.Outbox.RetryInterval)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_NOTIFICATION_OUTBOX_DIR":            "/data/outbox",
+		"WATCHTOWER_NOTIFICATION_OUTBOX_MAX_AGE":        "72h",
+		"WATCHTOWER_NOTIFICATION_OUTBOX_RETRY_INTERVAL": "30s",
+	})
+
+	assert.Equal(t, "/data/outbox", cfg.Notify.Outbox.Dir)
+	assert.Equal(t, 72*time.Hour, cfg.Notify.Outbox.MaxAge)
+	assert.Equal(t, 30*time.Second, cfg.Notify.Outbox.RetryInterval)
+
+	cmd := &cobra.Command{Use: "watchtower"}
+
+	flags.SetDefaults()
+	flags.RegisterAll(cmd)
+	require.NoError(t, cmd.ParseFlags([]string{
+		"--notification-outbox-dir", "/data/outbox",
+		"--notification-outbox-retry-interval", "0s",
+	}))
+
+	_, err := config.Load(testLogger(), cmd, nil)
+	require.ErrorIs(t, err, config.ErrInvalidNotificationOutboxRetryInterval)
+}
+
+func TestLoad_NotificationEmail(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+
+	assert.Equal(t, notify.Email{}, cfg.Notify.Email)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_NOTIFICATION_EMAIL_HTML":          "true",
+		"WATCHTOWER_NOTIFICATION_EMAIL_HTML_TEMPLATE": "<p>{{.Title}}</p>",
+		"WATCHTOWER_NOTIFICATION_EMAIL_TEXT_TEMPLATE": "default",
+		"WATCHTOWER_NOTIFICATION_EMAIL_ATTACH_REPORT": "true",
+	})
+
+	assert.Equal(t, notify.Email{
+		HTML:         true,
+		HTMLTemplate: "<p>{{.Title}}</p>",
+		TextTemplate: "default",
+		AttachReport: true,
+	}, cfg.Notify.Email)
+}
+
+func TestLoad_NotificationQuietHours(t *testing.T) {
+	cfg := newLoadedCommand(t, nil)
+
+	assert.Empty(t, cfg.Notify.QuietHours.Windows)
+	assert.Equal(t, time.Local, cfg.Notify.QuietHours.Location)
+	assert.False(t, cfg.Notify.QuietHours.AllowFailures)
+	assert.Empty(t, cfg.Notify.QuietHours.File)
+
+	cfg = newLoadedCommand(t, map[string]string{
+		"WATCHTOWER_NOTIFICATION_QUIET_HOURS":                "22:00-07:00, 12:30-13:00",
+		"WATCHTOWER_NOTIFICATION_QUIET_HOURS_TIMEZONE":       "Europe/Berlin",
+		"WATCHTOWER_NOTIFICATION_QUIET_HOURS_ALLOW_FAILURES": "true",
+		"WATCHTOWER_NOTIFICATION_QUIET_HOURS_FILE":           "/data/quiet.json",
+		"WATCHTOWER_NOTIFICATION_ROUTES": `[{"name":"chat","urls":["logger://"],"quiet_hours":"off"},` +
+			`{"name":"mail","urls":["logger://"],"quiet_hours":"20:00-08:00"}]`,
+	})
+
+	assert.Equal(t, []notify.QuietWindow{
+		{Start: 22 * time.Hour, End: 7 * time.Hour},
+		{Start: 12*time.Hour + 30*time.Minute, End: 13 * time.Hour},
+	}, cfg.Notify.QuietHours.Windows)
+	assert.Equal(t, "Europe/Berlin", cfg.Notify.QuietHours.Location.String())
+	assert.True(t, cfg.Notify.QuietHours.AllowFailures)
+	assert.Equal(t, "/data/quiet.json", cfg.Notify.QuietHours.File)
+	require.Len(t, cfg.Notify.Routes, 2)
+	assert.Empty(t, cfg.Notify.Routes[0].QuietWindows)
+	assert.Equal(t, []notify.QuietWindow{{Start: 20 * time.Hour, End: 8 * time.Hour}}, cfg.Notify.Routes[1].QuietWindows)
+
+	for _, tc := range []struct {
+		args []string
+		err  error
+	}{
+		{[]string{"--notification-quiet-hours", "22:00"}, config.ErrInvalidNotificationQuietHours},
+		{[]string{"--notification-quiet-hours", "25:00-07:00"}, config.ErrInvalidNotificationQuietHours},
+		{[]string{"--notification-quiet-hours", "07:00-07:00"}, config.ErrInvalidNotificationQuietHours},
+		{
+			[]string{"--notification-quiet-hours", "22:00-07:00", "--notification-quiet-hours-timezone", "Mars/Olympus"},
+			config.ErrInvalidNotificationQuietHoursTimezone,
+		},
+		{
+			[]string{"--notification-routes", `[{"urls":["logger://"],"quiet_hours":"late"}]`},
+			config.ErrInvalidNotificationRoute,
+		},
+	} {
+		cmd := &cobra.Command{Use: "watchtower"}
+
+		flags.SetDefaults()
+		flags.RegisterAll(cmd)
+		require.NoError(t, cmd.ParseFlags(tc.args))
+
+		_, err := config.Load(testLogger(), cmd, nil)
+		require.ErrorIs(t, err, tc.err, tc.args)
+	}
+}
+
 func TestUpdateParams_CompleteSnapshot(t *testing.T) {
 	cfg := newLoadedCommand(t, map[string]string{
 		"WATCHTOWER_CLEANUP":                "true",
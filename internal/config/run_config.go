@@ -148,7 +148,9 @@ func HTTPAPIEndpointsEnabled(cfg types.RunConfig) bool {
 		cfg.EnableHistoryAPI ||
 		cfg.EnableImagesAPI ||
 		cfg.EnableConfigAPI ||
-		cfg.EnableEventsAPI
+		cfg.EnableEventsAPI ||
+		cfg.EnableOutboxAPI ||
+		cfg.EnableQuarantineAPI
 }
 
 // ValidateAPIHost ensures http-api-host is empty (all interfaces) or a valid IP.
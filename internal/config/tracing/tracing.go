@@ -0,0 +1,14 @@
+// Package tracing holds OpenTelemetry tracing settings.
+package tracing
+
+// Tracing holds OpenTelemetry exporter and sampling configuration.
+type Tracing struct {
+	// Exporter selects the span exporter ("none", "otlp-grpc", or "otlp-http").
+	Exporter string
+	// Endpoint is the OTLP collector host:port or URL.
+	Endpoint string
+	// Insecure disables TLS for the exporter connection.
+	Insecure bool
+	// SampleRatio is the fraction of scans that are traced.
+	SampleRatio float64
+}
@@ -11,6 +11,18 @@ type Update struct {
 	// Cleanup removes previously used images after updating
 	// (--cleanup / WATCHTOWER_CLEANUP).
 	Cleanup bool
+	// CleanupKeep is the number of previous images kept per repository instead of removing
+	// them right after the update (--cleanup-keep / WATCHTOWER_CLEANUP_KEEP).
+	CleanupKeep int
+	// CleanupKeepDuration is how long previous images are kept instead of removing them
+	// right after the update (--cleanup-keep-duration / WATCHTOWER_CLEANUP_KEEP_DURATION).
+	CleanupKeepDuration time.Duration
+	// CleanupStateFile persists retained previous images across restarts; empty keeps them
+	// in memory only (--cleanup-state-file / WATCHTOWER_CLEANUP_STATE_FILE).
+	CleanupStateFile string
+	// CleanupDangling also removes dangling images of monitored repositories
+	// (--cleanup-dangling / WATCHTOWER_CLEANUP_DANGLING).
+	CleanupDangling bool
 	// NoPull skips pulling new images from the registry
 	// (--no-pull / WATCHTOWER_NO_PULL).
 	NoPull bool
@@ -41,4 +53,46 @@ type Update struct {
 	EphemeralSelfUpdate bool
 	// PullFailureDelay is the delay after a failed Watchtower self-update pull.
 	PullFailureDelay time.Duration
+	// VolumeBackupDir is the host path or volume receiving volume backups of labeled containers
+	// (--volume-backup-dir / WATCHTOWER_VOLUME_BACKUP_DIR). Empty disables backups.
+	VolumeBackupDir string
+	// VolumeBackupImage is the image of the helper container that archives volumes
+	// (--volume-backup-image / WATCHTOWER_VOLUME_BACKUP_IMAGE).
+	VolumeBackupImage string
+	// VolumeBackupKeep is the number of backup generations kept per container, 0 for all
+	// (--volume-backup-keep / WATCHTOWER_VOLUME_BACKUP_KEEP).
+	VolumeBackupKeep int
+	// QuarantineAfter is the number of failed updates to the same image before it is
+	// quarantined, 0 to disable quarantine (--quarantine-after / WATCHTOWER_QUARANTINE_AFTER).
+	QuarantineAfter int
+	// QuarantineBackoff is how long a quarantined image is first held back; it doubles with
+	// every further failure (--quarantine-backoff / WATCHTOWER_QUARANTINE_BACKOFF).
+	QuarantineBackoff time.Duration
+	// QuarantineMaxBackoff caps the quarantine period
+	// (--quarantine-max-backoff / WATCHTOWER_QUARANTINE_MAX_BACKOFF).
+	QuarantineMaxBackoff time.Duration
+	// MaxUpdates is the number of containers updated per run, deferring the rest to the
+	// next run, 0 for no limit (--max-updates / WATCHTOWER_MAX_UPDATES).
+	MaxUpdates int
+	// CircuitBreakerFailures halts the remaining updates of a run after this many consecutive
+	// failures, 0 to disable (--circuit-breaker-failures / WATCHTOWER_CIRCUIT_BREAKER_FAILURES).
+	CircuitBreakerFailures int
+	// CircuitBreakerPercent halts the remaining updates of a run once this percentage of its
+	// planned updates failed, 0 to disable (--circuit-breaker-percent / WATCHTOWER_CIRCUIT_BREAKER_PERCENT).
+	CircuitBreakerPercent int
+	// ObservePeriod is how long updated containers are watched for crashes and restart
+	// loops, 0 to disable (--observe-period / WATCHTOWER_OBSERVE_PERIOD).
+	ObservePeriod time.Duration
+	// ObserveMaxRestarts is the number of restarts tolerated while a container is observed
+	// (--observe-max-restarts / WATCHTOWER_OBSERVE_MAX_RESTARTS).
+	ObserveMaxRestarts int
+	// ObserveLogLines is the number of log lines of a crashed container attached to the report
+	// (--observe-log-lines / WATCHTOWER_OBSERVE_LOG_LINES).
+	ObserveLogLines int
+	// RevertOnCrash recreates containers that crashed while observed from their previous image
+	// (--revert-on-crash / WATCHTOWER_REVERT_ON_CRASH).
+	RevertOnCrash bool
+	// LockDir is the directory of the update lock file shared with the rollback subcommand,
+	// empty for the temporary directory (--lock-dir / WATCHTOWER_LOCK_DIR).
+	LockDir string
 }
@@ -26,26 +26,44 @@ func (c Config) UpdateParams(overrides RunOverrides) types.UpdateParams {
 	}
 
 	return types.UpdateParams{
-		Filter:              filterFn,
-		Cleanup:             c.Update.Cleanup,
-		NoRestart:           c.Update.NoRestart,
-		ReviveStopped:       c.Client.ReviveStopped,
-		Timeout:             c.Update.StopTimeout,
-		MonitorOnly:         c.Update.MonitorOnly,
-		NoPull:              c.Update.NoPull,
-		LifecycleHooks:      c.Lifecycle.Enabled,
-		RollingRestart:      c.Update.RollingRestart,
-		LabelPrecedence:     c.Update.LabelPrecedence,
-		PullFailureDelay:    c.Update.PullFailureDelay,
-		LifecycleUID:        c.Lifecycle.UID,
-		LifecycleGID:        c.Lifecycle.GID,
-		CPUCopyMode:         cpuCopyMode,
-		RunOnce:             overrides.RunOnce || c.Mode.RunOnce,
-		CurrentContainerID:  overrides.CurrentContainerID,
-		UseComposeDependsOn: c.Update.UseComposeDependsOn,
-		SkipSelfUpdate:      overrides.SkipSelfUpdate,
-		EphemeralSelfUpdate: c.Update.EphemeralSelfUpdate,
-		CooldownDelay:       c.Update.CooldownDelay,
-		LabelEnable:         c.Filter.LabelEnable,
+		Filter:               filterFn,
+		Cleanup:              c.Update.Cleanup,
+		NoRestart:            c.Update.NoRestart,
+		ReviveStopped:        c.Client.ReviveStopped,
+		Timeout:              c.Update.StopTimeout,
+		MonitorOnly:          c.Update.MonitorOnly,
+		NoPull:               c.Update.NoPull,
+		LifecycleHooks:       c.Lifecycle.Enabled,
+		RollingRestart:       c.Update.RollingRestart,
+		LabelPrecedence:      c.Update.LabelPrecedence,
+		PullFailureDelay:     c.Update.PullFailureDelay,
+		LifecycleUID:         c.Lifecycle.UID,
+		LifecycleGID:         c.Lifecycle.GID,
+		LifecycleOutputLimit: c.Lifecycle.OutputLimit,
+		LifecycleRedact:      c.Lifecycle.Redact,
+		PreScanHook:          c.Lifecycle.PreScanHook,
+		PreRestartBatchHook:  c.Lifecycle.PreRestartBatchHook,
+		PostRestartBatchHook: c.Lifecycle.PostRestartBatchHook,
+		PostScanHook:         c.Lifecycle.PostScanHook,
+		ScanHookTimeout:      c.Lifecycle.ScanHookTimeout,
+		CPUCopyMode:          cpuCopyMode,
+		RunOnce:              overrides.RunOnce || c.Mode.RunOnce,
+		Targeted:             overrides.Targeted,
+		CurrentContainerID:   overrides.CurrentContainerID,
+		UseComposeDependsOn:  c.Update.UseComposeDependsOn,
+		SkipSelfUpdate:       overrides.SkipSelfUpdate,
+		EphemeralSelfUpdate:  c.Update.EphemeralSelfUpdate,
+		CooldownDelay:        c.Update.CooldownDelay,
+		LabelEnable:          c.Filter.LabelEnable,
+		VolumeBackupDir:      c.Update.VolumeBackupDir,
+		VolumeBackupImage:    c.Update.VolumeBackupImage,
+		VolumeBackupKeep:     c.Update.VolumeBackupKeep,
+		MaxUpdates:           c.Update.MaxUpdates,
+		BreakerFailures:      c.Update.CircuitBreakerFailures,
+		BreakerPercent:       c.Update.CircuitBreakerPercent,
+		ObservePeriod:        c.Update.ObservePeriod,
+		ObserveMaxRestarts:   c.Update.ObserveMaxRestarts,
+		ObserveLogLines:      c.Update.ObserveLogLines,
+		RevertOnCrash:        c.Update.RevertOnCrash,
 	}
 }
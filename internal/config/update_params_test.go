@@ -36,22 +36,39 @@ func TestUpdateParamsAssignsEveryField(t *testing.T) {
 			RunOnce: false,
 		},
 		Update: update.Update{
-			Cleanup:             true,
-			NoPull:              true,
-			NoRestart:           true,
-			MonitorOnly:         true,
-			RollingRestart:      false,
-			StopTimeout:         30 * time.Second,
-			CooldownDelay:       24 * time.Hour,
-			UseComposeDependsOn: true,
-			LabelPrecedence:     true,
-			EphemeralSelfUpdate: true,
-			PullFailureDelay:    5 * time.Second,
+			Cleanup:                true,
+			NoPull:                 true,
+			NoRestart:              true,
+			MonitorOnly:            true,
+			RollingRestart:         false,
+			StopTimeout:            30 * time.Second,
+			CooldownDelay:          24 * time.Hour,
+			UseComposeDependsOn:    true,
+			LabelPrecedence:        true,
+			EphemeralSelfUpdate:    true,
+			PullFailureDelay:       5 * time.Second,
+			VolumeBackupDir:        "/srv/backups",
+			VolumeBackupImage:      "busybox:stable",
+			VolumeBackupKeep:       5,
+			MaxUpdates:             10,
+			CircuitBreakerFailures: 3,
+			CircuitBreakerPercent:  50,
+			ObservePeriod:          time.Minute,
+			ObserveMaxRestarts:     2,
+			ObserveLogLines:        40,
+			RevertOnCrash:          true,
 		},
 		Lifecycle: lifecycle.Lifecycle{
-			Enabled: true,
-			UID:     1000,
-			GID:     1000,
+			Enabled:              true,
+			UID:                  1000,
+			GID:                  1000,
+			OutputLimit:          2048,
+			Redact:               "secret-[0-9]+",
+			PreScanHook:          "/hooks/pause-backups",
+			PreRestartBatchHook:  "https://lb.example/maintenance",
+			PostRestartBatchHook: "https://lb.example/ready",
+			PostScanHook:         "/hooks/resume-backups",
+			ScanHookTimeout:      time.Minute,
 		},
 		Filter: filter.Filter{
 			Predicate:   filters.NoFilter,
@@ -64,6 +81,7 @@ func TestUpdateParamsAssignsEveryField(t *testing.T) {
 		RunOnce:            true,
 		SkipSelfUpdate:     true,
 		CurrentContainerID: types.ContainerID("abc123"),
+		Targeted:           true,
 	}
 
 	params := cfg.UpdateParams(ov)
@@ -81,16 +99,34 @@ func TestUpdateParamsAssignsEveryField(t *testing.T) {
 	assert.Equal(t, 5*time.Second, params.PullFailureDelay)
 	assert.Equal(t, 1000, params.LifecycleUID)
 	assert.Equal(t, 1000, params.LifecycleGID)
+	assert.Equal(t, 2048, params.LifecycleOutputLimit)
+	assert.Equal(t, "secret-[0-9]+", params.LifecycleRedact)
+	assert.Equal(t, "/hooks/pause-backups", params.PreScanHook)
+	assert.Equal(t, "https://lb.example/maintenance", params.PreRestartBatchHook)
+	assert.Equal(t, "https://lb.example/ready", params.PostRestartBatchHook)
+	assert.Equal(t, "/hooks/resume-backups", params.PostScanHook)
+	assert.Equal(t, time.Minute, params.ScanHookTimeout)
 	assert.Equal(t, "auto", params.CPUCopyMode)
 	assert.True(t, params.RunOnce)
+	assert.True(t, params.Targeted)
 	assert.Equal(t, types.ContainerID("abc123"), params.CurrentContainerID)
 	assert.True(t, params.UseComposeDependsOn)
 	assert.True(t, params.SkipSelfUpdate)
 	assert.True(t, params.EphemeralSelfUpdate)
 	assert.Equal(t, 24*time.Hour, params.CooldownDelay)
+	assert.Equal(t, "/srv/backups", params.VolumeBackupDir)
+	assert.Equal(t, "busybox:stable", params.VolumeBackupImage)
+	assert.Equal(t, 5, params.VolumeBackupKeep)
+	assert.Equal(t, 10, params.MaxUpdates)
+	assert.Equal(t, 3, params.BreakerFailures)
+	assert.Equal(t, 50, params.BreakerPercent)
+	assert.Equal(t, time.Minute, params.ObservePeriod)
+	assert.Equal(t, 2, params.ObserveMaxRestarts)
+	assert.Equal(t, 40, params.ObserveLogLines)
+	assert.True(t, params.RevertOnCrash)
 
 	// Exhaustiveness: every exported field must be non-zero in this fixture
-	// (Filter is a func; RunOnce and SkipSelfUpdate come from overrides).
+	// (Filter is a func; RunOnce, SkipSelfUpdate and Targeted come from overrides).
 	val := reflect.ValueOf(params)
 	typ := val.Type()
 
@@ -104,6 +140,9 @@ func TestUpdateParamsAssignsEveryField(t *testing.T) {
 			assert.False(t, fv.Bool())
 		case "Filter":
 			assert.False(t, fv.IsNil(), "Filter must be assigned")
+		case "ScanID":
+			// Assigned per scan by actions.Update, not by configuration.
+			assert.True(t, fv.IsZero())
 		default:
 			assert.False(t, fv.IsZero(), "field %s must be assigned by UpdateParams", field.Name)
 		}
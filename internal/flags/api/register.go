@@ -79,6 +79,13 @@ func Specs() []spec.FlagSpec {
 			EnvKeys: []string{"WATCHTOWER_HTTP_API_EVENTS_TOKEN"},
 			Help:    "Sets an authentication token for the events SSE endpoint. Required when the events endpoint is enabled. Supports Bearer header and query parameter access_token (for browser EventSource)",
 		},
+		{
+			Name:    "http-api-events-format",
+			Kind:    spec.KindString,
+			Default: "native",
+			EnvKeys: []string{"WATCHTOWER_HTTP_API_EVENTS_FORMAT"},
+			Help:    "Encoding of the events SSE stream: native (Watchtower event envelope) or cloudevents (structured CloudEvents 1.0)",
+		},
 		{
 			Name:    "http-api-periodic-polls",
 			Kind:    spec.KindBool,
@@ -0,0 +1,52 @@
+// Package events registers outbound event delivery flags.
+package events
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
+)
+
+// DefaultSinkTimeout is the static default per-request event sink timeout.
+const DefaultSinkTimeout = 10 * time.Second
+
+// Specs returns events domain flag metadata with static defaults.
+//
+// Returns:
+//   - []spec.FlagSpec: Events flag specifications.
+func Specs() []spec.FlagSpec {
+	return []spec.FlagSpec{
+		{
+			Name:      "events-sink-url",
+			Kind:      spec.KindStringSlice,
+			Default:   []string{},
+			EnvKeys:   []string{"WATCHTOWER_EVENTS_SINK_URL"},
+			ListParse: spec.ListCommaOrSpace,
+			Help:      "HTTP endpoints that receive every Watchtower event as a CloudEvents 1.0 POST request. Comma- or space-separated.",
+		},
+		{
+			Name:    "events-sink-mode",
+			Kind:    spec.KindString,
+			Default: "structured",
+			EnvKeys: []string{"WATCHTOWER_EVENTS_SINK_MODE"},
+			Help:    "CloudEvents HTTP content mode for event sinks: structured (application/cloudevents+json body) or binary (ce-* headers)",
+		},
+		{
+			Name:    "events-sink-timeout",
+			Kind:    spec.KindDuration,
+			Default: DefaultSinkTimeout,
+			EnvKeys: []string{"WATCHTOWER_EVENTS_SINK_TIMEOUT"},
+			Help:    "Maximum duration of a single event sink request (default: 10s)",
+		},
+	}
+}
+
+// Register adds events domain flags to the root command.
+//
+// Parameters:
+//   - rootCmd: Root Cobra command.
+func Register(rootCmd *cobra.Command) {
+	spec.MustRegister(rootCmd.PersistentFlags(), Specs())
+}
@@ -17,13 +17,18 @@ import (
 	"github.com/nicholas-fedor/watchtower/internal/flags/client"
 	"github.com/nicholas-fedor/watchtower/internal/flags/compat"
 	"github.com/nicholas-fedor/watchtower/internal/flags/docker"
+	"github.com/nicholas-fedor/watchtower/internal/flags/events"
 	"github.com/nicholas-fedor/watchtower/internal/flags/filter"
+	"github.com/nicholas-fedor/watchtower/internal/flags/heartbeat"
 	"github.com/nicholas-fedor/watchtower/internal/flags/lifecycle"
 	flagslogging "github.com/nicholas-fedor/watchtower/internal/flags/logging"
+	"github.com/nicholas-fedor/watchtower/internal/flags/metrics"
 	"github.com/nicholas-fedor/watchtower/internal/flags/mode"
+	"github.com/nicholas-fedor/watchtower/internal/flags/mqtt"
 	"github.com/nicholas-fedor/watchtower/internal/flags/notify"
 	"github.com/nicholas-fedor/watchtower/internal/flags/registry"
 	"github.com/nicholas-fedor/watchtower/internal/flags/schedule"
+	"github.com/nicholas-fedor/watchtower/internal/flags/tracing"
 	"github.com/nicholas-fedor/watchtower/internal/flags/update"
 	"github.com/nicholas-fedor/watchtower/internal/flags/utils"
 	"github.com/nicholas-fedor/watchtower/internal/logging"
@@ -82,6 +87,11 @@ func RegisterSystemFlags(rootCmd *cobra.Command) {
 	registry.Register(rootCmd)
 	compat.Register(rootCmd)
 	api.Register(rootCmd)
+	events.Register(rootCmd)
+	metrics.Register(rootCmd)
+	tracing.Register(rootCmd)
+	heartbeat.Register(rootCmd)
+	mqtt.Register(rootCmd)
 	flagslogging.Register(rootCmd)
 }
 
@@ -253,8 +263,13 @@ func GetSecretsFromFiles(log *zerolog.Logger, rootCmd *cobra.Command) {
 		// TODO: Remove just before v2 Release.
 		"notification-gotify-token",
 		"notification-url",
+		"notification-routes",
+		"notification-email-html-template",
+		"notification-email-text-template",
 		"http-api-token",
 		"http-api-events-token",
+		"heartbeat-url",
+		"mqtt-password",
 	}
 
 	// Process each secret flag.
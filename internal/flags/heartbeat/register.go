@@ -0,0 +1,51 @@
+// Package heartbeat registers dead-man's-switch heartbeat flags.
+package heartbeat
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
+)
+
+// DefaultTimeout is the static default per-ping heartbeat timeout.
+const DefaultTimeout = 10 * time.Second
+
+// Specs returns heartbeat domain flag metadata with static defaults.
+//
+// Returns:
+//   - []spec.FlagSpec: Heartbeat flag specifications.
+func Specs() []spec.FlagSpec {
+	return []spec.FlagSpec{
+		{
+			Name:    "heartbeat-url",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_HEARTBEAT_URL"},
+			Help:    "Monitor URL pinged at the start and end of every scan (healthchecks.io or Uptime Kuma push URL)",
+		},
+		{
+			Name:    "heartbeat-format",
+			Kind:    spec.KindString,
+			Default: "healthchecks",
+			EnvKeys: []string{"WATCHTOWER_HEARTBEAT_FORMAT"},
+			Help:    "Heartbeat ping format: healthchecks (/start, /fail suffixes) or uptime-kuma (status query parameters)",
+		},
+		{
+			Name:    "heartbeat-timeout",
+			Kind:    spec.KindDuration,
+			Default: DefaultTimeout,
+			EnvKeys: []string{"WATCHTOWER_HEARTBEAT_TIMEOUT"},
+			Help:    "Maximum duration of a single heartbeat ping (default: 10s)",
+		},
+	}
+}
+
+// Register adds heartbeat domain flags to the root command.
+//
+// Parameters:
+//   - rootCmd: Root Cobra command.
+func Register(rootCmd *cobra.Command) {
+	spec.MustRegister(rootCmd.PersistentFlags(), Specs())
+}
@@ -2,6 +2,8 @@
 package lifecycle
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
@@ -34,6 +36,55 @@ func Specs() []spec.FlagSpec {
 			EnvKeys: []string{"WATCHTOWER_LIFECYCLE_GID"},
 			Help:    "Default GID to run lifecycle hooks as (can be overridden by container labels)",
 		},
+		{
+			Name:    "lifecycle-output-limit",
+			Kind:    spec.KindInt,
+			Default: 1024,
+			EnvKeys: []string{"WATCHTOWER_LIFECYCLE_OUTPUT_LIMIT"},
+			Help:    "Maximum bytes of lifecycle hook output kept in reports and notifications (0 omits output)",
+		},
+		{
+			Name:    "lifecycle-redact",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_LIFECYCLE_REDACT"},
+			Help:    "Regular expression whose matches are redacted from captured lifecycle hook output",
+		},
+		{
+			Name:    "pre-scan-hook",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_PRE_SCAN_HOOK"},
+			Help:    "Command or http(s) URL Watchtower runs before each scan; failure aborts the scan",
+		},
+		{
+			Name:    "pre-restart-batch-hook",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_PRE_RESTART_BATCH_HOOK"},
+			Help:    "Command or http(s) URL Watchtower runs before the first container is stopped; failure aborts the restarts",
+		},
+		{
+			Name:    "post-restart-batch-hook",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_POST_RESTART_BATCH_HOOK"},
+			Help:    "Command or http(s) URL Watchtower runs after the last container is started",
+		},
+		{
+			Name:    "post-scan-hook",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_POST_SCAN_HOOK"},
+			Help:    "Command or http(s) URL Watchtower runs after each scan",
+		},
+		{
+			Name:    "scan-hook-timeout",
+			Kind:    spec.KindDuration,
+			Default: 5 * time.Minute,
+			EnvKeys: []string{"WATCHTOWER_SCAN_HOOK_TIMEOUT"},
+			Help:    "Timeout for each pre-scan, restart batch, and post-scan hook",
+		},
 	}
 }
 
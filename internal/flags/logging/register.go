@@ -48,6 +48,14 @@ func Specs() []spec.FlagSpec {
 			EnvKeys: []string{"WATCHTOWER_TRACE"},
 			Help:    "Enable trace mode with very verbose logging - caution, exposes credentials",
 		},
+		{
+			Name:      "log-output",
+			Kind:      spec.KindStringSlice,
+			Default:   []string{},
+			EnvKeys:   []string{"WATCHTOWER_LOG_OUTPUT"},
+			ListParse: spec.ListCommaOrSpace,
+			Help:      "Additional log output URLs: syslog+udp|tcp|tls://host:port, gelf+udp|tcp://host:port, or file:///path. Each accepts level and format query parameters. Comma- or space-separated.",
+		},
 		{
 			Name:    "no-color",
 			Kind:    spec.KindBool,
@@ -0,0 +1,73 @@
+// Package metrics registers Prometheus metrics flags.
+package metrics
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
+)
+
+// DefaultPushTimeout is the static default limit for pushing run metrics.
+const DefaultPushTimeout = 30 * time.Second
+
+// Specs returns metrics domain flag metadata with static defaults.
+//
+// Returns:
+//   - []spec.FlagSpec: Metrics flag specifications.
+func Specs() []spec.FlagSpec {
+	return []spec.FlagSpec{
+		{
+			Name:      "metrics-container-labels",
+			Kind:      spec.KindStringSlice,
+			Default:   []string{"container", "image"},
+			EnvKeys:   []string{"WATCHTOWER_METRICS_CONTAINER_LABELS"},
+			ListParse: spec.ListCommaOrSpace,
+			Help:      "Labels kept on per-container metrics (container, image), or \"none\" to aggregate them into a single series. Comma- or space-separated.",
+		},
+		{
+			Name:    "metrics-push-url",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_METRICS_PUSH_URL"},
+			Help:    "Pushgateway or remote-write URL that receives the run's metrics at the end of --run-once",
+		},
+		{
+			Name:    "metrics-push-format",
+			Kind:    spec.KindString,
+			Default: "pushgateway",
+			EnvKeys: []string{"WATCHTOWER_METRICS_PUSH_FORMAT"},
+			Help:    "Protocol used for --metrics-push-url: pushgateway or remote-write",
+		},
+		{
+			Name:    "metrics-push-job",
+			Kind:    spec.KindString,
+			Default: "watchtower",
+			EnvKeys: []string{"WATCHTOWER_METRICS_PUSH_JOB"},
+			Help:    "Job label of pushed metrics",
+		},
+		{
+			Name:    "metrics-push-instance",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_METRICS_PUSH_INSTANCE"},
+			Help:    "Instance label of pushed metrics (default: notifications hostname or system hostname)",
+		},
+		{
+			Name:    "metrics-push-timeout",
+			Kind:    spec.KindDuration,
+			Default: DefaultPushTimeout,
+			EnvKeys: []string{"WATCHTOWER_METRICS_PUSH_TIMEOUT"},
+			Help:    "Maximum duration of all metrics push attempts, including retries (default: 30s)",
+		},
+	}
+}
+
+// Register adds metrics domain flags to the root command.
+//
+// Parameters:
+//   - rootCmd: Root Cobra command.
+func Register(rootCmd *cobra.Command) {
+	spec.MustRegister(rootCmd.PersistentFlags(), Specs())
+}
@@ -0,0 +1,96 @@
+// Package mqtt registers MQTT and Home Assistant integration flags.
+package mqtt
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
+)
+
+// Static defaults for MQTT topics.
+const (
+	// DefaultTopicPrefix is the root of the published topics.
+	DefaultTopicPrefix = "watchtower"
+	// DefaultDiscoveryPrefix is Home Assistant's default discovery prefix.
+	DefaultDiscoveryPrefix = "homeassistant"
+)
+
+// Specs returns MQTT domain flag metadata with static defaults.
+//
+// Returns:
+//   - []spec.FlagSpec: MQTT flag specifications.
+func Specs() []spec.FlagSpec {
+	return []spec.FlagSpec{
+		{
+			Name:    "mqtt-broker-url",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_MQTT_BROKER_URL"},
+			Help:    "MQTT broker URL (mqtt://host:1883 or mqtts://host:8883) to publish container update state to",
+		},
+		{
+			Name:    "mqtt-username",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_MQTT_USERNAME"},
+			Help:    "MQTT broker username",
+		},
+		{
+			Name:    "mqtt-password",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_MQTT_PASSWORD"},
+			Help:    "MQTT broker password",
+		},
+		{
+			Name:    "mqtt-client-id",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_MQTT_CLIENT_ID"},
+			Help:    "MQTT client ID, also identifying the Home Assistant device (default: hostname)",
+		},
+		{
+			Name:    "mqtt-topic-prefix",
+			Kind:    spec.KindString,
+			Default: DefaultTopicPrefix,
+			EnvKeys: []string{"WATCHTOWER_MQTT_TOPIC_PREFIX"},
+			Help:    "Root topic for container state, scan summary, and command topics",
+		},
+		{
+			Name:    "mqtt-discovery",
+			Kind:    spec.KindBool,
+			Default: true,
+			EnvKeys: []string{"WATCHTOWER_MQTT_DISCOVERY"},
+			Help:    "Publish Home Assistant MQTT discovery configs for container update entities",
+		},
+		{
+			Name:    "mqtt-discovery-prefix",
+			Kind:    spec.KindString,
+			Default: DefaultDiscoveryPrefix,
+			EnvKeys: []string{"WATCHTOWER_MQTT_DISCOVERY_PREFIX"},
+			Help:    "Home Assistant MQTT discovery prefix",
+		},
+		{
+			Name:    "mqtt-tls-ca-file",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_MQTT_TLS_CA_FILE"},
+			Help:    "PEM CA bundle used to verify an mqtts:// broker instead of the system roots",
+		},
+		{
+			Name:    "mqtt-tls-skip-verify",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_MQTT_TLS_SKIP_VERIFY"},
+			Help:    "Skip verification of the mqtts:// broker certificate",
+		},
+	}
+}
+
+// Register adds MQTT domain flags to the root command.
+//
+// Parameters:
+//   - rootCmd: Root Cobra command.
+func Register(rootCmd *cobra.Command) {
+	spec.MustRegister(rootCmd.PersistentFlags(), Specs())
+}
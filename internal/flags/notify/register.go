@@ -2,6 +2,8 @@
 package notify
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
@@ -59,6 +61,13 @@ func Specs() []spec.FlagSpec {
 			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_TEMPLATE_FILE"},
 			Help:    "Path to a file containing the Shoutrrr text/template for the messages",
 		},
+		{
+			Name:    "notification-template-dir",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_TEMPLATE_DIR"},
+			Help:    "Directory of templates named by the notification-template container label",
+		},
 		{
 			Name:    "notification-report",
 			Kind:    spec.KindBool,
@@ -94,6 +103,139 @@ func Specs() []spec.FlagSpec {
 			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_SPLIT_BY_CONTAINER"},
 			Help:    "Send separate notifications for each updated container instead of grouping them",
 		},
+		{
+			Name:    "notification-routes",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_ROUTES"},
+			Help:    "JSON array of notification routing rules, or a path to a file containing it",
+		},
+		{
+			Name:    "notification-suppress-repeats",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_SUPPRESS_REPEATS"},
+			Help:    "Announce an available update only once per container and image digest",
+		},
+		{
+			Name:    "notification-suppress-repeats-file",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_SUPPRESS_REPEATS_FILE"},
+			Help:    "Path of the file that keeps announced image digests across restarts",
+		},
+		{
+			Name:    "notification-reminder-interval",
+			Kind:    spec.KindDuration,
+			Default: time.Duration(0),
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_REMINDER_INTERVAL"},
+			Help:    "Re-announce a suppressed available update after this long (0 never reminds)",
+		},
+		{
+			Name:    "notification-digest-schedule",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_DIGEST_SCHEDULE"},
+			Help:    "Cron expression for sending a digest of all scans instead of one notification per scan",
+		},
+		{
+			Name:    "notification-digest-file",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_DIGEST_FILE"},
+			Help:    "Path of the file that keeps the pending digest across restarts",
+		},
+		{
+			Name:    "notification-digest-template",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_DIGEST_TEMPLATE"},
+			Help:    "The shoutrrr text/template for digest notifications, or a builtin template name",
+		},
+		{
+			Name:    "notification-digest-immediate-failures",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_DIGEST_IMMEDIATE_FAILURES"},
+			Help:    "Send the regular notification right away for scans with failures while in digest mode",
+		},
+		{
+			Name:    "notification-outbox-dir",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_OUTBOX_DIR"},
+			Help:    "Directory that keeps undeliverable notifications for retry, one file per service URL",
+		},
+		{
+			Name:    "notification-outbox-max-age",
+			Kind:    spec.KindDuration,
+			Default: 24 * time.Hour,
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_OUTBOX_MAX_AGE"},
+			Help:    "Drop undelivered notifications older than this (0 keeps them until delivered)",
+		},
+		{
+			Name:    "notification-outbox-retry-interval",
+			Kind:    spec.KindDuration,
+			Default: time.Minute,
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_OUTBOX_RETRY_INTERVAL"},
+			Help:    "Delay before retrying an undelivered notification, doubled after each failed retry",
+		},
+		{
+			Name:    "notification-quiet-hours",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_QUIET_HOURS"},
+			Help:    "Comma-separated HH:MM-HH:MM ranges during which notifications are held until the range ends",
+		},
+		{
+			Name:    "notification-quiet-hours-timezone",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_QUIET_HOURS_TIMEZONE"},
+			Help:    "IANA time zone of the quiet hours ranges (defaults to the local time zone)",
+		},
+		{
+			Name:    "notification-quiet-hours-allow-failures",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_QUIET_HOURS_ALLOW_FAILURES"},
+			Help:    "Send notifications about failures right away during quiet hours",
+		},
+		{
+			Name:    "notification-quiet-hours-file",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_QUIET_HOURS_FILE"},
+			Help:    "Path of a JSON file keeping notifications held for quiet hours across restarts",
+		},
+		{
+			Name:    "notification-email-html",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_EMAIL_HTML"},
+			Help:    "Send smtp:// notifications as multipart emails with a plain-text and an HTML part",
+		},
+		{
+			Name:    "notification-email-html-template",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_EMAIL_HTML_TEMPLATE"},
+			Help:    "The html/template for the HTML part of email notifications, or a path to a file containing it",
+		},
+		{
+			Name:    "notification-email-text-template",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_EMAIL_TEXT_TEMPLATE"},
+			Help:    "The text/template for the plain-text part of email notifications, or a path to a file containing it",
+		},
+		{
+			Name:    "notification-email-attach-report",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_NOTIFICATION_EMAIL_ATTACH_REPORT"},
+			Help:    "Attach the full notification data as a JSON file to HTML email notifications",
+		},
 
 		{
 			Name:       "notifications",
@@ -7,21 +7,27 @@ import (
 	"github.com/nicholas-fedor/watchtower/internal/flags/client"
 	"github.com/nicholas-fedor/watchtower/internal/flags/compat"
 	"github.com/nicholas-fedor/watchtower/internal/flags/docker"
+	"github.com/nicholas-fedor/watchtower/internal/flags/events"
 	"github.com/nicholas-fedor/watchtower/internal/flags/filter"
+	"github.com/nicholas-fedor/watchtower/internal/flags/heartbeat"
 	"github.com/nicholas-fedor/watchtower/internal/flags/lifecycle"
 	"github.com/nicholas-fedor/watchtower/internal/flags/logging"
+	"github.com/nicholas-fedor/watchtower/internal/flags/metrics"
 	"github.com/nicholas-fedor/watchtower/internal/flags/mode"
+	"github.com/nicholas-fedor/watchtower/internal/flags/mqtt"
 	"github.com/nicholas-fedor/watchtower/internal/flags/notify"
 	"github.com/nicholas-fedor/watchtower/internal/flags/registry"
 	"github.com/nicholas-fedor/watchtower/internal/flags/schedule"
 	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
+	"github.com/nicholas-fedor/watchtower/internal/flags/tracing"
 	"github.com/nicholas-fedor/watchtower/internal/flags/update"
 )
 
 // RegisterAll registers every domain's flags on the root command.
 //
 // Domain packages match the config taxonomy: docker, client, schedule, mode,
-// update, lifecycle, filter, registry, compat, api, notify, logging.
+// update, lifecycle, filter, registry, compat, api, events, metrics, tracing,
+// heartbeat, mqtt, notify, logging.
 //
 // Parameters:
 //   - rootCmd: Root Cobra command.
@@ -36,6 +42,11 @@ func RegisterAll(rootCmd *cobra.Command) {
 	registry.Register(rootCmd)
 	compat.Register(rootCmd)
 	api.Register(rootCmd)
+	events.Register(rootCmd)
+	metrics.Register(rootCmd)
+	tracing.Register(rootCmd)
+	heartbeat.Register(rootCmd)
+	mqtt.Register(rootCmd)
 	notify.Register(rootCmd)
 	logging.Register(rootCmd)
 }
@@ -56,6 +67,11 @@ func AllSpecs() []spec.FlagSpec {
 		registry.Specs(),
 		compat.Specs(),
 		api.Specs(),
+		events.Specs(),
+		metrics.Specs(),
+		tracing.Specs(),
+		heartbeat.Specs(),
+		mqtt.Specs(),
 		notify.Specs(),
 		logging.Specs(),
 	)
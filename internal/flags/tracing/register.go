@@ -0,0 +1,53 @@
+// Package tracing registers OpenTelemetry tracing flags.
+package tracing
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/nicholas-fedor/watchtower/internal/flags/spec"
+)
+
+// Specs returns tracing domain flag metadata with static defaults.
+//
+// Returns:
+//   - []spec.FlagSpec: Tracing flag specifications.
+func Specs() []spec.FlagSpec {
+	return []spec.FlagSpec{
+		{
+			Name:    "tracing-exporter",
+			Kind:    spec.KindString,
+			Default: "none",
+			EnvKeys: []string{"WATCHTOWER_TRACING_EXPORTER"},
+			Help:    "OpenTelemetry span exporter: none, otlp-grpc, or otlp-http",
+		},
+		{
+			Name:    "tracing-endpoint",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_TRACING_ENDPOINT"},
+			Help:    "OTLP collector host:port or URL. Defaults to OTEL_EXPORTER_OTLP_ENDPOINT or the exporter's localhost default",
+		},
+		{
+			Name:    "tracing-insecure",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_TRACING_INSECURE"},
+			Help:    "Disable TLS for the OTLP exporter connection",
+		},
+		{
+			Name:    "tracing-sample-ratio",
+			Kind:    spec.KindString,
+			Default: "1",
+			EnvKeys: []string{"WATCHTOWER_TRACING_SAMPLE_RATIO"},
+			Help:    "Fraction of scans to trace, between 0 and 1 (default: 1)",
+		},
+	}
+}
+
+// Register adds tracing domain flags to the root command.
+//
+// Parameters:
+//   - rootCmd: Root Cobra command.
+func Register(rootCmd *cobra.Command) {
+	spec.MustRegister(rootCmd.PersistentFlags(), Specs())
+}
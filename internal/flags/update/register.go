@@ -12,6 +12,21 @@ import (
 // DefaultStopTimeout is the static default container stop timeout.
 const DefaultStopTimeout = 30 * time.Second
 
+// DefaultVolumeBackupImage is the static default image of the volume backup helper.
+const DefaultVolumeBackupImage = "busybox:stable"
+
+// DefaultVolumeBackupKeep is the static default number of volume backup generations kept.
+const DefaultVolumeBackupKeep = 3
+
+// DefaultQuarantineBackoff is the static default period an image is first quarantined for.
+const DefaultQuarantineBackoff = "1h"
+
+// DefaultQuarantineMaxBackoff is the static default cap on the quarantine period.
+const DefaultQuarantineMaxBackoff = "7d"
+
+// DefaultObserveLogLines is the static default number of log lines attached to crash reports.
+const DefaultObserveLogLines = 20
+
 // Specs returns update domain flag metadata with static defaults.
 //
 // Returns:
@@ -26,6 +41,34 @@ func Specs() []spec.FlagSpec {
 			EnvKeys:   []string{"WATCHTOWER_CLEANUP"},
 			Help:      "Remove previously used images after updating",
 		},
+		{
+			Name:    "cleanup-keep",
+			Kind:    spec.KindInt,
+			Default: 0,
+			EnvKeys: []string{"WATCHTOWER_CLEANUP_KEEP"},
+			Help:    "Number of previous images kept per repository when cleaning up (0 removes them right after the update)",
+		},
+		{
+			Name:    "cleanup-keep-duration",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_CLEANUP_KEEP_DURATION"},
+			Help:    "How long previous images are kept when cleaning up. Supports h, m, s, d (days), w (weeks), M (months) (e.g., 72h, 7d)",
+		},
+		{
+			Name:    "cleanup-state-file",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_CLEANUP_STATE_FILE"},
+			Help:    "File that keeps track of retained previous images across restarts",
+		},
+		{
+			Name:    "cleanup-dangling",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_CLEANUP_DANGLING"},
+			Help:    "Also remove dangling images of monitored repositories left behind by pulls when cleaning up",
+		},
 		{
 			Name:    "no-pull",
 			Kind:    spec.KindBool,
@@ -91,6 +134,104 @@ func Specs() []spec.FlagSpec {
 			EnvKeys: []string{"WATCHTOWER_EPHEMERAL_SELF_UPDATE"},
 			Help:    "Use an ephemeral container to orchestrate Watchtower self-updates (experimental)",
 		},
+		{
+			Name:    "volume-backup-dir",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_VOLUME_BACKUP_DIR"},
+			Help:    "Host path or volume name receiving volume backups of containers labeled for backup",
+		},
+		{
+			Name:    "volume-backup-image",
+			Kind:    spec.KindString,
+			Default: DefaultVolumeBackupImage,
+			EnvKeys: []string{"WATCHTOWER_VOLUME_BACKUP_IMAGE"},
+			Help:    "Image of the helper container that archives volumes",
+		},
+		{
+			Name:    "volume-backup-keep",
+			Kind:    spec.KindInt,
+			Default: DefaultVolumeBackupKeep,
+			EnvKeys: []string{"WATCHTOWER_VOLUME_BACKUP_KEEP"},
+			Help:    "Number of volume backup generations kept per container (0 keeps all)",
+		},
+		{
+			Name:    "quarantine-after",
+			Kind:    spec.KindInt,
+			Default: 0,
+			EnvKeys: []string{"WATCHTOWER_QUARANTINE_AFTER"},
+			Help:    "Number of failed updates to the same image before it is quarantined (0 disables quarantine)",
+		},
+		{
+			Name:    "quarantine-backoff",
+			Kind:    spec.KindString,
+			Default: DefaultQuarantineBackoff,
+			EnvKeys: []string{"WATCHTOWER_QUARANTINE_BACKOFF"},
+			Help:    "How long a quarantined image is first held back, doubling with every further failure. Supports h, m, s, d (days), w (weeks), M (months)",
+		},
+		{
+			Name:    "quarantine-max-backoff",
+			Kind:    spec.KindString,
+			Default: DefaultQuarantineMaxBackoff,
+			EnvKeys: []string{"WATCHTOWER_QUARANTINE_MAX_BACKOFF"},
+			Help:    "Longest period a quarantined image is held back. Supports h, m, s, d (days), w (weeks), M (months)",
+		},
+		{
+			Name:    "max-updates",
+			Kind:    spec.KindInt,
+			Default: 0,
+			EnvKeys: []string{"WATCHTOWER_MAX_UPDATES"},
+			Help:    "Maximum number of containers updated per run; the rest are deferred to the next run (0 updates all)",
+		},
+		{
+			Name:    "circuit-breaker-failures",
+			Kind:    spec.KindInt,
+			Default: 0,
+			EnvKeys: []string{"WATCHTOWER_CIRCUIT_BREAKER_FAILURES"},
+			Help:    "Halt the remaining updates of a run after this many consecutive failures (0 disables)",
+		},
+		{
+			Name:    "circuit-breaker-percent",
+			Kind:    spec.KindInt,
+			Default: 0,
+			EnvKeys: []string{"WATCHTOWER_CIRCUIT_BREAKER_PERCENT"},
+			Help:    "Halt the remaining updates of a run once this percentage of its planned updates failed (0 disables)",
+		},
+		{
+			Name:    "observe-period",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_OBSERVE_PERIOD"},
+			Help:    "How long updated containers are watched for crashes and restart loops (e.g., 30s, 2m; 0 disables)",
+		},
+		{
+			Name:    "observe-max-restarts",
+			Kind:    spec.KindInt,
+			Default: 0,
+			EnvKeys: []string{"WATCHTOWER_OBSERVE_MAX_RESTARTS"},
+			Help:    "Number of restarts tolerated while an updated container is observed",
+		},
+		{
+			Name:    "observe-log-lines",
+			Kind:    spec.KindInt,
+			Default: DefaultObserveLogLines,
+			EnvKeys: []string{"WATCHTOWER_OBSERVE_LOG_LINES"},
+			Help:    "Number of log lines of a crashed container attached to the report (0 attaches none)",
+		},
+		{
+			Name:    "revert-on-crash",
+			Kind:    spec.KindBool,
+			Default: false,
+			EnvKeys: []string{"WATCHTOWER_REVERT_ON_CRASH"},
+			Help:    "Recreate containers that crashed while observed from their previous image",
+		},
+		{
+			Name:    "lock-dir",
+			Kind:    spec.KindString,
+			Default: "",
+			EnvKeys: []string{"WATCHTOWER_LOCK_DIR"},
+			Help:    "Directory of the update lock file shared with the rollback subcommand (defaults to the temporary directory)",
+		},
 	}
 }
 
@@ -0,0 +1,21 @@
+// Package heartbeat sends dead-man's-switch pings to an external monitor
+// around every update scan.
+//
+// A monitor such as healthchecks.io or an Uptime Kuma push monitor alerts when
+// the expected pings stop arriving, which catches a Watchtower that has silently
+// stopped scanning (hung Docker socket, crashed container) as well as failed
+// scans.
+//
+// Key components:
+//   - Heartbeat: Sends the start, success, and failure pings.
+//   - Result: Scan outcome reported by Finish.
+//
+// Usage example:
+//
+//	hb, err := heartbeat.New(log, "https://hc-ping.com/<uuid>", heartbeat.FormatHealthchecks, 10*time.Second)
+//	if err != nil {
+//	    return err
+//	}
+//	hb.Start(ctx)
+//	hb.Finish(ctx, heartbeat.Result{Scanned: 5, Duration: time.Since(start)})
+package heartbeat
@@ -0,0 +1,279 @@
+package heartbeat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Supported ping formats.
+const (
+	// FormatHealthchecks pings <url>/start, <url>, and <url>/fail with a text body.
+	FormatHealthchecks = "healthchecks"
+	// FormatUptimeKuma pings an Uptime Kuma push URL with status, msg, and ping query parameters.
+	FormatUptimeKuma = "uptime-kuma"
+)
+
+// defaultTimeout bounds each ping when no timeout is configured.
+const defaultTimeout = 10 * time.Second
+
+// Errors returned by the heartbeat.
+var (
+	// ErrInvalidFormat indicates an unsupported ping format.
+	ErrInvalidFormat = errors.New("invalid heartbeat format")
+	// ErrInvalidURL indicates the ping URL is not an absolute HTTP(S) URL.
+	ErrInvalidURL = errors.New("invalid heartbeat URL")
+	// ErrPingRejected indicates the monitor returned a non-2xx status.
+	ErrPingRejected = errors.New("heartbeat ping rejected")
+)
+
+// Result is the outcome of a scan reported by Finish.
+type Result struct {
+	// Scanned is the number of containers scanned.
+	Scanned int
+	// Updated is the number of containers updated.
+	Updated int
+	// Failed is the number of containers whose update failed.
+	Failed int
+	// Duration is the wall-clock duration of the scan.
+	Duration time.Duration
+	// Err is the error that aborted the scan, if any.
+	Err error
+}
+
+// failed reports whether the result should be sent as a failure ping.
+//
+// Returns:
+//   - bool: True if the scan aborted or any container failed.
+func (r Result) failed() bool {
+	return r.Err != nil || r.Failed > 0
+}
+
+// summary formats the result as a single line.
+//
+// Returns:
+//   - string: The counts and duration, followed by the error when present.
+func (r Result) summary() string {
+	text := fmt.Sprintf("failed=%d scanned=%d updated=%d duration=%s",
+		r.Failed, r.Scanned, r.Updated, r.Duration.Round(time.Millisecond))
+	if r.Err != nil {
+		text += " error=" + r.Err.Error()
+	}
+
+	return text
+}
+
+// Heartbeat sends start, success, and failure pings to an external monitor.
+type Heartbeat struct {
+	log    *zerolog.Logger
+	url    *url.URL
+	format string
+	client *http.Client
+}
+
+// New creates a heartbeat for the given ping URL.
+//
+// Parameters:
+//   - log: Logger for ping failures; nil disables logging.
+//   - rawURL: The monitor's ping URL.
+//   - format: FormatHealthchecks or FormatUptimeKuma; empty uses FormatHealthchecks.
+//   - timeout: Per-ping timeout; zero uses a 10 second default.
+//
+// Returns:
+//   - *Heartbeat: The configured heartbeat.
+//   - error: Non-nil if the URL or format is invalid.
+func New(log *zerolog.Logger, rawURL, format string, timeout time.Duration) (*Heartbeat, error) {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	switch format {
+	case "":
+		format = FormatHealthchecks
+	case FormatHealthchecks, FormatUptimeKuma:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidFormat, format)
+	}
+
+	pingURL, err := url.Parse(rawURL)
+	if err != nil || (pingURL.Scheme != "http" && pingURL.Scheme != "https") || pingURL.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidURL, rawURL)
+	}
+
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	return &Heartbeat{
+		log:    log,
+		url:    pingURL,
+		format: format,
+		client: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Start pings the monitor that a scan has started.
+//
+// Uptime Kuma has no start state, so no ping is sent in that format.
+//
+// Parameters:
+//   - ctx: Scan context; cancellation does not stop the ping.
+func (h *Heartbeat) Start(ctx context.Context) {
+	if h.format == FormatUptimeKuma {
+		return
+	}
+
+	h.send(ctx, h.healthchecksURL("/start"), "scan started")
+}
+
+// Finish pings the monitor with the outcome of a scan.
+//
+// A scan that aborted or left any container failed is sent as a failure.
+//
+// Parameters:
+//   - ctx: Scan context; cancellation does not stop the ping.
+//   - result: The scan outcome.
+func (h *Heartbeat) Finish(ctx context.Context, result Result) {
+	if h.format == FormatUptimeKuma {
+		h.send(ctx, h.uptimeKumaURL(result), "")
+
+		return
+	}
+
+	suffix := ""
+	if result.failed() {
+		suffix = "/fail"
+	}
+
+	h.send(ctx, h.healthchecksURL(suffix), result.summary())
+}
+
+// healthchecksURL returns the ping URL with a path suffix appended.
+//
+// Parameters:
+//   - suffix: Path suffix such as "/start" or "/fail"; empty for success.
+//
+// Returns:
+//   - string: The ping URL.
+func (h *Heartbeat) healthchecksURL(suffix string) string {
+	pingURL := *h.url
+	pingURL.Path = strings.TrimSuffix(pingURL.Path, "/") + suffix
+
+	return pingURL.String()
+}
+
+// uptimeKumaURL returns the push URL with the result encoded in the query.
+//
+// Parameters:
+//   - result: The scan outcome.
+//
+// Returns:
+//   - string: The push URL with status, msg, and ping set.
+func (h *Heartbeat) uptimeKumaURL(result Result) string {
+	status := "up"
+	if result.failed() {
+		status = "down"
+	}
+
+	pingURL := *h.url
+	query := pingURL.Query()
+	query.Set("status", status)
+	query.Set("msg", result.summary())
+	query.Set("ping", strconv.FormatInt(result.Duration.Milliseconds(), 10))
+	pingURL.RawQuery = query.Encode()
+
+	return pingURL.String()
+}
+
+// send delivers one ping and logs failures.
+//
+// Parameters:
+//   - ctx: Scan context; cancellation does not stop the ping.
+//   - pingURL: The URL to ping.
+//   - body: Text body; empty sends a GET request.
+func (h *Heartbeat) send(ctx context.Context, pingURL, body string) {
+	err := h.ping(context.WithoutCancel(ctx), pingURL, body)
+	if err != nil {
+		h.log.Warn().Err(err).Msg("Failed to send heartbeat ping")
+
+		return
+	}
+
+	h.log.Debug().Str("url", redact(pingURL)).Msg("Sent heartbeat ping")
+}
+
+// ping performs the HTTP request for one ping.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - pingURL: The URL to ping.
+//   - body: Text body; empty sends a GET request.
+//
+// Returns:
+//   - error: Non-nil if the request fails or the monitor returns a non-2xx status.
+func (h *Heartbeat) ping(ctx context.Context, pingURL, body string) error {
+	method := http.MethodGet
+
+	var reader io.Reader
+
+	if body != "" {
+		method = http.MethodPost
+		reader = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, pingURL, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create heartbeat request: %w", err)
+	}
+
+	if body != "" {
+		req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		// Drop the *url.Error wrapper, which repeats the full URL including the token.
+		urlErr := &url.Error{}
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return fmt.Errorf("failed to send heartbeat to %s: %w", redact(pingURL), err)
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %s returned status %d", ErrPingRejected, redact(pingURL), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// redact strips the path and query from a ping URL for logging, since both
+// commonly carry the monitor's secret token.
+//
+// Parameters:
+//   - pingURL: The URL to redact.
+//
+// Returns:
+//   - string: Scheme and host only.
+func redact(pingURL string) string {
+	parsed, err := url.Parse(pingURL)
+	if err != nil {
+		return "<invalid URL>"
+	}
+
+	return parsed.Scheme + "://" + parsed.Host
+}
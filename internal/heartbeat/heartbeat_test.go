@@ -0,0 +1,163 @@
+package heartbeat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errScan = errors.New("docker socket unavailable")
+
+type ping struct {
+	method string
+	path   string
+	query  map[string]string
+	body   string
+}
+
+func newPingServer(t *testing.T, status int) (*httptest.Server, func() []ping) {
+	t.Helper()
+
+	var (
+		mu    sync.Mutex
+		pings []ping
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		query := make(map[string]string)
+		for key := range r.URL.Query() {
+			query[key] = r.URL.Query().Get(key)
+		}
+
+		mu.Lock()
+		pings = append(pings, ping{method: r.Method, path: r.URL.Path, query: query, body: string(body)})
+		mu.Unlock()
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []ping {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return append([]ping(nil), pings...)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(nil, "https://hc-ping.com/abc", "statuspage", 0)
+	require.ErrorIs(t, err, ErrInvalidFormat)
+
+	for _, rawURL := range []string{"", "hc-ping.com/abc", "ftp://hc-ping.com/abc", "https://"} {
+		_, err = New(nil, rawURL, FormatHealthchecks, 0)
+		require.ErrorIs(t, err, ErrInvalidURL, rawURL)
+	}
+
+	hb, err := New(nil, "https://hc-ping.com/abc", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, FormatHealthchecks, hb.format)
+	assert.Equal(t, defaultTimeout, hb.client.Timeout)
+}
+
+func TestHeartbeat_Healthchecks(t *testing.T) {
+	t.Parallel()
+
+	server, pings := newPingServer(t, http.StatusOK)
+
+	hb, err := New(nil, server.URL+"/ping/abc/", FormatHealthchecks, time.Second)
+	require.NoError(t, err)
+
+	hb.Start(context.Background())
+	hb.Finish(context.Background(), Result{Scanned: 4, Updated: 1, Duration: 1500 * time.Millisecond})
+	hb.Finish(context.Background(), Result{Scanned: 4, Failed: 2, Duration: time.Second})
+	hb.Finish(context.Background(), Result{Err: errScan})
+
+	got := pings()
+	require.Len(t, got, 4)
+
+	assert.Equal(t, "/ping/abc/start", got[0].path)
+	assert.Equal(t, http.MethodPost, got[0].method)
+
+	assert.Equal(t, "/ping/abc", got[1].path)
+	assert.Equal(t, "failed=0 scanned=4 updated=1 duration=1.5s", got[1].body)
+
+	assert.Equal(t, "/ping/abc/fail", got[2].path)
+	assert.Equal(t, "failed=2 scanned=4 updated=0 duration=1s", got[2].body)
+
+	assert.Equal(t, "/ping/abc/fail", got[3].path)
+	assert.Contains(t, got[3].body, "error=docker socket unavailable")
+}
+
+func TestHeartbeat_UptimeKuma(t *testing.T) {
+	t.Parallel()
+
+	server, pings := newPingServer(t, http.StatusOK)
+
+	hb, err := New(nil, server.URL+"/api/push/token?status=up&msg=OK&ping=", FormatUptimeKuma, time.Second)
+	require.NoError(t, err)
+
+	hb.Start(context.Background())
+	hb.Finish(context.Background(), Result{Scanned: 2, Duration: 250 * time.Millisecond})
+	hb.Finish(context.Background(), Result{Scanned: 2, Failed: 1, Duration: time.Second})
+
+	got := pings()
+	require.Len(t, got, 2, "Uptime Kuma has no start ping")
+
+	assert.Equal(t, http.MethodGet, got[0].method)
+	assert.Equal(t, "/api/push/token", got[0].path)
+	assert.Equal(t, "up", got[0].query["status"])
+	assert.Equal(t, "250", got[0].query["ping"])
+	assert.Equal(t, "failed=0 scanned=2 updated=0 duration=250ms", got[0].query["msg"])
+
+	assert.Equal(t, "down", got[1].query["status"])
+	assert.Equal(t, "1000", got[1].query["ping"])
+}
+
+func TestHeartbeat_CanceledContextStillPings(t *testing.T) {
+	t.Parallel()
+
+	server, pings := newPingServer(t, http.StatusOK)
+
+	hb, err := New(nil, server.URL, FormatHealthchecks, time.Second)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	hb.Finish(ctx, Result{Err: context.Canceled})
+	require.Len(t, pings(), 1)
+}
+
+func TestHeartbeat_FailureIsLoggedWithoutToken(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newPingServer(t, http.StatusNotFound)
+
+	var buf bytes.Buffer
+
+	log := zerolog.New(&buf)
+
+	hb, err := New(&log, server.URL+"/secret-token", FormatHealthchecks, time.Second)
+	require.NoError(t, err)
+
+	hb.Start(context.Background())
+
+	assert.Contains(t, buf.String(), "Failed to send heartbeat ping")
+	assert.Contains(t, buf.String(), "status 404")
+	assert.NotContains(t, buf.String(), "secret-token")
+}
@@ -0,0 +1,20 @@
+// Package hostlock provides the update lock shared by Watchtower processes on a host.
+//
+// The daemon serializes scans and rollbacks with an in-process lock. Commands
+// run in a separate process, such as `docker exec watchtower /watchtower rollback`,
+// take the same lock through a file so they wait for a running scan instead of
+// racing it.
+//
+// Key components:
+//   - Path: Returns the lock file path for a scope.
+//   - Acquire: Waits for the lock, polling until it is free or the context ends.
+//   - Lock.Release: Releases the lock.
+//
+// Usage example:
+//
+//	lock, err := hostlock.Acquire(ctx, hostlock.Path(dir, scope))
+//	if err != nil {
+//	    return err
+//	}
+//	defer lock.Release()
+package hostlock
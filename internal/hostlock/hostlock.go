@@ -0,0 +1,109 @@
+package hostlock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// pollInterval is how often Acquire retries a held lock.
+const pollInterval = 100 * time.Millisecond
+
+// lockFileMode is the permission mode of the lock file.
+const lockFileMode = 0o600
+
+// Errors for the host lock.
+var (
+	// errOpenLockFile indicates the lock file could not be opened.
+	errOpenLockFile = errors.New("failed to open lock file")
+	// errLockFailed indicates the lock file could not be locked.
+	errLockFailed = errors.New("failed to lock file")
+	// errWaitCanceled indicates the context ended while the lock was held elsewhere.
+	errWaitCanceled = errors.New("canceled waiting for update lock")
+)
+
+// Lock is a held host lock.
+type Lock struct {
+	file *os.File
+}
+
+// Path returns the lock file path for a scope.
+//
+// Watchtower instances with different scopes update different containers, so
+// each scope has its own lock. Processes only exclude each other when they see
+// the same directory, which the temporary directory of separate containers is not.
+//
+// Parameters:
+//   - dir: Directory of the lock file, empty for the temporary directory.
+//   - scope: The configured scope, empty for none.
+//
+// Returns:
+//   - string: Path of the lock file.
+func Path(dir, scope string) string {
+	name := "watchtower.lock"
+	if scope != "" {
+		name = "watchtower-" + filepath.Base(scope) + ".lock"
+	}
+
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// Acquire takes the lock, waiting while another process holds it.
+//
+// Parameters:
+//   - ctx: Context ending the wait.
+//   - path: Lock file path, created if missing.
+//
+// Returns:
+//   - *Lock: The held lock.
+//   - error: Non-nil if the file cannot be opened or locked, or ctx ends first.
+func Acquire(ctx context.Context, path string) (*Lock, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, lockFileMode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errOpenLockFile, err)
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		locked, err := tryLock(file)
+		if err != nil {
+			_ = file.Close()
+
+			return nil, fmt.Errorf("%w: %s: %w", errLockFailed, path, err)
+		}
+
+		if locked {
+			return &Lock{file: file}, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			_ = file.Close()
+
+			return nil, fmt.Errorf("%w: %w", errWaitCanceled, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// Release releases the lock.
+//
+// Closing the file releases it, so a lock is also released when the process exits.
+func (l *Lock) Release() {
+	if l == nil || l.file == nil {
+		return
+	}
+
+	_ = unlock(l.file)
+	_ = l.file.Close()
+	l.file = nil
+}
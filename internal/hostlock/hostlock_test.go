@@ -0,0 +1,61 @@
+package hostlock
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAcquire_WaitsForRelease verifies that a held lock blocks a second
+// Acquire until it is released.
+func TestAcquire_WaitsForRelease(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "watchtower.lock")
+
+	lock, err := Acquire(context.Background(), path)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*pollInterval)
+	defer cancel()
+
+	_, err = Acquire(ctx, path)
+	require.ErrorIs(t, err, errWaitCanceled)
+
+	lock.Release()
+
+	second, err := Acquire(context.Background(), path)
+	require.NoError(t, err)
+
+	second.Release()
+}
+
+// TestAcquire_OpenFailure verifies that an unusable lock path is reported.
+func TestAcquire_OpenFailure(t *testing.T) {
+	t.Parallel()
+
+	_, err := Acquire(context.Background(), filepath.Join(t.TempDir(), "missing", "watchtower.lock"))
+	require.ErrorIs(t, err, errOpenLockFile)
+}
+
+// TestPath_Scope verifies that each scope has its own lock file.
+func TestPath_Scope(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "watchtower.lock", filepath.Base(Path("", "")))
+	assert.Equal(t, "watchtower-prod.lock", filepath.Base(Path("", "prod")))
+	assert.NotEqual(t, Path("", ""), Path("", "prod"))
+}
+
+// TestPath_Dir verifies that a configured directory replaces the temporary directory.
+func TestPath_Dir(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, filepath.Join(os.TempDir(), "watchtower.lock"), Path("", ""))
+	assert.Equal(t, filepath.Join("/data", "watchtower-prod.lock"), Path("/data", "prod"))
+
+}
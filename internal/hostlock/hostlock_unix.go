@@ -0,0 +1,37 @@
+//go:build unix
+
+package hostlock
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// tryLock takes an exclusive lock on file without blocking.
+//
+// Parameters:
+//   - file: The open lock file.
+//
+// Returns:
+//   - bool: True if the lock was taken, false if another process holds it.
+//   - error: Non-nil if locking fails for another reason.
+func tryLock(file *os.File) (bool, error) {
+	err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// unlock releases the lock on file.
+//
+// Parameters:
+//   - file: The locked file.
+//
+// Returns:
+//   - error: Non-nil if unlocking fails.
+func unlock(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
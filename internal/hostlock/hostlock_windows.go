@@ -0,0 +1,45 @@
+//go:build windows
+
+package hostlock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLock takes an exclusive lock on file without blocking.
+//
+// Parameters:
+//   - file: The open lock file.
+//
+// Returns:
+//   - bool: True if the lock was taken, false if another process holds it.
+//   - error: Non-nil if locking fails for another reason.
+func tryLock(file *os.File) (bool, error) {
+	err := windows.LockFileEx(
+		windows.Handle(file.Fd()),
+		windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY,
+		0,
+		1,
+		0,
+		&windows.Overlapped{},
+	)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// unlock releases the lock on file.
+//
+// Parameters:
+//   - file: The locked file.
+//
+// Returns:
+//   - error: Non-nil if unlocking fails.
+func unlock(file *os.File) error {
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &windows.Overlapped{})
+}
@@ -0,0 +1,217 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files so they sort chronologically.
+const backupTimeFormat = "20060102T150405.000"
+
+// logFilePerm is the permission of created log files.
+const logFilePerm = 0o640
+
+// rotatingFile appends to a log file and rotates it by size and age.
+//
+// A rotated file is renamed to <path>.<UTC timestamp>. Only the newest
+// maxBackups rotated files are kept.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// newRotatingFile opens or creates the log file.
+//
+// Parameters:
+//   - path: The log file path.
+//   - maxSize: Rotate before the file exceeds this many bytes; zero disables size rotation.
+//   - maxAge: Rotate once the file is older than this; zero disables age rotation.
+//   - maxBackups: Number of rotated files kept; zero keeps all.
+//
+// Returns:
+//   - *rotatingFile: The open file.
+//   - error: Non-nil if the file cannot be opened.
+func newRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*rotatingFile, error) {
+	file := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+
+	err := file.open()
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Write appends p, rotating first when it would exceed the size or age limit.
+//
+// Parameters:
+//   - p: The encoded log line.
+//
+// Returns:
+//   - int: Bytes written.
+//   - error: Non-nil if rotation or the write fails.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	tooLarge := f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize
+	tooOld := f.maxAge > 0 && f.now().Sub(f.openedAt) >= f.maxAge
+
+	if tooLarge || tooOld {
+		err := f.rotate()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	written, err := f.file.Write(p)
+	f.size += int64(written)
+
+	if err != nil {
+		return written, fmt.Errorf("failed to write log file: %w", err)
+	}
+
+	return written, nil
+}
+
+// Close closes the file.
+//
+// Returns:
+//   - error: Non-nil if the file cannot be closed.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	if err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	return nil
+}
+
+// open opens the log file for appending. An existing file's age is taken from
+// its modification time. f.mu must be held or f not yet shared.
+//
+// Returns:
+//   - error: Non-nil if the file cannot be opened.
+func (f *rotatingFile) open() error {
+	err := os.MkdirAll(filepath.Dir(f.path), 0o750)
+	if err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, logFilePerm)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = f.now()
+
+	if f.size > 0 {
+		f.openedAt = info.ModTime()
+	}
+
+	return nil
+}
+
+// rotate renames the current file, opens a new one, and prunes old backups. f.mu must be held.
+//
+// Returns:
+//   - error: Non-nil if the file cannot be renamed or reopened.
+func (f *rotatingFile) rotate() error {
+	_ = f.file.Close()
+	f.file = nil
+
+	backup := f.path + "." + f.now().UTC().Format(backupTimeFormat)
+
+	err := os.Rename(f.path, backup)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	err = f.open()
+	if err != nil {
+		return err
+	}
+
+	f.prune()
+
+	return nil
+}
+
+// prune removes all but the newest maxBackups rotated files. f.mu must be held.
+func (f *rotatingFile) prune() {
+	if f.maxBackups == 0 {
+		return
+	}
+
+	dir, base := filepath.Split(f.path)
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var backups []string
+
+	for _, entry := range entries {
+		suffix, ok := strings.CutPrefix(entry.Name(), base+".")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		_, err := time.Parse(backupTimeFormat, suffix)
+		if err == nil {
+			backups = append(backups, entry.Name())
+		}
+	}
+
+	slices.Sort(backups)
+
+	for len(backups) > f.maxBackups {
+		_ = os.Remove(filepath.Join(dir, backups[0]))
+		backups = backups[1:]
+	}
+}
@@ -0,0 +1,177 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// GELF UDP chunking limits.
+const (
+	// gelfChunkSize is the payload per UDP chunk, below common path MTUs.
+	gelfChunkSize = 1420
+	// gelfMaxChunks is the protocol's chunk limit; larger messages are dropped.
+	gelfMaxChunks = 128
+)
+
+// errGELFTooLarge indicates a message that does not fit 128 UDP chunks.
+var errGELFTooLarge = errors.New("GELF message too large")
+
+// gelfChunkMagic starts every chunked GELF datagram.
+var gelfChunkMagic = []byte{0x1e, 0x0f}
+
+// unsafeGELFFieldChars matches characters not allowed in GELF additional field names.
+var unsafeGELFFieldChars = regexp.MustCompile(`[^\w.\-]`)
+
+// gelfWriter sends zerolog events as GELF 1.1 messages.
+//
+// Event fields become additional fields, e.g. container becomes _container.
+// UDP messages larger than one datagram are chunked; TCP messages are
+// null-byte delimited.
+type gelfWriter struct {
+	conn     *netConn
+	hostname string
+}
+
+// newGELFWriter creates a GELF writer for the output.
+//
+// Parameters:
+//   - output: The GELF output.
+//   - hostname: The host field.
+//
+// Returns:
+//   - *gelfWriter: The writer.
+func newGELFWriter(output Output, hostname string) *gelfWriter {
+	return &gelfWriter{
+		conn:     newNetConn(output.Network, output.Address, false),
+		hostname: hostname,
+	}
+}
+
+// Write sends one event.
+//
+// Parameters:
+//   - p: The zerolog JSON event.
+//
+// Returns:
+//   - int: len(p) on success.
+//   - error: Non-nil if the event cannot be encoded or sent.
+func (w *gelfWriter) Write(p []byte) (int, error) {
+	decoded, err := decodeEvent(p)
+	if err != nil {
+		return 0, err
+	}
+
+	message, err := w.encode(decoded)
+	if err != nil {
+		return 0, err
+	}
+
+	if w.conn.stream() {
+		err = w.conn.send(append(message, 0))
+	} else {
+		var chunks [][]byte
+
+		chunks, err = gelfChunks(message)
+		if err == nil {
+			err = w.conn.send(chunks...)
+		}
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection.
+//
+// Returns:
+//   - error: Always nil.
+func (w *gelfWriter) Close() error {
+	return w.conn.Close()
+}
+
+// encode renders an event as a GELF 1.1 JSON message.
+//
+// Parameters:
+//   - decoded: The event.
+//
+// Returns:
+//   - []byte: The JSON message.
+//   - error: Non-nil if the message cannot be encoded.
+func (w *gelfWriter) encode(decoded event) ([]byte, error) {
+	shortMessage := decoded.message
+	if shortMessage == "" {
+		shortMessage = "-"
+	}
+
+	message := map[string]any{
+		"version":       "1.1",
+		"host":          w.hostname,
+		"short_message": shortMessage,
+		"timestamp":     float64(decoded.time.UnixMilli()) / 1000,
+		"level":         severity(decoded.level),
+	}
+
+	for name, value := range decoded.fields {
+		field := "_" + unsafeGELFFieldChars.ReplaceAllString(name, "_")
+		if field == "_id" {
+			field = "_id_"
+		}
+
+		// GELF accepts only string and number values.
+		switch value.(type) {
+		case string, json.Number:
+			message[field] = value
+		default:
+			message[field] = fieldString(value)
+		}
+	}
+
+	encoded, err := json.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode GELF message: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// gelfChunks splits a message into GELF UDP chunks.
+//
+// Parameters:
+//   - message: The JSON message.
+//
+// Returns:
+//   - [][]byte: The message itself when it fits one datagram, otherwise its chunks.
+//   - error: Non-nil if the message needs more than 128 chunks.
+func gelfChunks(message []byte) ([][]byte, error) {
+	if len(message) <= gelfChunkSize {
+		return [][]byte{message}, nil
+	}
+
+	count := (len(message) + gelfChunkSize - 1) / gelfChunkSize
+	if count > gelfMaxChunks {
+		return nil, fmt.Errorf("%w: %d bytes", errGELFTooLarge, len(message))
+	}
+
+	messageID := make([]byte, 8)
+	_, _ = rand.Read(messageID)
+
+	chunks := make([][]byte, 0, count)
+
+	for index := range count {
+		end := min((index+1)*gelfChunkSize, len(message))
+
+		chunk := append([]byte{}, gelfChunkMagic...)
+		chunk = append(chunk, messageID...)
+		chunk = append(chunk, byte(index), byte(count))
+		chunk = append(chunk, message[index*gelfChunkSize:end]...)
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,146 @@
+package logging
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// Network output timing.
+const (
+	networkWriteTimeout = 5 * time.Second
+	// networkRetryDelay is how long messages are dropped after a failed connection attempt.
+	networkRetryDelay = 30 * time.Second
+)
+
+// netConn is a lazily dialed, self-healing connection to a log server.
+//
+// A write error closes the connection and the next write redials. After a
+// failed dial, messages are dropped silently for networkRetryDelay so an
+// unreachable server neither stalls nor floods stderr with write errors.
+type netConn struct {
+	network   string
+	address   string
+	tlsConfig *tls.Config
+
+	mu       sync.Mutex
+	conn     net.Conn
+	failedAt time.Time
+}
+
+// newNetConn creates a connection for the given output network.
+//
+// Parameters:
+//   - network: udp, tcp, or tls.
+//   - address: The server host:port.
+//   - tlsSkipVerify: Disables certificate verification for tls.
+//
+// Returns:
+//   - *netConn: The unconnected connection.
+func newNetConn(network, address string, tlsSkipVerify bool) *netConn {
+	conn := &netConn{network: network, address: address}
+
+	if network == "tls" {
+		host, _, _ := net.SplitHostPort(address)
+		conn.tlsConfig = &tls.Config{
+			ServerName:         host,
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: tlsSkipVerify, //nolint:gosec // Opt-in for self-signed log servers.
+		}
+	}
+
+	return conn
+}
+
+// stream reports whether the connection is stream-oriented and needs message framing.
+//
+// Returns:
+//   - bool: True for tcp and tls.
+func (c *netConn) stream() bool {
+	return c.network != "udp"
+}
+
+// send writes each packet, dialing first if needed.
+//
+// Parameters:
+//   - packets: Datagrams for udp, or framed messages for tcp and tls.
+//
+// Returns:
+//   - error: Non-nil if dialing or the write fails; nil while messages are dropped.
+func (c *netConn) send(packets ...[]byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if time.Since(c.failedAt) < networkRetryDelay {
+			return nil
+		}
+
+		err := c.dial()
+		if err != nil {
+			c.failedAt = time.Now()
+
+			return err
+		}
+	}
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(networkWriteTimeout))
+
+	for _, packet := range packets {
+		_, err := c.conn.Write(packet)
+		if err != nil {
+			_ = c.conn.Close()
+			c.conn = nil
+
+			return fmt.Errorf("failed to write to log output %s: %w", c.address, err)
+		}
+	}
+
+	return nil
+}
+
+// dial opens the connection. c.mu must be held.
+//
+// Returns:
+//   - error: Non-nil if the server cannot be reached.
+func (c *netConn) dial() error {
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	dialer := &net.Dialer{Timeout: defaultDialTimeout}
+
+	switch c.network {
+	case "tls":
+		conn, err = tls.DialWithDialer(dialer, "tcp", c.address, c.tlsConfig)
+	default:
+		conn, err = dialer.Dial(c.network, c.address)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to connect to log output %s: %w", c.address, err)
+	}
+
+	c.conn = conn
+
+	return nil
+}
+
+// Close closes the connection.
+//
+// Returns:
+//   - error: Always nil.
+func (c *netConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+
+	return nil
+}
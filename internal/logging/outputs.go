@@ -0,0 +1,531 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Output kinds.
+const (
+	// OutputSyslog sends RFC 5424 syslog messages.
+	OutputSyslog = "syslog"
+	// OutputGELF sends Graylog Extended Log Format messages.
+	OutputGELF = "gelf"
+	// OutputFile appends to a local file with size and age rotation.
+	OutputFile = "file"
+)
+
+// Output formats.
+const (
+	// FormatText sends the message text as the syslog MSG.
+	FormatText = "text"
+	// FormatJSON writes the zerolog JSON event.
+	FormatJSON = "json"
+	// FormatLogfmt writes key=value lines.
+	FormatLogfmt = "logfmt"
+)
+
+// Default output settings.
+const (
+	defaultSyslogTag      = "watchtower"
+	defaultSyslogFacility = "daemon"
+	defaultMaxSize        = 100 * 1024 * 1024
+	defaultMaxBackups     = 5
+	defaultDialTimeout    = 5 * time.Second
+)
+
+// Errors returned when parsing outputs.
+var (
+	// ErrInvalidLogOutput indicates a log output URL that cannot be parsed.
+	ErrInvalidLogOutput = errors.New("invalid log output")
+	// ErrUnsupportedOutputFormat indicates a format the output kind cannot write.
+	ErrUnsupportedOutputFormat = errors.New("unsupported log output format")
+)
+
+// syslogFacilities maps facility names to RFC 5424 facility codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// Output is a parsed log output.
+//
+// Outputs are written as URLs:
+//
+//	syslog+udp://host:514?level=warn&tag=watchtower&facility=daemon
+//	syslog+tcp://host:601, syslog+tls://host:6514?tls_skip_verify=true
+//	gelf+udp://host:12201, gelf+tcp://host:12201
+//	file:///var/log/watchtower.log?format=logfmt&max_size=10MB&max_age=168h&max_backups=5
+type Output struct {
+	// Kind is OutputSyslog, OutputGELF, or OutputFile.
+	Kind string
+	// Network is udp, tcp, or tls for network outputs.
+	Network string
+	// Address is the host:port of a network output.
+	Address string
+	// Path is the file path of a file output.
+	Path string
+	// Level is the minimum level written; zerolog.NoLevel uses the console level.
+	Level zerolog.Level
+	// Format selects the message encoding; see ParseOutput for the formats of each kind.
+	Format string
+	// Tag is the syslog APP-NAME.
+	Tag string
+	// Facility is the syslog facility code.
+	Facility int
+	// Hostname overrides the host reported in syslog and GELF messages.
+	Hostname string
+	// TLSSkipVerify disables server certificate verification for syslog over TLS.
+	TLSSkipVerify bool
+	// MaxSize rotates the file before it grows beyond this many bytes; zero disables size rotation.
+	MaxSize int64
+	// MaxAge rotates the file once it is older than this; zero disables age rotation.
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept; zero keeps all.
+	MaxBackups int
+}
+
+// ParseOutput parses a log output URL.
+//
+// Formats: syslog supports text (default) and json; GELF always writes GELF
+// JSON; file supports json (default) and logfmt.
+//
+// Parameters:
+//   - raw: The output URL.
+//
+// Returns:
+//   - Output: The parsed output.
+//   - error: Non-nil if the URL, kind, or a parameter is invalid.
+func ParseOutput(raw string) (Output, error) {
+	parsed, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return Output{}, fmt.Errorf("%w: %w", ErrInvalidLogOutput, err)
+	}
+
+	kind, network, _ := strings.Cut(parsed.Scheme, "+")
+	output := Output{Kind: kind, Network: network, Level: zerolog.NoLevel}
+	query := parsed.Query()
+
+	switch kind {
+	case OutputSyslog, OutputGELF:
+		err = output.parseNetwork(parsed)
+	case OutputFile:
+		err = output.parseFile(parsed, query)
+	default:
+		err = fmt.Errorf("%w: unknown scheme %q", ErrInvalidLogOutput, parsed.Scheme)
+	}
+
+	if err != nil {
+		return Output{}, err
+	}
+
+	if rawLevel := query.Get("level"); rawLevel != "" {
+		output.Level, err = ParseLevel(rawLevel)
+		if err != nil {
+			return Output{}, fmt.Errorf("%w: %w", ErrInvalidLogOutput, err)
+		}
+	}
+
+	output.Format = strings.ToLower(query.Get("format"))
+	output.Hostname = query.Get("hostname")
+
+	err = output.parseFormat(query)
+	if err != nil {
+		return Output{}, err
+	}
+
+	return output, nil
+}
+
+// parseNetwork reads the network and address of a syslog or GELF output.
+//
+// Parameters:
+//   - parsed: The output URL.
+//
+// Returns:
+//   - error: Non-nil if the network or host is invalid.
+func (o *Output) parseNetwork(parsed *url.URL) error {
+	if o.Network == "" {
+		o.Network = "udp"
+	}
+
+	switch {
+	case o.Network == "udp", o.Network == "tcp":
+	case o.Network == "tls" && o.Kind == OutputSyslog:
+	default:
+		return fmt.Errorf("%w: unsupported %s network %q", ErrInvalidLogOutput, o.Kind, o.Network)
+	}
+
+	if parsed.Hostname() == "" || parsed.Port() == "" {
+		return fmt.Errorf("%w: %s output needs host:port", ErrInvalidLogOutput, o.Kind)
+	}
+
+	o.Address = parsed.Host
+
+	return nil
+}
+
+// parseFile reads the path and rotation settings of a file output.
+//
+// Parameters:
+//   - parsed: The output URL.
+//   - query: The URL query.
+//
+// Returns:
+//   - error: Non-nil if the path or a rotation setting is invalid.
+func (o *Output) parseFile(parsed *url.URL, query url.Values) error {
+	o.Path = parsed.Path
+	if parsed.Opaque != "" {
+		o.Path = parsed.Opaque
+	}
+
+	if o.Network != "" || o.Path == "" {
+		return fmt.Errorf("%w: file output needs a path, e.g. file:///var/log/watchtower.log", ErrInvalidLogOutput)
+	}
+
+	o.MaxSize = defaultMaxSize
+	o.MaxBackups = defaultMaxBackups
+
+	var err error
+
+	if raw := query.Get("max_size"); raw != "" {
+		o.MaxSize, err = parseSize(raw)
+		if err != nil {
+			return err
+		}
+	}
+
+	if raw := query.Get("max_age"); raw != "" {
+		o.MaxAge, err = time.ParseDuration(raw)
+		if err != nil || o.MaxAge < 0 {
+			return fmt.Errorf("%w: max_age %q", ErrInvalidLogOutput, raw)
+		}
+	}
+
+	if raw := query.Get("max_backups"); raw != "" {
+		o.MaxBackups, err = strconv.Atoi(raw)
+		if err != nil || o.MaxBackups < 0 {
+			return fmt.Errorf("%w: max_backups %q", ErrInvalidLogOutput, raw)
+		}
+	}
+
+	return nil
+}
+
+// parseFormat validates the format and the syslog settings.
+//
+// Parameters:
+//   - query: The URL query.
+//
+// Returns:
+//   - error: Non-nil if the format or syslog facility is invalid.
+func (o *Output) parseFormat(query url.Values) error {
+	supported := map[string][]string{
+		OutputSyslog: {FormatText, FormatJSON},
+		OutputGELF:   {OutputGELF},
+		OutputFile:   {FormatJSON, FormatLogfmt},
+	}[o.Kind]
+
+	if o.Format == "" {
+		o.Format = supported[0]
+	}
+
+	valid := false
+
+	for _, format := range supported {
+		valid = valid || format == o.Format
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: %s output cannot write %q", ErrUnsupportedOutputFormat, o.Kind, o.Format)
+	}
+
+	if o.Kind != OutputSyslog {
+		return nil
+	}
+
+	o.Tag = query.Get("tag")
+	if o.Tag == "" {
+		o.Tag = defaultSyslogTag
+	}
+
+	facility := strings.ToLower(query.Get("facility"))
+	if facility == "" {
+		facility = defaultSyslogFacility
+	}
+
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		return fmt.Errorf("%w: syslog facility %q", ErrInvalidLogOutput, facility)
+	}
+
+	o.Facility = code
+	o.TLSSkipVerify, _ = strconv.ParseBool(query.Get("tls_skip_verify"))
+
+	return nil
+}
+
+// parseSize parses a byte size with an optional KB, MB, or GB suffix.
+//
+// Parameters:
+//   - raw: The size, e.g. "10MB" or "1048576".
+//
+// Returns:
+//   - int64: The size in bytes.
+//   - error: Non-nil if the size is not a non-negative number.
+func parseSize(raw string) (int64, error) {
+	value := strings.ToUpper(strings.TrimSpace(raw))
+	multiplier := int64(1)
+
+	for _, unit := range []struct {
+		suffix string
+		bytes  int64
+	}{{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30}, {"B", 1}} {
+		if trimmed, ok := strings.CutSuffix(value, unit.suffix); ok {
+			value, multiplier = strings.TrimSpace(trimmed), unit.bytes
+
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || size < 0 {
+		return 0, fmt.Errorf("%w: max_size %q", ErrInvalidLogOutput, raw)
+	}
+
+	return size * multiplier, nil
+}
+
+// Open creates the writer for the output.
+//
+// Network outputs connect lazily, so an unreachable server does not prevent
+// startup; messages are dropped until it is reachable again.
+//
+// Returns:
+//   - io.WriteCloser: Writer accepting zerolog JSON events.
+//   - error: Non-nil if a file output cannot be opened.
+func (o Output) Open() (io.WriteCloser, error) {
+	hostname := o.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	switch o.Kind {
+	case OutputSyslog:
+		return newSyslogWriter(o, hostname), nil
+	case OutputGELF:
+		return newGELFWriter(o, hostname), nil
+	default:
+		file, err := newRotatingFile(o.Path, o.MaxSize, o.MaxAge, o.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+
+		if o.Format == FormatLogfmt {
+			return formattedFile{ConsoleWriter: LogfmtWriter(file), file: file}, nil
+		}
+
+		return file, nil
+	}
+}
+
+// formattedFile writes ConsoleWriter output to a rotating file.
+type formattedFile struct {
+	zerolog.ConsoleWriter
+
+	file *rotatingFile
+}
+
+// Close closes the underlying file.
+//
+// Returns:
+//   - error: Non-nil if the file cannot be closed.
+func (f formattedFile) Close() error {
+	return f.file.Close()
+}
+
+// WithOutputs returns a logger that writes to the console and to every output.
+//
+// The console keeps the level of log; each output filters at its own level, so
+// the returned logger's level is the most verbose of them.
+//
+// Parameters:
+//   - log: The configured console logger; its level becomes the console level.
+//   - console: The console writer from ConfigureWriter.
+//   - outputs: The parsed outputs.
+//
+// Returns:
+//   - *zerolog.Logger: The combined logger.
+//   - []io.Closer: The opened outputs, to close at shutdown.
+//   - error: Non-nil if an output cannot be opened; already opened outputs are closed.
+func WithOutputs(log *zerolog.Logger, console io.Writer, outputs []Output) (*zerolog.Logger, []io.Closer, error) {
+	consoleLevel := log.GetLevel()
+	minLevel := consoleLevel
+	writers := []io.Writer{levelFilter{writer: console, level: consoleLevel}}
+	closers := make([]io.Closer, 0, len(outputs))
+
+	for _, output := range outputs {
+		writer, err := output.Open()
+		if err != nil {
+			for _, closer := range closers {
+				_ = closer.Close()
+			}
+
+			return log, nil, err
+		}
+
+		level := output.Level
+		if level == zerolog.NoLevel {
+			level = consoleLevel
+		}
+
+		minLevel = min(minLevel, level)
+		writers = append(writers, levelFilter{writer: writer, level: level})
+		closers = append(closers, writer)
+	}
+
+	combined := zerolog.New(zerolog.MultiLevelWriter(writers...)).Level(minLevel).With().Timestamp().Logger()
+
+	return &combined, closers, nil
+}
+
+// levelFilter drops events below a minimum level.
+type levelFilter struct {
+	writer io.Writer
+	level  zerolog.Level
+}
+
+// Write passes events without a level through.
+//
+// Parameters:
+//   - p: The encoded event.
+//
+// Returns:
+//   - int: len(p).
+//   - error: The writer's error.
+func (f levelFilter) Write(p []byte) (int, error) {
+	return f.writer.Write(p) //nolint:wrapcheck // Pass-through writer.
+}
+
+// WriteLevel writes events at or above the filter level.
+//
+// Parameters:
+//   - level: The event level.
+//   - p: The encoded event.
+//
+// Returns:
+//   - int: len(p), also for dropped events.
+//   - error: The writer's error.
+func (f levelFilter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < f.level && level != zerolog.NoLevel {
+		return len(p), nil
+	}
+
+	return f.writer.Write(p) //nolint:wrapcheck // Pass-through writer.
+}
+
+// event is a decoded zerolog JSON event.
+type event struct {
+	time    time.Time
+	level   zerolog.Level
+	message string
+	fields  map[string]any
+	raw     []byte
+}
+
+// decodeEvent decodes a zerolog JSON event, separating the standard fields.
+//
+// Parameters:
+//   - p: The encoded event.
+//
+// Returns:
+//   - event: The decoded event.
+//   - error: Non-nil if p is not a JSON object.
+func decodeEvent(p []byte) (event, error) {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+
+	var fields map[string]any
+
+	err := decoder.Decode(&fields)
+	if err != nil {
+		return event{}, fmt.Errorf("failed to decode log event: %w", err)
+	}
+
+	decoded := event{time: time.Now(), level: zerolog.NoLevel, fields: fields, raw: bytes.TrimSpace(p)}
+
+	if raw, ok := fields[zerolog.TimestampFieldName].(string); ok {
+		parsed, err := time.Parse(zerolog.TimeFieldFormat, raw)
+		if err == nil {
+			decoded.time = parsed
+		}
+	}
+
+	if raw, ok := fields[zerolog.LevelFieldName].(string); ok {
+		decoded.level, _ = zerolog.ParseLevel(raw)
+	}
+
+	decoded.message, _ = fields[zerolog.MessageFieldName].(string)
+
+	delete(fields, zerolog.TimestampFieldName)
+	delete(fields, zerolog.LevelFieldName)
+	delete(fields, zerolog.MessageFieldName)
+
+	return decoded, nil
+}
+
+// severity maps a zerolog level to a syslog severity, also used by GELF.
+//
+// Parameters:
+//   - level: The event level.
+//
+// Returns:
+//   - int: The syslog severity (0 emergency through 7 debug).
+func severity(level zerolog.Level) int {
+	switch level {
+	case zerolog.PanicLevel:
+		return 0
+	case zerolog.FatalLevel:
+		return 2
+	case zerolog.ErrorLevel:
+		return 3
+	case zerolog.WarnLevel:
+		return 4
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return 7
+	default:
+		return 6
+	}
+}
+
+// fieldString renders a decoded field value as text.
+//
+// Parameters:
+//   - value: The decoded JSON value.
+//
+// Returns:
+//   - string: Strings as-is, other values as JSON.
+func fieldString(value any) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
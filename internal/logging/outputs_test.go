@@ -0,0 +1,266 @@
+package logging_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nicholas-fedor/watchtower/internal/logging"
+)
+
+func TestParseOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		raw  string
+		want logging.Output
+	}{
+		{
+			name: "syslog defaults to udp and text",
+			raw:  "syslog://logs.example.com:514",
+			want: logging.Output{
+				Kind: logging.OutputSyslog, Network: "udp", Address: "logs.example.com:514",
+				Level: zerolog.NoLevel, Format: logging.FormatText, Tag: "watchtower", Facility: 3,
+			},
+		},
+		{
+			name: "syslog over tls with options",
+			raw:  "syslog+tls://logs.example.com:6514?level=warn&format=json&tag=wt&facility=local3&tls_skip_verify=true",
+			want: logging.Output{
+				Kind: logging.OutputSyslog, Network: "tls", Address: "logs.example.com:6514",
+				Level: zerolog.WarnLevel, Format: logging.FormatJSON, Tag: "wt", Facility: 19, TLSSkipVerify: true,
+			},
+		},
+		{
+			name: "gelf over tcp",
+			raw:  "gelf+tcp://graylog:12201?hostname=docker-01",
+			want: logging.Output{
+				Kind: logging.OutputGELF, Network: "tcp", Address: "graylog:12201",
+				Level: zerolog.NoLevel, Format: logging.OutputGELF, Hostname: "docker-01",
+			},
+		},
+		{
+			name: "file with rotation",
+			raw:  "file:///var/log/watchtower.log?format=logfmt&max_size=10MB&max_age=24h&max_backups=2&level=debug",
+			want: logging.Output{
+				Kind: logging.OutputFile, Path: "/var/log/watchtower.log", Level: zerolog.DebugLevel,
+				Format: logging.FormatLogfmt, MaxSize: 10 << 20, MaxAge: 24 * time.Hour, MaxBackups: 2,
+			},
+		},
+		{
+			name: "file defaults",
+			raw:  "file:///tmp/watchtower.log",
+			want: logging.Output{
+				Kind: logging.OutputFile, Path: "/tmp/watchtower.log", Level: zerolog.NoLevel,
+				Format: logging.FormatJSON, MaxSize: 100 << 20, MaxBackups: 5,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := logging.ParseOutput(tt.raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseOutput_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		raw  string
+		want error
+	}{
+		{raw: "kafka://broker:9092", want: logging.ErrInvalidLogOutput},
+		{raw: "syslog://logs.example.com", want: logging.ErrInvalidLogOutput},
+		{raw: "gelf+tls://graylog:12201", want: logging.ErrInvalidLogOutput},
+		{raw: "syslog://logs:514?facility=nope", want: logging.ErrInvalidLogOutput},
+		{raw: "syslog://logs:514?level=loud", want: logging.ErrInvalidLogOutput},
+		{raw: "file://", want: logging.ErrInvalidLogOutput},
+		{raw: "file:///tmp/w.log?max_size=big", want: logging.ErrInvalidLogOutput},
+		{raw: "file:///tmp/w.log?max_age=-1h", want: logging.ErrInvalidLogOutput},
+		{raw: "file:///tmp/w.log?format=text", want: logging.ErrUnsupportedOutputFormat},
+		{raw: "gelf://graylog:12201?format=json", want: logging.ErrUnsupportedOutputFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := logging.ParseOutput(tt.raw)
+			require.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+// listenUDP starts a UDP listener and returns it with its address.
+func listenUDP(t *testing.T) (net.PacketConn, string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn, conn.LocalAddr().String()
+}
+
+// readPacket reads one datagram.
+func readPacket(t *testing.T, conn net.PacketConn) []byte {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	buf := make([]byte, 65535)
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+
+	return buf[:n]
+}
+
+func TestWithOutputs_Syslog(t *testing.T) {
+	t.Parallel()
+
+	listener, address := listenUDP(t)
+
+	output, err := logging.ParseOutput("syslog://" + address + "?hostname=docker-01&facility=local0")
+	require.NoError(t, err)
+
+	console := &bytes.Buffer{}
+	base := logging.New(console, logging.InfoLevel)
+
+	log, closers, err := logging.WithOutputs(base, console, []logging.Output{output})
+	require.NoError(t, err)
+
+	defer closers[0].Close()
+
+	log.Warn().Str("container", "nginx").Bool("notify", true).Msg("Update failed")
+
+	message := string(readPacket(t, listener))
+
+	assert.True(t, strings.HasPrefix(message, "<132>1 "), message)
+	assert.Contains(t, message, " docker-01 watchtower ")
+	assert.Contains(t, message, `[watchtower@32473 container="nginx" notify="true"] Update failed`)
+	assert.Contains(t, console.String(), "Update failed")
+}
+
+func TestWithOutputs_GELF(t *testing.T) {
+	t.Parallel()
+
+	listener, address := listenUDP(t)
+
+	output, err := logging.ParseOutput("gelf://" + address + "?hostname=docker-01")
+	require.NoError(t, err)
+
+	base := logging.New(&bytes.Buffer{}, logging.InfoLevel)
+
+	log, closers, err := logging.WithOutputs(base, &bytes.Buffer{}, []logging.Output{output})
+	require.NoError(t, err)
+
+	defer closers[0].Close()
+
+	log.Error().Str("container", "nginx").Str("image", "nginx:latest").Int("id", 7).Msg("Update failed")
+
+	var message map[string]any
+	require.NoError(t, json.Unmarshal(readPacket(t, listener), &message))
+
+	assert.Equal(t, "1.1", message["version"])
+	assert.Equal(t, "docker-01", message["host"])
+	assert.Equal(t, "Update failed", message["short_message"])
+	assert.InDelta(t, 3, message["level"], 0)
+	assert.Equal(t, "nginx", message["_container"])
+	assert.Equal(t, "nginx:latest", message["_image"])
+	assert.InDelta(t, 7, message["_id_"], 0)
+	assert.NotContains(t, message, "_level")
+}
+
+func TestWithOutputs_GELFChunking(t *testing.T) {
+	t.Parallel()
+
+	listener, address := listenUDP(t)
+
+	output, err := logging.ParseOutput("gelf+udp://" + address)
+	require.NoError(t, err)
+
+	log, closers, err := logging.WithOutputs(logging.New(&bytes.Buffer{}, logging.InfoLevel), &bytes.Buffer{}, []logging.Output{output})
+	require.NoError(t, err)
+
+	defer closers[0].Close()
+
+	log.Info().Str("body", strings.Repeat("x", 3000)).Msg("large")
+
+	first := readPacket(t, listener)
+	require.GreaterOrEqual(t, len(first), 12)
+	assert.Equal(t, []byte{0x1e, 0x0f}, first[:2])
+	assert.Equal(t, byte(0), first[10])
+	assert.Equal(t, byte(3), first[11])
+}
+
+func TestWithOutputs_LevelPerOutput(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "watchtower.log")
+
+	output, err := logging.ParseOutput("file://" + path + "?level=debug")
+	require.NoError(t, err)
+
+	console := &bytes.Buffer{}
+
+	log, closers, err := logging.WithOutputs(logging.New(console, logging.WarnLevel), console, []logging.Output{output})
+	require.NoError(t, err)
+
+	log.Debug().Msg("debug only in file")
+	log.Warn().Msg("warn everywhere")
+	require.NoError(t, closers[0].Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(content), "debug only in file")
+	assert.Contains(t, string(content), "warn everywhere")
+	assert.NotContains(t, console.String(), "debug only in file")
+	assert.Contains(t, console.String(), "warn everywhere")
+}
+
+func TestWithOutputs_FileRotation(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "watchtower.log")
+
+	output, err := logging.ParseOutput("file://" + path + "?format=logfmt&max_size=200B&max_backups=2")
+	require.NoError(t, err)
+
+	log, closers, err := logging.WithOutputs(logging.New(&bytes.Buffer{}, logging.InfoLevel), &bytes.Buffer{}, []logging.Output{output})
+	require.NoError(t, err)
+
+	for range 6 {
+		log.Info().Str("container", "nginx").Msg(strings.Repeat("a", 120))
+		time.Sleep(5 * time.Millisecond) // Distinct backup timestamps.
+	}
+
+	require.NoError(t, closers[0].Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 3, "current file and two backups")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "container=nginx")
+	assert.NotContains(t, string(content), `"container"`)
+	assert.LessOrEqual(t, len(content), 200)
+}
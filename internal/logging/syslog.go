@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// syslogSDID is the structured data ID carrying event fields. 32473 is the
+// private enterprise number reserved for documentation (RFC 5612).
+const syslogSDID = "watchtower@32473"
+
+// maxSDNameLength is the RFC 5424 limit for structured data parameter names.
+const maxSDNameLength = 32
+
+// syslogWriter sends zerolog events as RFC 5424 syslog messages.
+//
+// Event fields such as container, image, and notify become SD-PARAMs of one
+// SD-ELEMENT. Stream transports use octet-counting framing (RFC 6587).
+type syslogWriter struct {
+	conn     *netConn
+	body     string
+	tag      string
+	facility int
+	hostname string
+	pid      string
+}
+
+// newSyslogWriter creates a syslog writer for the output.
+//
+// Parameters:
+//   - output: The syslog output.
+//   - hostname: The HOSTNAME field.
+//
+// Returns:
+//   - *syslogWriter: The writer.
+func newSyslogWriter(output Output, hostname string) *syslogWriter {
+	return &syslogWriter{
+		conn:     newNetConn(output.Network, output.Address, output.TLSSkipVerify),
+		body:     output.Format,
+		tag:      output.Tag,
+		facility: output.Facility,
+		hostname: hostname,
+		pid:      strconv.Itoa(os.Getpid()),
+	}
+}
+
+// Write sends one event.
+//
+// Parameters:
+//   - p: The zerolog JSON event.
+//
+// Returns:
+//   - int: len(p) on success.
+//   - error: Non-nil if the event cannot be decoded or sent.
+func (w *syslogWriter) Write(p []byte) (int, error) {
+	decoded, err := decodeEvent(p)
+	if err != nil {
+		return 0, err
+	}
+
+	message := []byte(w.format(decoded))
+	if w.conn.stream() {
+		message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+	}
+
+	err = w.conn.send(message)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection.
+//
+// Returns:
+//   - error: Always nil.
+func (w *syslogWriter) Close() error {
+	return w.conn.Close()
+}
+
+// format renders an event as an RFC 5424 message.
+//
+// Parameters:
+//   - decoded: The event.
+//
+// Returns:
+//   - string: The message without transport framing.
+func (w *syslogWriter) format(decoded event) string {
+	var builder strings.Builder
+
+	builder.WriteString("<")
+	builder.WriteString(strconv.Itoa(w.facility*8 + severity(decoded.level)))
+	builder.WriteString(">1 ")
+	builder.WriteString(decoded.time.UTC().Format(time.RFC3339Nano))
+	builder.WriteString(" ")
+	builder.WriteString(headerField(w.hostname))
+	builder.WriteString(" ")
+	builder.WriteString(headerField(w.tag))
+	builder.WriteString(" ")
+	builder.WriteString(w.pid)
+	builder.WriteString(" - ")
+	builder.WriteString(structuredData(decoded.fields))
+
+	message := decoded.message
+	if w.body == FormatJSON {
+		message = string(decoded.raw)
+	}
+
+	if message != "" {
+		builder.WriteString(" ")
+		builder.WriteString(message)
+	}
+
+	return builder.String()
+}
+
+// structuredData renders event fields as one SD-ELEMENT.
+//
+// Parameters:
+//   - fields: The event fields without time, level, and message.
+//
+// Returns:
+//   - string: The SD-ELEMENT, or "-" when there are no fields.
+func structuredData(fields map[string]any) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	var builder strings.Builder
+
+	builder.WriteString("[" + syslogSDID)
+
+	for _, name := range names {
+		builder.WriteString(" ")
+		builder.WriteString(sdName(name))
+		builder.WriteString(`="`)
+		builder.WriteString(sdValueEscaper.Replace(fieldString(fields[name])))
+		builder.WriteString(`"`)
+	}
+
+	builder.WriteString("]")
+
+	return builder.String()
+}
+
+// sdValueEscaper escapes the characters RFC 5424 reserves in PARAM-VALUE.
+var sdValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+
+// sdName makes a field name a valid SD-NAME.
+//
+// Parameters:
+//   - name: The field name.
+//
+// Returns:
+//   - string: Printable ASCII without '=', ' ', ']', or '"', at most 32 characters.
+func sdName(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	if len(safe) > maxSDNameLength {
+		safe = safe[:maxSDNameLength]
+	}
+
+	return safe
+}
+
+// headerField renders a syslog header field, using the NILVALUE for empty values.
+//
+// Parameters:
+//   - value: The field value.
+//
+// Returns:
+//   - string: The value with spaces removed, or "-".
+func headerField(value string) string {
+	value = strings.ReplaceAll(value, " ", "_")
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
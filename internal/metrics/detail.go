@@ -0,0 +1,279 @@
+package metrics
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Per-container series label names, also accepted by SetContainerLabels.
+const (
+	// ContainerLabel is the container name label on per-container series.
+	ContainerLabel = "container"
+	// ImageLabel is the image name label on per-container series.
+	ImageLabel = "image"
+)
+
+// registryStatusError is the status label used when a registry request fails
+// before a response is received.
+const registryStatusError = "error"
+
+// containerState is the last known per-container outcome used to rebuild the
+// labelled container series after every scan.
+type containerState struct {
+	image       string
+	pending     bool
+	lastChecked time.Time
+	lastUpdated time.Time
+}
+
+// containerSeriesKey identifies one exported per-container series after
+// dropped labels have been blanked.
+type containerSeriesKey struct {
+	container string
+	image     string
+}
+
+// newDetailCollectors initializes the per-container, registry, pull, and
+// notification outbox collectors on the metrics handler.
+func (m *Metrics) newDetailCollectors() {
+	containerLabels := []string{ContainerLabel, ImageLabel}
+
+	m.updateAvailable = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "watchtower_container_update_available",
+		Help: "Whether a newer image is available but not applied (1) or not (0); counts containers when labels are dropped",
+	}, containerLabels)
+	m.lastChecked = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "watchtower_container_last_checked_timestamp",
+		Help: "Unix timestamp of the last scan that checked the container",
+	}, containerLabels)
+	m.lastUpdated = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "watchtower_container_last_updated_timestamp",
+		Help: "Unix timestamp of the last successful update of the container",
+	}, containerLabels)
+	m.registryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "watchtower_registry_request_duration_seconds",
+		Help:    "Duration of HTTP requests to container registries",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"host", "method", "status"})
+	m.registryRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "watchtower_registry_ratelimit_remaining",
+		Help: "Remaining registry request budget reported by the RateLimit-Remaining header",
+	}, []string{"host"})
+	m.pullDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name: "watchtower_image_pull_duration_seconds",
+		Help: "Duration of image pulls",
+		//nolint:mnd // 1s to ~8.5m in powers of two.
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	})
+	m.notificationOutbox = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "watchtower_notification_outbox_messages",
+		Help: "Number of undelivered notifications waiting in the outbox for a retry",
+	})
+	m.containers = make(map[string]*containerState)
+	m.keepContainerLabel = true
+	m.keepImageLabel = true
+}
+
+// detailCollectors returns the collectors created by newDetailCollectors.
+func (m *Metrics) detailCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.updateAvailable,
+		m.lastChecked,
+		m.lastUpdated,
+		m.registryDuration,
+		m.registryRemaining,
+		m.pullDuration,
+		m.notificationOutbox,
+	}
+}
+
+// SetContainerLabels selects which labels are kept on per-container series.
+//
+// Dropped labels are exported with an empty value, which Prometheus treats as
+// absent, and series sharing the remaining labels are aggregated. Passing no
+// labels collapses each per-container metric into a single series.
+//
+// Parameters:
+//   - labels: Labels to keep (ContainerLabel, ImageLabel).
+func (m *Metrics) SetContainerLabels(labels []string) {
+	m.containersMu.Lock()
+	defer m.containersMu.Unlock()
+
+	m.keepContainerLabel = slices.Contains(labels, ContainerLabel)
+	m.keepImageLabel = slices.Contains(labels, ImageLabel)
+
+	m.rebuildContainerSeriesLocked()
+}
+
+// RecordContainers updates the per-container series from a scan report.
+//
+// Containers not present in the report keep their previous values so scans
+// filtered to a subset of containers do not erase the other series. A full
+// scan drops the series of containers it did not see, such as removed ones.
+//
+// Parameters:
+//   - report: Scan report; nil is ignored.
+//   - full: True if the scan covered every monitored container.
+func (m *Metrics) RecordContainers(report types.Report, full bool) {
+	if report == nil {
+		return
+	}
+
+	now := time.Now()
+	pending := make(map[types.ContainerID]bool)
+
+	for _, container := range report.Stale() {
+		pending[container.ID()] = true
+	}
+
+	for _, container := range report.Failed() {
+		pending[container.ID()] = true
+	}
+
+	m.containersMu.Lock()
+	defer m.containersMu.Unlock()
+
+	for _, container := range report.Scanned() {
+		state := m.containerStateLocked(container)
+		state.pending = pending[container.ID()]
+		state.lastChecked = now
+	}
+
+	for _, container := range report.Updated() {
+		state := m.containerStateLocked(container)
+		state.pending = false
+		state.lastUpdated = now
+	}
+
+	if full {
+		seen := make(map[string]bool)
+		for _, container := range report.All() {
+			seen[container.Name()] = true
+		}
+
+		for name := range m.containers {
+			if !seen[name] {
+				delete(m.containers, name)
+			}
+		}
+	}
+
+	m.rebuildContainerSeriesLocked()
+}
+
+// ObserveRegistryRequest records the duration and status of a registry request.
+//
+// Parameters:
+//   - host: Registry host.
+//   - method: HTTP method.
+//   - status: HTTP status code, or zero when no response was received.
+//   - duration: Time taken by the request.
+func (m *Metrics) ObserveRegistryRequest(host, method string, status int, duration time.Duration) {
+	statusLabel := registryStatusError
+	if status > 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+
+	m.registryDuration.WithLabelValues(host, method, statusLabel).Observe(duration.Seconds())
+}
+
+// SetRegistryRateLimitRemaining records the remaining request budget for a registry.
+//
+// Parameters:
+//   - host: Registry host.
+//   - remaining: Remaining requests in the current window.
+func (m *Metrics) SetRegistryRateLimitRemaining(host string, remaining int) {
+	m.registryRemaining.WithLabelValues(host).Set(float64(remaining))
+}
+
+// ObserveImagePull records the duration of an image pull.
+//
+// Parameters:
+//   - duration: Time taken by the pull.
+func (m *Metrics) ObserveImagePull(duration time.Duration) {
+	m.pullDuration.Observe(duration.Seconds())
+}
+
+// SetNotificationOutboxDepth records the number of undelivered notifications awaiting a retry.
+//
+// Parameters:
+//   - depth: Messages held in the outbox across all service URLs.
+func (m *Metrics) SetNotificationOutboxDepth(depth int) {
+	m.notificationOutbox.Set(float64(depth))
+}
+
+// containerStateLocked returns the tracked state for a container, creating it
+// when missing. Callers must hold containersMu.
+//
+// Parameters:
+//   - container: Container report to look up by name.
+//
+// Returns:
+//   - *containerState: The tracked state with the current image name.
+func (m *Metrics) containerStateLocked(container types.ContainerReport) *containerState {
+	state, ok := m.containers[container.Name()]
+	if !ok {
+		state = &containerState{}
+		m.containers[container.Name()] = state
+	}
+
+	state.image = container.ImageName()
+
+	return state
+}
+
+// rebuildContainerSeriesLocked resets the per-container vectors and exports the
+// tracked state grouped by the kept labels. Callers must hold containersMu.
+func (m *Metrics) rebuildContainerSeriesLocked() {
+	m.updateAvailable.Reset()
+	m.lastChecked.Reset()
+	m.lastUpdated.Reset()
+
+	available := make(map[containerSeriesKey]float64)
+	checked := make(map[containerSeriesKey]time.Time)
+	updated := make(map[containerSeriesKey]time.Time)
+
+	for name, state := range m.containers {
+		key := containerSeriesKey{}
+		if m.keepContainerLabel {
+			key.container = name
+		}
+
+		if m.keepImageLabel {
+			key.image = state.image
+		}
+
+		if _, ok := available[key]; !ok {
+			available[key] = 0
+		}
+
+		if state.pending {
+			available[key]++
+		}
+
+		if state.lastChecked.After(checked[key]) {
+			checked[key] = state.lastChecked
+		}
+
+		if state.lastUpdated.After(updated[key]) {
+			updated[key] = state.lastUpdated
+		}
+	}
+
+	for key, value := range available {
+		m.updateAvailable.WithLabelValues(key.container, key.image).Set(value)
+	}
+
+	for key, value := range checked {
+		m.lastChecked.WithLabelValues(key.container, key.image).Set(float64(value.Unix()))
+	}
+
+	for key, value := range updated {
+		m.lastUpdated.WithLabelValues(key.container, key.image).Set(float64(value.Unix()))
+	}
+}
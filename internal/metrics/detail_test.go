@@ -0,0 +1,285 @@
+package metrics
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+	mockTypes "github.com/nicholas-fedor/watchtower/pkg/types/mocks"
+)
+
+// newTestMetrics creates a metrics handler on an isolated registry.
+func newTestMetrics(t *testing.T) (*Metrics, *prometheus.Registry) {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+
+	m, err := NewWithRegistry(registry)
+	if err != nil {
+		t.Fatalf("NewWithRegistry() returned error: %v", err)
+	}
+
+	t.Cleanup(m.Shutdown)
+
+	return m, registry
+}
+
+// mockContainerReport returns a container report with the given identity.
+func mockContainerReport(t *testing.T, id, name, image string) types.ContainerReport {
+	t.Helper()
+
+	report := mockTypes.NewMockContainerReport(t)
+	report.EXPECT().ID().Return(types.ContainerID(id)).Maybe()
+	report.EXPECT().Name().Return(name).Maybe()
+	report.EXPECT().ImageName().Return(image).Maybe()
+
+	return report
+}
+
+// mockScanReport returns a report with the given scanned, stale, failed, and updated containers.
+func mockScanReport(t *testing.T, scanned, stale, failed, updated []types.ContainerReport) types.Report {
+	t.Helper()
+
+	report := mockTypes.NewMockReport(t)
+	report.EXPECT().Scanned().Return(scanned)
+	report.EXPECT().Stale().Return(stale)
+	report.EXPECT().Failed().Return(failed)
+	report.EXPECT().Updated().Return(updated)
+
+	return report
+}
+
+// gatherSeries returns the series of a metric family keyed by "container|image".
+func gatherSeries(t *testing.T, registry *prometheus.Registry, name string) map[string]float64 {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	series := make(map[string]float64)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			series[labelValue(metric, ContainerLabel)+"|"+labelValue(metric, ImageLabel)] = metric.GetGauge().GetValue()
+		}
+	}
+
+	return series
+}
+
+// labelValue returns the value of a label on a gathered metric.
+func labelValue(metric *dto.Metric, name string) string {
+	for _, pair := range metric.GetLabel() {
+		if pair.GetName() == name {
+			return pair.GetValue()
+		}
+	}
+
+	return ""
+}
+
+func TestMetrics_RecordContainers(t *testing.T) {
+	m, registry := newTestMetrics(t)
+
+	web := mockContainerReport(t, "web-id", "web", "nginx:latest")
+	db := mockContainerReport(t, "db-id", "db", "postgres:16")
+	cache := mockContainerReport(t, "cache-id", "cache", "redis:7")
+
+	before := time.Now().Unix()
+
+	m.RecordContainers(mockScanReport(t,
+		[]types.ContainerReport{web, db, cache},
+		[]types.ContainerReport{db},
+		[]types.ContainerReport{cache},
+		[]types.ContainerReport{web},
+	), false)
+
+	available := gatherSeries(t, registry, "watchtower_container_update_available")
+
+	want := map[string]float64{"web|nginx:latest": 0, "db|postgres:16": 1, "cache|redis:7": 1}
+	for key, value := range want {
+		if available[key] != value {
+			t.Errorf("update_available[%s] = %v, want %v", key, available[key], value)
+		}
+	}
+
+	checked := gatherSeries(t, registry, "watchtower_container_last_checked_timestamp")
+	if len(checked) != 3 || checked["db|postgres:16"] < float64(before) {
+		t.Errorf("last_checked = %v, want 3 series at or after %d", checked, before)
+	}
+
+	updated := gatherSeries(t, registry, "watchtower_container_last_updated_timestamp")
+	if len(updated) != 1 || updated["web|nginx:latest"] < float64(before) {
+		t.Errorf("last_updated = %v, want only web", updated)
+	}
+}
+
+func TestMetrics_RecordContainers_KeepsUnscannedContainers(t *testing.T) {
+	m, registry := newTestMetrics(t)
+
+	web := mockContainerReport(t, "web-id", "web", "nginx:latest")
+	db := mockContainerReport(t, "db-id", "db", "postgres:16")
+
+	m.RecordContainers(mockScanReport(t,
+		[]types.ContainerReport{web, db},
+		[]types.ContainerReport{db},
+		nil,
+		nil,
+	), false)
+	m.RecordContainers(mockScanReport(t,
+		[]types.ContainerReport{web},
+		nil,
+		nil,
+		nil,
+	), false)
+
+	available := gatherSeries(t, registry, "watchtower_container_update_available")
+	if available["db|postgres:16"] != 1 {
+		t.Errorf("db series = %v, want retained pending update", available["db|postgres:16"])
+	}
+}
+
+func TestMetrics_RecordContainers_FullScanDropsUnseenContainers(t *testing.T) {
+	m, registry := newTestMetrics(t)
+
+	web := mockContainerReport(t, "web-id", "web", "nginx:latest")
+	db := mockContainerReport(t, "db-id", "db", "postgres:16")
+
+	m.RecordContainers(mockScanReport(t,
+		[]types.ContainerReport{web, db},
+		[]types.ContainerReport{db},
+		nil,
+		nil,
+	), false)
+
+	report := mockTypes.NewMockReport(t)
+	report.EXPECT().Scanned().Return([]types.ContainerReport{web})
+	report.EXPECT().Stale().Return(nil)
+	report.EXPECT().Failed().Return(nil)
+	report.EXPECT().Updated().Return(nil)
+	report.EXPECT().All().Return([]types.ContainerReport{web})
+
+	m.RecordContainers(report, true)
+
+	for _, name := range []string{
+		"watchtower_container_update_available",
+		"watchtower_container_last_checked_timestamp",
+	} {
+		series := gatherSeries(t, registry, name)
+		if _, ok := series["db|postgres:16"]; ok || len(series) != 1 {
+			t.Errorf("%s = %v, want only web", name, series)
+		}
+	}
+}
+
+func TestMetrics_SetContainerLabels(t *testing.T) {
+	m, registry := newTestMetrics(t)
+
+	web := mockContainerReport(t, "web-id", "web", "nginx:latest")
+	api := mockContainerReport(t, "api-id", "api", "nginx:latest")
+	db := mockContainerReport(t, "db-id", "db", "postgres:16")
+
+	m.RecordContainers(mockScanReport(t,
+		[]types.ContainerReport{web, api, db},
+		[]types.ContainerReport{web, api, db},
+		nil,
+		nil,
+	), false)
+
+	m.SetContainerLabels([]string{ImageLabel})
+
+	available := gatherSeries(t, registry, "watchtower_container_update_available")
+	if len(available) != 2 || available["|nginx:latest"] != 2 || available["|postgres:16"] != 1 {
+		t.Errorf("image-only series = %v, want nginx:2 postgres:1", available)
+	}
+
+	m.SetContainerLabels([]string{"none"})
+
+	available = gatherSeries(t, registry, "watchtower_container_update_available")
+	if len(available) != 1 || available["|"] != 3 {
+		t.Errorf("aggregated series = %v, want a single series of 3", available)
+	}
+}
+
+func TestMetrics_RecordContainers_NilReport(t *testing.T) {
+	m, _ := newTestMetrics(t)
+
+	m.RecordContainers(nil, true)
+}
+
+func TestMetrics_RegistryAndPullMetrics(t *testing.T) {
+	m, registry := newTestMetrics(t)
+
+	m.ObserveRegistryRequest("index.docker.io", http.MethodHead, http.StatusOK, 150*time.Millisecond)
+	m.ObserveRegistryRequest("index.docker.io", http.MethodGet, 0, time.Second)
+	m.SetRegistryRateLimitRemaining("index.docker.io", 42)
+	m.ObserveImagePull(3 * time.Second)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	byName := make(map[string]*dto.MetricFamily)
+	for _, family := range families {
+		byName[family.GetName()] = family
+	}
+
+	requests := byName["watchtower_registry_request_duration_seconds"]
+	if requests == nil || len(requests.GetMetric()) != 2 {
+		t.Fatalf("registry request series = %v, want 2", requests)
+	}
+
+	statuses := map[string]bool{}
+	for _, metric := range requests.GetMetric() {
+		statuses[labelValue(metric, "status")] = true
+	}
+
+	if !statuses["200"] || !statuses[registryStatusError] {
+		t.Errorf("registry request statuses = %v, want 200 and error", statuses)
+	}
+
+	remaining := byName["watchtower_registry_ratelimit_remaining"]
+	if remaining == nil {
+		t.Fatal("ratelimit remaining family missing")
+	}
+
+	verifyMetricValue(t, remaining, 42)
+
+	pulls := byName["watchtower_image_pull_duration_seconds"]
+	if pulls == nil || pulls.GetMetric()[0].GetHistogram().GetSampleCount() != 1 {
+		t.Errorf("pull duration = %v, want one observation", pulls)
+	}
+}
+
+func TestMetrics_SetNotificationOutboxDepth(t *testing.T) {
+	m, registry := newTestMetrics(t)
+
+	m.SetNotificationOutboxDepth(3)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	for _, family := range families {
+		if family.GetName() == "watchtower_notification_outbox_messages" {
+			verifyMetricValue(t, family, 3)
+
+			return
+		}
+	}
+
+	t.Fatal("notification outbox family missing")
+}
@@ -4,6 +4,9 @@
 // Key components:
 //   - Metrics: Handles metric queuing and updates.
 //   - NewMetric: Creates metrics from scan reports.
+//   - RecordContainers: Exports per-container series with configurable labels.
+//   - ObserveRegistryRequest, ObserveImagePull: Record registry and pull timings.
+//   - Push: Sends run-once results to a Pushgateway or remote-write endpoint.
 //
 // Usage example:
 //
@@ -45,6 +45,18 @@ type Metrics struct {
 	history        []HistoryEntry     // Ring buffer of scan history.
 	historyIdx     int                // Current write position in the ring buffer.
 	historyMu      sync.RWMutex       // Protects history and historyIdx.
+
+	updateAvailable    *prometheus.GaugeVec       // Per-container pending update flag.
+	lastChecked        *prometheus.GaugeVec       // Per-container last scan timestamp.
+	lastUpdated        *prometheus.GaugeVec       // Per-container last update timestamp.
+	registryDuration   *prometheus.HistogramVec   // Registry request durations.
+	registryRemaining  *prometheus.GaugeVec       // Registry rate-limit budget.
+	pullDuration       prometheus.Histogram       // Image pull durations.
+	notificationOutbox prometheus.Gauge           // Undelivered notifications awaiting retry.
+	containers         map[string]*containerState // Tracked per-container state by name.
+	keepContainerLabel bool                       // Export the container label.
+	keepImageLabel     bool                       // Export the image label.
+	containersMu       sync.Mutex                 // Protects containers and label selection.
 	//nolint:containedctx
 	ctx    context.Context    // Context for cancellation.
 	cancel context.CancelFunc // Cancel function for the context.
@@ -109,6 +121,8 @@ func NewWithRegistry(registry prometheus.Registerer) (*Metrics, error) {
 		cancel:  cancel,
 	}
 
+	metrics.newDetailCollectors()
+
 	// Register the metrics with the provided registry.
 	// If a metric is already registered, return an error to avoid duplicate collectors.
 	collectors := []prometheus.Collector{
@@ -122,6 +136,7 @@ func NewWithRegistry(registry prometheus.Registerer) (*Metrics, error) {
 		metrics.skippedScans,
 		metrics.dropped,
 	}
+	collectors = append(collectors, metrics.detailCollectors()...)
 
 	for _, c := range collectors {
 		err := registry.Register(c)
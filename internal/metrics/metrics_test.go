@@ -691,20 +691,25 @@ func TestNewWithRegistry(t *testing.T) {
 				t.Fatalf("Failed to gather metrics: %v", err)
 			}
 
-			if len(metricFamilies) != 9 {
-				t.Errorf("Expected 9 metric families registered, got %d", len(metricFamilies))
+			// Labelled vectors are only gathered once they have a series, so
+			// the unlabelled pull histogram and outbox gauge are the only
+			// detail families here.
+			if len(metricFamilies) != 11 {
+				t.Errorf("Expected 11 metric families registered, got %d", len(metricFamilies))
 			}
 
 			expectedNames := map[string]bool{
-				"watchtower_containers_scanned":         true,
-				"watchtower_containers_updated":         true,
-				"watchtower_containers_failed":          true,
-				"watchtower_containers_restarted":       true,
-				"watchtower_containers_skipped":         true,
-				"watchtower_containers_restarted_total": true,
-				"watchtower_scans_total":                true,
-				"watchtower_scans_skipped_total":        true,
-				"watchtower_metrics_dropped_total":      true,
+				"watchtower_containers_scanned":           true,
+				"watchtower_containers_updated":           true,
+				"watchtower_containers_failed":            true,
+				"watchtower_containers_restarted":         true,
+				"watchtower_containers_skipped":           true,
+				"watchtower_containers_restarted_total":   true,
+				"watchtower_scans_total":                  true,
+				"watchtower_scans_skipped_total":          true,
+				"watchtower_metrics_dropped_total":        true,
+				"watchtower_image_pull_duration_seconds":  true,
+				"watchtower_notification_outbox_messages": true,
 			}
 
 			for _, mf := range metricFamilies {
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Supported push formats.
+const (
+	// PushFormatPushgateway pushes to a Prometheus Pushgateway.
+	PushFormatPushgateway = "pushgateway"
+	// PushFormatRemoteWrite sends a Prometheus remote-write 1.0 request.
+	PushFormatRemoteWrite = "remote-write"
+)
+
+// pushAttempts is the maximum number of push attempts per run.
+const pushAttempts = 3
+
+// pushRetryDelay is the delay before the first retry; it doubles after each attempt.
+var pushRetryDelay = time.Second
+
+var (
+	// ErrInvalidPushFormat indicates an unsupported push format.
+	ErrInvalidPushFormat = errors.New("invalid metrics push format")
+	// errPushRejected indicates the receiver rejected the push and retrying cannot succeed.
+	errPushRejected = errors.New("metrics push rejected")
+)
+
+// PushOptions configures where and how run metrics are pushed.
+type PushOptions struct {
+	// URL is the Pushgateway base URL or the remote-write endpoint.
+	URL string
+	// Format selects PushFormatPushgateway or PushFormatRemoteWrite.
+	Format string
+	// Job is the job label of pushed series.
+	Job string
+	// Instance is the instance label of pushed series.
+	Instance string
+	// Timeout bounds all push attempts together.
+	Timeout time.Duration
+	// Client sends the push requests; nil uses http.DefaultClient.
+	Client *http.Client
+}
+
+// Push sends the result of a single run to a Pushgateway or remote-write endpoint.
+//
+// The pushed series are the scan gauges built from metric, the run duration and
+// completion time, and the per-container, registry, and pull series recorded by
+// the handler. Failed attempts are retried with exponential backoff until
+// pushAttempts is reached or the timeout expires.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - opts: Destination, labels, and timeout.
+//   - metric: The run's scan metric; nil pushes zero counts.
+//   - duration: Wall-clock duration of the run.
+//
+// Returns:
+//   - error: Non-nil if every attempt failed or the options are invalid.
+func (m *Metrics) Push(ctx context.Context, opts PushOptions, metric *Metric, duration time.Duration) error {
+	registry, err := m.runRegistry(metric, duration)
+	if err != nil {
+		return err
+	}
+
+	client := opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var send func(context.Context) error
+
+	switch opts.Format {
+	case "", PushFormatPushgateway:
+		pusher := push.New(opts.URL, opts.Job).
+			Grouping("instance", opts.Instance).
+			Gatherer(registry).
+			Client(client)
+		send = pusher.PushContext
+	case PushFormatRemoteWrite:
+		send = func(ctx context.Context) error {
+			return remoteWrite(ctx, client, opts, registry)
+		}
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidPushFormat, opts.Format)
+	}
+
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	return retryPush(ctx, send)
+}
+
+// runRegistry builds a registry holding the series pushed for one run.
+//
+// Parameters:
+//   - metric: The run's scan metric; nil records zero counts.
+//   - duration: Wall-clock duration of the run.
+//
+// Returns:
+//   - *prometheus.Registry: Registry with run gauges and the handler's detail collectors.
+//   - error: Non-nil if a collector cannot be registered.
+func (m *Metrics) runRegistry(metric *Metric, duration time.Duration) (*prometheus.Registry, error) {
+	if metric == nil {
+		metric = &Metric{}
+	}
+
+	gauge := func(name, help string, value float64) prometheus.Collector {
+		g := prometheus.NewGauge(prometheus.GaugeOpts{Name: name, Help: help})
+		g.Set(value)
+
+		return g
+	}
+
+	collectors := []prometheus.Collector{
+		gauge("watchtower_containers_scanned", "Number of containers scanned for changes by watchtower during the run", float64(metric.Scanned)),
+		gauge("watchtower_containers_updated", "Number of containers updated by watchtower during the run", float64(metric.Updated)),
+		gauge("watchtower_containers_failed", "Number of containers where update failed during the run", float64(metric.Failed)),
+		gauge("watchtower_containers_restarted", "Number of containers restarted due to linked dependencies during the run", float64(metric.Restarted)),
+		gauge("watchtower_containers_skipped", "Number of containers skipped during the run", float64(metric.Skipped)),
+		gauge("watchtower_run_duration_seconds", "Duration of the run", duration.Seconds()),
+		gauge("watchtower_run_last_completion_timestamp_seconds", "Unix timestamp of the end of the run", float64(time.Now().Unix())),
+	}
+	collectors = append(collectors, m.detailCollectors()...)
+
+	registry := prometheus.NewRegistry()
+
+	for _, c := range collectors {
+		err := registry.Register(c)
+		if err != nil {
+			return nil, fmt.Errorf("failed to register push metric: %w", err)
+		}
+	}
+
+	return registry, nil
+}
+
+// retryPush calls send until it succeeds, the error is permanent, the attempts
+// are exhausted, or ctx is done.
+//
+// Parameters:
+//   - ctx: Context bounding all attempts.
+//   - send: Performs one push attempt.
+//
+// Returns:
+//   - error: The last attempt's error, or nil on success.
+func retryPush(ctx context.Context, send func(context.Context) error) error {
+	delay := pushRetryDelay
+
+	var err error
+
+	for attempt := 1; ; attempt++ {
+		err = send(ctx)
+		if err == nil || errors.Is(err, errPushRejected) || attempt == pushAttempts {
+			break
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return fmt.Errorf("metrics push timed out after %d attempts: %w", attempt, err)
+		case <-timer.C:
+		}
+
+		delay *= 2
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// pushRequest records the parts of a push request checked by the tests.
+type pushRequest struct {
+	method  string
+	path    string
+	headers http.Header
+	body    []byte
+}
+
+// newPushServer starts a server that replies with the given status codes in
+// order, repeating the last one, and records every request.
+func newPushServer(t *testing.T, statuses ...int) (*httptest.Server, *[]pushRequest) {
+	t.Helper()
+
+	var (
+		requests []pushRequest
+		calls    atomic.Int32
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests = append(requests, pushRequest{method: r.Method, path: r.URL.Path, headers: r.Header, body: body})
+
+		index := min(int(calls.Add(1))-1, len(statuses)-1)
+		w.WriteHeader(statuses[index])
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestMetrics_Push_Pushgateway(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestMetrics(t)
+	m.ObserveImagePull(time.Second)
+
+	server, requests := newPushServer(t, http.StatusOK)
+
+	err := m.Push(t.Context(), PushOptions{
+		URL:      server.URL,
+		Format:   PushFormatPushgateway,
+		Job:      "watchtower",
+		Instance: "host-1",
+		Timeout:  time.Second,
+	}, &Metric{Scanned: 3, Updated: 1}, 2*time.Second)
+	if err != nil {
+		t.Fatalf("Push() returned error: %v", err)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("Expected 1 request, got %d", len(*requests))
+	}
+
+	request := (*requests)[0]
+	if request.method != http.MethodPut || request.path != "/metrics/job/watchtower/instance/host-1" {
+		t.Errorf("Unexpected request %s %s", request.method, request.path)
+	}
+
+	for _, name := range []string{"watchtower_containers_updated", "watchtower_run_duration_seconds", "watchtower_image_pull_duration_seconds"} {
+		if !bytes.Contains(request.body, []byte(name)) {
+			t.Errorf("Expected pushed body to contain %s", name)
+		}
+	}
+}
+
+func TestMetrics_Push_RemoteWrite(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestMetrics(t)
+	server, requests := newPushServer(t, http.StatusNoContent)
+
+	err := m.Push(t.Context(), PushOptions{
+		URL:      server.URL + "/api/v1/write",
+		Format:   PushFormatRemoteWrite,
+		Job:      "watchtower",
+		Instance: "host-1",
+		Timeout:  time.Second,
+	}, &Metric{Scanned: 3}, time.Second)
+	if err != nil {
+		t.Fatalf("Push() returned error: %v", err)
+	}
+
+	request := (*requests)[0]
+	if request.method != http.MethodPost || request.path != "/api/v1/write" {
+		t.Errorf("Unexpected request %s %s", request.method, request.path)
+	}
+
+	if request.headers.Get("Content-Encoding") != "snappy" || request.headers.Get("Content-Type") != "application/x-protobuf" {
+		t.Errorf("Unexpected headers %v", request.headers)
+	}
+
+	decoded, err := s2.Decode(nil, request.body)
+	if err != nil {
+		t.Fatalf("Failed to decode snappy body: %v", err)
+	}
+
+	for _, value := range []string{"__name__", "watchtower_containers_scanned", "job", "instance", "host-1"} {
+		if !bytes.Contains(decoded, []byte(value)) {
+			t.Errorf("Expected remote-write body to contain %q", value)
+		}
+	}
+}
+
+func TestToRemoteWriteSeries(t *testing.T) {
+	t.Parallel()
+
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pull_seconds",
+		Buckets: []float64{1, 5},
+	}, []string{"host", "empty"})
+	registry.MustRegister(histogram)
+	histogram.WithLabelValues("ghcr.io", "").Observe(2)
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatalf("Failed to gather metrics: %v", err)
+	}
+
+	series := toRemoteWriteSeries(families, []remoteWriteLabel{{name: "job", value: "watchtower"}})
+
+	got := make([]string, 0, len(series))
+
+	for _, s := range series {
+		labels := make([]string, 0, len(s.labels))
+		for _, label := range s.labels {
+			labels = append(labels, label.name+"="+label.value)
+		}
+
+		got = append(got, strings.Join(labels, ",")+" "+formatFloat(s.value))
+	}
+
+	want := []string{
+		"__name__=pull_seconds_bucket,host=ghcr.io,job=watchtower,le=1 0",
+		"__name__=pull_seconds_bucket,host=ghcr.io,job=watchtower,le=5 1",
+		"__name__=pull_seconds_bucket,host=ghcr.io,job=watchtower,le=+Inf 1",
+		"__name__=pull_seconds_sum,host=ghcr.io,job=watchtower 2",
+		"__name__=pull_seconds_count,host=ghcr.io,job=watchtower 1",
+	}
+
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected series:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+//nolint:paralleltest // Shortens the package-level retry delay.
+func TestMetrics_Push_Retries(t *testing.T) {
+	previousDelay := pushRetryDelay
+	pushRetryDelay = time.Millisecond
+
+	t.Cleanup(func() { pushRetryDelay = previousDelay })
+
+	m, _ := newTestMetrics(t)
+
+	t.Run("retries server errors", func(t *testing.T) {
+		server, requests := newPushServer(t, http.StatusServiceUnavailable, http.StatusOK)
+
+		err := m.Push(t.Context(), PushOptions{URL: server.URL, Job: "watchtower", Timeout: time.Second}, nil, 0)
+		if err != nil {
+			t.Fatalf("Push() returned error: %v", err)
+		}
+
+		if len(*requests) != 2 {
+			t.Errorf("Expected 2 attempts, got %d", len(*requests))
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		server, requests := newPushServer(t, http.StatusInternalServerError)
+
+		err := m.Push(t.Context(), PushOptions{URL: server.URL, Job: "watchtower", Timeout: time.Second}, nil, 0)
+		if err == nil {
+			t.Fatal("Expected Push() to fail")
+		}
+
+		if len(*requests) != pushAttempts {
+			t.Errorf("Expected %d attempts, got %d", pushAttempts, len(*requests))
+		}
+	})
+
+	t.Run("does not retry rejected remote writes", func(t *testing.T) {
+		server, requests := newPushServer(t, http.StatusBadRequest)
+
+		err := m.Push(t.Context(), PushOptions{
+			URL:     server.URL,
+			Format:  PushFormatRemoteWrite,
+			Job:     "watchtower",
+			Timeout: time.Second,
+		}, nil, 0)
+		if !errors.Is(err, errPushRejected) {
+			t.Fatalf("Expected errPushRejected, got %v", err)
+		}
+
+		if len(*requests) != 1 {
+			t.Errorf("Expected 1 attempt, got %d", len(*requests))
+		}
+	})
+}
+
+func TestMetrics_Push_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestMetrics(t)
+
+	err := m.Push(t.Context(), PushOptions{URL: "http://localhost", Format: "graphite"}, nil, 0)
+	if !errors.Is(err, ErrInvalidPushFormat) {
+		t.Errorf("Expected ErrInvalidPushFormat, got %v", err)
+	}
+}
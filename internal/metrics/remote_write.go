@@ -0,0 +1,221 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// remoteWriteErrorBodyLimit caps how much of an error response is included in errors.
+const remoteWriteErrorBodyLimit = 512
+
+// Protobuf field numbers of the remote-write 1.0 WriteRequest messages.
+const (
+	fieldWriteRequestTimeseries = 1
+	fieldTimeSeriesLabels       = 1
+	fieldTimeSeriesSamples      = 2
+	fieldLabelName              = 1
+	fieldLabelValue             = 2
+	fieldSampleValue            = 1
+	fieldSampleTimestamp        = 2
+)
+
+// remoteWriteLabel is one label of a remote-write series.
+type remoteWriteLabel struct {
+	name  string
+	value string
+}
+
+// remoteWriteSeries is one remote-write series with a single sample.
+type remoteWriteSeries struct {
+	labels []remoteWriteLabel
+	value  float64
+}
+
+// remoteWrite sends the gathered series as a snappy-compressed remote-write 1.0 request.
+//
+// Parameters:
+//   - ctx: Context for the request.
+//   - client: HTTP client.
+//   - opts: Endpoint and job/instance labels.
+//   - gatherer: Source of the series.
+//
+// Returns:
+//   - error: Non-nil if gathering or the request fails; wraps errPushRejected
+//     for client errors other than 429.
+func remoteWrite(ctx context.Context, client *http.Client, opts PushOptions, gatherer prometheus.Gatherer) error {
+	families, err := gatherer.Gather()
+	if err != nil {
+		return fmt.Errorf("failed to gather metrics: %w", err)
+	}
+
+	extra := []remoteWriteLabel{{name: "job", value: opts.Job}, {name: "instance", value: opts.Instance}}
+	body := s2.EncodeSnappy(nil, encodeWriteRequest(toRemoteWriteSeries(families, extra), time.Now()))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, opts.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote-write request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote-write request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(resp.Body, remoteWriteErrorBodyLimit))
+	statusErr := fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, opts.URL, bytes.TrimSpace(message))
+
+	if resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", errPushRejected, statusErr)
+	}
+
+	return statusErr
+}
+
+// toRemoteWriteSeries flattens metric families into remote-write series.
+//
+// Histograms and summaries are expanded into their classic _bucket, _sum, and
+// _count series. Empty label values are omitted.
+//
+// Parameters:
+//   - families: Gathered metric families.
+//   - extra: Labels added to every series, such as job and instance.
+//
+// Returns:
+//   - []remoteWriteSeries: Series with sorted labels.
+func toRemoteWriteSeries(families []*dto.MetricFamily, extra []remoteWriteLabel) []remoteWriteSeries {
+	var series []remoteWriteSeries
+
+	for _, family := range families {
+		name := family.GetName()
+
+		for _, metric := range family.GetMetric() {
+			add := func(suffix string, value float64, labels ...remoteWriteLabel) {
+				all := []remoteWriteLabel{{name: "__name__", value: name + suffix}}
+				all = append(all, extra...)
+				all = append(all, labels...)
+
+				for _, pair := range metric.GetLabel() {
+					all = append(all, remoteWriteLabel{name: pair.GetName(), value: pair.GetValue()})
+				}
+
+				all = slices.DeleteFunc(all, func(l remoteWriteLabel) bool { return l.value == "" })
+				slices.SortFunc(all, func(a, b remoteWriteLabel) int { return strings.Compare(a.name, b.name) })
+
+				series = append(series, remoteWriteSeries{labels: all, value: value})
+			}
+
+			switch family.GetType() {
+			case dto.MetricType_COUNTER:
+				add("", metric.GetCounter().GetValue())
+			case dto.MetricType_GAUGE:
+				add("", metric.GetGauge().GetValue())
+			case dto.MetricType_HISTOGRAM:
+				histogram := metric.GetHistogram()
+				for _, bucket := range histogram.GetBucket() {
+					if math.IsInf(bucket.GetUpperBound(), 1) {
+						continue
+					}
+
+					add("_bucket", float64(bucket.GetCumulativeCount()), remoteWriteLabel{name: "le", value: formatFloat(bucket.GetUpperBound())})
+				}
+
+				add("_bucket", float64(histogram.GetSampleCount()), remoteWriteLabel{name: "le", value: "+Inf"})
+				add("_sum", histogram.GetSampleSum())
+				add("_count", float64(histogram.GetSampleCount()))
+			case dto.MetricType_SUMMARY:
+				summary := metric.GetSummary()
+				for _, quantile := range summary.GetQuantile() {
+					add("", quantile.GetValue(), remoteWriteLabel{name: "quantile", value: formatFloat(quantile.GetQuantile())})
+				}
+
+				add("_sum", summary.GetSampleSum())
+				add("_count", float64(summary.GetSampleCount()))
+			case dto.MetricType_UNTYPED:
+				add("", metric.GetUntyped().GetValue())
+			case dto.MetricType_GAUGE_HISTOGRAM:
+				// Not produced by Watchtower collectors.
+			}
+		}
+	}
+
+	return series
+}
+
+// encodeWriteRequest encodes series as a remote-write 1.0 WriteRequest protobuf message.
+//
+// Parameters:
+//   - series: Series to encode.
+//   - timestamp: Sample timestamp for every series.
+//
+// Returns:
+//   - []byte: The encoded message.
+func encodeWriteRequest(series []remoteWriteSeries, timestamp time.Time) []byte {
+	var request []byte
+
+	for _, s := range series {
+		var encoded []byte
+
+		for _, label := range s.labels {
+			var pair []byte
+			pair = protowire.AppendTag(pair, fieldLabelName, protowire.BytesType)
+			pair = protowire.AppendString(pair, label.name)
+			pair = protowire.AppendTag(pair, fieldLabelValue, protowire.BytesType)
+			pair = protowire.AppendString(pair, label.value)
+
+			encoded = protowire.AppendTag(encoded, fieldTimeSeriesLabels, protowire.BytesType)
+			encoded = protowire.AppendBytes(encoded, pair)
+		}
+
+		var sample []byte
+		sample = protowire.AppendTag(sample, fieldSampleValue, protowire.Fixed64Type)
+		sample = protowire.AppendFixed64(sample, math.Float64bits(s.value))
+		sample = protowire.AppendTag(sample, fieldSampleTimestamp, protowire.VarintType)
+		sample = protowire.AppendVarint(sample, uint64(timestamp.UnixMilli())) //nolint:gosec // Protobuf int64 is varint-encoded as uint64.
+
+		encoded = protowire.AppendTag(encoded, fieldTimeSeriesSamples, protowire.BytesType)
+		encoded = protowire.AppendBytes(encoded, sample)
+
+		request = protowire.AppendTag(request, fieldWriteRequestTimeseries, protowire.BytesType)
+		request = protowire.AppendBytes(request, encoded)
+	}
+
+	return request
+}
+
+// formatFloat formats a bucket bound or quantile the way Prometheus exposes it.
+//
+// Parameters:
+//   - value: The value to format.
+//
+// Returns:
+//   - string: The formatted value, with "+Inf" for positive infinity.
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
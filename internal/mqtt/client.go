@@ -0,0 +1,516 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// MQTT 3.1.1 control packet types, shifted into the fixed header's high nibble.
+const (
+	packetConnect    byte = 0x10
+	packetConnack    byte = 0x20
+	packetPublish    byte = 0x30
+	packetPuback     byte = 0x40
+	packetSubscribe  byte = 0x80
+	packetSuback     byte = 0x90
+	packetPingreq    byte = 0xC0
+	packetPingresp   byte = 0xD0
+	packetDisconnect byte = 0xE0
+)
+
+// CONNECT flags.
+const (
+	connectCleanSession byte = 0x02
+	connectWill         byte = 0x04
+	connectWillRetain   byte = 0x20
+	connectPassword     byte = 0x40
+	connectUsername     byte = 0x80
+)
+
+// Protocol constants.
+const (
+	protocolLevel      = 4
+	publishRetain byte = 0x01
+	// subscribeFlags are the reserved flag bits the SUBSCRIBE fixed header must carry.
+	subscribeFlags byte = 0x02
+	subackFailure  byte = 0x80
+	// maxRemainingLength is the largest remaining length a four-byte varint encodes.
+	maxRemainingLength = 268435455
+)
+
+// writeTimeout bounds every packet write so a stalled broker cannot block a scan.
+const writeTimeout = 10 * time.Second
+
+// Errors returned by the client.
+var (
+	// ErrConnectRefused indicates the broker rejected the CONNECT packet.
+	ErrConnectRefused = errors.New("MQTT broker refused connection")
+	// ErrSubscribeRefused indicates the broker rejected a subscription.
+	ErrSubscribeRefused = errors.New("MQTT broker refused subscription")
+	// errMalformedPacket indicates a packet that does not follow MQTT 3.1.1.
+	errMalformedPacket = errors.New("malformed MQTT packet")
+	// errPacketTooLarge indicates a packet exceeding the protocol's size limit.
+	errPacketTooLarge = errors.New("MQTT packet too large")
+)
+
+// connackReasons describes the CONNACK return codes 1 through 5.
+var connackReasons = map[byte]string{
+	1: "unacceptable protocol version",
+	2: "identifier rejected",
+	3: "server unavailable",
+	4: "bad user name or password",
+	5: "not authorized",
+}
+
+// message is a PUBLISH received from the broker.
+type message struct {
+	topic   string
+	payload []byte
+}
+
+// will is the last-will message the broker publishes when the connection drops.
+type will struct {
+	topic   string
+	payload []byte
+	retain  bool
+}
+
+// connectOptions holds the CONNECT packet fields.
+type connectOptions struct {
+	clientID  string
+	username  string
+	password  string
+	keepAlive time.Duration
+	will      *will
+}
+
+// client is a minimal MQTT 3.1.1 client supporting QoS 0 publishes and subscriptions.
+type client struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	writeMu  sync.Mutex
+	packetID uint16
+	idMu     sync.Mutex
+}
+
+// dial opens a broker connection and completes the CONNECT handshake.
+//
+// Parameters:
+//   - ctx: Context bounding the dial and handshake.
+//   - address: Broker host:port.
+//   - tlsConfig: TLS settings; nil connects over plain TCP.
+//   - opts: CONNECT packet fields.
+//
+// Returns:
+//   - *client: The connected client.
+//   - error: Non-nil if the connection or handshake fails.
+func dial(ctx context.Context, address string, tlsConfig *tls.Config, opts connectOptions) (*client, error) {
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if tlsConfig != nil {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker %s: %w", address, err)
+	}
+
+	mqttClient := &client{conn: conn, reader: bufio.NewReader(conn)}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	err = mqttClient.connect(opts)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, err
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return mqttClient, nil
+}
+
+// connect sends CONNECT and waits for CONNACK.
+//
+// Parameters:
+//   - opts: CONNECT packet fields.
+//
+// Returns:
+//   - error: Non-nil if the packet cannot be exchanged or the broker refuses it.
+func (c *client) connect(opts connectOptions) error {
+	flags := connectCleanSession
+	payload := appendString(nil, opts.clientID)
+
+	if opts.will != nil {
+		flags |= connectWill
+		if opts.will.retain {
+			flags |= connectWillRetain
+		}
+
+		payload = appendString(payload, opts.will.topic)
+		payload = appendBytes(payload, opts.will.payload)
+	}
+
+	if opts.username != "" {
+		flags |= connectUsername
+		payload = appendString(payload, opts.username)
+	}
+
+	if opts.password != "" {
+		flags |= connectPassword
+		payload = appendString(payload, opts.password)
+	}
+
+	body := appendString(nil, "MQTT")
+	body = append(body, protocolLevel, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(opts.keepAlive/time.Second)) //nolint:gosec // Keepalive is validated to fit.
+
+	err := c.write(packetConnect, append(body, payload...))
+	if err != nil {
+		return err
+	}
+
+	header, packet, err := c.read()
+	if err != nil {
+		return fmt.Errorf("failed to read CONNACK: %w", err)
+	}
+
+	if header&0xF0 != packetConnack || len(packet) != 2 {
+		return fmt.Errorf("%w: expected CONNACK", errMalformedPacket)
+	}
+
+	if code := packet[1]; code != 0 {
+		reason, ok := connackReasons[code]
+		if !ok {
+			reason = fmt.Sprintf("return code %d", code)
+		}
+
+		return fmt.Errorf("%w: %s", ErrConnectRefused, reason)
+	}
+
+	return nil
+}
+
+// publish sends a QoS 0 PUBLISH.
+//
+// Parameters:
+//   - topic: Topic name.
+//   - payload: Message payload.
+//   - retain: Whether the broker keeps the message for new subscribers.
+//
+// Returns:
+//   - error: Non-nil if the packet cannot be written.
+func (c *client) publish(topic string, payload []byte, retain bool) error {
+	header := packetPublish
+	if retain {
+		header |= publishRetain
+	}
+
+	return c.write(header, append(appendString(nil, topic), payload...))
+}
+
+// subscribe sends a QoS 0 SUBSCRIBE. The SUBACK is checked by the read loop.
+//
+// Parameters:
+//   - filter: Topic filter, possibly with wildcards.
+//
+// Returns:
+//   - error: Non-nil if the packet cannot be written.
+func (c *client) subscribe(filter string) error {
+	body := binary.BigEndian.AppendUint16(nil, c.nextPacketID())
+	body = appendString(body, filter)
+	body = append(body, 0)
+
+	return c.write(packetSubscribe|subscribeFlags, body)
+}
+
+// ping sends a PINGREQ to keep the connection alive.
+//
+// Returns:
+//   - error: Non-nil if the packet cannot be written.
+func (c *client) ping() error {
+	return c.write(packetPingreq, nil)
+}
+
+// disconnect sends DISCONNECT and closes the connection, so the broker
+// discards the last-will message.
+func (c *client) disconnect() {
+	_ = c.write(packetDisconnect, nil)
+	_ = c.conn.Close()
+}
+
+// close closes the connection without DISCONNECT, so the broker publishes the last-will message.
+func (c *client) close() {
+	_ = c.conn.Close()
+}
+
+// readLoop reads packets until the connection fails, passing received messages to handle.
+//
+// Parameters:
+//   - idleTimeout: Maximum time without any packet from the broker; zero waits indefinitely.
+//   - handle: Called for every received PUBLISH.
+//
+// Returns:
+//   - error: The error that ended the loop.
+func (c *client) readLoop(idleTimeout time.Duration, handle func(message)) error {
+	for {
+		if idleTimeout > 0 {
+			_ = c.conn.SetReadDeadline(time.Now().Add(idleTimeout))
+		}
+
+		header, packet, err := c.read()
+		if err != nil {
+			return err
+		}
+
+		switch header & 0xF0 {
+		case packetPublish:
+			msg, err := c.receivePublish(header, packet)
+			if err != nil {
+				return err
+			}
+
+			handle(msg)
+		case packetSuback:
+			if len(packet) < 3 {
+				return fmt.Errorf("%w: short SUBACK", errMalformedPacket)
+			}
+
+			for _, code := range packet[2:] {
+				if code == subackFailure {
+					return ErrSubscribeRefused
+				}
+			}
+		case packetPingresp, packetPuback:
+		default:
+			return fmt.Errorf("%w: unexpected packet type %#x", errMalformedPacket, header&0xF0)
+		}
+	}
+}
+
+// receivePublish decodes a PUBLISH and acknowledges it when delivered at QoS 1.
+//
+// Parameters:
+//   - header: The fixed header byte.
+//   - packet: The remaining packet bytes.
+//
+// Returns:
+//   - message: The decoded message.
+//   - error: Non-nil if the packet is malformed or the PUBACK cannot be written.
+func (c *client) receivePublish(header byte, packet []byte) (message, error) {
+	topic, rest, ok := readString(packet)
+	if !ok {
+		return message{}, fmt.Errorf("%w: PUBLISH topic", errMalformedPacket)
+	}
+
+	qos := (header >> 1) & 0x03
+	if qos > 0 {
+		if len(rest) < 2 {
+			return message{}, fmt.Errorf("%w: PUBLISH packet identifier", errMalformedPacket)
+		}
+
+		packetID := rest[:2]
+		rest = rest[2:]
+
+		if qos == 1 {
+			err := c.write(packetPuback, packetID)
+			if err != nil {
+				return message{}, err
+			}
+		}
+	}
+
+	return message{topic: topic, payload: rest}, nil
+}
+
+// nextPacketID returns a non-zero packet identifier.
+//
+// Returns:
+//   - uint16: The identifier.
+func (c *client) nextPacketID() uint16 {
+	c.idMu.Lock()
+	defer c.idMu.Unlock()
+
+	c.packetID++
+	if c.packetID == 0 {
+		c.packetID = 1
+	}
+
+	return c.packetID
+}
+
+// write sends one packet.
+//
+// Parameters:
+//   - header: The fixed header byte.
+//   - body: The variable header and payload.
+//
+// Returns:
+//   - error: Non-nil if the packet is too large or cannot be written.
+func (c *client) write(header byte, body []byte) error {
+	if len(body) > maxRemainingLength {
+		return errPacketTooLarge
+	}
+
+	packet := append([]byte{header}, encodeLength(len(body))...)
+	packet = append(packet, body...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+	_, err := c.conn.Write(packet)
+	if err != nil {
+		return fmt.Errorf("failed to write MQTT packet: %w", err)
+	}
+
+	return nil
+}
+
+// read receives one packet.
+//
+// Returns:
+//   - byte: The fixed header byte.
+//   - []byte: The remaining packet bytes.
+//   - error: Non-nil if the packet cannot be read.
+func (c *client) read() (byte, []byte, error) {
+	header, err := c.reader.ReadByte()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read MQTT packet: %w", err)
+	}
+
+	length, err := decodeLength(c.reader)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	packet := make([]byte, length)
+
+	_, err = io.ReadFull(c.reader, packet)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read MQTT packet: %w", err)
+	}
+
+	return header, packet, nil
+}
+
+// encodeLength encodes a remaining length as an MQTT variable byte integer.
+//
+// Parameters:
+//   - length: The remaining length.
+//
+// Returns:
+//   - []byte: One to four encoded bytes.
+func encodeLength(length int) []byte {
+	encoded := make([]byte, 0, 4)
+
+	for {
+		digit := byte(length % 128)
+		length /= 128
+
+		if length > 0 {
+			digit |= 0x80
+		}
+
+		encoded = append(encoded, digit)
+
+		if length == 0 {
+			return encoded
+		}
+	}
+}
+
+// decodeLength reads an MQTT variable byte integer.
+//
+// Parameters:
+//   - reader: Source of the encoded bytes.
+//
+// Returns:
+//   - int: The decoded remaining length.
+//   - error: Non-nil if the value is longer than four bytes or cannot be read.
+func decodeLength(reader io.ByteReader) (int, error) {
+	length, multiplier := 0, 1
+
+	for range 4 {
+		digit, err := reader.ReadByte()
+		if err != nil {
+			return 0, fmt.Errorf("failed to read MQTT packet length: %w", err)
+		}
+
+		length += int(digit&0x7F) * multiplier
+		if digit&0x80 == 0 {
+			return length, nil
+		}
+
+		multiplier *= 128
+	}
+
+	return 0, fmt.Errorf("%w: remaining length", errMalformedPacket)
+}
+
+// appendString appends a length-prefixed UTF-8 string.
+//
+// Parameters:
+//   - buf: Destination buffer.
+//   - value: The string.
+//
+// Returns:
+//   - []byte: The extended buffer.
+func appendString(buf []byte, value string) []byte {
+	return appendBytes(buf, []byte(value))
+}
+
+// appendBytes appends length-prefixed binary data.
+//
+// Parameters:
+//   - buf: Destination buffer.
+//   - value: The data.
+//
+// Returns:
+//   - []byte: The extended buffer.
+func appendBytes(buf, value []byte) []byte {
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(value))) //nolint:gosec // Topics and credentials are far below 64 KiB.
+
+	return append(buf, value...)
+}
+
+// readString reads a length-prefixed UTF-8 string.
+//
+// Parameters:
+//   - buf: Source buffer.
+//
+// Returns:
+//   - string: The string.
+//   - []byte: The bytes following the string.
+//   - bool: False if buf is too short.
+func readString(buf []byte) (string, []byte, bool) {
+	if len(buf) < 2 {
+		return "", nil, false
+	}
+
+	length := int(binary.BigEndian.Uint16(buf))
+	if len(buf) < 2+length {
+		return "", nil, false
+	}
+
+	return string(buf[2 : 2+length]), buf[2+length:], true
+}
@@ -0,0 +1,33 @@
+// Package mqtt publishes container update state to an MQTT broker for home
+// automation systems such as Home Assistant.
+//
+// After every scan the publisher sends a retained JSON state per container
+// (update available, current and latest image, last updated) and a scan
+// summary. With discovery enabled it also announces each container as a Home
+// Assistant MQTT update entity, and an "install" on an entity's command topic
+// runs Watchtower's targeted update for that container.
+//
+// Topics, with the default "watchtower" prefix:
+//   - watchtower/status: "online" or "offline" (last will).
+//   - watchtower/scan: The last scan summary.
+//   - watchtower/containers/<name>/state: A container's state.
+//   - watchtower/containers/<name>/command: Install requests.
+//   - homeassistant/update/<client-id>/<name>/config: Discovery configs.
+//
+// The package includes a minimal MQTT 3.1.1 client supporting QoS 0 publishes
+// and subscriptions over TCP or TLS.
+//
+// Key components:
+//   - Publisher: Maintains the connection and publishes states.
+//   - Options: Broker and topic settings.
+//   - ContainerState: The per-container state payload.
+//
+// Usage example:
+//
+//	pub, err := mqtt.New(log, mqtt.Options{BrokerURL: "mqtt://broker:1883", Discovery: true}, install)
+//	if err != nil {
+//	    return err
+//	}
+//	go pub.Run(ctx)
+//	pub.PublishScan(report, mqtt.Summary{Scanned: 5})
+package mqtt
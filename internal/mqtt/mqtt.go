@@ -0,0 +1,747 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Default topic prefixes.
+const (
+	// DefaultTopicPrefix is the root of the state, summary, and command topics.
+	DefaultTopicPrefix = "watchtower"
+	// DefaultDiscoveryPrefix is Home Assistant's default MQTT discovery prefix.
+	DefaultDiscoveryPrefix = "homeassistant"
+)
+
+// PayloadInstall is the command payload Home Assistant sends to install an update.
+const PayloadInstall = "install"
+
+// Availability payloads published to the status topic.
+const (
+	availabilityOnline  = "online"
+	availabilityOffline = "offline"
+)
+
+// Connection timing.
+const (
+	defaultKeepAlive  = 30 * time.Second
+	connectTimeout    = 10 * time.Second
+	minReconnectDelay = time.Second
+	maxReconnectDelay = time.Minute
+)
+
+// Errors returned by the publisher.
+var (
+	// ErrInvalidBrokerURL indicates the broker URL is not an mqtt://, mqtts://, tcp://, ssl://, or tls:// URL with a host.
+	ErrInvalidBrokerURL = errors.New("invalid MQTT broker URL")
+	// ErrInvalidTopicPrefix indicates a topic prefix that is empty or contains wildcards.
+	ErrInvalidTopicPrefix = errors.New("invalid MQTT topic prefix")
+	// ErrInvalidCACert indicates the CA file contains no PEM certificates.
+	ErrInvalidCACert = errors.New("invalid MQTT CA certificate")
+)
+
+// unsafeIDChars matches characters Home Assistant does not allow in discovery node and object IDs.
+var unsafeIDChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Options configures the broker connection and topic layout.
+type Options struct {
+	// BrokerURL is the broker address, e.g. mqtt://broker:1883 or mqtts://broker:8883.
+	BrokerURL string
+	// Username is the broker user; empty connects anonymously.
+	Username string
+	// Password is the broker password.
+	Password string
+	// ClientID identifies the connection and the Home Assistant device.
+	ClientID string
+	// TopicPrefix is the root of the published topics; empty uses DefaultTopicPrefix.
+	TopicPrefix string
+	// Discovery publishes Home Assistant discovery configs.
+	Discovery bool
+	// DiscoveryPrefix is Home Assistant's discovery prefix; empty uses DefaultDiscoveryPrefix.
+	DiscoveryPrefix string
+	// CAFile is a PEM bundle used instead of the system roots to verify the broker.
+	CAFile string
+	// TLSSkipVerify disables broker certificate verification.
+	TLSSkipVerify bool
+	// KeepAlive is the MQTT keepalive interval; zero uses 30 seconds.
+	KeepAlive time.Duration
+	// Version is the Watchtower version reported in the discovery device.
+	Version string
+}
+
+// InstallFunc runs the targeted update of one container when Home Assistant requests an install.
+type InstallFunc func(ctx context.Context, container string)
+
+// ContainerState is the retained state published for each container.
+//
+// The installed_version, latest_version, in_progress, title, and release_url
+// fields follow the Home Assistant MQTT update entity's JSON state schema.
+type ContainerState struct {
+	// Container is the container name.
+	Container string `json:"container"`
+	// Image is the image name with tag.
+	Image string `json:"image"`
+	// State is the container's state in the last scan that included it.
+	State string `json:"state"`
+	// UpdateAvailable is true when a newer image has been found but not installed.
+	UpdateAvailable bool `json:"update_available"`
+	// InstalledVersion is the short ID of the running image.
+	InstalledVersion string `json:"installed_version"`
+	// LatestVersion is the short ID of the newest image found.
+	LatestVersion string `json:"latest_version"`
+	// CurrentDigest is the full ID of the running image.
+	CurrentDigest string `json:"current_digest"`
+	// LatestDigest is the full ID of the newest image found.
+	LatestDigest string `json:"latest_digest"`
+	// LastUpdated is when Watchtower last updated the container; nil if not since startup.
+	LastUpdated *time.Time `json:"last_updated"`
+	// InProgress is true while an install requested from Home Assistant runs.
+	InProgress bool `json:"in_progress"`
+	// MonitorOnly is true when Watchtower only reports updates for the container.
+	MonitorOnly bool `json:"monitor_only"`
+	// Title is the image name, shown by Home Assistant as the update title.
+	Title string `json:"title"`
+	// ReleaseURL is the latest image's OCI source URL, when labeled.
+	ReleaseURL string `json:"release_url,omitempty"`
+	// Error is the update error from the last scan, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// Summary is the retained scan summary.
+type Summary struct {
+	// Scanned is the number of containers scanned.
+	Scanned int `json:"scanned"`
+	// Updated is the number of containers updated.
+	Updated int `json:"updated"`
+	// Failed is the number of containers whose update failed.
+	Failed int `json:"failed"`
+	// Duration is the scan duration.
+	Duration time.Duration `json:"-"`
+	// Err is the error that aborted the scan, if any.
+	Err error `json:"-"`
+}
+
+// summaryPayload is the JSON encoding of a Summary.
+type summaryPayload struct {
+	Summary
+
+	DurationMS int64     `json:"duration_ms"`
+	FinishedAt time.Time `json:"finished_at"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// discoveryConfig is a Home Assistant MQTT update entity discovery payload.
+type discoveryConfig struct {
+	Name                string          `json:"name"`
+	UniqueID            string          `json:"unique_id"`
+	StateTopic          string          `json:"state_topic"`
+	JSONAttributesTopic string          `json:"json_attributes_topic"`
+	CommandTopic        string          `json:"command_topic,omitempty"`
+	PayloadInstall      string          `json:"payload_install,omitempty"`
+	AvailabilityTopic   string          `json:"availability_topic"`
+	Device              discoveryDevice `json:"device"`
+	Origin              discoveryOrigin `json:"origin"`
+}
+
+// discoveryDevice groups the update entities under one Home Assistant device.
+type discoveryDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+	SWVersion    string   `json:"sw_version,omitempty"`
+}
+
+// discoveryOrigin identifies the application publishing discovery configs.
+type discoveryOrigin struct {
+	Name       string `json:"name"`
+	SWVersion  string `json:"sw_version,omitempty"`
+	SupportURL string `json:"support_url"`
+}
+
+// Publisher publishes container states and scan summaries to an MQTT broker,
+// announces them to Home Assistant, and runs installs requested on the command topic.
+//
+// States are cached and republished after every reconnect, so publishing while
+// the broker is unreachable only delays delivery.
+type Publisher struct {
+	log       *zerolog.Logger
+	opts      Options
+	address   string
+	tlsConfig *tls.Config
+	install   InstallFunc
+
+	mu         sync.Mutex
+	client     *client
+	containers map[string]*ContainerState
+	discovered map[string]bool
+	summary    []byte
+}
+
+// New creates a publisher for the given broker.
+//
+// Parameters:
+//   - log: Logger for connection and publish failures; nil disables logging.
+//   - opts: Broker connection and topic settings.
+//   - install: Runs a targeted update when Home Assistant requests an install; nil disables the command topic.
+//
+// Returns:
+//   - *Publisher: The configured publisher. Call Run to connect.
+//   - error: Non-nil if the broker URL, topic prefix, or CA file is invalid.
+func New(log *zerolog.Logger, opts Options, install InstallFunc) (*Publisher, error) {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	if opts.TopicPrefix == "" {
+		opts.TopicPrefix = DefaultTopicPrefix
+	}
+
+	if opts.DiscoveryPrefix == "" {
+		opts.DiscoveryPrefix = DefaultDiscoveryPrefix
+	}
+
+	for _, prefix := range []string{opts.TopicPrefix, opts.DiscoveryPrefix} {
+		if strings.ContainsAny(prefix, "+#") || strings.Trim(prefix, "/") == "" {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTopicPrefix, prefix)
+		}
+	}
+
+	opts.TopicPrefix = strings.Trim(opts.TopicPrefix, "/")
+	opts.DiscoveryPrefix = strings.Trim(opts.DiscoveryPrefix, "/")
+
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = defaultKeepAlive
+	}
+
+	if opts.ClientID == "" {
+		opts.ClientID = DefaultTopicPrefix
+	}
+
+	address, useTLS, err := parseBrokerURL(opts.BrokerURL)
+	if err != nil {
+		return nil, err
+	}
+
+	publisher := &Publisher{
+		log:        log,
+		opts:       opts,
+		address:    address,
+		install:    install,
+		containers: make(map[string]*ContainerState),
+		discovered: make(map[string]bool),
+	}
+
+	if useTLS {
+		publisher.tlsConfig, err = newTLSConfig(address, opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return publisher, nil
+}
+
+// parseBrokerURL validates the broker URL and resolves its address.
+//
+// Parameters:
+//   - rawURL: The broker URL.
+//
+// Returns:
+//   - string: The broker host:port, with 1883 or 8883 filled in when no port is given.
+//   - bool: True for TLS schemes.
+//   - error: Non-nil if the URL is invalid.
+func parseBrokerURL(rawURL string) (string, bool, error) {
+	brokerURL, err := url.Parse(rawURL)
+	if err != nil || brokerURL.Hostname() == "" {
+		return "", false, fmt.Errorf("%w: %q", ErrInvalidBrokerURL, rawURL)
+	}
+
+	var useTLS bool
+
+	port := "1883"
+
+	switch brokerURL.Scheme {
+	case "mqtt", "tcp":
+	case "mqtts", "ssl", "tls":
+		useTLS = true
+		port = "8883"
+	default:
+		return "", false, fmt.Errorf("%w: %q", ErrInvalidBrokerURL, rawURL)
+	}
+
+	if brokerURL.Port() != "" {
+		port = brokerURL.Port()
+	}
+
+	return net.JoinHostPort(brokerURL.Hostname(), port), useTLS, nil
+}
+
+// newTLSConfig builds the TLS settings for a TLS broker URL.
+//
+// Parameters:
+//   - address: The broker host:port.
+//   - opts: Publisher options carrying the CA file and verification setting.
+//
+// Returns:
+//   - *tls.Config: The TLS settings.
+//   - error: Non-nil if the CA file cannot be read or holds no certificates.
+func newTLSConfig(address string, opts Options) (*tls.Config, error) {
+	host, _, _ := net.SplitHostPort(address)
+
+	tlsConfig := &tls.Config{
+		ServerName:         host,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.TLSSkipVerify, //nolint:gosec // Opt-in for self-signed brokers.
+	}
+
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read MQTT CA file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCACert, opts.CAFile)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// Run keeps the broker connection open until ctx is canceled, reconnecting
+// with exponential backoff after failures.
+//
+// Parameters:
+//   - ctx: Process context; cancellation publishes the offline status and disconnects.
+func (p *Publisher) Run(ctx context.Context) {
+	delay := minReconnectDelay
+
+	for {
+		connected, err := p.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if connected {
+			delay = minReconnectDelay
+		}
+
+		p.log.Warn().Err(err).Dur("retry_in", delay).Msg("MQTT connection lost")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// PublishScan caches and publishes the container states and summary of a scan.
+//
+// Parameters:
+//   - report: The session report; nil when the scan aborted.
+//   - summary: The scan counts and error.
+func (p *Publisher) PublishScan(report types.Report, summary Summary) {
+	now := time.Now().UTC()
+
+	payload := summaryPayload{
+		Summary:    summary,
+		DurationMS: summary.Duration.Milliseconds(),
+		FinishedAt: now,
+	}
+	if summary.Err != nil {
+		payload.Error = summary.Err.Error()
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		p.log.Warn().Err(err).Msg("Failed to encode MQTT scan summary")
+
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.summary = encoded
+
+	var names []string
+
+	if report != nil {
+		for _, container := range report.All() {
+			name := strings.TrimPrefix(container.Name(), "/")
+			p.containers[name] = newContainerState(container, p.containers[name], now)
+			names = append(names, name)
+		}
+	}
+
+	if p.client == nil {
+		return
+	}
+
+	p.publishLocked(p.summaryTopic(), p.summary)
+
+	for _, name := range names {
+		p.publishContainerLocked(name)
+	}
+}
+
+// newContainerState derives a container's state from its scan report.
+//
+// Parameters:
+//   - report: The container's report.
+//   - previous: The cached state, or nil.
+//   - now: The scan completion time.
+//
+// Returns:
+//   - *ContainerState: The new state.
+func newContainerState(report types.ContainerReport, previous *ContainerState, now time.Time) *ContainerState {
+	current, latest := report.CurrentImageID(), report.LatestImageID()
+	if latest == "" {
+		latest = current
+	}
+
+	var lastUpdated *time.Time
+	if previous != nil {
+		lastUpdated = previous.LastUpdated
+	}
+
+	// An updated container runs the latest image.
+	if report.State() == session.UpdatedStateString {
+		current = latest
+		lastUpdated = &now
+	}
+
+	return &ContainerState{
+		Container:        strings.TrimPrefix(report.Name(), "/"),
+		Image:            report.ImageName(),
+		State:            strings.ToLower(report.State()),
+		UpdateAvailable:  current != latest,
+		InstalledVersion: current.ShortID(),
+		LatestVersion:    latest.ShortID(),
+		CurrentDigest:    string(current),
+		LatestDigest:     string(latest),
+		LastUpdated:      lastUpdated,
+		MonitorOnly:      report.IsMonitorOnly(),
+		Title:            report.ImageName(),
+		ReleaseURL:       report.LatestImageMetadata().Source,
+		Error:            report.Error(),
+	}
+}
+
+// session connects once and serves the connection until it fails or ctx is canceled.
+//
+// Parameters:
+//   - ctx: Process context.
+//
+// Returns:
+//   - bool: True if the connection was established.
+//   - error: The error that ended the session.
+func (p *Publisher) session(ctx context.Context) (bool, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+
+	mqttClient, err := dial(dialCtx, p.address, p.tlsConfig, connectOptions{
+		clientID:  p.opts.ClientID,
+		username:  p.opts.Username,
+		password:  p.opts.Password,
+		keepAlive: p.opts.KeepAlive,
+		will: &will{
+			topic:   p.statusTopic(),
+			payload: []byte(availabilityOffline),
+			retain:  true,
+		},
+	})
+
+	cancel()
+
+	if err != nil {
+		return false, err
+	}
+
+	p.log.Debug().Str("broker", p.address).Msg("Connected to MQTT broker")
+
+	readErr := make(chan error, 1)
+
+	go func() {
+		readErr <- mqttClient.readLoop(p.opts.KeepAlive*3/2, func(msg message) {
+			p.handleCommand(ctx, msg)
+		})
+	}()
+
+	if p.install != nil {
+		err = mqttClient.subscribe(p.commandTopic("+"))
+		if err != nil {
+			mqttClient.close()
+
+			return true, err
+		}
+	}
+
+	p.mu.Lock()
+	p.client = mqttClient
+	clear(p.discovered)
+	p.publishLocked(p.statusTopic(), []byte(availabilityOnline))
+
+	if p.summary != nil {
+		p.publishLocked(p.summaryTopic(), p.summary)
+	}
+
+	for name := range p.containers {
+		p.publishContainerLocked(name)
+	}
+	p.mu.Unlock()
+
+	ticker := time.NewTicker(p.opts.KeepAlive)
+	defer ticker.Stop()
+
+	defer func() {
+		p.mu.Lock()
+		p.client = nil
+		p.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = mqttClient.publish(p.statusTopic(), []byte(availabilityOffline), true)
+			mqttClient.disconnect()
+
+			return true, ctx.Err()
+		case err := <-readErr:
+			mqttClient.close()
+
+			return true, err
+		case <-ticker.C:
+			err := mqttClient.ping()
+			if err != nil {
+				mqttClient.close()
+
+				return true, err
+			}
+		}
+	}
+}
+
+// handleCommand starts an install for a message on a container's command topic.
+//
+// Parameters:
+//   - ctx: Process context passed to the install.
+//   - msg: The received message.
+func (p *Publisher) handleCommand(ctx context.Context, msg message) {
+	if p.install == nil {
+		return
+	}
+
+	segment, ok := strings.CutPrefix(msg.topic, p.opts.TopicPrefix+"/containers/")
+	if !ok {
+		return
+	}
+
+	segment, ok = strings.CutSuffix(segment, "/command")
+	if !ok || strings.Contains(segment, "/") {
+		return
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(string(msg.payload)), PayloadInstall) {
+		p.log.Debug().Str("topic", msg.topic).Msg("Ignoring unknown MQTT command")
+
+		return
+	}
+
+	p.mu.Lock()
+
+	name := ""
+
+	for candidate := range p.containers {
+		if topicSegment(candidate) == segment {
+			name = candidate
+
+			break
+		}
+	}
+
+	if name == "" || p.containers[name].InProgress {
+		p.mu.Unlock()
+		p.log.Debug().Str("container", segment).Msg("Ignoring MQTT install for unknown or busy container")
+
+		return
+	}
+
+	p.containers[name].InProgress = true
+	p.publishContainerLocked(name)
+	p.mu.Unlock()
+
+	p.log.Info().Str("container", name).Msg("Installing update requested over MQTT")
+
+	go func() {
+		p.install(ctx, name)
+
+		// The scan normally replaces the state; clear the flag if it did not.
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		if state := p.containers[name]; state != nil && state.InProgress {
+			state.InProgress = false
+			p.publishContainerLocked(name)
+		}
+	}()
+}
+
+// publishContainerLocked publishes a container's discovery config, once per
+// connection, and its state. p.mu must be held.
+//
+// Parameters:
+//   - name: The container name.
+func (p *Publisher) publishContainerLocked(name string) {
+	state := p.containers[name]
+	if p.client == nil || state == nil {
+		return
+	}
+
+	if p.opts.Discovery && !p.discovered[name] {
+		config, err := json.Marshal(p.discoveryConfig(state))
+		if err == nil {
+			p.publishLocked(p.discoveryTopic(name), config)
+			p.discovered[name] = true
+		}
+	}
+
+	payload, err := json.Marshal(state)
+	if err != nil {
+		p.log.Warn().Err(err).Str("container", name).Msg("Failed to encode MQTT container state")
+
+		return
+	}
+
+	p.publishLocked(p.stateTopic(name), payload)
+}
+
+// publishLocked publishes a retained message. A failed write closes the
+// connection so Run reconnects and republishes the cached state. p.mu must be held.
+//
+// Parameters:
+//   - topic: Topic name.
+//   - payload: Message payload.
+func (p *Publisher) publishLocked(topic string, payload []byte) {
+	if p.client == nil {
+		return
+	}
+
+	err := p.client.publish(topic, payload, true)
+	if err != nil {
+		p.log.Warn().Err(err).Str("topic", topic).Msg("Failed to publish MQTT message")
+		p.client.close()
+		p.client = nil
+	}
+}
+
+// discoveryConfig builds the Home Assistant update entity config for a container.
+//
+// Monitor-only containers get no command topic, so Home Assistant shows
+// their updates without an install button.
+//
+// Parameters:
+//   - state: The container's state.
+//
+// Returns:
+//   - discoveryConfig: The discovery payload.
+func (p *Publisher) discoveryConfig(state *ContainerState) discoveryConfig {
+	nodeID := discoveryID(p.opts.ClientID)
+
+	config := discoveryConfig{
+		Name:                state.Container,
+		UniqueID:            nodeID + "_" + discoveryID(state.Container),
+		StateTopic:          p.stateTopic(state.Container),
+		JSONAttributesTopic: p.stateTopic(state.Container),
+		AvailabilityTopic:   p.statusTopic(),
+		Device: discoveryDevice{
+			Identifiers:  []string{nodeID},
+			Name:         "Watchtower (" + p.opts.ClientID + ")",
+			Manufacturer: "Watchtower",
+			Model:        "Container updater",
+			SWVersion:    p.opts.Version,
+		},
+		Origin: discoveryOrigin{
+			Name:       "Watchtower",
+			SWVersion:  p.opts.Version,
+			SupportURL: "https://watchtower.nickfedor.com",
+		},
+	}
+
+	if p.install != nil && !state.MonitorOnly {
+		config.CommandTopic = p.commandTopic(topicSegment(state.Container))
+		config.PayloadInstall = PayloadInstall
+	}
+
+	return config
+}
+
+// statusTopic returns the availability topic.
+func (p *Publisher) statusTopic() string {
+	return p.opts.TopicPrefix + "/status"
+}
+
+// summaryTopic returns the scan summary topic.
+func (p *Publisher) summaryTopic() string {
+	return p.opts.TopicPrefix + "/scan"
+}
+
+// stateTopic returns a container's state topic.
+func (p *Publisher) stateTopic(name string) string {
+	return p.opts.TopicPrefix + "/containers/" + topicSegment(name) + "/state"
+}
+
+// commandTopic returns the command topic for a container topic segment or wildcard.
+func (p *Publisher) commandTopic(segment string) string {
+	return p.opts.TopicPrefix + "/containers/" + segment + "/command"
+}
+
+// discoveryTopic returns a container's Home Assistant discovery topic.
+func (p *Publisher) discoveryTopic(name string) string {
+	return p.opts.DiscoveryPrefix + "/update/" + discoveryID(p.opts.ClientID) + "/" + discoveryID(name) + "/config"
+}
+
+// topicSegment makes a container name safe for use as one topic level.
+//
+// Parameters:
+//   - name: The container name.
+//
+// Returns:
+//   - string: The name with the leading slash removed and "/", "+", and "#" replaced by "_".
+func topicSegment(name string) string {
+	return strings.NewReplacer("/", "_", "+", "_", "#", "_").Replace(strings.TrimPrefix(name, "/"))
+}
+
+// discoveryID makes a name safe for use as a Home Assistant node or object ID.
+//
+// Parameters:
+//   - name: The name.
+//
+// Returns:
+//   - string: The name with characters outside [a-zA-Z0-9_-] replaced by "_".
+func discoveryID(name string) string {
+	return unsafeIDChars.ReplaceAllString(strings.TrimPrefix(name, "/"), "_")
+}
@@ -0,0 +1,507 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+type published struct {
+	topic   string
+	payload string
+	retain  bool
+}
+
+type connectPacket struct {
+	clientID  string
+	username  string
+	password  string
+	willTopic string
+	keepAlive uint16
+}
+
+// fakeBroker accepts one client at a time and records its packets.
+type fakeBroker struct {
+	t        *testing.T
+	listener net.Listener
+	code     byte
+
+	mu        sync.Mutex
+	conn      net.Conn
+	connects  []connectPacket
+	messages  []published
+	subscribe []string
+}
+
+func newFakeBroker(t *testing.T, code byte) *fakeBroker {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	broker := &fakeBroker{t: t, listener: listener, code: code}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go broker.serve()
+
+	return broker
+}
+
+func (b *fakeBroker) url() string {
+	return "mqtt://" + b.listener.Addr().String()
+}
+
+func (b *fakeBroker) serve() {
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		b.mu.Lock()
+		b.conn = conn
+		b.mu.Unlock()
+
+		b.handle(conn)
+	}
+}
+
+func (b *fakeBroker) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		header, err := reader.ReadByte()
+		if err != nil {
+			return
+		}
+
+		length, err := decodeLength(reader)
+		if err != nil {
+			return
+		}
+
+		packet := make([]byte, length)
+
+		_, err = readFull(reader, packet)
+		if err != nil {
+			return
+		}
+
+		switch header & 0xF0 {
+		case packetConnect:
+			b.recordConnect(packet)
+			_, _ = conn.Write([]byte{packetConnack, 2, 0, b.code})
+		case packetPublish:
+			topic, payload, _ := readString(packet)
+
+			b.mu.Lock()
+			b.messages = append(b.messages, published{topic: topic, payload: string(payload), retain: header&publishRetain != 0})
+			b.mu.Unlock()
+		case packetSubscribe:
+			filter, _, _ := readString(packet[2:])
+
+			b.mu.Lock()
+			b.subscribe = append(b.subscribe, filter)
+			b.mu.Unlock()
+
+			_, _ = conn.Write([]byte{packetSuback, 3, packet[0], packet[1], 0})
+		case packetPingreq:
+			_, _ = conn.Write([]byte{packetPingresp, 0})
+		case packetDisconnect:
+			return
+		}
+	}
+}
+
+func (b *fakeBroker) recordConnect(packet []byte) {
+	_, rest, _ := readString(packet)
+	flags := rest[1]
+	keepAlive := binary.BigEndian.Uint16(rest[2:4])
+	rest = rest[4:]
+
+	connect := connectPacket{keepAlive: keepAlive}
+	connect.clientID, rest, _ = readString(rest)
+
+	if flags&connectWill != 0 {
+		connect.willTopic, rest, _ = readString(rest)
+		_, rest, _ = readString(rest)
+	}
+
+	if flags&connectUsername != 0 {
+		connect.username, rest, _ = readString(rest)
+	}
+
+	if flags&connectPassword != 0 {
+		connect.password, _, _ = readString(rest)
+	}
+
+	b.mu.Lock()
+	b.connects = append(b.connects, connect)
+	b.mu.Unlock()
+}
+
+// send publishes a message to the connected client.
+func (b *fakeBroker) send(topic, payload string) {
+	body := append(appendString(nil, topic), payload...)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_, err := b.conn.Write(append(append([]byte{packetPublish}, encodeLength(len(body))...), body...))
+	require.NoError(b.t, err)
+}
+
+// dropConnection closes the client connection without a DISCONNECT.
+func (b *fakeBroker) dropConnection() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	_ = b.conn.Close()
+}
+
+// waitFor waits until a message on topic has been received and returns the latest one.
+func (b *fakeBroker) waitFor(topic string, match func(published) bool) published {
+	b.t.Helper()
+
+	var found published
+
+	require.Eventually(b.t, func() bool {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		for i := len(b.messages) - 1; i >= 0; i-- {
+			if b.messages[i].topic == topic && (match == nil || match(b.messages[i])) {
+				found = b.messages[i]
+
+				return true
+			}
+		}
+
+		return false
+	}, 5*time.Second, 10*time.Millisecond, "no message on %s", topic)
+
+	return found
+}
+
+func (b *fakeBroker) count(topic string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	count := 0
+
+	for _, msg := range b.messages {
+		if msg.topic == topic {
+			count++
+		}
+	}
+
+	return count
+}
+
+func readFull(reader *bufio.Reader, buf []byte) (int, error) {
+	read := 0
+
+	for read < len(buf) {
+		n, err := reader.Read(buf[read:])
+		if err != nil {
+			return read, err
+		}
+
+		read += n
+	}
+
+	return read, nil
+}
+
+type fakeContainerReport struct {
+	name        string
+	image       string
+	current     types.ImageID
+	latest      types.ImageID
+	state       string
+	monitorOnly bool
+}
+
+func (r fakeContainerReport) ID() types.ContainerID                   { return types.ContainerID("id-" + r.name) }
+func (r fakeContainerReport) Name() string                            { return r.name }
+func (r fakeContainerReport) CurrentImageID() types.ImageID           { return r.current }
+func (r fakeContainerReport) LatestImageID() types.ImageID            { return r.latest }
+func (r fakeContainerReport) ImageName() string                       { return r.image }
+func (r fakeContainerReport) Error() string                           { return "" }
+func (r fakeContainerReport) State() string                           { return r.state }
+func (r fakeContainerReport) IsMonitorOnly() bool                     { return r.monitorOnly }
+func (r fakeContainerReport) NewContainerID() types.ContainerID       { return "" }
+func (fakeContainerReport) CurrentImageMetadata() types.ImageMetadata { return types.ImageMetadata{} }
+func (fakeContainerReport) HookRuns() []types.HookRun                 { return nil }
+func (fakeContainerReport) BackupPath() string                        { return "" }
+func (fakeContainerReport) CrashLogs() []string                       { return nil }
+
+func (fakeContainerReport) LatestImageMetadata() types.ImageMetadata {
+	return types.ImageMetadata{Source: "https://github.com/example/app"}
+}
+
+type fakeReport []types.ContainerReport
+
+func (r fakeReport) Scanned() []types.ContainerReport { return r }
+func (fakeReport) Updated() []types.ContainerReport   { return nil }
+func (fakeReport) Failed() []types.ContainerReport    { return nil }
+func (fakeReport) Skipped() []types.ContainerReport   { return nil }
+func (fakeReport) Stale() []types.ContainerReport     { return nil }
+func (fakeReport) Fresh() []types.ContainerReport     { return nil }
+func (fakeReport) Restarted() []types.ContainerReport { return nil }
+func (r fakeReport) All() []types.ContainerReport     { return r }
+
+const (
+	oldImage types.ImageID = "sha256:1111111111111111111111111111111111111111111111111111111111111111"
+	newImage types.ImageID = "sha256:2222222222222222222222222222222222222222222222222222222222222222"
+)
+
+func startPublisher(t *testing.T, opts Options, install InstallFunc) *Publisher {
+	t.Helper()
+
+	publisher, err := New(nil, opts, install)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		publisher.Run(ctx)
+		close(done)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return publisher
+}
+
+func TestNew_Validation(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURL := range []string{"", "broker:1883", "http://broker", "mqtt://"} {
+		_, err := New(nil, Options{BrokerURL: rawURL}, nil)
+		require.ErrorIs(t, err, ErrInvalidBrokerURL, rawURL)
+	}
+
+	for _, prefix := range []string{"watchtower/#", "home/+/x", "/"} {
+		_, err := New(nil, Options{BrokerURL: "mqtt://broker", TopicPrefix: prefix}, nil)
+		require.ErrorIs(t, err, ErrInvalidTopicPrefix, prefix)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0o600))
+
+	_, err := New(nil, Options{BrokerURL: "mqtts://broker", CAFile: caFile}, nil)
+	require.ErrorIs(t, err, ErrInvalidCACert)
+
+	publisher, err := New(nil, Options{BrokerURL: "mqtt://broker"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "broker:1883", publisher.address)
+	assert.Nil(t, publisher.tlsConfig)
+	assert.Equal(t, DefaultTopicPrefix, publisher.opts.TopicPrefix)
+	assert.Equal(t, DefaultDiscoveryPrefix, publisher.opts.DiscoveryPrefix)
+
+	publisher, err = New(nil, Options{BrokerURL: "mqtts://broker", TLSSkipVerify: true}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "broker:8883", publisher.address)
+	require.NotNil(t, publisher.tlsConfig)
+	assert.True(t, publisher.tlsConfig.InsecureSkipVerify)
+}
+
+func TestDial_ConnectRefused(t *testing.T) {
+	t.Parallel()
+
+	broker := newFakeBroker(t, 5)
+
+	_, err := dial(context.Background(), broker.listener.Addr().String(), nil, connectOptions{clientID: "test"})
+	require.ErrorIs(t, err, ErrConnectRefused)
+	assert.Contains(t, err.Error(), "not authorized")
+}
+
+func TestPublisher_PublishesStateAndDiscovery(t *testing.T) {
+	t.Parallel()
+
+	broker := newFakeBroker(t, 0)
+
+	publisher, err := New(nil, Options{
+		BrokerURL: broker.url(),
+		Username:  "user",
+		Password:  "secret",
+		ClientID:  "nas.lan",
+		Discovery: true,
+		Version:   "v1.2.3",
+	}, func(context.Context, string) {})
+	require.NoError(t, err)
+
+	// Scans published before the connection are delivered once connected.
+	publisher.PublishScan(fakeReport{
+		fakeContainerReport{name: "/web", image: "nginx:latest", current: oldImage, latest: newImage, state: session.StaleStateString},
+		fakeContainerReport{name: "/db", image: "postgres:16", current: oldImage, latest: newImage, state: session.UpdatedStateString},
+		fakeContainerReport{name: "/cache", image: "redis:7", current: oldImage, state: session.FreshStateString, monitorOnly: true},
+	}, Summary{Scanned: 3, Updated: 1, Duration: 1500 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		publisher.Run(ctx)
+		close(done)
+	}()
+
+	assert.Equal(t, "online", broker.waitFor("watchtower/status", nil).payload)
+
+	broker.mu.Lock()
+	connect := broker.connects[0]
+	subscriptions := append([]string(nil), broker.subscribe...)
+	broker.mu.Unlock()
+
+	assert.Equal(t, connectPacket{
+		clientID:  "nas.lan",
+		username:  "user",
+		password:  "secret",
+		willTopic: "watchtower/status",
+		keepAlive: 30,
+	}, connect)
+	assert.Equal(t, []string{"watchtower/containers/+/command"}, subscriptions)
+
+	var summary map[string]any
+
+	msg := broker.waitFor("watchtower/scan", nil)
+	assert.True(t, msg.retain)
+	require.NoError(t, json.Unmarshal([]byte(msg.payload), &summary))
+	assert.InDelta(t, 3, summary["scanned"], 0)
+	assert.InDelta(t, 1500, summary["duration_ms"], 0)
+
+	var web ContainerState
+
+	msg = broker.waitFor("watchtower/containers/web/state", nil)
+	assert.True(t, msg.retain)
+	require.NoError(t, json.Unmarshal([]byte(msg.payload), &web))
+	assert.True(t, web.UpdateAvailable)
+	assert.Equal(t, "111111111111", web.InstalledVersion)
+	assert.Equal(t, "222222222222", web.LatestVersion)
+	assert.Equal(t, string(newImage), web.LatestDigest)
+	assert.Equal(t, "https://github.com/example/app", web.ReleaseURL)
+	assert.Nil(t, web.LastUpdated)
+
+	var db ContainerState
+
+	require.NoError(t, json.Unmarshal([]byte(broker.waitFor("watchtower/containers/db/state", nil).payload), &db))
+	assert.False(t, db.UpdateAvailable)
+	assert.Equal(t, "222222222222", db.InstalledVersion)
+	assert.NotNil(t, db.LastUpdated)
+
+	var config map[string]any
+
+	require.NoError(t, json.Unmarshal([]byte(broker.waitFor("homeassistant/update/nas_lan/web/config", nil).payload), &config))
+	assert.Equal(t, "web", config["name"])
+	assert.Equal(t, "nas_lan_web", config["unique_id"])
+	assert.Equal(t, "watchtower/containers/web/state", config["state_topic"])
+	assert.Equal(t, "watchtower/containers/web/command", config["command_topic"])
+	assert.Equal(t, "install", config["payload_install"])
+	assert.Equal(t, "watchtower/status", config["availability_topic"])
+
+	config = nil
+	require.NoError(t, json.Unmarshal([]byte(broker.waitFor("homeassistant/update/nas_lan/cache/config", nil).payload), &config))
+	assert.NotContains(t, config, "command_topic", "monitor-only containers have no install action")
+
+	cancel()
+	<-done
+
+	broker.waitFor("watchtower/status", func(msg published) bool { return msg.payload == "offline" })
+}
+
+func TestPublisher_InstallCommand(t *testing.T) {
+	t.Parallel()
+
+	broker := newFakeBroker(t, 0)
+	installed := make(chan string, 1)
+	release := make(chan struct{})
+
+	publisher := startPublisher(t, Options{BrokerURL: broker.url(), Discovery: true}, func(_ context.Context, name string) {
+		installed <- name
+		<-release
+	})
+
+	broker.waitFor("watchtower/status", nil)
+
+	publisher.PublishScan(fakeReport{
+		fakeContainerReport{name: "/web", image: "nginx:latest", current: oldImage, latest: newImage, state: session.StaleStateString},
+	}, Summary{Scanned: 1})
+	broker.waitFor("watchtower/containers/web/state", nil)
+
+	broker.send("watchtower/containers/unknown/command", "install")
+	broker.send("watchtower/containers/web/command", "reboot")
+	broker.send("watchtower/containers/web/command", "install")
+
+	select {
+	case name := <-installed:
+		assert.Equal(t, "web", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("install was not requested")
+	}
+
+	broker.waitFor("watchtower/containers/web/state", func(msg published) bool {
+		var state ContainerState
+
+		return json.Unmarshal([]byte(msg.payload), &state) == nil && state.InProgress
+	})
+
+	close(release)
+
+	broker.waitFor("watchtower/containers/web/state", func(msg published) bool {
+		var state ContainerState
+
+		return json.Unmarshal([]byte(msg.payload), &state) == nil && !state.InProgress
+	})
+}
+
+func TestPublisher_RepublishesAfterReconnect(t *testing.T) {
+	t.Parallel()
+
+	broker := newFakeBroker(t, 0)
+	publisher := startPublisher(t, Options{BrokerURL: broker.url(), Discovery: true}, nil)
+
+	broker.waitFor("watchtower/status", nil)
+
+	publisher.PublishScan(fakeReport{
+		fakeContainerReport{name: "/web", image: "nginx:latest", current: oldImage, latest: newImage, state: session.StaleStateString},
+	}, Summary{Scanned: 1})
+	broker.waitFor("watchtower/containers/web/state", nil)
+
+	broker.dropConnection()
+
+	require.Eventually(t, func() bool {
+		return broker.count("watchtower/containers/web/state") == 2 &&
+			broker.count("homeassistant/update/watchtower/web/config") == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	broker.mu.Lock()
+	assert.Empty(t, broker.subscribe, "no command subscription without an install function")
+	broker.mu.Unlock()
+}
+
+func TestTopicNames(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "a_b_c_d", topicSegment("/a/b+c#d"))
+	assert.Equal(t, "my_app-1", discoveryID("/my.app-1"))
+}
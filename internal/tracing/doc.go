@@ -0,0 +1,26 @@
+// Package tracing provides optional OpenTelemetry tracing for Watchtower.
+//
+// Tracing is disabled by default; span helpers then operate on the OpenTelemetry
+// no-op provider and add no measurable overhead. When an OTLP exporter is
+// configured, each update scan produces one root span with a child span per
+// container, and nested spans for registry, image pull, container lifecycle,
+// lifecycle hook, and health wait operations.
+//
+// Key components:
+//   - Setup: Installs the OTLP exporter, sampler, and W3C propagators.
+//   - Start, StartContainer, RecordError: Create spans and mark them failed.
+//   - TraceParent: Returns the W3C trace context of a context for event payloads.
+//   - NewTransport: Propagates the trace context of each request into
+//     outbound HTTP calls.
+//
+// Usage example:
+//
+//	shutdown, err := tracing.Setup(ctx, tracing.Options{Exporter: tracing.ExporterOTLPGRPC})
+//	if err != nil {
+//	    return err
+//	}
+//	defer shutdown(context.Background())
+//
+//	ctx, span := tracing.StartContainer(ctx, "container.stop", c)
+//	defer span.End()
+package tracing
@@ -0,0 +1,302 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Supported span exporters.
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = "none"
+	// ExporterOTLPGRPC exports spans with OTLP over gRPC (default port 4317).
+	ExporterOTLPGRPC = "otlp-grpc"
+	// ExporterOTLPHTTP exports spans with OTLP over HTTP/protobuf (default port 4318).
+	ExporterOTLPHTTP = "otlp-http"
+)
+
+// instrumentationName identifies Watchtower spans to the tracer provider.
+const instrumentationName = "github.com/nicholas-fedor/watchtower"
+
+// serviceName is the service.name resource attribute on exported spans.
+const serviceName = "watchtower"
+
+// Span attribute keys shared by the instrumented packages.
+const (
+	// ContainerNameKey is the container name attribute.
+	ContainerNameKey = attribute.Key("container.name")
+	// ContainerIDKey is the container ID attribute.
+	ContainerIDKey = attribute.Key("container.id")
+	// ImageNameKey is the container image name attribute.
+	ImageNameKey = attribute.Key("container.image.name")
+)
+
+// ErrInvalidExporter indicates an unsupported exporter name.
+var ErrInvalidExporter = errors.New("invalid tracing exporter")
+
+// traceContext is the W3C propagator used for event payloads regardless of the
+// globally installed propagator.
+var traceContext = propagation.TraceContext{}
+
+// Options configures the tracer provider installed by Setup.
+type Options struct {
+	// Exporter selects the span exporter (ExporterNone, ExporterOTLPGRPC, ExporterOTLPHTTP).
+	Exporter string
+	// Endpoint is the collector host:port or URL; empty uses the OTEL_EXPORTER_OTLP_* environment or the exporter default.
+	Endpoint string
+	// Insecure disables TLS for the exporter connection.
+	Insecure bool
+	// SampleRatio is the fraction of new traces that are sampled (0 to 1).
+	SampleRatio float64
+	// ServiceVersion is the service.version resource attribute.
+	ServiceVersion string
+}
+
+// Setup installs the global tracer provider and propagators.
+//
+// With ExporterNone or an empty exporter Setup leaves the no-op provider in
+// place and returns a no-op shutdown function.
+//
+// Parameters:
+//   - ctx: Context for exporter construction.
+//   - opts: Exporter, endpoint, and sampling options.
+//
+// Returns:
+//   - func(context.Context) error: Flushes buffered spans and stops the exporter.
+//   - error: Non-nil if the exporter name is invalid or the exporter cannot be created.
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	noop := func(context.Context) error { return nil }
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch strings.ToLower(opts.Exporter) {
+	case "", ExporterNone:
+		return noop, nil
+	case ExporterOTLPGRPC:
+		exporter, err = otlptracegrpc.New(ctx, grpcOptions(opts)...)
+	case ExporterOTLPHTTP:
+		exporter, err = otlptracehttp.New(ctx, httpOptions(opts)...)
+	default:
+		return noop, fmt.Errorf("%w: %q", ErrInvalidExporter, opts.Exporter)
+	}
+
+	if err != nil {
+		return noop, fmt.Errorf("failed to create %s exporter: %w", opts.Exporter, err)
+	}
+
+	res, err := resource.New(ctx,
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			attribute.String("service.name", serviceName),
+			attribute.String("service.version", opts.ServiceVersion),
+		),
+	)
+	if err != nil {
+		return noop, fmt.Errorf("failed to build tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	return provider.Shutdown, nil
+}
+
+// Start creates a span as a child of the span in ctx.
+//
+// Parameters:
+//   - ctx: Parent context.
+//   - name: Span name.
+//   - attrs: Initial span attributes.
+//
+// Returns:
+//   - context.Context: Context carrying the new span.
+//   - trace.Span: The started span; callers must call End.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartContainer creates a span for an operation on a container.
+//
+// Container attributes are only read when the span is recording so disabled
+// tracing adds no container lookups.
+//
+// Parameters:
+//   - ctx: Parent context.
+//   - name: Span name.
+//   - container: The container; nil adds no container attributes.
+//   - attrs: Additional span attributes.
+//
+// Returns:
+//   - context.Context: Context carrying the new span.
+//   - trace.Span: The started span; callers must call End.
+func StartContainer(
+	ctx context.Context,
+	name string,
+	container types.Container,
+	attrs ...attribute.KeyValue,
+) (context.Context, trace.Span) {
+	ctx, span := Start(ctx, name, attrs...)
+	if span.IsRecording() {
+		span.SetAttributes(ContainerAttributes(container)...)
+	}
+
+	return ctx, span
+}
+
+// RecordError marks a span as failed with the given error.
+//
+// Parameters:
+//   - span: The span to update.
+//   - err: The error; nil leaves the span unchanged.
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// ContainerAttributes returns the identifying span attributes for a container.
+//
+// Parameters:
+//   - container: The container; nil returns no attributes.
+//
+// Returns:
+//   - []attribute.KeyValue: Name, ID, and image name attributes.
+func ContainerAttributes(container types.Container) []attribute.KeyValue {
+	if container == nil {
+		return nil
+	}
+
+	return []attribute.KeyValue{
+		ContainerNameKey.String(container.Name()),
+		ContainerIDKey.String(string(container.ID())),
+		ImageNameKey.String(container.ImageName()),
+	}
+}
+
+// Detach returns a background context carrying only the span from ctx.
+//
+// It is used where work intentionally outlives the parent context's
+// cancellation but should still be recorded under the parent span.
+//
+// Parameters:
+//   - ctx: Context holding the span.
+//
+// Returns:
+//   - context.Context: A non-cancelable context with the same span.
+func Detach(ctx context.Context) context.Context {
+	return trace.ContextWithSpan(context.Background(), trace.SpanFromContext(ctx))
+}
+
+// TraceParent returns the W3C traceparent and tracestate values for ctx.
+//
+// Parameters:
+//   - ctx: Context holding the span.
+//
+// Returns:
+//   - string: The traceparent value, or empty when ctx has no valid span.
+//   - string: The tracestate value, or empty.
+func TraceParent(ctx context.Context) (string, string) {
+	carrier := propagation.MapCarrier{}
+	traceContext.Inject(ctx, carrier)
+
+	return carrier.Get("traceparent"), carrier.Get("tracestate")
+}
+
+// ContextFromTraceParent returns a context whose remote parent is the given
+// W3C trace context.
+//
+// Parameters:
+//   - ctx: Base context.
+//   - traceParent: The traceparent value.
+//   - traceState: The tracestate value.
+//
+// Returns:
+//   - context.Context: ctx with the remote span context, or ctx unchanged when traceParent is invalid.
+func ContextFromTraceParent(ctx context.Context, traceParent, traceState string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+
+	return traceContext.Extract(ctx, propagation.MapCarrier{
+		"traceparent": traceParent,
+		"tracestate":  traceState,
+	})
+}
+
+// grpcOptions builds the OTLP/gRPC exporter options.
+//
+// Parameters:
+//   - opts: Tracing options.
+//
+// Returns:
+//   - []otlptracegrpc.Option: Endpoint and TLS options.
+func grpcOptions(opts Options) []otlptracegrpc.Option {
+	var exporterOpts []otlptracegrpc.Option
+
+	switch {
+	case strings.Contains(opts.Endpoint, "://"):
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithEndpointURL(opts.Endpoint))
+	case opts.Endpoint != "":
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+	}
+
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracegrpc.WithInsecure())
+	}
+
+	return exporterOpts
+}
+
+// httpOptions builds the OTLP/HTTP exporter options.
+//
+// Parameters:
+//   - opts: Tracing options.
+//
+// Returns:
+//   - []otlptracehttp.Option: Endpoint and TLS options.
+func httpOptions(opts Options) []otlptracehttp.Option {
+	var exporterOpts []otlptracehttp.Option
+
+	switch {
+	case strings.Contains(opts.Endpoint, "://"):
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	case opts.Endpoint != "":
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpoint(opts.Endpoint))
+	}
+
+	if opts.Insecure {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithInsecure())
+	}
+
+	return exporterOpts
+}
@@ -0,0 +1,228 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	mockTypes "github.com/nicholas-fedor/watchtower/pkg/types/mocks"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+var errTest = errors.New("boom")
+
+// useRecorder installs an in-memory tracer provider for the duration of the test.
+func useRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	previousProvider := otel.GetTracerProvider()
+	previousPropagator := otel.GetTextMapPropagator()
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	return exporter
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	t.Parallel()
+
+	for _, exporter := range []string{"", ExporterNone} {
+		shutdown, err := Setup(t.Context(), Options{Exporter: exporter})
+		require.NoError(t, err)
+		require.NoError(t, shutdown(t.Context()))
+	}
+}
+
+func TestSetup_InvalidExporter(t *testing.T) {
+	t.Parallel()
+
+	_, err := Setup(t.Context(), Options{Exporter: "zipkin"})
+	require.ErrorIs(t, err, ErrInvalidExporter)
+}
+
+func TestGRPCOptions(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, grpcOptions(Options{}))
+	assert.Len(t, grpcOptions(Options{Endpoint: "collector:4317", Insecure: true}), 2)
+	assert.Len(t, httpOptions(Options{Endpoint: "https://collector:4318/v1/traces"}), 1)
+}
+
+//nolint:paralleltest // Replaces the global tracer provider.
+func TestStartAndRecordError(t *testing.T) {
+	exporter := useRecorder(t)
+
+	ctx, parent := Start(context.Background(), "watchtower.scan")
+	_, child := Start(ctx, "container.stop", ContainerNameKey.String("web"))
+	RecordError(child, errTest)
+	RecordError(child, nil)
+	child.End()
+	parent.End()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "container.stop", spans[0].Name)
+	assert.Equal(t, spans[1].SpanContext.SpanID(), spans[0].Parent.SpanID())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+	assert.Equal(t, "boom", spans[0].Status.Description)
+	assert.Equal(t, codes.Unset, spans[1].Status.Code)
+}
+
+func TestContainerAttributes(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, ContainerAttributes(nil))
+
+	container := mockTypes.NewMockContainer(t)
+	container.EXPECT().Name().Return("web")
+	container.EXPECT().ID().Return("abc123")
+	container.EXPECT().ImageName().Return("nginx:latest")
+
+	attrs := ContainerAttributes(container)
+	require.Len(t, attrs, 3)
+	assert.Equal(t, "web", attrs[0].Value.AsString())
+	assert.Equal(t, "abc123", attrs[1].Value.AsString())
+	assert.Equal(t, "nginx:latest", attrs[2].Value.AsString())
+}
+
+func TestTraceParentRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	traceParent, traceState := TraceParent(context.Background())
+	assert.Empty(t, traceParent)
+	assert.Empty(t, traceState)
+
+	ctx := ContextFromTraceParent(context.Background(), testTraceParent, "vendor=value")
+	assert.True(t, trace.SpanContextFromContext(ctx).IsRemote())
+
+	traceParent, traceState = TraceParent(ctx)
+	assert.Equal(t, testTraceParent, traceParent)
+	assert.Equal(t, "vendor=value", traceState)
+
+	assert.Equal(t, context.Background(), ContextFromTraceParent(context.Background(), "", ""))
+}
+
+func TestDetach(t *testing.T) {
+	t.Parallel()
+
+	parent, cancel := context.WithCancel(ContextFromTraceParent(context.Background(), testTraceParent, ""))
+	cancel()
+
+	detached := Detach(parent)
+	require.NoError(t, detached.Err())
+
+	traceParent, _ := TraceParent(detached)
+	assert.Equal(t, testTraceParent, traceParent)
+}
+
+//nolint:paralleltest // Replaces the global tracer provider.
+func TestTransport_InjectsTraceContext(t *testing.T) {
+	exporter := useRecorder(t)
+
+	var received []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("Traceparent"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	send := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	// Request without a span starts a new trace.
+	send(context.Background())
+
+	// Request with a span is parented on it.
+	send(ContextFromTraceParent(context.Background(), testTraceParent, ""))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	require.Len(t, received, 2)
+
+	assert.False(t, spans[0].Parent.IsValid())
+	assert.Contains(t, received[0], spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[1].SpanContext.TraceID().String())
+	assert.Equal(t, trace.SpanKindClient, spans[1].SpanKind)
+	assert.Contains(t, received[1], "4bf92f3577b34da6a3ce929d0e0e4736")
+}
+
+//nolint:paralleltest // Replaces the global tracer provider.
+func TestTransport_ConcurrentRequestsKeepTheirParents(t *testing.T) {
+	exporter := useRecorder(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	client := &http.Client{Transport: NewTransport(nil)}
+
+	parents := []string{
+		testTraceParent,
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+	}
+
+	var group sync.WaitGroup
+
+	for _, parent := range parents {
+		group.Go(func() {
+			ctx := ContextFromTraceParent(context.Background(), parent, "")
+
+			for range 5 {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+				assert.NoError(t, err)
+
+				resp, err := client.Do(req)
+				if assert.NoError(t, err) {
+					assert.NoError(t, resp.Body.Close())
+				}
+			}
+		})
+	}
+
+	group.Wait()
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 10)
+
+	perTrace := map[string]int{}
+	for _, span := range spans {
+		perTrace[span.SpanContext.TraceID().String()]++
+	}
+
+	assert.Equal(t, map[string]int{
+		"4bf92f3577b34da6a3ce929d0e0e4736": 5,
+		"0af7651916cd43dd8448eb211c80319c": 5,
+	}, perTrace)
+}
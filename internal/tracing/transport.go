@@ -0,0 +1,69 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// transport records a client span per request and injects the W3C trace
+// context headers.
+type transport struct {
+	base http.RoundTripper
+}
+
+// NewTransport wraps a round tripper with client spans and trace context
+// propagation.
+//
+// Each request is parented on the span in its own context, so clients that
+// send requests for several operations at once keep them apart.
+//
+// Parameters:
+//   - base: The underlying round tripper; nil uses http.DefaultTransport.
+//
+// Returns:
+//   - http.RoundTripper: The instrumented round tripper.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &transport{base: base}
+}
+
+// RoundTrip sends the request under a client span.
+//
+// Parameters:
+//   - req: The outbound request.
+//
+// Returns:
+//   - *http.Response: The response from the underlying round tripper.
+//   - error: Non-nil if the request fails.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := otel.Tracer(instrumentationName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+		),
+	)
+	defer span.End()
+
+	// Clone before mutating headers; RoundTrippers must not modify the caller's request.
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		RecordError(span, err)
+
+		return nil, err //nolint:wrapcheck // RoundTrippers must return the transport error unchanged.
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	return resp, nil
+}
@@ -21,6 +21,7 @@ import (
 	dockerClient "github.com/moby/moby/client"
 
 	"github.com/nicholas-fedor/watchtower/internal/flags"
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/registry"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
@@ -212,6 +213,24 @@ type Client interface {
 		params types.UpdateParams,
 	) (bool, types.ImageID, string, error)
 
+	// LatestImageMetadata returns the OCI metadata of the newest image found by
+	// CheckContainerUpdate. A known local image is inspected; otherwise, the
+	// image config is read from the registry without pulling layers.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control.
+	//   - container: Container that was checked.
+	//   - latestImage: Latest local image ID from CheckContainerUpdate, or empty if only the registry has it.
+	//
+	// Returns:
+	//   - types.ImageMetadata: Metadata of the newest image.
+	//   - error: Non-nil if the image cannot be inspected or fetched.
+	LatestImageMetadata(
+		ctx context.Context,
+		container types.Container,
+		latestImage types.ImageID,
+	) (types.ImageMetadata, error)
+
 	// ExecuteCommand runs a command inside a container and returns whether
 	// to skip updates based on the result.
 	//
@@ -246,6 +265,27 @@ type Client interface {
 	//   - error: Non-nil if removal fails, nil on success.
 	RemoveImageByID(ctx context.Context, imageID types.ImageID, imageName string) error
 
+	// ListImages returns the top-level images on the Docker host.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control.
+	//
+	// Returns:
+	//   - []types.ImageSummary: Images with their tags, registry digests, and sizes.
+	//   - error: Non-nil if listing fails, nil on success.
+	ListImages(ctx context.Context) ([]types.ImageSummary, error)
+
+	// HasImage reports whether an image is present on the Docker host.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control.
+	//   - imageID: ID of the image.
+	//
+	// Returns:
+	//   - bool: True if the image exists.
+	//   - error: Non-nil if the image cannot be inspected for another reason.
+	HasImage(ctx context.Context, imageID types.ImageID) (bool, error)
+
 	// WarnOnHeadPullFailed determines whether to log a warning when a HEAD request fails during image pulls.
 	//
 	// The decision is based on the configured warning strategy and container context.
@@ -368,6 +408,37 @@ type Client interface {
 	// Returns:
 	//   - error: Non-nil if starting fails, nil on success.
 	StartContainerByID(ctx context.Context, containerID types.ContainerID) error
+
+	// BackupVolumes archives a stopped container's volumes into the configured
+	// backup directory using a short-lived helper container.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control.
+	//   - container: Stopped container whose volumes are archived.
+	//   - params: Update parameters with the backup directory, image, and retention.
+	//
+	// Returns:
+	//   - string: Backup directory of this generation, or empty if the container has no volumes.
+	//   - error: Non-nil if the backup fails, nil on success.
+	BackupVolumes(ctx context.Context, container types.Container, params types.UpdateParams) (string, error)
+
+	// ObserveContainer watches a started container for crashes and restart loops.
+	//
+	// Parameters:
+	//   - ctx: Context for cancellation and timeout control.
+	//   - containerID: ID of the container to observe.
+	//   - period: How long to observe the container; zero or negative skips the observation.
+	//   - params: Update parameters with the restart threshold and log line count.
+	//
+	// Returns:
+	//   - error: *CrashError if the container exited or restarted too often, non-nil if
+	//     inspection fails or the context is canceled, nil if it stayed up.
+	ObserveContainer(
+		ctx context.Context,
+		containerID types.ContainerID,
+		period time.Duration,
+		params types.UpdateParams,
+	) error
 }
 
 // client is the concrete implementation of the Client interface.
@@ -683,9 +754,14 @@ func (c *client) GetCurrentWatchtowerContainer(
 // Returns:
 //   - error: Non-nil if stop fails, nil on success.
 func (c *client) StopContainer(ctx context.Context, container types.Container, timeout time.Duration) error {
+	ctx, span := tracing.StartContainer(ctx, "container.stop", container)
+	defer span.End()
+
 	// Stop container using helper function.
 	err := StopSourceContainer(c.logger(), ctx, c.api, container, timeout)
 	if err != nil {
+		tracing.RecordError(span, err)
+
 		c.logger().Debug().
 			Err(err).
 			Str("container", container.Name()).
@@ -717,6 +793,9 @@ func (c *client) StopContainer(ctx context.Context, container types.Container, t
 // Returns:
 //   - error: Non-nil if stop/removal fails, nil on success.
 func (c *client) StopAndRemoveContainer(ctx context.Context, container types.Container, timeout time.Duration) error {
+	ctx, span := tracing.StartContainer(ctx, "container.stop", container)
+	defer span.End()
+
 	// Stop and remove container using helper function with volume option.
 	err := StopAndRemoveSourceContainer(c.logger(),
 		ctx,
@@ -726,6 +805,8 @@ func (c *client) StopAndRemoveContainer(ctx context.Context, container types.Con
 		c.RemoveVolumes,
 	)
 	if err != nil {
+		tracing.RecordError(span, err)
+
 		c.logger().Debug().
 			Err(err).
 			Str("container", container.Name()).
@@ -754,6 +835,9 @@ func (c *client) StopAndRemoveContainer(ctx context.Context, container types.Con
 //   - types.ContainerID: ID of the new container.
 //   - error: Non-nil if creation fails, nil on success.
 func (c *client) CreateContainer(ctx context.Context, container types.Container) (types.ContainerID, error) {
+	ctx, span := tracing.StartContainer(ctx, "container.create", container)
+	defer span.End()
+
 	fields := map[string]any{
 		"container": container.Name(),
 		"image":     container.ImageName(),
@@ -786,6 +870,8 @@ func (c *client) CreateContainer(ctx context.Context, container types.Container)
 		isPodman,
 	)
 	if err != nil {
+		tracing.RecordError(span, err)
+
 		c.logger().Debug().
 			Err(err).
 			Fields(fields).
@@ -813,6 +899,9 @@ func (c *client) CreateContainer(ctx context.Context, container types.Container)
 //   - types.ContainerID: ID of the new container.
 //   - error: Non-nil if creation/start fails, nil on success.
 func (c *client) StartContainer(ctx context.Context, container types.Container) (types.ContainerID, error) {
+	ctx, span := tracing.StartContainer(ctx, "container.start", container)
+	defer span.End()
+
 	fields := map[string]any{
 		"container": container.Name(),
 		"image":     container.ImageName(),
@@ -847,6 +936,8 @@ func (c *client) StartContainer(ctx context.Context, container types.Container)
 		isPodman,
 	)
 	if err != nil {
+		tracing.RecordError(span, err)
+
 		c.logger().Debug().
 			Err(err).
 			Fields(fields).
@@ -884,6 +975,9 @@ func (c *client) StartContainerByID(
 	ctx context.Context,
 	containerID types.ContainerID,
 ) error {
+	ctx, span := tracing.Start(ctx, "container.start", tracing.ContainerIDKey.String(string(containerID)))
+	defer span.End()
+
 	clogVal := c.logger().With().
 		Str("container_id", containerID.ShortID()).
 		Logger()
@@ -897,6 +991,8 @@ func (c *client) StartContainerByID(
 		dockerClient.ContainerStartOptions{},
 	)
 	if err != nil {
+		tracing.RecordError(span, err)
+
 		clog.Debug().
 			Err(err).
 			Msg("Failed to start container by ID")
@@ -1168,8 +1264,63 @@ func (c *client) CheckContainerUpdate(
 	return available, newestImage, latestDigest, err
 }
 
+// LatestImageMetadata returns the OCI metadata of the newest image for a container.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control.
+//   - container: Container that was checked.
+//   - latestImage: Latest local image ID, or empty to read the registry.
+//
+// Returns:
+//   - types.ImageMetadata: Metadata of the newest image.
+//   - error: Non-nil if the image cannot be inspected or fetched.
+func (c *client) LatestImageMetadata(
+	ctx context.Context,
+	container types.Container,
+	latestImage types.ImageID,
+) (types.ImageMetadata, error) {
+	if latestImage != "" {
+		info, err := c.api.ImageInspect(ctx, string(latestImage))
+		if err != nil {
+			return types.ImageMetadata{}, fmt.Errorf(
+				"%w: %s: %w",
+				errInspectImageFailed,
+				latestImage,
+				err,
+			)
+		}
+
+		if info.Config == nil {
+			return types.ImageMetadata{}, nil
+		}
+
+		return types.ImageMetadataFromLabels(info.Config.Labels), nil
+	}
+
+	opts, err := registry.GetPullOptions(c.logger(), container.ImageName())
+	if err != nil {
+		return types.ImageMetadata{}, fmt.Errorf(
+			"%w: %s: %w",
+			errFailedToLoadPullOptions,
+			container.ImageName(),
+			err,
+		)
+	}
+
+	metadata, err := registry.FetchImageMetadata(c.logger(), ctx, container, opts.RegistryAuth)
+	if err != nil {
+		return types.ImageMetadata{}, fmt.Errorf("failed to fetch image metadata: %w", err)
+	}
+
+	return metadata, nil
+}
+
 // ExecuteCommand runs a command inside a container and evaluates its result.
 //
+// When the container sets the hook image label, the command runs in a sidecar
+// container from that image instead, sharing the container's network namespace.
+// A CommandResult attached with WithCommandResult receives the exit code and output.
+//
 // Parameters:
 //   - ctx: Context for cancellation and timeout control.
 //   - container: Container to execute command in.
@@ -1222,6 +1373,12 @@ func (c *client) ExecuteCommand(
 			Msg("Setting exec user")
 	}
 
+	// Run the command in a sidecar container when the target has a hook image.
+	hookImage, _ := container.GetLabel(hookImageLabel)
+	if hookImage = strings.TrimSpace(hookImage); hookImage != "" {
+		return c.runHookContainer(ctx, container, hookImage, command, timeout, user, metadataJSON)
+	}
+
 	// Set up exec configuration with command and metadata.
 	clog.Debug().
 		Str("command", command).
@@ -1377,6 +1534,57 @@ func (c *client) RemoveImageByID(
 	return nil
 }
 
+// ListImages returns the top-level images on the Docker host.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control.
+//
+// Returns:
+//   - []types.ImageSummary: Images with their tags, registry digests, and sizes.
+//   - error: Non-nil if listing fails, nil on success.
+func (c *client) ListImages(ctx context.Context) ([]types.ImageSummary, error) {
+	result, err := c.api.ImageList(ctx, dockerClient.ImageListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errListImagesFailed, err)
+	}
+
+	images := make([]types.ImageSummary, 0, len(result.Items))
+	for _, item := range result.Items {
+		images = append(images, types.ImageSummary{
+			ID:          types.ImageID(item.ID),
+			RepoTags:    item.RepoTags,
+			RepoDigests: item.RepoDigests,
+			Size:        item.Size,
+		})
+	}
+
+	c.logger().Debug().Int("count", len(images)).Msg("Listed images")
+
+	return images, nil
+}
+
+// HasImage reports whether an image is present on the Docker host.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control.
+//   - imageID: ID of the image.
+//
+// Returns:
+//   - bool: True if the image exists.
+//   - error: Non-nil if the image cannot be inspected for another reason.
+func (c *client) HasImage(ctx context.Context, imageID types.ImageID) (bool, error) {
+	_, err := c.api.ImageInspect(ctx, string(imageID))
+	if cerrdefs.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, fmt.Errorf("%w: %s: %w", errInspectImageFailed, imageID.ShortID(), err)
+	}
+
+	return true, nil
+}
+
 // GetVersion returns the client's API version.
 //
 // Returns:
@@ -1455,6 +1663,30 @@ func (c *client) WaitForContainerHealthy(
 	ctx context.Context,
 	containerID types.ContainerID,
 	timeout time.Duration,
+) error {
+	ctx, span := tracing.Start(ctx, "container.wait_healthy", tracing.ContainerIDKey.String(string(containerID)))
+	defer span.End()
+
+	err := c.waitForHealthy(ctx, containerID, timeout)
+	tracing.RecordError(span, err)
+
+	return err
+}
+
+// waitForHealthy polls the container health status until it is healthy,
+// unhealthy, or the timeout elapses.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control.
+//   - containerID: ID of the container to wait for.
+//   - timeout: Maximum duration to wait; zero or negative waits indefinitely.
+//
+// Returns:
+//   - error: Non-nil on timeout, cancellation, unhealthy status, or inspection failure.
+func (c *client) waitForHealthy(
+	ctx context.Context,
+	containerID types.ContainerID,
+	timeout time.Duration,
 ) error {
 	// Guard against zero/negative timeouts by using a non-deadline context.
 	// This allows the function to poll at least once rather than immediately timing out.
@@ -1842,8 +2074,6 @@ func (c *client) waitForExecOrTimeout(
 	execOutput string,
 	timeout int,
 ) (bool, error) {
-	const ExTempFail = 75
-
 	clogVal := c.logger().With().
 		Str("exec_id", execID).
 		Logger()
@@ -1896,26 +2126,43 @@ func (c *client) waitForExecOrTimeout(
 				Msg("Command output captured")
 		}
 
-		// Handle specific exit codes.
-		if execInspect.ExitCode == ExTempFail {
-			return true, nil // Skip updates on temporary failure.
-		}
+		recordCommandResult(ctx, execInspect.ExitCode, execOutput)
 
-		if execInspect.ExitCode > 0 {
-			err := fmt.Errorf(
-				"%w with exit code %d: %s",
-				errCommandFailed,
-				execInspect.ExitCode,
-				execOutput,
-			)
-			clog.Debug().
-				Err(err).
-				Msg("Command execution failed")
+		return evaluateExitCode(clog, execInspect.ExitCode, execOutput)
+	}
+}
 
-			return false, err
-		}
+// evaluateExitCode maps a lifecycle command's exit code to the hook result.
+//
+// Exit code 75 (EX_TEMPFAIL) skips the update, any other non-zero code fails.
+//
+// Parameters:
+//   - clog: Logger for failures.
+//   - exitCode: Exit code of the command.
+//   - output: Captured command output included in failures.
+//
+// Returns:
+//   - bool: True if updates should be skipped.
+//   - error: Non-nil if the command failed.
+func evaluateExitCode(clog *zerolog.Logger, exitCode int, output string) (bool, error) {
+	const ExTempFail = 75
+
+	if exitCode == ExTempFail {
+		return true, nil // Skip updates on temporary failure.
+	}
+
+	if exitCode > 0 {
+		err := fmt.Errorf(
+			"%w with exit code %d: %s",
+			errCommandFailed,
+			exitCode,
+			output,
+		)
+		clog.Debug().
+			Err(err).
+			Msg("Command execution failed")
 
-		break
+		return false, err
 	}
 
 	return false, nil
@@ -0,0 +1,46 @@
+package container
+
+import "context"
+
+// CommandResult receives the exit code and output of a lifecycle command.
+//
+// ExecuteCommand only reports whether the update should be skipped; callers
+// that need the raw result attach a CommandResult with WithCommandResult.
+type CommandResult struct {
+	// ExitCode is the command's exit code. It is left unchanged when the
+	// command did not finish.
+	ExitCode int
+	// Output is the captured stdout/stderr, capped at 1 MiB.
+	Output string
+}
+
+// commandResultKey is the context key for a *CommandResult.
+type commandResultKey struct{}
+
+// WithCommandResult returns a context that makes ExecuteCommand fill result.
+//
+// Parameters:
+//   - ctx: Parent context.
+//   - result: Result to fill once the command finishes.
+//
+// Returns:
+//   - context.Context: Context carrying result.
+func WithCommandResult(ctx context.Context, result *CommandResult) context.Context {
+	return context.WithValue(ctx, commandResultKey{}, result)
+}
+
+// recordCommandResult stores a finished command's result in ctx, if requested.
+//
+// Parameters:
+//   - ctx: Context passed to ExecuteCommand.
+//   - exitCode: Exit code of the command.
+//   - output: Captured command output.
+func recordCommandResult(ctx context.Context, exitCode int, output string) {
+	result, ok := ctx.Value(commandResultKey{}).(*CommandResult)
+	if !ok || result == nil {
+		return
+	}
+
+	result.ExitCode = exitCode
+	result.Output = output
+}
@@ -77,8 +77,10 @@ type Container struct {
 	OldImageID         types.ImageID                    // Stores the image ID before update for cleanup tracking
 	normalizedName     string                           // Cached normalized container name
 	imageName          string                           // Cached resolved image name with tag
+	createImage        string                           // Image ID to recreate from instead of imageName
 	containerInfo      *dockerContainer.InspectResponse // Docker container metadata
 	imageInfo          *dockerImage.InspectResponse     // Docker image metadata
+	latestImageInfo    *dockerImage.InspectResponse     // Newest image found by the staleness check
 	// log is the process logger for operational Warn/Error/Debug on this instance.
 	// Set at construction (client list/get). Nil falls back to nopLog() so interface
 	// methods never panic. Production paths always set a real logger.
@@ -320,6 +322,47 @@ func (c *Container) SetImageName(name string) {
 	c.imageName = normalized
 }
 
+// SetCreateImage makes GetCreateConfig recreate the container from an image ID.
+//
+// The image name is kept in the original image label, which ImageName prefers,
+// so the recreated container keeps its image name without the name being
+// retagged to the image.
+//
+// Parameters:
+//   - imageID: Image to recreate the container from.
+func (c *Container) SetCreateImage(imageID types.ImageID) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.containerInfo != nil && c.containerInfo.Config != nil {
+		if c.containerInfo.Config.Labels == nil {
+			c.containerInfo.Config.Labels = make(map[string]string)
+		}
+
+		c.containerInfo.Config.Labels[zodiacLabel] = c.imageNameLocked()
+	}
+
+	c.createImage = string(imageID)
+}
+
+// createImageLocked returns the image reference a recreated container is created from.
+//
+// Callers must hold mu.
+//
+// Returns:
+//   - string: Image ID set by SetCreateImage, or the image name.
+func (c *Container) createImageLocked() string {
+	if c.createImage != "" {
+		return c.createImage
+	}
+
+	return c.imageNameLocked()
+}
+
 // HasImageInfo indicates whether image metadata is available.
 //
 // Returns:
@@ -336,6 +379,28 @@ func (c *Container) ImageInfo() *dockerImage.InspectResponse {
 	return c.imageInfo
 }
 
+// SetLatestImageInfo records the newest image found by the staleness check.
+//
+// Parameters:
+//   - info: Inspect data of the newest image.
+func (c *Container) SetLatestImageInfo(info *dockerImage.InspectResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.latestImageInfo = info
+}
+
+// LatestImageInfo returns the newest image found by the staleness check.
+//
+// Returns:
+//   - *dockerImage.InspectResponse: Image metadata, or nil if no newer image was found.
+func (c *Container) LatestImageInfo() *dockerImage.InspectResponse {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.latestImageInfo
+}
+
 // GetCreateConfig generates a container configuration for recreation.
 //
 // It isolates runtime overrides from image defaults and sets the image name.
@@ -354,17 +419,17 @@ func (c *Container) GetCreateConfig() *dockerContainer.Config {
 	if c.containerInfo == nil {
 		clog.Warn().Msg("No container info available, returning minimal config")
 
-		return &dockerContainer.Config{Image: c.imageNameLocked()}
+		return &dockerContainer.Config{Image: c.createImageLocked()}
 	}
 
 	config := *c.containerInfo.Config
 	hostConfig := c.containerInfo.HostConfig
 
 	// Handle missing image info case.
-	if c.imageInfo == nil {
+	if c.imageInfo == nil || c.imageInfo.Config == nil {
 		clog.Warn().Msg("No image info available, using container config as-is")
 
-		config.Image = c.imageNameLocked()
+		config.Image = c.createImageLocked()
 
 		return &config
 	}
@@ -450,7 +515,7 @@ func (c *Container) GetCreateConfig() *dockerContainer.Config {
 		config.ExposedPorts[p] = struct{}{} // Add ports from bindings.
 	}
 
-	config.Image = c.imageNameLocked()
+	config.Image = c.createImageLocked()
 	clog.Debug().
 		Str("image", config.Image).
 		Msg("Generated create config")
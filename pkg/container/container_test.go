@@ -239,6 +239,16 @@ var _ = ginkgo.Describe("Container", func() {
 			gomega.Expect(config.Image).To(gomega.Equal("unknown:latest"))
 			gomega.Expect(config).To(gomega.Equal(&dockerContainer.Config{Image: "unknown:latest"}))
 		})
+
+		ginkgo.It("creates from the image ID and keeps the image name in a label", func() {
+			c := MockContainer(WithImageName("web:1"))
+			c.SetCreateImage("sha256:previous")
+
+			config := c.GetCreateConfig()
+			gomega.Expect(config.Image).To(gomega.Equal("sha256:previous"))
+			gomega.Expect(config.Labels).To(gomega.HaveKeyWithValue(zodiacLabel, "web:1"))
+			gomega.Expect(c.ImageName()).To(gomega.Equal("web:1"))
+		})
 	})
 
 	ginkgo.Describe("Metadata Retrieval", func() {
@@ -84,6 +84,8 @@ var (
 	errReadPullResponseFailed = errors.New("failed to read pull response")
 	// errRemoveImageFailed indicates a failure to remove an image from the Docker host.
 	errRemoveImageFailed = errors.New("failed to remove image")
+	// errListImagesFailed indicates a failure to list images on the Docker host.
+	errListImagesFailed = errors.New("failed to list images")
 )
 
 // Errors for image cooldown operations in cooldown.go and image.go.
@@ -96,6 +98,8 @@ var (
 var (
 	// errLabelNotFound indicates a requested label is not present in the container's metadata.
 	errLabelNotFound = errors.New("label not found")
+	// errInvalidObservePeriod indicates the observe-period label is not a non-negative duration.
+	errInvalidObservePeriod = errors.New("invalid observe-period label")
 )
 
 // Errors for container ID detection operations in container_id.go.
@@ -123,3 +127,35 @@ var (
 	// ErrEphemeralStartFailed indicates a failure to start the ephemeral orchestrator container.
 	ErrEphemeralStartFailed = errors.New("failed to start ephemeral orchestrator container")
 )
+
+// Errors for lifecycle hook sidecar operations in hook_container.go.
+var (
+	// errPullHookImageFailed indicates a failure to find or pull the lifecycle hook image.
+	errPullHookImageFailed = errors.New("failed to pull hook image")
+	// errCreateHookFailed indicates a failure to create the lifecycle hook sidecar container.
+	errCreateHookFailed = errors.New("failed to create hook container")
+	// errStartHookFailed indicates a failure to start the lifecycle hook sidecar container.
+	errStartHookFailed = errors.New("failed to start hook container")
+	// errWaitHookFailed indicates a failure or timeout while waiting for the hook sidecar to exit.
+	errWaitHookFailed = errors.New("failed to wait for hook container")
+)
+
+// Errors for volume backups in volume_backup.go.
+var (
+	// errPullBackupImageFailed indicates a failure to find or pull the volume backup image.
+	errPullBackupImageFailed = errors.New("failed to pull volume backup image")
+	// errCreateBackupFailed indicates a failure to create the volume backup container.
+	errCreateBackupFailed = errors.New("failed to create volume backup container")
+	// errStartBackupFailed indicates a failure to start the volume backup container.
+	errStartBackupFailed = errors.New("failed to start volume backup container")
+	// errVolumeBackupFailed indicates the volume backup container exited non-zero.
+	errVolumeBackupFailed = errors.New("volume backup failed")
+)
+
+// Errors for post-update observation in observe.go.
+var (
+	// ErrContainerCrashed indicates a container exited or kept restarting while it was observed after an update.
+	ErrContainerCrashed = errors.New("container crashed after update")
+	// errObserveCanceled indicates the observation ended before its period because the context was canceled.
+	errObserveCanceled = errors.New("container observation canceled")
+)
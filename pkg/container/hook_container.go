@@ -0,0 +1,440 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	cerrdefs "github.com/containerd/errdefs"
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerClient "github.com/moby/moby/client"
+
+	"github.com/nicholas-fedor/watchtower/pkg/registry"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// hookCleanupTimeout bounds removal of a hook sidecar, which may need to kill a timed-out command.
+const hookCleanupTimeout = 30 * time.Second
+
+// runHookContainer runs a lifecycle command in a short-lived sidecar container.
+//
+// The sidecar is created from hookImage, joins the target's network namespace,
+// optionally mounts its volumes, and receives the same WT_CONTAINER metadata as
+// an exec hook. It is always removed afterwards; sidecars left behind by a
+// crash are removed at startup by RemoveOrphanedHookContainers.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - target: Container the hook runs for.
+//   - hookImage: Image to create the sidecar from.
+//   - command: Command run with sh -c.
+//   - timeout: Minutes to wait before timeout (0 for no timeout).
+//   - user: User to run the command as, empty for the image default.
+//   - metadataJSON: Container metadata passed as WT_CONTAINER.
+//
+// Returns:
+//   - bool: True if updates should be skipped, false otherwise.
+//   - error: Non-nil if the sidecar cannot run or the command fails.
+func (c *client) runHookContainer(
+	ctx context.Context,
+	target types.Container,
+	hookImage string,
+	command string,
+	timeout int,
+	user string,
+	metadataJSON string,
+) (bool, error) {
+	clogVal := c.logger().With().
+		Str("container_id", string(target.ID())).
+		Str("hook_image", hookImage).
+		Logger()
+	clog := &clogVal
+
+	err := c.ensureImage(ctx, hookImage)
+	if err != nil {
+		clog.Debug().
+			Err(err).
+			Msg("Failed to prepare hook image")
+
+		return false, fmt.Errorf("%w: %w", errPullHookImageFailed, err)
+	}
+
+	clog.Debug().
+		Str("command", command).
+		Msg("Creating hook container")
+
+	resp, err := c.api.ContainerCreate(ctx, dockerClient.ContainerCreateOptions{
+		Config:     buildHookConfig(hookImage, command, user, metadataJSON, helperScope(target)),
+		HostConfig: buildHookHostConfig(target),
+	})
+	if err != nil {
+		clog.Debug().
+			Err(err).
+			Msg("Failed to create hook container")
+
+		return false, fmt.Errorf("%w: %w", errCreateHookFailed, err)
+	}
+
+	clogVal = clog.With().
+		Str("hook_id", types.ContainerID(resp.ID).ShortID()).
+		Logger()
+
+	defer c.removeHookContainer(clog, resp.ID)
+
+	hookCtx := ctx
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+
+		hookCtx, cancel = context.WithTimeout(ctx, time.Duration(timeout)*time.Minute)
+		defer cancel()
+	}
+
+	_, err = c.api.ContainerStart(hookCtx, resp.ID, dockerClient.ContainerStartOptions{})
+	if err != nil {
+		clog.Debug().
+			Err(err).
+			Msg("Failed to start hook container")
+
+		return false, fmt.Errorf("%w: %w", errStartHookFailed, err)
+	}
+
+	exitCode, err := c.waitForHookContainer(hookCtx, resp.ID)
+	if err != nil {
+		clog.Debug().
+			Err(err).
+			Msg("Failed to wait for hook container")
+
+		return true, err
+	}
+
+	output, err := c.captureHookOutput(ctx, resp.ID)
+	if err != nil {
+		clog.Warn().
+			Err(err).
+			Msg("Failed to capture hook output")
+	}
+
+	recordCommandResult(ctx, exitCode, output)
+
+	skipUpdate, err := evaluateExitCode(clog, exitCode, output)
+
+	clog.Debug().
+		Str("command", command).
+		Str("output", output).
+		Int("exit_code", exitCode).
+		Bool("skip_update", skipUpdate).
+		Msg("Executed command in hook container")
+
+	return skipUpdate, err
+}
+
+// ensureImage pulls a helper image if it is not present locally.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - ref: Image reference.
+//
+// Returns:
+//   - error: Non-nil if the image cannot be inspected or pulled.
+func (c *client) ensureImage(ctx context.Context, ref string) error {
+	_, err := c.api.ImageInspect(ctx, ref)
+	if err == nil {
+		return nil
+	}
+
+	if !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("%w: %s: %w", errInspectImageFailed, ref, err)
+	}
+
+	opts, err := registry.GetPullOptions(c.logger(), ref)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", errFailedToLoadPullOptions, ref, err)
+	}
+
+	err = newImageClient(c.api, c.logger()).performImagePull(
+		ctx,
+		ref,
+		opts,
+		map[string]any{"image": ref},
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ref, err)
+	}
+
+	return nil
+}
+
+// waitForHookContainer waits for the hook sidecar to exit.
+//
+// Parameters:
+//   - ctx: Context carrying the hook timeout.
+//   - hookID: Sidecar container ID.
+//
+// Returns:
+//   - int: Exit code of the command.
+//   - error: Non-nil if waiting fails or times out.
+func (c *client) waitForHookContainer(ctx context.Context, hookID string) (int, error) {
+	wait := c.api.ContainerWait(ctx, hookID, dockerClient.ContainerWaitOptions{
+		Condition: dockerContainer.WaitConditionNotRunning,
+	})
+
+	select {
+	case result := <-wait.Result:
+		if result.Error != nil && result.Error.Message != "" {
+			return 0, fmt.Errorf("%w: %s", errWaitHookFailed, result.Error.Message)
+		}
+
+		return int(result.StatusCode), nil
+	case err := <-wait.Error:
+		return 0, fmt.Errorf("%w: %w", errWaitHookFailed, err)
+	case <-ctx.Done():
+		return 0, fmt.Errorf("%w: %w", errWaitHookFailed, ctx.Err())
+	}
+}
+
+// captureHookOutput reads the hook sidecar's output, capped at maxExecOutputSize.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - hookID: Sidecar container ID.
+//
+// Returns:
+//   - string: Trimmed output.
+//   - error: Non-nil if the logs cannot be read.
+func (c *client) captureHookOutput(ctx context.Context, hookID string) (string, error) {
+	logs, err := c.api.ContainerLogs(ctx, hookID, dockerClient.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errReadExecOutputFailed, err)
+	}
+	defer logs.Close()
+
+	// The sidecar runs with a TTY, so the log stream is not multiplexed.
+	var writer bytes.Buffer
+
+	_, err = io.Copy(&writer, io.LimitReader(logs, maxExecOutputSize))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errReadExecOutputFailed, err)
+	}
+
+	return strings.TrimSpace(writer.String()), nil
+}
+
+// removeHookContainer force-removes the hook sidecar.
+//
+// A fresh context is used so the sidecar is removed even after the hook
+// timed out or the caller's context was canceled.
+//
+// Parameters:
+//   - clog: Logger for failures.
+//   - hookID: Sidecar container ID.
+func (c *client) removeHookContainer(clog *zerolog.Logger, hookID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), hookCleanupTimeout)
+	defer cancel()
+
+	_, err := c.api.ContainerRemove(ctx, hookID, dockerClient.ContainerRemoveOptions{Force: true})
+	if err != nil && !cerrdefs.IsNotFound(err) {
+		clog.Warn().
+			Err(err).
+			Msg("Failed to remove hook container")
+	}
+}
+
+// buildHookConfig builds the container configuration for a hook sidecar.
+//
+// The entrypoint is replaced with sh -c so the hook image's own entrypoint
+// does not interfere. The sidecar carries the hook and scope labels for orphan
+// cleanup and is disabled so Watchtower never tries to update it.
+//
+// Parameters:
+//   - hookImage: Image to create the sidecar from.
+//   - command: Command run with sh -c.
+//   - user: User to run the command as, empty for the image default.
+//   - metadataJSON: Container metadata passed as WT_CONTAINER.
+//   - helperScope: Scope of the target container, empty for none.
+//
+// Returns:
+//   - *dockerContainer.Config: The container configuration.
+func buildHookConfig(hookImage, command, user, metadataJSON, helperScope string) *dockerContainer.Config {
+	return &dockerContainer.Config{
+		Image:      hookImage,
+		Entrypoint: []string{"sh", "-c"},
+		Cmd:        []string{command},
+		Env:        []string{"WT_CONTAINER=" + metadataJSON},
+		User:       user,
+		Tty:        true,
+		Labels:     helperLabels(helperScope),
+	}
+}
+
+// helperScope returns the scope a helper container inherits from its target.
+//
+// Watchtower only manages containers of its own scope, so the target's scope
+// is the scope of the instance running the helper.
+//
+// Parameters:
+//   - target: Container the helper runs for.
+//
+// Returns:
+//   - string: The target's scope, empty for none.
+func helperScope(target types.Container) string {
+	value, _ := target.Scope()
+
+	return value
+}
+
+// helperLabels returns the labels of a hook sidecar or volume backup helper.
+//
+// Parameters:
+//   - helperScope: Scope of the helper, empty for none.
+//
+// Returns:
+//   - map[string]string: The hook label, the scope label when scoped, and a disabled enable label.
+func helperLabels(helperScope string) map[string]string {
+	labels := map[string]string{
+		HookContainerLabel: "true",
+		enableLabel:        "false",
+	}
+
+	if helperScope != "" {
+		labels[scope] = helperScope
+	}
+
+	return labels
+}
+
+// buildHookHostConfig builds the host configuration for a hook sidecar.
+//
+// The sidecar shares the target's network namespace so the hook reaches the
+// service on localhost. Volumes are shared when hookVolumesLabel is true, or
+// read-only when it is "ro".
+//
+// Parameters:
+//   - target: Container the hook runs for.
+//
+// Returns:
+//   - *dockerContainer.HostConfig: The host configuration.
+func buildHookHostConfig(target types.Container) *dockerContainer.HostConfig {
+	hostConfig := &dockerContainer.HostConfig{
+		NetworkMode: dockerContainer.NetworkMode("container:" + string(target.ID())),
+	}
+
+	value, _ := target.GetLabel(hookVolumesLabel)
+	value = strings.TrimSpace(value)
+
+	if strings.EqualFold(value, "ro") {
+		hostConfig.VolumesFrom = []string{string(target.ID()) + ":ro"}
+	} else if share, err := strconv.ParseBool(value); err == nil && share {
+		hostConfig.VolumesFrom = []string{string(target.ID())}
+	}
+
+	return hostConfig
+}
+
+// RemoveOrphanedHookContainers removes lifecycle hook sidecars that persisted
+// due to crashes or unexpected termination.
+//
+// This is called during startup alongside RemoveOrphanedOrchestrators. Only
+// helpers of the current scope are removed, so instances with other scopes
+// keep the sidecars they are running.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control.
+//   - client: Container client for Docker operations.
+//   - currentScope: Scope of this Watchtower instance, empty for none.
+//
+// Returns:
+//   - int: Number of orphaned hook containers removed.
+//   - error: Non-nil if listing fails, nil on success.
+func RemoveOrphanedHookContainers(log *zerolog.Logger,
+	ctx context.Context,
+	client Client,
+	currentScope string,
+) (int, error) {
+	clogVal := log.With().
+		Str("function", "RemoveOrphanedHookContainers").
+		Str("scope", currentScope).
+		Logger()
+	clog := &clogVal
+
+	// Normalize empty scope to "none" for consistent comparison.
+	if currentScope == "" {
+		currentScope = "none"
+	}
+
+	clog.Debug().Msg("Checking for orphaned lifecycle hook containers")
+
+	allContainers, err := client.ListContainers(ctx)
+	if err != nil {
+		clog.Error().
+			Err(err).
+			Msg("Failed to list containers for hook container cleanup")
+
+		return 0, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	removed := 0
+
+	for _, c := range allContainers {
+		containerInfo := c.ContainerInfo()
+		if containerInfo == nil || containerInfo.Config == nil {
+			continue
+		}
+
+		if containerInfo.Config.Labels[HookContainerLabel] != "true" {
+			continue
+		}
+
+		// Scope check: only remove helpers left behind by an instance of the same scope.
+		containerScope := containerInfo.Config.Labels[scope]
+		if containerScope == "" {
+			containerScope = "none"
+		}
+
+		if containerScope != currentScope {
+			clog.Debug().
+				Str("container", c.Name()).
+				Str("container_scope", containerScope).
+				Msg("Skipping lifecycle hook container in different scope")
+
+			continue
+		}
+
+		clog.Info().
+			Str("container", c.Name()).
+			Str("id", c.ID().ShortID()).
+			Msg("Removing orphaned lifecycle hook container")
+
+		err := client.StopAndRemoveContainer(ctx, c, 0)
+		if err != nil {
+			clog.Warn().
+				Err(err).
+				Str("container", c.Name()).
+				Str("id", c.ID().ShortID()).
+				Msg("Failed to remove orphaned hook container")
+
+			continue
+		}
+
+		removed++
+	}
+
+	if removed > 0 {
+		clog.Info().
+			Int("count", removed).
+			Msg("Removed orphaned lifecycle hook containers")
+	} else {
+		clog.Debug().Msg("No orphaned lifecycle hook containers found")
+	}
+
+	return removed, nil
+}
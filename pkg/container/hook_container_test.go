@@ -0,0 +1,281 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerImage "github.com/moby/moby/api/types/image"
+	dockerClient "github.com/moby/moby/client"
+	mock "github.com/stretchr/testify/mock"
+
+	mockContainer "github.com/nicholas-fedor/watchtower/pkg/container/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+var _ = ginkgo.Describe("Lifecycle hook containers", func() {
+	ginkgo.Describe("buildHookConfig", func() {
+		ginkgo.It("should run the command with sh -c and pass container metadata", func() {
+			config := buildHookConfig("alpine:3", "curl localhost/drain", "1000:1000", `{"name":"app"}`, "")
+
+			gomega.Expect(config.Image).To(gomega.Equal("alpine:3"))
+			gomega.Expect(config.Entrypoint).To(gomega.BeEquivalentTo([]string{"sh", "-c"}))
+			gomega.Expect(config.Cmd).To(gomega.BeEquivalentTo([]string{"curl localhost/drain"}))
+			gomega.Expect(config.Env).To(gomega.ConsistOf(`WT_CONTAINER={"name":"app"}`))
+			gomega.Expect(config.User).To(gomega.Equal("1000:1000"))
+			gomega.Expect(config.Tty).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should label the sidecar for cleanup and exclude it from updates", func() {
+			config := buildHookConfig("alpine:3", "true", "", "{}", "")
+
+			gomega.Expect(config.Labels).To(gomega.HaveKeyWithValue(HookContainerLabel, "true"))
+			gomega.Expect(config.Labels).To(gomega.HaveKeyWithValue(enableLabel, "false"))
+			gomega.Expect(config.Labels).NotTo(gomega.HaveKey(scope))
+		})
+
+		ginkgo.It("should label the sidecar with the target's scope", func() {
+			config := buildHookConfig("alpine:3", "true", "", "{}", "prod")
+
+			gomega.Expect(config.Labels).To(gomega.HaveKeyWithValue(scope, "prod"))
+		})
+	})
+
+	ginkgo.Describe("buildHookHostConfig", func() {
+		ginkgo.It("should share the target's network namespace", func() {
+			hostConfig := buildHookHostConfig(MockContainer(WithID("target-id")))
+
+			gomega.Expect(hostConfig.NetworkMode).To(gomega.Equal(dockerContainer.NetworkMode("container:target-id")))
+			gomega.Expect(hostConfig.VolumesFrom).To(gomega.BeEmpty())
+			gomega.Expect(hostConfig.AutoRemove).To(gomega.BeFalse())
+		})
+
+		ginkgo.DescribeTable("should share volumes according to the label",
+			func(value string, expected []string) {
+				hostConfig := buildHookHostConfig(MockContainer(
+					WithID("target-id"),
+					WithLabels(map[string]string{hookVolumesLabel: value}),
+				))
+
+				if expected == nil {
+					gomega.Expect(hostConfig.VolumesFrom).To(gomega.BeEmpty())
+				} else {
+					gomega.Expect(hostConfig.VolumesFrom).To(gomega.Equal(expected))
+				}
+			},
+			ginkgo.Entry("true", "true", []string{"target-id"}),
+			ginkgo.Entry("read-only", "ro", []string{"target-id:ro"}),
+			ginkgo.Entry("false", "false", nil),
+			ginkgo.Entry("invalid", "maybe", nil),
+		)
+	})
+
+	ginkgo.Describe("ExecuteCommand with a hook image", func() {
+		var (
+			mockServer *ghttp.Server
+			testClient *client
+			target     *Container
+		)
+
+		const hookID = "hook-container-id"
+
+		ginkgo.BeforeEach(func() {
+			mockServer = ghttp.NewServer()
+			docker, err := dockerClient.New(
+				dockerClient.WithHost(mockServer.URL()),
+				dockerClient.WithHTTPClient(mockServer.HTTPTestServer.Client()),
+			)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			testClient = &client{log: testLog(), api: docker}
+			target = MockContainer(
+				WithID("target-id"),
+				WithLabels(map[string]string{
+					hookImageLabel:   "hooks:latest",
+					hookVolumesLabel: "true",
+				}),
+			)
+
+			mockServer.AppendHandlers(APIVersionPingHandler())
+		})
+
+		ginkgo.AfterEach(func() {
+			mockServer.Close()
+		})
+
+		// appendHookHandlers mocks a hook sidecar run that exits with exitCode and prints output.
+		appendHookHandlers := func(exitCode int, output string) {
+			mockServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.MatchRegexp(`^/v[0-9.]+/images/hooks:latest/json$`)),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, dockerImage.InspectResponse{ID: "sha256:hooks"}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", gomega.MatchRegexp(`^/v[0-9.]+/containers/create$`)),
+					func(_ http.ResponseWriter, r *http.Request) {
+						var body struct {
+							dockerContainer.Config
+
+							HostConfig dockerContainer.HostConfig
+						}
+						gomega.Expect(json.NewDecoder(r.Body).Decode(&body)).To(gomega.Succeed())
+						gomega.Expect(body.Image).To(gomega.Equal("hooks:latest"))
+						gomega.Expect(body.Cmd).To(gomega.BeEquivalentTo([]string{"drain"}))
+						gomega.Expect(body.Env[0]).To(gomega.HavePrefix(`WT_CONTAINER={"name":`))
+						gomega.Expect(body.HostConfig.NetworkMode).
+							To(gomega.Equal(dockerContainer.NetworkMode("container:target-id")))
+						gomega.Expect(body.HostConfig.VolumesFrom).To(gomega.ConsistOf("target-id"))
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, dockerContainer.CreateResponse{ID: hookID}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+hookID+`/start$`)),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+hookID+`/wait$`)),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, dockerContainer.WaitResponse{StatusCode: int64(exitCode)}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+hookID+`/logs$`)),
+					ghttp.RespondWith(http.StatusOK, output),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+hookID+`$`), "force=1"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+		}
+
+		ginkgo.It("should succeed and remove the sidecar when the command exits with 0", func() {
+			appendHookHandlers(0, "drained\n")
+
+			skip, err := testClient.ExecuteCommand(context.Background(), target, "drain", 1, -1, -1)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(skip).To(gomega.BeFalse())
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.HaveLen(7))
+		})
+
+		ginkgo.It("should skip the update when the command exits with 75", func() {
+			appendHookHandlers(75, "")
+
+			skip, err := testClient.ExecuteCommand(context.Background(), target, "drain", 1, -1, -1)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(skip).To(gomega.BeTrue())
+		})
+
+		ginkgo.It("should fail with the captured output when the command exits non-zero", func() {
+			appendHookHandlers(2, "connection refused\n")
+
+			skip, err := testClient.ExecuteCommand(context.Background(), target, "drain", 1, -1, -1)
+
+			gomega.Expect(err).To(gomega.MatchError(errCommandFailed))
+			gomega.Expect(err.Error()).To(gomega.ContainSubstring("exit code 2: connection refused"))
+			gomega.Expect(skip).To(gomega.BeFalse())
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.HaveLen(7))
+		})
+
+		ginkgo.It("should report the exit code and output to a command result", func() {
+			appendHookHandlers(2, "connection refused\n")
+
+			result := &CommandResult{ExitCode: -1}
+			_, err := testClient.ExecuteCommand(WithCommandResult(context.Background(), result), target, "drain", 1, -1, -1)
+
+			gomega.Expect(err).To(gomega.MatchError(errCommandFailed))
+			gomega.Expect(result.ExitCode).To(gomega.Equal(2))
+			gomega.Expect(result.Output).To(gomega.Equal("connection refused"))
+		})
+
+		ginkgo.It("should not create a sidecar when the hook image cannot be inspected", func() {
+			mockServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.MatchRegexp(`^/v[0-9.]+/images/hooks:latest/json$`)),
+					ghttp.RespondWith(http.StatusInternalServerError, "daemon error"),
+				),
+			)
+
+			_, err := testClient.ExecuteCommand(context.Background(), target, "drain", 1, -1, -1)
+
+			gomega.Expect(err).To(gomega.MatchError(errInspectImageFailed))
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.HaveLen(2))
+		})
+	})
+
+	ginkgo.Describe("RemoveOrphanedHookContainers", func() {
+		ginkgo.It("should remove only labeled hook containers", func() {
+			ctx := context.Background()
+			hook := MockContainer(
+				WithID("hook123"),
+				WithName("hook"),
+				WithLabels(map[string]string{HookContainerLabel: "true"}),
+			)
+			regular := MockContainer(WithID("regular123"), WithName("regular"))
+
+			mockAPIClient := mockContainer.NewMockClient(ginkgo.GinkgoT())
+			mockAPIClient.EXPECT().
+				ListContainers(ctx).
+				Return([]types.Container{regular, hook}, nil)
+			mockAPIClient.EXPECT().
+				StopAndRemoveContainer(ctx, hook, mock.AnythingOfType("time.Duration")).
+				Return(nil)
+
+			count, err := RemoveOrphanedHookContainers(testLog(), ctx, mockAPIClient, "")
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(count).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("should remove only hook containers of the current scope", func() {
+			ctx := context.Background()
+			scoped := MockContainer(
+				WithID("scoped123"),
+				WithName("scoped-hook"),
+				WithLabels(map[string]string{HookContainerLabel: "true", scope: "prod"}),
+			)
+			other := MockContainer(
+				WithID("other123"),
+				WithName("other-hook"),
+				WithLabels(map[string]string{HookContainerLabel: "true", scope: "staging"}),
+			)
+			unscoped := MockContainer(
+				WithID("unscoped123"),
+				WithName("unscoped-hook"),
+				WithLabels(map[string]string{HookContainerLabel: "true"}),
+			)
+
+			mockAPIClient := mockContainer.NewMockClient(ginkgo.GinkgoT())
+			mockAPIClient.EXPECT().
+				ListContainers(ctx).
+				Return([]types.Container{scoped, other, unscoped}, nil)
+			mockAPIClient.EXPECT().
+				StopAndRemoveContainer(ctx, scoped, mock.AnythingOfType("time.Duration")).
+				Return(nil)
+
+			count, err := RemoveOrphanedHookContainers(testLog(), ctx, mockAPIClient, "prod")
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(count).To(gomega.Equal(1))
+		})
+
+		ginkgo.It("should return an error when listing fails", func() {
+			ctx := context.Background()
+
+			mockAPIClient := mockContainer.NewMockClient(ginkgo.GinkgoT())
+			mockAPIClient.EXPECT().
+				ListContainers(ctx).
+				Return(nil, errListContainersFailed)
+
+			count, err := RemoveOrphanedHookContainers(testLog(), ctx, mockAPIClient, "")
+
+			gomega.Expect(err).To(gomega.MatchError(errListContainersFailed))
+			gomega.Expect(count).To(gomega.Equal(0))
+		})
+	})
+})
@@ -6,15 +6,19 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/distribution/reference"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 
 	cerrdefs "github.com/containerd/errdefs"
 	dockerContainer "github.com/moby/moby/api/types/container"
 	dockerImage "github.com/moby/moby/api/types/image"
 	dockerClient "github.com/moby/moby/client"
 
+	"github.com/nicholas-fedor/watchtower/internal/metrics"
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/registry"
 	"github.com/nicholas-fedor/watchtower/pkg/registry/auth"
 	"github.com/nicholas-fedor/watchtower/pkg/registry/digest"
@@ -306,10 +310,24 @@ func (c imageClient) HasNewImage(
 			nil
 	}
 
+	// A container rolled back from this image stays on its current one until unpinned.
+	if newImageID == RolledBackFrom(sourceContainer) {
+		clog.Debug().
+			Str("new_id", newImageID.ShortID()).
+			Msg("Skipping image the container was rolled back from")
+
+		return false, currentImageID, "", nil
+	}
+
 	clog.Info().
 		Str("new_id", newImageID.ShortID()).
 		Msg("Found new image")
 
+	// Keep the newest image's config so reports can show its OCI labels.
+	if container, ok := sourceContainer.(*Container); ok {
+		container.SetLatestImageInfo(&newImageInfo.InspectResponse)
+	}
+
 	return true,
 		newImageID,
 		ExtractImageDigest(
@@ -395,6 +413,9 @@ func (c imageClient) PullImage(
 	warnOnHeadFailed WarningStrategy,
 	params types.UpdateParams,
 ) error {
+	ctx, span := tracing.StartContainer(ctx, "container.pull_image", sourceContainer)
+	defer span.End()
+
 	fields := map[string]any{
 		"container": sourceContainer.Name(),
 		"image":     sourceContainer.ImageName(),
@@ -420,7 +441,10 @@ func (c imageClient) PullImage(
 			Err(err).
 			Msg("Failed to load authentication credentials")
 
-		return fmt.Errorf("%w: %s: %w", errPullImageFailed, sourceContainer.ImageName(), err)
+		err = fmt.Errorf("%w: %s: %w", errPullImageFailed, sourceContainer.ImageName(), err)
+		tracing.RecordError(span, err)
+
+		return err
 	}
 
 	// Log if authentication credentials are successfully loaded.
@@ -435,6 +459,8 @@ func (c imageClient) PullImage(
 	}
 
 	if skip {
+		span.SetAttributes(attribute.Bool("watchtower.pull.skipped", true))
+
 		return nil
 	}
 
@@ -445,7 +471,10 @@ func (c imageClient) PullImage(
 		return cooldownErr
 	}
 
-	return c.performImagePull(ctx, sourceContainer.ImageName(), opts, fields)
+	err = c.performImagePull(ctx, sourceContainer.ImageName(), opts, fields)
+	tracing.RecordError(span, err)
+
+	return err
 }
 
 // RemoveImageByID deletes an image from the Docker host.
@@ -718,6 +747,8 @@ func (c imageClient) performImagePull(
 			return fmt.Errorf("image pull cooldown wait: %w", cooldownErr)
 		}
 
+		pullStart := time.Now()
+
 		response, err := c.api.ImagePull(ctx, imageName, opts)
 		if err != nil {
 			info := ratelimit.FromErrorMessage(err.Error())
@@ -772,6 +803,7 @@ func (c imageClient) performImagePull(
 			return fmt.Errorf("%w: %s: %w", errReadPullResponseFailed, imageName, waitErr)
 		}
 
+		metrics.Default().ObserveImagePull(time.Since(pullStart))
 		clog.Debug().Msg("Image pull completed")
 
 		return nil
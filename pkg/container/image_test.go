@@ -18,10 +18,12 @@ import (
 	"github.com/spf13/viper"
 
 	cerrdefs "github.com/containerd/errdefs"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
 	dockerContainer "github.com/moby/moby/api/types/container"
 	dockerImage "github.com/moby/moby/api/types/image"
 	dockerClient "github.com/moby/moby/client"
 	gomegaTypes "github.com/onsi/gomega/types"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/nicholas-fedor/watchtower/internal/util"
 	mockContainer "github.com/nicholas-fedor/watchtower/pkg/container/mocks"
@@ -1003,6 +1005,47 @@ var _ = ginkgo.Describe("the client", func() {
 				}
 			})
 		})
+
+		ginkgo.When("the container was rolled back from the latest image", func() {
+			ginkgo.It("should not report the container as stale", func() {
+				currentImageID := "sha256:" + util.GenerateRandomSHA256()
+				badImageID := "sha256:" + util.GenerateRandomSHA256()
+				container := MockContainer(
+					WithImageName("registry.example.com/app:latest"),
+					WithRepoDigests([]string{}),
+					WithLabels(map[string]string{RolledBackFromLabel: badImageID}),
+					func(container *dockerContainer.InspectResponse, image *dockerImage.InspectResponse) {
+						container.Image = currentImageID
+						image.ID = currentImageID
+					},
+				)
+
+				mockServer.AllowUnhandledRequests = true
+				mockServer.AppendHandlers(
+					ghttp.CombineHandlers(
+						ghttp.VerifyRequest(
+							"GET",
+							gomega.HaveSuffix("/images/registry.example.com/app:latest/json"),
+						),
+						ghttp.RespondWithJSONEncoded(http.StatusOK, dockerImage.InspectResponse{
+							ID:          badImageID,
+							RepoDigests: []string{},
+						}),
+					),
+				)
+
+				c := &client{log: testLog(), api: mockClient}
+
+				stale, latestID, _, err := c.IsContainerStale(
+					context.Background(),
+					container,
+					types.UpdateParams{},
+				)
+				gomega.Expect(err).To(gomega.Succeed())
+				gomega.Expect(stale).To(gomega.BeFalse())
+				gomega.Expect(string(latestID)).To(gomega.Equal(currentImageID))
+			})
+		})
 	})
 })
 
@@ -1152,6 +1195,57 @@ var _ = ginkgo.Describe("CheckContainerUpdate", func() {
 		})
 	})
 
+	ginkgo.When("no-pull is enabled and a newer local image exists", func() {
+		ginkgo.It("records the newer image and reads its metadata locally", func() {
+			currentImageID := "sha256:" + util.GenerateRandomSHA256()
+			newImageID := "sha256:" + util.GenerateRandomSHA256()
+			container := MockContainer(
+				WithImageName("test-image:latest"),
+				func(container *dockerContainer.InspectResponse, image *dockerImage.InspectResponse) {
+					container.Image = currentImageID
+					image.ID = currentImageID
+				},
+			)
+			newImage := dockerImage.InspectResponse{
+				ID: newImageID,
+				Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{
+					Labels: map[string]string{
+						types.ImageVersionLabel:  "1.5.0",
+						types.ImageRevisionLabel: "def5678",
+					},
+				}},
+			}
+
+			mockServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.HaveSuffix("/images/test-image:latest/json")),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, newImage),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.HaveSuffix("/images/"+newImageID+"/json")),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, newImage),
+				),
+			)
+
+			c := &client{log: testLog(), api: mockClient}
+
+			available, latestID, _, err := c.CheckContainerUpdate(
+				context.Background(),
+				container,
+				types.UpdateParams{NoPull: true},
+			)
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(available).To(gomega.BeTrue())
+			gomega.Expect(latestID).To(gomega.Equal(types.ImageID(newImageID)))
+			gomega.Expect(container.LatestImageInfo()).NotTo(gomega.BeNil())
+			gomega.Expect(container.LatestImageInfo().ID).To(gomega.Equal(newImageID))
+
+			metadata, err := c.LatestImageMetadata(context.Background(), container, latestID)
+			gomega.Expect(err).To(gomega.Succeed())
+			gomega.Expect(metadata).To(gomega.Equal(types.ImageMetadata{Version: "1.5.0", Revision: "def5678"}))
+		})
+	})
+
 	ginkgo.When("local image has empty RepoDigests", func() {
 		ginkgo.It("treats the image as up-to-date without pulling", func() {
 			currentImageID := "sha256:" + util.GenerateRandomSHA256()
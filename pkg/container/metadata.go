@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nicholas-fedor/watchtower/internal/util"
@@ -26,15 +27,27 @@ const (
 	dependsOnLabel = "com.centurylinklabs.watchtower.depends-on"
 	// ContainerChainLabel accumulates container IDs across Watchtower self-updates, comma-separated.
 	ContainerChainLabel = "com.centurylinklabs.watchtower.container-chain"
-	// zodiacLabel stores the original image name for Zodiac compatibility.
+	// zodiacLabel stores the original image name of a container created from an image ID,
+	// as set by Zodiac and by rollbacks.
 	zodiacLabel = "com.centurylinklabs.zodiac.original-image"
 	// scope defines a unique monitoring scope for this Watchtower instance.
 	scope = "com.centurylinklabs.watchtower.scope"
 	// OrchestratorLabel identifies ephemeral orchestrator containers used during self-update.
 	OrchestratorLabel = "com.centurylinklabs.watchtower.ephemeral-orchestrator"
+	// HookContainerLabel identifies short-lived helper containers: lifecycle hook sidecars and volume backups.
+	HookContainerLabel = "com.centurylinklabs.watchtower.lifecycle-hook"
 	// cooldownDelayLabel sets the minimum image age before updating this container.
 	// Accepts duration strings (e.g., "24h", "3d", "1w", "0" to disable).
 	cooldownDelayLabel = "com.centurylinklabs.watchtower.cooldown-delay"
+	// volumeBackupLabel archives the container's volumes before it is recreated from a new image (true/false).
+	volumeBackupLabel = "com.centurylinklabs.watchtower.backup-volumes"
+	// PreviousImageLabel records the image ID a container ran before its last update, for rollbacks.
+	PreviousImageLabel = "com.centurylinklabs.watchtower.previous-image"
+	// RolledBackFromLabel records the image ID a container was rolled back from; updates to it are skipped.
+	RolledBackFromLabel = "com.centurylinklabs.watchtower.rolled-back-from"
+	// observePeriodLabel sets how long the container is watched for crashes after it is updated.
+	// Accepts duration strings (e.g., "30s", "2m", "0" to disable).
+	observePeriodLabel = "com.centurylinklabs.watchtower.observe-period"
 )
 
 // Lifecycle hook labels configure commands executed during container update phases.
@@ -55,6 +68,10 @@ const (
 	lifecycleUIDLabel = "com.centurylinklabs.watchtower.lifecycle.uid"
 	// lifecycleGIDLabel specifies the GID to run lifecycle hooks as.
 	lifecycleGIDLabel = "com.centurylinklabs.watchtower.lifecycle.gid"
+	// hookImageLabel runs lifecycle hooks in a sidecar container from this image instead of exec.
+	hookImageLabel = "com.centurylinklabs.watchtower.lifecycle.hook-image"
+	// hookVolumesLabel mounts the container's volumes into the hook sidecar (true, false, or ro).
+	hookVolumesLabel = "com.centurylinklabs.watchtower.lifecycle.hook-volumes"
 )
 
 // GetLifecyclePreCheckCommand returns the pre-check command from labels.
@@ -464,6 +481,110 @@ func (c *Container) GetContainerChain() (string, bool) {
 	return c.getLabelValue(ContainerChainLabel)
 }
 
+// VolumeBackupEnabled reports whether a container's volumes are backed up before it is updated.
+//
+// Parameters:
+//   - container: Container to check.
+//
+// Returns:
+//   - bool: True if the backup-volumes label is set to true.
+func VolumeBackupEnabled(container types.Container) bool {
+	value, ok := container.GetLabel(volumeBackupLabel)
+	if !ok {
+		return false
+	}
+
+	enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+
+	return err == nil && enabled
+}
+
+// PreviousImage returns the image a container ran before its last update.
+//
+// Parameters:
+//   - container: Container to check.
+//
+// Returns:
+//   - types.ImageID: Previous image ID, or empty if the container was not updated by Watchtower.
+func PreviousImage(container types.Container) types.ImageID {
+	value, _ := container.GetLabel(PreviousImageLabel)
+
+	return types.ImageID(strings.TrimSpace(value))
+}
+
+// RolledBackFrom returns the image a container was rolled back from.
+//
+// Updates to this image are skipped until the container is unpinned.
+//
+// Parameters:
+//   - container: Container to check.
+//
+// Returns:
+//   - types.ImageID: Image ID the container is pinned against, or empty if it was not rolled back.
+func RolledBackFrom(container types.Container) types.ImageID {
+	value, _ := container.GetLabel(RolledBackFromLabel)
+
+	return types.ImageID(strings.TrimSpace(value))
+}
+
+// ObservePeriod returns how long a container is watched for crashes after it is updated.
+//
+// The observe-period label overrides the global period; an absent or empty
+// label uses params.ObservePeriod.
+//
+// Parameters:
+//   - container: Container to check.
+//   - params: Update parameters with the global period.
+//
+// Returns:
+//   - time.Duration: Observation period; zero disables the observation.
+//   - error: Non-nil if the label is not a non-negative duration, with the global period returned.
+func ObservePeriod(container types.Container, params types.UpdateParams) (time.Duration, error) {
+	value, ok := container.GetLabel(observePeriodLabel)
+	if !ok || strings.TrimSpace(value) == "" {
+		return params.ObservePeriod, nil
+	}
+
+	period, err := util.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return params.ObservePeriod, fmt.Errorf("%w: %w", errInvalidObservePeriod, err)
+	}
+
+	if period < 0 {
+		return params.ObservePeriod, fmt.Errorf("%w: %s", errInvalidObservePeriod, value)
+	}
+
+	return period, nil
+}
+
+// SetLabel sets or removes a label on the configuration a container is recreated from.
+//
+// Docker cannot change the labels of an existing container, so this only
+// affects the container created from it by a later update or rollback.
+//
+// Parameters:
+//   - container: Container about to be recreated.
+//   - label: Label key.
+//   - value: Label value; empty removes the label.
+func SetLabel(container types.Container, label, value string) {
+	info := container.ContainerInfo()
+	if info == nil || info.Config == nil {
+		return
+	}
+
+	if value == "" {
+		delete(info.Config.Labels, label)
+
+		return
+	}
+
+	if info.Config.Labels == nil {
+		info.Config.Labels = make(map[string]string)
+	}
+
+	info.Config.Labels[label] = value
+}
+
 // IsWatchtower identifies if this is the Watchtower container.
 //
 // Returns:
@@ -40,6 +40,78 @@ func (_m *MockClient) EXPECT() *MockClient_Expecter {
 	return &MockClient_Expecter{mock: &_m.Mock}
 }
 
+// BackupVolumes provides a mock function for the type MockClient
+func (_mock *MockClient) BackupVolumes(ctx context.Context, container types.Container, params types.UpdateParams) (string, error) {
+	ret := _mock.Called(ctx, container, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackupVolumes")
+	}
+
+	var r0 string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, types.Container, types.UpdateParams) (string, error)); ok {
+		return returnFunc(ctx, container, params)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, types.Container, types.UpdateParams) string); ok {
+		r0 = returnFunc(ctx, container, params)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, types.Container, types.UpdateParams) error); ok {
+		r1 = returnFunc(ctx, container, params)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_BackupVolumes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BackupVolumes'
+type MockClient_BackupVolumes_Call struct {
+	*mock.Call
+}
+
+// BackupVolumes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - container types.Container
+//   - params types.UpdateParams
+func (_e *MockClient_Expecter) BackupVolumes(ctx any, container any, params any) *MockClient_BackupVolumes_Call {
+	return &MockClient_BackupVolumes_Call{Call: _e.mock.On("BackupVolumes", ctx, container, params)}
+}
+
+func (_c *MockClient_BackupVolumes_Call) Run(run func(ctx context.Context, container types.Container, params types.UpdateParams)) *MockClient_BackupVolumes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 types.Container
+		if args[1] != nil {
+			arg1 = args[1].(types.Container)
+		}
+		var arg2 types.UpdateParams
+		if args[2] != nil {
+			arg2 = args[2].(types.UpdateParams)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_BackupVolumes_Call) Return(s string, err error) *MockClient_BackupVolumes_Call {
+	_c.Call.Return(s, err)
+	return _c
+}
+
+func (_c *MockClient_BackupVolumes_Call) RunAndReturn(run func(ctx context.Context, container types.Container, params types.UpdateParams) (string, error)) *MockClient_BackupVolumes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CheckContainerUpdate provides a mock function for the type MockClient
 func (_mock *MockClient) CheckContainerUpdate(ctx context.Context, container types.Container, params types.UpdateParams) (bool, types.ImageID, string, error) {
 	ret := _mock.Called(ctx, container, params)
@@ -606,6 +678,72 @@ func (_c *MockClient_GetVersion_Call) RunAndReturn(run func() string) *MockClien
 	return _c
 }
 
+// HasImage provides a mock function for the type MockClient
+func (_mock *MockClient) HasImage(ctx context.Context, imageID types.ImageID) (bool, error) {
+	ret := _mock.Called(ctx, imageID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasImage")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, types.ImageID) (bool, error)); ok {
+		return returnFunc(ctx, imageID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, types.ImageID) bool); ok {
+		r0 = returnFunc(ctx, imageID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, types.ImageID) error); ok {
+		r1 = returnFunc(ctx, imageID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_HasImage_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasImage'
+type MockClient_HasImage_Call struct {
+	*mock.Call
+}
+
+// HasImage is a helper method to define mock.On call
+//   - ctx context.Context
+//   - imageID types.ImageID
+func (_e *MockClient_Expecter) HasImage(ctx any, imageID any) *MockClient_HasImage_Call {
+	return &MockClient_HasImage_Call{Call: _e.mock.On("HasImage", ctx, imageID)}
+}
+
+func (_c *MockClient_HasImage_Call) Run(run func(ctx context.Context, imageID types.ImageID)) *MockClient_HasImage_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 types.ImageID
+		if args[1] != nil {
+			arg1 = args[1].(types.ImageID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_HasImage_Call) Return(b bool, err error) *MockClient_HasImage_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockClient_HasImage_Call) RunAndReturn(run func(ctx context.Context, imageID types.ImageID) (bool, error)) *MockClient_HasImage_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsContainerStale provides a mock function for the type MockClient
 func (_mock *MockClient) IsContainerStale(ctx context.Context, container types.Container, params types.UpdateParams) (bool, types.ImageID, string, error) {
 	ret := _mock.Called(ctx, container, params)
@@ -690,6 +828,78 @@ func (_c *MockClient_IsContainerStale_Call) RunAndReturn(run func(ctx context.Co
 	return _c
 }
 
+// LatestImageMetadata provides a mock function for the type MockClient
+func (_mock *MockClient) LatestImageMetadata(ctx context.Context, container types.Container, latestImage types.ImageID) (types.ImageMetadata, error) {
+	ret := _mock.Called(ctx, container, latestImage)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LatestImageMetadata")
+	}
+
+	var r0 types.ImageMetadata
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, types.Container, types.ImageID) (types.ImageMetadata, error)); ok {
+		return returnFunc(ctx, container, latestImage)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, types.Container, types.ImageID) types.ImageMetadata); ok {
+		r0 = returnFunc(ctx, container, latestImage)
+	} else {
+		r0 = ret.Get(0).(types.ImageMetadata)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, types.Container, types.ImageID) error); ok {
+		r1 = returnFunc(ctx, container, latestImage)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_LatestImageMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LatestImageMetadata'
+type MockClient_LatestImageMetadata_Call struct {
+	*mock.Call
+}
+
+// LatestImageMetadata is a helper method to define mock.On call
+//   - ctx context.Context
+//   - container types.Container
+//   - latestImage types.ImageID
+func (_e *MockClient_Expecter) LatestImageMetadata(ctx any, container any, latestImage any) *MockClient_LatestImageMetadata_Call {
+	return &MockClient_LatestImageMetadata_Call{Call: _e.mock.On("LatestImageMetadata", ctx, container, latestImage)}
+}
+
+func (_c *MockClient_LatestImageMetadata_Call) Run(run func(ctx context.Context, container types.Container, latestImage types.ImageID)) *MockClient_LatestImageMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 types.Container
+		if args[1] != nil {
+			arg1 = args[1].(types.Container)
+		}
+		var arg2 types.ImageID
+		if args[2] != nil {
+			arg2 = args[2].(types.ImageID)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_LatestImageMetadata_Call) Return(imageMetadata types.ImageMetadata, err error) *MockClient_LatestImageMetadata_Call {
+	_c.Call.Return(imageMetadata, err)
+	return _c
+}
+
+func (_c *MockClient_LatestImageMetadata_Call) RunAndReturn(run func(ctx context.Context, container types.Container, latestImage types.ImageID) (types.ImageMetadata, error)) *MockClient_LatestImageMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListContainers provides a mock function for the type MockClient
 func (_mock *MockClient) ListContainers(ctx context.Context, filter ...types.Filter) ([]types.Container, error) {
 	var tmpRet mock.Arguments
@@ -767,6 +977,137 @@ func (_c *MockClient_ListContainers_Call) RunAndReturn(run func(ctx context.Cont
 	return _c
 }
 
+// ListImages provides a mock function for the type MockClient
+func (_mock *MockClient) ListImages(ctx context.Context) ([]types.ImageSummary, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListImages")
+	}
+
+	var r0 []types.ImageSummary
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]types.ImageSummary, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []types.ImageSummary); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.ImageSummary)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockClient_ListImages_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListImages'
+type MockClient_ListImages_Call struct {
+	*mock.Call
+}
+
+// ListImages is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockClient_Expecter) ListImages(ctx any) *MockClient_ListImages_Call {
+	return &MockClient_ListImages_Call{Call: _e.mock.On("ListImages", ctx)}
+}
+
+func (_c *MockClient_ListImages_Call) Run(run func(ctx context.Context)) *MockClient_ListImages_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_ListImages_Call) Return(imageSummarys []types.ImageSummary, err error) *MockClient_ListImages_Call {
+	_c.Call.Return(imageSummarys, err)
+	return _c
+}
+
+func (_c *MockClient_ListImages_Call) RunAndReturn(run func(ctx context.Context) ([]types.ImageSummary, error)) *MockClient_ListImages_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ObserveContainer provides a mock function for the type MockClient
+func (_mock *MockClient) ObserveContainer(ctx context.Context, containerID types.ContainerID, period time.Duration, params types.UpdateParams) error {
+	ret := _mock.Called(ctx, containerID, period, params)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ObserveContainer")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, types.ContainerID, time.Duration, types.UpdateParams) error); ok {
+		r0 = returnFunc(ctx, containerID, period, params)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockClient_ObserveContainer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveContainer'
+type MockClient_ObserveContainer_Call struct {
+	*mock.Call
+}
+
+// ObserveContainer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - containerID types.ContainerID
+//   - period time.Duration
+//   - params types.UpdateParams
+func (_e *MockClient_Expecter) ObserveContainer(ctx any, containerID any, period any, params any) *MockClient_ObserveContainer_Call {
+	return &MockClient_ObserveContainer_Call{Call: _e.mock.On("ObserveContainer", ctx, containerID, period, params)}
+}
+
+func (_c *MockClient_ObserveContainer_Call) Run(run func(ctx context.Context, containerID types.ContainerID, period time.Duration, params types.UpdateParams)) *MockClient_ObserveContainer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 types.ContainerID
+		if args[1] != nil {
+			arg1 = args[1].(types.ContainerID)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 types.UpdateParams
+		if args[3] != nil {
+			arg3 = args[3].(types.UpdateParams)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockClient_ObserveContainer_Call) Return(err error) *MockClient_ObserveContainer_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockClient_ObserveContainer_Call) RunAndReturn(run func(ctx context.Context, containerID types.ContainerID, period time.Duration, params types.UpdateParams) error) *MockClient_ObserveContainer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Ping provides a mock function for the type MockClient
 func (_mock *MockClient) Ping(ctx context.Context) error {
 	ret := _mock.Called(ctx)
@@ -0,0 +1,226 @@
+package container
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/moby/moby/api/pkg/stdcopy"
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerClient "github.com/moby/moby/client"
+
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// observeInterval is how often an observed container is inspected.
+var observeInterval = time.Second
+
+// CrashError describes a container that crashed while it was observed after an update.
+type CrashError struct {
+	// ExitCode is the exit code of the container's last run.
+	ExitCode int
+	// Restarts is the number of times the container restarted since it was created.
+	Restarts int
+	// Exited is true if the container stopped, false if it restarted too often.
+	Exited bool
+	// Logs holds the last lines of the container's output, oldest first.
+	Logs []string
+}
+
+func (e *CrashError) Error() string {
+	if e.Exited {
+		return fmt.Sprintf("%s: exited with code %d", ErrContainerCrashed, e.ExitCode)
+	}
+
+	return fmt.Sprintf("%s: restarted %d times, last exit code %d", ErrContainerCrashed, e.Restarts, e.ExitCode)
+}
+
+func (e *CrashError) Unwrap() error { return ErrContainerCrashed }
+
+// ObserveContainer watches a started container for crashes and restart loops.
+//
+// The container is inspected every second for the given period. It fails the
+// observation once it exits with a non-zero code, or once it restarted more
+// than params.ObserveMaxRestarts times; the last params.ObserveLogLines lines
+// of its output are then attached to the returned *CrashError. A container
+// that exits with code 0, such as a one-shot job, completed and passes.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - containerID: ID of the container to observe.
+//   - period: How long to observe the container; zero or negative skips the observation.
+//   - params: Update parameters with the restart threshold and log line count.
+//
+// Returns:
+//   - error: *CrashError if the container crashed, non-nil if inspection fails or ctx is canceled, nil otherwise.
+func (c *client) ObserveContainer(
+	ctx context.Context,
+	containerID types.ContainerID,
+	period time.Duration,
+	params types.UpdateParams,
+) error {
+	if period <= 0 {
+		return nil
+	}
+
+	ctx, span := tracing.Start(ctx, "container.observe", tracing.ContainerIDKey.String(string(containerID)))
+	defer span.End()
+
+	err := c.observe(ctx, containerID, period, params)
+	tracing.RecordError(span, err)
+
+	return err
+}
+
+// observe polls the container state until the period elapses or the container crashes.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - containerID: ID of the container to observe.
+//   - period: How long to observe the container.
+//   - params: Update parameters with the restart threshold and log line count.
+//
+// Returns:
+//   - error: *CrashError if the container crashed, non-nil on inspection failure or cancellation.
+func (c *client) observe(
+	ctx context.Context,
+	containerID types.ContainerID,
+	period time.Duration,
+	params types.UpdateParams,
+) error {
+	clogVal := c.logger().With().
+		Str("container_id", containerID.ShortID()).
+		Dur("period", period).
+		Logger()
+	clog := &clogVal
+
+	deadline := time.NewTimer(period)
+	defer deadline.Stop()
+
+	ticker := time.NewTicker(observeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", errObserveCanceled, ctx.Err())
+		case <-deadline.C:
+			clog.Debug().Msg("Container stayed up during observation")
+
+			return nil
+		case <-ticker.C:
+			inspect, err := c.api.ContainerInspect(
+				ctx,
+				string(containerID),
+				dockerClient.ContainerInspectOptions{},
+			)
+			if err != nil {
+				return fmt.Errorf("%w: %w", errInspectContainerFailed, err)
+			}
+
+			state := inspect.Container.State
+			if state == nil {
+				continue
+			}
+
+			exited := state.Status == dockerContainer.StateExited || state.Status == dockerContainer.StateDead
+			restarts := inspect.Container.RestartCount
+
+			if restarts <= params.ObserveMaxRestarts {
+				if !exited {
+					continue
+				}
+
+				if state.ExitCode == 0 {
+					clog.Debug().Msg("Container exited cleanly during observation")
+
+					return nil
+				}
+			}
+
+			tty := inspect.Container.Config != nil && inspect.Container.Config.Tty
+
+			clog.Debug().
+				Str("status", string(state.Status)).
+				Int("exit_code", state.ExitCode).
+				Int("restarts", restarts).
+				Msg("Container crashed during observation")
+
+			return &CrashError{
+				ExitCode: state.ExitCode,
+				Restarts: restarts,
+				Exited:   exited,
+				Logs:     c.tailContainerLogs(ctx, clog, containerID, tty, params.ObserveLogLines),
+			}
+		}
+	}
+}
+
+// tailContainerLogs returns the last lines of a container's output.
+//
+// Failures to read the logs are logged and yield no lines, so they never hide the crash itself.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - clog: Logger for read failures.
+//   - containerID: ID of the container.
+//   - tty: Whether the container runs with a TTY, in which case its output is not multiplexed.
+//   - lines: Number of lines to return; zero or negative returns none.
+//
+// Returns:
+//   - []string: Output lines, oldest first.
+func (c *client) tailContainerLogs(
+	ctx context.Context,
+	clog *zerolog.Logger,
+	containerID types.ContainerID,
+	tty bool,
+	lines int,
+) []string {
+	if lines <= 0 {
+		return nil
+	}
+
+	logs, err := c.api.ContainerLogs(ctx, string(containerID), dockerClient.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Tail:       strconv.Itoa(lines),
+	})
+	if err != nil {
+		clog.Debug().Err(err).Msg("Failed to read logs of crashed container")
+
+		return nil
+	}
+	defer logs.Close()
+
+	var output bytes.Buffer
+
+	source := io.LimitReader(logs, maxExecOutputSize)
+	if tty {
+		_, err = io.Copy(&output, source)
+	} else {
+		_, err = stdcopy.StdCopy(&output, &output, source)
+	}
+
+	if err != nil {
+		clog.Debug().Err(err).Msg("Failed to read logs of crashed container")
+	}
+
+	text := strings.TrimRight(strings.ReplaceAll(output.String(), "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil
+	}
+
+	tail := strings.Split(text, "\n")
+	if len(tail) > lines {
+		tail = tail[len(tail)-lines:]
+	}
+
+	return tail
+}
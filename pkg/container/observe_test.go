@@ -0,0 +1,180 @@
+package container
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerClient "github.com/moby/moby/client"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// multiplexedLog frames log output the way Docker streams it for containers without a TTY.
+func multiplexedLog(stream byte, text string) []byte {
+	frame := make([]byte, 8, 8+len(text))
+	frame[0] = stream
+	binary.BigEndian.PutUint32(frame[4:], uint32(len(text)))
+
+	return append(frame, text...)
+}
+
+var _ = ginkgo.Describe("Container observation", func() {
+	ginkgo.DescribeTable("ObservePeriod",
+		func(labels map[string]string, expected time.Duration, fails bool) {
+			period, err := ObservePeriod(
+				MockContainer(WithLabels(labels)),
+				types.UpdateParams{ObservePeriod: time.Minute},
+			)
+
+			gomega.Expect(period).To(gomega.Equal(expected))
+
+			if fails {
+				gomega.Expect(err).To(gomega.MatchError(errInvalidObservePeriod))
+			} else {
+				gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			}
+		},
+		ginkgo.Entry("unset", map[string]string{}, time.Minute, false),
+		ginkgo.Entry("empty", map[string]string{observePeriodLabel: " "}, time.Minute, false),
+		ginkgo.Entry("override", map[string]string{observePeriodLabel: "30s"}, 30*time.Second, false),
+		ginkgo.Entry("disabled", map[string]string{observePeriodLabel: "0"}, time.Duration(0), false),
+		ginkgo.Entry("invalid", map[string]string{observePeriodLabel: "soon"}, time.Minute, true),
+	)
+
+	ginkgo.Describe("ObserveContainer", func() {
+		var (
+			mockServer *ghttp.Server
+			testClient *client
+			params     types.UpdateParams
+			interval   time.Duration
+		)
+
+		const observedID = "observed-container-id"
+
+		ginkgo.BeforeEach(func() {
+			mockServer = ghttp.NewServer()
+			docker, err := dockerClient.New(
+				dockerClient.WithHost(mockServer.URL()),
+				dockerClient.WithHTTPClient(mockServer.HTTPTestServer.Client()),
+			)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			testClient = &client{log: testLog(), api: docker}
+			params = types.UpdateParams{ObserveMaxRestarts: 1, ObserveLogLines: 2}
+
+			interval = observeInterval
+			observeInterval = 10 * time.Millisecond
+
+			mockServer.AppendHandlers(APIVersionPingHandler())
+		})
+
+		ginkgo.AfterEach(func() {
+			observeInterval = interval
+
+			mockServer.Close()
+		})
+
+		// inspectState mocks one inspection of the observed container.
+		inspectState := func(status dockerContainer.ContainerState, exitCode, restarts int) http.HandlerFunc {
+			return ghttp.CombineHandlers(
+				ghttp.VerifyRequest("GET", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+observedID+`/json$`)),
+				ghttp.RespondWithJSONEncoded(http.StatusOK, dockerContainer.InspectResponse{
+					ID:           observedID,
+					RestartCount: restarts,
+					State:        &dockerContainer.State{Status: status, ExitCode: exitCode},
+					Config:       &dockerContainer.Config{},
+				}),
+			)
+		}
+
+		ginkgo.It("should pass a container that stays up for the period", func() {
+			mockServer.RouteToHandler("GET", regexp.MustCompile(`^/v[0-9.]+/containers/`+observedID+`/json$`),
+				inspectState(dockerContainer.StateRunning, 0, 1))
+
+			err := testClient.ObserveContainer(context.Background(), observedID, 50*time.Millisecond, params)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should fail a container that exits and attach its last log lines", func() {
+			logs := append(multiplexedLog(1, "starting\nlistening on :8080\n"), multiplexedLog(2, "panic: config missing\n")...)
+
+			mockServer.AppendHandlers(
+				inspectState(dockerContainer.StateRunning, 0, 0),
+				inspectState(dockerContainer.StateExited, 2, 0),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+observedID+`/logs$`), "stderr=1&stdout=1&tail=2"),
+					ghttp.RespondWith(http.StatusOK, logs),
+				),
+			)
+
+			err := testClient.ObserveContainer(context.Background(), observedID, time.Minute, params)
+
+			crash, ok := errors.AsType[*CrashError](err)
+			gomega.Expect(ok).To(gomega.BeTrue())
+			gomega.Expect(err).To(gomega.MatchError(ErrContainerCrashed))
+			gomega.Expect(crash.Exited).To(gomega.BeTrue())
+			gomega.Expect(crash.ExitCode).To(gomega.Equal(2))
+			gomega.Expect(crash.Logs).To(gomega.Equal([]string{"listening on :8080", "panic: config missing"}))
+		})
+
+		ginkgo.It("should pass a container that exits with code 0", func() {
+			mockServer.AppendHandlers(
+				inspectState(dockerContainer.StateRunning, 0, 0),
+				inspectState(dockerContainer.StateExited, 0, 0),
+			)
+
+			err := testClient.ObserveContainer(context.Background(), observedID, time.Minute, params)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.HaveLen(3))
+		})
+
+		ginkgo.It("should fail a container that exits with code 0 after restarting more than the threshold", func() {
+			params.ObserveLogLines = 0
+
+			mockServer.AppendHandlers(inspectState(dockerContainer.StateExited, 0, 2))
+
+			err := testClient.ObserveContainer(context.Background(), observedID, time.Minute, params)
+
+			crash, ok := errors.AsType[*CrashError](err)
+			gomega.Expect(ok).To(gomega.BeTrue())
+			gomega.Expect(crash.Exited).To(gomega.BeTrue())
+			gomega.Expect(crash.ExitCode).To(gomega.BeZero())
+		})
+
+		ginkgo.It("should fail a container that restarts more than the threshold", func() {
+			params.ObserveLogLines = 0
+
+			mockServer.AppendHandlers(
+				inspectState(dockerContainer.StateRestarting, 1, 1),
+				inspectState(dockerContainer.StateRunning, 1, 2),
+			)
+
+			err := testClient.ObserveContainer(context.Background(), observedID, time.Minute, params)
+
+			crash, ok := errors.AsType[*CrashError](err)
+			gomega.Expect(ok).To(gomega.BeTrue())
+			gomega.Expect(crash.Exited).To(gomega.BeFalse())
+			gomega.Expect(crash.Restarts).To(gomega.Equal(2))
+			gomega.Expect(crash.Logs).To(gomega.BeEmpty())
+			gomega.Expect(err.Error()).To(gomega.ContainSubstring("restarted 2 times"))
+		})
+
+		ginkgo.It("should not inspect the container without a period", func() {
+			err := testClient.ObserveContainer(context.Background(), observedID, 0, params)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.BeEmpty())
+		})
+	})
+})
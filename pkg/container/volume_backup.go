@@ -0,0 +1,237 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerMount "github.com/moby/moby/api/types/mount"
+	dockerClient "github.com/moby/moby/client"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Mount points inside the volume backup helper.
+const (
+	// backupVolumesRoot is where the container's volumes are mounted read-only.
+	backupVolumesRoot = "/volumes"
+	// backupTargetRoot is where the backup directory or volume is mounted.
+	backupTargetRoot = "/backup"
+)
+
+// backupTimestampLayout names backup generations so they sort chronologically.
+const backupTimestampLayout = "20060102T150405Z"
+
+// BackupVolumes archives a stopped container's volumes with a helper container.
+//
+// Each volume is written as <volume>.tar.gz to <dir>/<container>/<timestamp>
+// in params.VolumeBackupDir, which is bind-mounted when it is an absolute path
+// and used as a named volume otherwise. Generations beyond
+// params.VolumeBackupKeep are deleted afterwards.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - container: Stopped container whose volumes are archived.
+//   - params: Update parameters with the backup directory, image, and retention.
+//
+// Returns:
+//   - string: Backup directory of this generation, or empty if the container has no volumes.
+//   - error: Non-nil if the helper cannot run or the archive fails.
+func (c *client) BackupVolumes(
+	ctx context.Context,
+	container types.Container,
+	params types.UpdateParams,
+) (string, error) {
+	clogVal := c.logger().With().
+		Str("container", container.Name()).
+		Str("backup_image", params.VolumeBackupImage).
+		Logger()
+	clog := &clogVal
+
+	volumes := containerVolumes(container)
+	if len(volumes) == 0 {
+		clog.Debug().Msg("No volumes to back up")
+
+		return "", nil
+	}
+
+	stamp := time.Now().UTC().Format(backupTimestampLayout)
+	backupPath := path.Join(params.VolumeBackupDir, container.Name(), stamp)
+
+	err := c.ensureImage(ctx, params.VolumeBackupImage)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errPullBackupImageFailed, err)
+	}
+
+	clog.Debug().
+		Strs("volumes", volumes).
+		Str("backup_path", backupPath).
+		Msg("Creating volume backup container")
+
+	resp, err := c.api.ContainerCreate(ctx, dockerClient.ContainerCreateOptions{
+		Config: buildBackupConfig(
+			params.VolumeBackupImage,
+			buildBackupScript(container.Name(), stamp, volumes, params.VolumeBackupKeep),
+			helperScope(container),
+		),
+		HostConfig: buildBackupHostConfig(params.VolumeBackupDir, volumes),
+	})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errCreateBackupFailed, err)
+	}
+
+	clogVal = clog.With().
+		Str("backup_id", types.ContainerID(resp.ID).ShortID()).
+		Logger()
+
+	defer c.removeHookContainer(clog, resp.ID)
+
+	_, err = c.api.ContainerStart(ctx, resp.ID, dockerClient.ContainerStartOptions{})
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", errStartBackupFailed, err)
+	}
+
+	exitCode, err := c.waitForHookContainer(ctx, resp.ID)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := c.captureHookOutput(ctx, resp.ID)
+	if err != nil {
+		clog.Warn().
+			Err(err).
+			Msg("Failed to capture volume backup output")
+	}
+
+	if exitCode != 0 {
+		return "", fmt.Errorf("%w: exit code %d: %s", errVolumeBackupFailed, exitCode, output)
+	}
+
+	clog.Info().
+		Int("volumes", len(volumes)).
+		Str("backup_path", backupPath).
+		Msg("Backed up container volumes")
+
+	return backupPath, nil
+}
+
+// containerVolumes returns the names of the volumes mounted in a container, sorted.
+//
+// Parameters:
+//   - container: Container to inspect.
+//
+// Returns:
+//   - []string: Volume names; bind mounts and tmpfs are excluded.
+func containerVolumes(container types.Container) []string {
+	info := container.ContainerInfo()
+	if info == nil {
+		return nil
+	}
+
+	var volumes []string
+
+	for _, mount := range info.Mounts {
+		if mount.Type == dockerMount.TypeVolume && mount.Name != "" {
+			volumes = append(volumes, mount.Name)
+		}
+	}
+
+	sort.Strings(volumes)
+
+	return volumes
+}
+
+// buildBackupScript builds the shell script run by the volume backup helper.
+//
+// Container and volume names are restricted by Docker to [a-zA-Z0-9_.-], so
+// they need no quoting.
+//
+// Parameters:
+//   - name: Container name, used as the backup subdirectory.
+//   - stamp: Generation timestamp.
+//   - volumes: Volume names to archive.
+//   - keep: Generations to keep, 0 for all.
+//
+// Returns:
+//   - string: Script run with sh -c.
+func buildBackupScript(name, stamp string, volumes []string, keep int) string {
+	base := backupTargetRoot + "/" + name
+	dest := base + "/" + stamp
+
+	lines := []string{"set -e", "mkdir -p " + dest}
+
+	for _, volume := range volumes {
+		lines = append(lines, fmt.Sprintf("tar -czf %s/%s.tar.gz -C %s/%s .",
+			dest, volume, backupVolumesRoot, volume))
+	}
+
+	if keep > 0 {
+		lines = append(lines, fmt.Sprintf("cd %s && ls -1 | sort -r | tail -n +%d | xargs -r rm -rf",
+			base, keep+1))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// buildBackupConfig builds the container configuration for a volume backup helper.
+//
+// The helper carries the hook and scope labels so the instance of its scope
+// removes it at startup if a crash left it behind, and is disabled so
+// Watchtower never tries to update it.
+//
+// Parameters:
+//   - image: Image to create the helper from.
+//   - script: Script run with sh -c.
+//   - helperScope: Scope of the backed up container, empty for none.
+//
+// Returns:
+//   - *dockerContainer.Config: The container configuration.
+func buildBackupConfig(image, script, helperScope string) *dockerContainer.Config {
+	return &dockerContainer.Config{
+		Image:      image,
+		Entrypoint: []string{"sh", "-c"},
+		Cmd:        []string{script},
+		Tty:        true,
+		Labels:     helperLabels(helperScope),
+	}
+}
+
+// buildBackupHostConfig builds the host configuration for a volume backup helper.
+//
+// Parameters:
+//   - backupDir: Absolute host path, bind-mounted, or volume name.
+//   - volumes: Volumes mounted read-only under backupVolumesRoot.
+//
+// Returns:
+//   - *dockerContainer.HostConfig: The host configuration.
+func buildBackupHostConfig(backupDir string, volumes []string) *dockerContainer.HostConfig {
+	target := dockerMount.Mount{
+		Type:   dockerMount.TypeVolume,
+		Source: backupDir,
+		Target: backupTargetRoot,
+	}
+	if filepath.IsAbs(backupDir) {
+		target.Type = dockerMount.TypeBind
+	}
+
+	mounts := []dockerMount.Mount{target}
+
+	for _, volume := range volumes {
+		mounts = append(mounts, dockerMount.Mount{
+			Type:     dockerMount.TypeVolume,
+			Source:   volume,
+			Target:   backupVolumesRoot + "/" + volume,
+			ReadOnly: true,
+		})
+	}
+
+	return &dockerContainer.HostConfig{
+		NetworkMode: "none",
+		Mounts:      mounts,
+	}
+}
@@ -0,0 +1,192 @@
+package container
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/ghttp"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerImage "github.com/moby/moby/api/types/image"
+	dockerMount "github.com/moby/moby/api/types/mount"
+	dockerClient "github.com/moby/moby/client"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// withMountPoints sets the mount points reported by container inspection.
+func withMountPoints(mounts ...dockerContainer.MountPoint) MockContainerUpdate {
+	return func(c *dockerContainer.InspectResponse, _ *dockerImage.InspectResponse) {
+		c.Mounts = mounts
+	}
+}
+
+var _ = ginkgo.Describe("Volume backups", func() {
+	dbVolumes := withMountPoints(
+		dockerContainer.MountPoint{Type: dockerMount.TypeVolume, Name: "pgdata", Destination: "/var/lib/postgresql/data"},
+		dockerContainer.MountPoint{Type: dockerMount.TypeBind, Source: "/etc/db.conf", Destination: "/etc/db.conf"},
+		dockerContainer.MountPoint{Type: dockerMount.TypeVolume, Name: "pgconf", Destination: "/etc/postgresql"},
+	)
+
+	ginkgo.DescribeTable("VolumeBackupEnabled",
+		func(labels map[string]string, expected bool) {
+			gomega.Expect(VolumeBackupEnabled(MockContainer(WithLabels(labels)))).To(gomega.Equal(expected))
+		},
+		ginkgo.Entry("true", map[string]string{volumeBackupLabel: "true"}, true),
+		ginkgo.Entry("false", map[string]string{volumeBackupLabel: "false"}, false),
+		ginkgo.Entry("invalid", map[string]string{volumeBackupLabel: "yes please"}, false),
+		ginkgo.Entry("unset", map[string]string{}, false),
+	)
+
+	ginkgo.Describe("containerVolumes", func() {
+		ginkgo.It("should return the sorted volume names and ignore bind mounts", func() {
+			gomega.Expect(containerVolumes(MockContainer(dbVolumes))).To(gomega.Equal([]string{"pgconf", "pgdata"}))
+		})
+	})
+
+	ginkgo.Describe("buildBackupScript", func() {
+		ginkgo.It("should archive every volume into the generation directory", func() {
+			script := buildBackupScript("db", "20240101T120000Z", []string{"pgconf", "pgdata"}, 0)
+
+			gomega.Expect(strings.Split(script, "\n")).To(gomega.Equal([]string{
+				"set -e",
+				"mkdir -p /backup/db/20240101T120000Z",
+				"tar -czf /backup/db/20240101T120000Z/pgconf.tar.gz -C /volumes/pgconf .",
+				"tar -czf /backup/db/20240101T120000Z/pgdata.tar.gz -C /volumes/pgdata .",
+			}))
+		})
+
+		ginkgo.It("should prune generations beyond the retention count", func() {
+			script := buildBackupScript("db", "20240101T120000Z", []string{"pgdata"}, 3)
+
+			gomega.Expect(script).To(gomega.HaveSuffix(
+				"cd /backup/db && ls -1 | sort -r | tail -n +4 | xargs -r rm -rf"))
+		})
+	})
+
+	ginkgo.Describe("buildBackupHostConfig", func() {
+		ginkgo.It("should bind-mount an absolute backup directory and mount volumes read-only", func() {
+			hostConfig := buildBackupHostConfig("/srv/backups", []string{"pgdata"})
+
+			gomega.Expect(hostConfig.NetworkMode).To(gomega.Equal(dockerContainer.NetworkMode("none")))
+			gomega.Expect(hostConfig.Mounts).To(gomega.Equal([]dockerMount.Mount{
+				{Type: dockerMount.TypeBind, Source: "/srv/backups", Target: "/backup"},
+				{Type: dockerMount.TypeVolume, Source: "pgdata", Target: "/volumes/pgdata", ReadOnly: true},
+			}))
+		})
+
+		ginkgo.It("should mount a relative backup directory as a named volume", func() {
+			hostConfig := buildBackupHostConfig("db-backups", nil)
+
+			gomega.Expect(hostConfig.Mounts).To(gomega.Equal([]dockerMount.Mount{
+				{Type: dockerMount.TypeVolume, Source: "db-backups", Target: "/backup"},
+			}))
+		})
+	})
+
+	ginkgo.Describe("BackupVolumes", func() {
+		var (
+			mockServer *ghttp.Server
+			testClient *client
+			params     types.UpdateParams
+		)
+
+		const backupID = "backup-container-id"
+
+		ginkgo.BeforeEach(func() {
+			mockServer = ghttp.NewServer()
+			docker, err := dockerClient.New(
+				dockerClient.WithHost(mockServer.URL()),
+				dockerClient.WithHTTPClient(mockServer.HTTPTestServer.Client()),
+			)
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			testClient = &client{log: testLog(), api: docker}
+			params = types.UpdateParams{
+				VolumeBackupDir:   "/srv/backups",
+				VolumeBackupImage: "busybox:stable",
+				VolumeBackupKeep:  3,
+			}
+
+			mockServer.AppendHandlers(APIVersionPingHandler())
+		})
+
+		ginkgo.AfterEach(func() {
+			mockServer.Close()
+		})
+
+		// appendBackupHandlers mocks a backup helper run that exits with exitCode and prints output.
+		appendBackupHandlers := func(exitCode int, output string) {
+			mockServer.AppendHandlers(
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.MatchRegexp(`^/v[0-9.]+/images/busybox:stable/json$`)),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, dockerImage.InspectResponse{ID: "sha256:busybox"}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", gomega.MatchRegexp(`^/v[0-9.]+/containers/create$`)),
+					func(_ http.ResponseWriter, r *http.Request) {
+						var body struct {
+							dockerContainer.Config
+
+							HostConfig dockerContainer.HostConfig
+						}
+						gomega.Expect(json.NewDecoder(r.Body).Decode(&body)).To(gomega.Succeed())
+						gomega.Expect(body.Image).To(gomega.Equal("busybox:stable"))
+						gomega.Expect(body.Labels).To(gomega.HaveKeyWithValue(HookContainerLabel, "true"))
+						gomega.Expect(body.HostConfig.Mounts).To(gomega.HaveLen(3))
+					},
+					ghttp.RespondWithJSONEncoded(http.StatusCreated, dockerContainer.CreateResponse{ID: backupID}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+backupID+`/start$`)),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("POST", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+backupID+`/wait$`)),
+					ghttp.RespondWithJSONEncoded(http.StatusOK, dockerContainer.WaitResponse{StatusCode: int64(exitCode)}),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("GET", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+backupID+`/logs$`)),
+					ghttp.RespondWith(http.StatusOK, output),
+				),
+				ghttp.CombineHandlers(
+					ghttp.VerifyRequest("DELETE", gomega.MatchRegexp(`^/v[0-9.]+/containers/`+backupID+`$`), "force=1"),
+					ghttp.RespondWith(http.StatusNoContent, nil),
+				),
+			)
+		}
+
+		ginkgo.It("should return the generation directory and remove the helper", func() {
+			appendBackupHandlers(0, "")
+
+			path, err := testClient.BackupVolumes(context.Background(), MockContainer(WithName("/db"), dbVolumes), params)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(path).To(gomega.MatchRegexp(`^/srv/backups/db/\d{8}T\d{6}Z$`))
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.HaveLen(7))
+		})
+
+		ginkgo.It("should fail with the captured output when the helper exits non-zero", func() {
+			appendBackupHandlers(1, "tar: write error: No space left on device\n")
+
+			path, err := testClient.BackupVolumes(context.Background(), MockContainer(WithName("/db"), dbVolumes), params)
+
+			gomega.Expect(err).To(gomega.MatchError(errVolumeBackupFailed))
+			gomega.Expect(err.Error()).To(gomega.ContainSubstring("No space left on device"))
+			gomega.Expect(path).To(gomega.BeEmpty())
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.HaveLen(7))
+		})
+
+		ginkgo.It("should do nothing for a container without volumes", func() {
+			path, err := testClient.BackupVolumes(context.Background(), MockContainer(WithName("/db")), params)
+
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+			gomega.Expect(path).To(gomega.BeEmpty())
+			gomega.Expect(mockServer.ReceivedRequests()).To(gomega.BeEmpty())
+		})
+	})
+})
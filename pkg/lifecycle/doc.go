@@ -3,13 +3,15 @@
 //
 // Key components:
 //   - Execute Functions: Handle lifecycle hook execution (e.g., ExecutePreUpdateCommand).
-//   - Client Integration: Uses container.Client for command execution.
+//   - Client Integration: Uses container.Client for command execution, via exec or a hook-image sidecar.
+//   - Webhooks: POST a WebhookPayload to the URL in a hook's "-url" label, for images without a shell.
+//   - Recorder: Collects each command's exit code, duration, and redacted output for reports.
 //
 // Usage example:
 //
 //	// log is the process *zerolog.Logger. ctx is the request/operation context.
 //	lifecycle.ExecutePreChecks(log, ctx, client, params, listed)
-//	success, err := lifecycle.ExecutePreUpdateCommand(log, ctx, client, container, uid, gid)
+//	success, err := lifecycle.ExecutePreUpdateCommand(log, ctx, client, container, params)
 //	if err != nil {
 //	    log.Error().Err(err).Msg("Pre-update failed")
 //	}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/container"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
@@ -39,7 +42,7 @@ func ExecutePreChecks(log *zerolog.Logger, ctx context.Context, client container
 	}
 
 	for _, currentContainer := range containers {
-		ExecutePreCheckCommand(log, ctx, client, currentContainer, params.LifecycleUID, params.LifecycleGID)
+		ExecutePreCheckCommand(log, ctx, client, currentContainer, params)
 	}
 }
 
@@ -62,7 +65,7 @@ func ExecutePostChecks(log *zerolog.Logger, ctx context.Context, client containe
 	}
 
 	for _, currentContainer := range containers {
-		ExecutePostCheckCommand(log, ctx, client, currentContainer, params.LifecycleUID, params.LifecycleGID)
+		ExecutePostCheckCommand(log, ctx, client, currentContainer, params)
 	}
 }
 
@@ -120,57 +123,52 @@ func resolveCheckContainers(
 	return containers, true
 }
 
-// ExecutePreCheckCommand executes the pre-check hook for a container.
+// ExecutePreCheckCommand executes the pre-check hooks for a container.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout.
 //   - client: Container client for execution.
 //   - container: Container to process.
-//   - uid: Default UID to run command as.
-//   - gid: Default GID to run command as.
-func ExecutePreCheckCommand(log *zerolog.Logger, ctx context.Context, client container.Client, container types.Container, uid, gid int) {
+//   - params: Update parameters with the default UID/GID and scan ID.
+func ExecutePreCheckCommand(log *zerolog.Logger, ctx context.Context, client container.Client, container types.Container, params types.UpdateParams) {
 	executeCheckCommand(
 		log,
 		ctx,
 		client,
 		container,
-		uid,
-		gid,
+		params,
 		container.GetLifecyclePreCheckCommand(),
 		"pre-check",
 	)
 }
 
-// ExecutePostCheckCommand executes the post-check hook for a container.
+// ExecutePostCheckCommand executes the post-check hooks for a container.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout.
 //   - client: Container client for execution.
 //   - container: Container to process.
-//   - uid: Default UID to run command as.
-//   - gid: Default GID to run command as.
-func ExecutePostCheckCommand(log *zerolog.Logger, ctx context.Context, client container.Client, container types.Container, uid, gid int) {
+//   - params: Update parameters with the default UID/GID and scan ID.
+func ExecutePostCheckCommand(log *zerolog.Logger, ctx context.Context, client container.Client, container types.Container, params types.UpdateParams) {
 	executeCheckCommand(
 		log,
 		ctx,
 		client,
 		container,
-		uid,
-		gid,
+		params,
 		container.GetLifecyclePostCheckCommand(),
 		"post-check",
 	)
 }
 
-// executeCheckCommand runs a pre-check or post-check lifecycle command.
+// executeCheckCommand runs a pre-check or post-check lifecycle command and webhook.
 //
 // Parameters:
 //   - log: Process logger.
 //   - ctx: Context for cancellation and timeout.
 //   - client: Container client for execution.
 //   - cont: Container to process.
-//   - uid: Default UID to run command as.
-//   - gid: Default GID to run command as.
+//   - params: Update parameters with the default UID/GID and scan ID.
 //   - command: Command string from the container labels.
 //   - phase: Label for logs ("pre-check" or "post-check").
 func executeCheckCommand(
@@ -178,7 +176,7 @@ func executeCheckCommand(
 	ctx context.Context,
 	client container.Client,
 	cont types.Container,
-	uid, gid int,
+	params types.UpdateParams,
 	command string,
 	phase string,
 ) {
@@ -188,28 +186,39 @@ func executeCheckCommand(
 	clog := &clogVal
 
 	// Determine effective UID/GID: use container labels if set, otherwise use defaults.
-	effectiveUID := uid
+	effectiveUID := params.LifecycleUID
 
 	containerUID, ok := cont.GetLifecycleUID()
 	if ok {
 		effectiveUID = containerUID
 	}
 
-	effectiveGID := gid
+	effectiveGID := params.LifecycleGID
 
 	containerGID, ok := cont.GetLifecycleGID()
 	if ok {
 		effectiveGID = containerGID
 	}
 
-	// Skip if no command is set.
-	if command == "" {
+	url := webhookURL(cont, phase)
+
+	// Skip if neither a command nor a webhook is set.
+	if command == "" && url == "" {
 		clog.Debug().
 			Msg("No " + phase + " command supplied. Skipping")
 
 		return
 	}
 
+	// Call the webhook after the command, if both are set.
+	if url != "" {
+		defer executeWebhook(clog, ctx, cont, url, newWebhookPayload(phase, params.ScanID, cont))
+	}
+
+	if command == "" {
+		return
+	}
+
 	// Execute command with fixed short timeout (1 minute).
 	// Check commands are lightweight health checks that should complete quickly,
 	// unlike update commands which may perform complex operations and use configurable timeouts.
@@ -217,7 +226,7 @@ func executeCheckCommand(
 		Str("command", command).
 		Msg("Executing " + phase + " command")
 
-	_, err := client.ExecuteCommand(ctx, cont, command, checkCommandTimeout, effectiveUID, effectiveGID)
+	_, err := executeTraced(ctx, client, cont, phase, command, checkCommandTimeout, effectiveUID, effectiveGID)
 	if err != nil {
 		// Match historical wording: "Pre-check command failed" / "Post-check command failed".
 		failedMsg := "Pre-check command failed"
@@ -231,40 +240,119 @@ func executeCheckCommand(
 	}
 }
 
-// ExecutePreUpdateCommand executes the pre-update hook for a container.
+// executeWebhook calls a webhook whose response cannot skip the update, logging failures.
+//
+// Parameters:
+//   - clog: Container logger.
+//   - ctx: Context for cancellation and timeout.
+//   - cont: Container to process.
+//   - url: Webhook URL from the container labels.
+//   - payload: Request body.
+func executeWebhook(clog *zerolog.Logger, ctx context.Context, cont types.Container, url string, payload WebhookPayload) {
+	clog.Debug().
+		Str("url", url).
+		Msg("Calling " + payload.Phase + " webhook")
+
+	_, err := callWebhook(clog, ctx, cont, url, payload)
+	if err != nil {
+		clog.Debug().
+			Err(err).
+			Msg("Failed to call " + payload.Phase + " webhook")
+	}
+}
+
+// ExecutePreUpdateCommand executes the pre-update hooks for a container.
+//
+// The command runs first, then the webhook; either can skip the update.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout.
 //   - client: Container client for execution.
 //   - container: Container to process.
-//   - uid: UID to run command as.
-//   - gid: GID to run command as.
+//   - params: Update parameters with the default UID/GID and scan ID.
 //
 // Returns:
-//   - bool: True if command ran, false if skipped.
+//   - bool: True if the update should be skipped (exit code 75 or a skip status).
 //   - error: Non-nil if execution fails, nil otherwise.
 func ExecutePreUpdateCommand(log *zerolog.Logger,
 	ctx context.Context,
 	client container.Client,
 	container types.Container,
-	uid int,
-	gid int,
+	params types.UpdateParams,
 ) (bool, error) {
 	timeout := container.PreUpdateTimeout()
 	command := container.GetLifecyclePreUpdateCommand()
+	url := webhookURL(container, "pre-update")
 	clogVal := log.With().
 		Str("container", container.Name()).
 		Int("timeout", timeout).
 		Logger()
 	clog := &clogVal
 
-	// Skip if no command or container isn't running.
-	if len(command) == 0 {
+	// Skip if neither a command nor a webhook is set.
+	if len(command) == 0 && url == "" {
 		clog.Debug().Msg("No pre-update command supplied. Skipping")
 
 		return false, nil
 	}
 
+	skip, err := executePreUpdateExec(clog, ctx, client, container, command, timeout, params)
+	if err != nil || skip || url == "" {
+		return skip, err
+	}
+
+	clog.Debug().
+		Str("url", url).
+		Msg("Calling pre-update webhook")
+
+	skip, err = callWebhook(clog, ctx, container, url, newWebhookPayload("pre-update", params.ScanID, container))
+	if err != nil {
+		clog.Debug().
+			Err(err).
+			Msg("Pre-update webhook failed")
+
+		return true, fmt.Errorf(
+			"%w for container %s: %w",
+			errPreUpdateFailed,
+			container.Name(),
+			err,
+		)
+	}
+
+	clog.Debug().
+		Bool("skip", skip).
+		Msg("Pre-update webhook called")
+
+	return skip, nil
+}
+
+// executePreUpdateExec runs the pre-update command inside a running container.
+//
+// Parameters:
+//   - clog: Container logger.
+//   - ctx: Context for cancellation and timeout.
+//   - client: Container client for execution.
+//   - container: Container to process.
+//   - command: Command from the container labels; empty skips.
+//   - timeout: Command timeout in minutes.
+//   - params: Update parameters with the default UID/GID.
+//
+// Returns:
+//   - bool: True if the update should be skipped (exit code 75).
+//   - error: Non-nil if execution fails, nil otherwise.
+func executePreUpdateExec(
+	clog *zerolog.Logger,
+	ctx context.Context,
+	client container.Client,
+	container types.Container,
+	command string,
+	timeout int,
+	params types.UpdateParams,
+) (bool, error) {
+	if len(command) == 0 {
+		return false, nil
+	}
+
 	if !container.IsRunning() || container.IsRestarting() {
 		clog.Debug().
 			Bool("is_running", container.IsRunning()).
@@ -275,14 +363,14 @@ func ExecutePreUpdateCommand(log *zerolog.Logger,
 	}
 
 	// Determine effective UID/GID: use container labels if set, otherwise use defaults.
-	effectiveUID := uid
+	effectiveUID := params.LifecycleUID
 
 	containerUID, ok := container.GetLifecycleUID()
 	if ok {
 		effectiveUID = containerUID
 	}
 
-	effectiveGID := gid
+	effectiveGID := params.LifecycleGID
 
 	containerGID, ok := container.GetLifecycleGID()
 	if ok {
@@ -294,7 +382,7 @@ func ExecutePreUpdateCommand(log *zerolog.Logger,
 		Str("command", command).
 		Msg("Executing pre-update command")
 
-	success, err := client.ExecuteCommand(ctx, container, command, timeout, effectiveUID, effectiveGID)
+	success, err := executeTraced(ctx, client, container, "pre-update", command, timeout, effectiveUID, effectiveGID)
 	if err != nil {
 		clog.Debug().
 			Err(err).
@@ -315,20 +403,20 @@ func ExecutePreUpdateCommand(log *zerolog.Logger,
 	return success, nil
 }
 
-// ExecutePostUpdateCommand executes the post-update hook for a container.
+// ExecutePostUpdateCommand executes the post-update hooks for a container.
 //
 // Parameters:
 //   - ctx: Context for cancellation and timeout.
 //   - client: Container client for execution.
+//   - sourceContainer: Container that was replaced, describing the old image.
 //   - newContainerID: ID of the updated container.
-//   - uid: UID to run command as.
-//   - gid: GID to run command as.
+//   - params: Update parameters with the default UID/GID and scan ID.
 func ExecutePostUpdateCommand(log *zerolog.Logger,
 	ctx context.Context,
 	client container.Client,
+	sourceContainer types.Container,
 	newContainerID types.ContainerID,
-	uid int,
-	gid int,
+	params types.UpdateParams,
 ) {
 	clogVal := log.With().
 		Str("container_id", newContainerID.ShortID()).
@@ -353,35 +441,45 @@ func ExecutePostUpdateCommand(log *zerolog.Logger,
 		Logger()
 	clog = &clogVal
 	command := newContainer.GetLifecyclePostUpdateCommand()
+	url := webhookURL(newContainer, "post-update")
 
 	// Determine effective UID/GID: use container labels if set, otherwise use defaults.
-	effectiveUID := uid
+	effectiveUID := params.LifecycleUID
 
 	containerUID, ok := newContainer.GetLifecycleUID()
 	if ok {
 		effectiveUID = containerUID
 	}
 
-	effectiveGID := gid
+	effectiveGID := params.LifecycleGID
 
 	containerGID, ok := newContainer.GetLifecycleGID()
 	if ok {
 		effectiveGID = containerGID
 	}
 
-	// Skip if no command is set.
-	if len(command) == 0 {
+	// Skip if neither a command nor a webhook is set.
+	if len(command) == 0 && url == "" {
 		clog.Debug().Msg("No post-update command supplied. Skipping")
 
 		return
 	}
 
+	// Call the webhook after the command, if both are set.
+	if url != "" {
+		defer executeWebhook(clog, ctx, newContainer, url, postUpdatePayload(params.ScanID, sourceContainer, newContainer))
+	}
+
+	if len(command) == 0 {
+		return
+	}
+
 	// Execute command with configured timeout.
 	clog.Debug().
 		Str("command", command).
 		Msg("Executing post-update command")
 
-	_, err = client.ExecuteCommand(ctx, newContainer, command, timeout, effectiveUID, effectiveGID)
+	_, err = executeTraced(ctx, client, newContainer, "post-update", command, timeout, effectiveUID, effectiveGID)
 	if err != nil {
 		clog.Debug().
 			Err(err).
@@ -389,3 +487,61 @@ func ExecutePostUpdateCommand(log *zerolog.Logger,
 			Msg("Post-update command failed")
 	}
 }
+
+// executeTraced runs a lifecycle command inside a span tagged with its phase.
+//
+// When ctx carries a Recorder, the run's exit code, duration, and output are recorded.
+//
+// Parameters:
+//   - ctx: Context for cancellation, timeout, and the parent span.
+//   - client: Container client for execution.
+//   - cont: Container to run the command in.
+//   - phase: Hook phase ("pre-check", "post-check", "pre-update", "post-update").
+//   - command: Command to execute.
+//   - timeout: Command timeout in minutes.
+//   - uid: UID to run command as.
+//   - gid: GID to run command as.
+//
+// Returns:
+//   - bool: The ExecuteCommand result.
+//   - error: Non-nil if execution fails.
+func executeTraced(
+	ctx context.Context,
+	client container.Client,
+	cont types.Container,
+	phase, command string,
+	timeout, uid, gid int,
+) (bool, error) {
+	ctx, span := tracing.StartContainer(ctx, "lifecycle.hook", cont,
+		attribute.String("watchtower.lifecycle.phase", phase))
+	defer span.End()
+
+	recorder := RecorderFrom(ctx)
+	if recorder == nil {
+		success, err := client.ExecuteCommand(ctx, cont, command, timeout, uid, gid)
+		tracing.RecordError(span, err)
+
+		return success, err //nolint:wrapcheck // Callers wrap with phase-specific context.
+	}
+
+	result := &container.CommandResult{ExitCode: -1}
+	start := time.Now()
+
+	success, err := client.ExecuteCommand(container.WithCommandResult(ctx, result), cont, command, timeout, uid, gid)
+	tracing.RecordError(span, err)
+
+	run := types.HookRun{
+		Phase:    phase,
+		Command:  command,
+		ExitCode: result.ExitCode,
+		Duration: time.Since(start),
+		Output:   result.Output,
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	recorder.record(cont, run)
+
+	return success, err //nolint:wrapcheck // Callers wrap with phase-specific context.
+}
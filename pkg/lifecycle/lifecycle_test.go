@@ -286,7 +286,7 @@ func TestExecutePreCheckCommand(t *testing.T) {
 				tt.setupClient(client)
 			}
 
-			ExecutePreCheckCommand(log, context.Background(), client, tt.container, 0, 0)
+			ExecutePreCheckCommand(log, context.Background(), client, tt.container, types.UpdateParams{})
 
 			output := logBuf.String()
 			assert.NotEmpty(t, output, "expected log output")
@@ -353,7 +353,7 @@ func TestExecutePostCheckCommand(t *testing.T) {
 				tt.setupClient(client)
 			}
 
-			ExecutePostCheckCommand(log, context.Background(), client, tt.container, 0, 0)
+			ExecutePostCheckCommand(log, context.Background(), client, tt.container, types.UpdateParams{})
 
 			output := logBuf.String()
 			assert.NotEmpty(t, output, "expected log output")
@@ -470,7 +470,7 @@ func runPreUpdateTest(t *testing.T, tt struct {
 		tt.setupClient(client)
 	}
 
-	result, err := ExecutePreUpdateCommand(log, context.Background(), client, tt.container, 0, 0)
+	result, err := ExecutePreUpdateCommand(log, context.Background(), client, tt.container, types.UpdateParams{})
 
 	assert.Equal(t, tt.expectedResult, result)
 
@@ -562,7 +562,7 @@ func TestExecutePostUpdateCommand(t *testing.T) {
 			log, logBuf := logging.NewTestLogger(logging.DebugLevel)
 			client := mockContainer.NewMockClient(t)
 			tt.setupClient(client)
-			ExecutePostUpdateCommand(log, context.Background(), client, tt.containerID, 0, 0)
+			ExecutePostUpdateCommand(log, context.Background(), client, mockedContainer(), tt.containerID, types.UpdateParams{})
 
 			output := logBuf.String()
 			assert.NotEmpty(t, output, "expected log output")
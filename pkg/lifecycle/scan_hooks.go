@@ -0,0 +1,299 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Scan hook phases, in the order they run during a scan.
+const (
+	// PreScanPhase runs after containers are listed, before anything is checked.
+	PreScanPhase = "pre-scan"
+	// PreRestartBatchPhase runs before the first container is stopped.
+	PreRestartBatchPhase = "pre-restart-batch"
+	// PostRestartBatchPhase runs after the last container is started.
+	PostRestartBatchPhase = "post-restart-batch"
+	// PostScanPhase runs when the scan has finished.
+	PostScanPhase = "post-scan"
+)
+
+// defaultScanHookTimeout bounds a scan hook when no timeout is configured.
+const defaultScanHookTimeout = 5 * time.Minute
+
+// maxScanHookOutput caps the command output included in scan hook errors.
+const maxScanHookOutput = 4096
+
+// maxScanHookCapture caps the command output kept from a scan hook (1 MiB, as for lifecycle commands).
+const maxScanHookCapture = 1 << 20
+
+// Errors for scan hook execution.
+var (
+	// errScanHookFailed indicates a scan hook that could not run, exited non-zero, or answered non-2xx.
+	errScanHookFailed = errors.New("scan hook failed")
+)
+
+// ScanHookPayload is the JSON document a scan hook receives on stdin or as the request body.
+type ScanHookPayload struct {
+	Phase      string              `json:"phase"`      // pre-scan, pre-restart-batch, post-restart-batch, or post-scan.
+	ScanID     string              `json:"scan_id"`    // Identifier of the scan.
+	Containers []ScanHookContainer `json:"containers"` // Containers the phase concerns.
+}
+
+// ScanHookContainer describes one container in a scan hook payload.
+type ScanHookContainer struct {
+	Name   string `json:"name"`            // Container name.
+	ID     string `json:"id"`              // Container ID.
+	Image  string `json:"image"`           // Image name with tag.
+	Update bool   `json:"update"`          // True if the container is recreated from a new image.
+	Error  string `json:"error,omitempty"` // Update error, in post-restart-batch and post-scan.
+}
+
+// NewScanHookContainers describes containers for a scan hook payload.
+//
+// Parameters:
+//   - containers: Containers the phase concerns.
+//
+// Returns:
+//   - []ScanHookContainer: One entry per container; stale containers are marked for update.
+func NewScanHookContainers(containers []types.Container) []ScanHookContainer {
+	described := make([]ScanHookContainer, 0, len(containers))
+
+	for _, cont := range containers {
+		described = append(described, ScanHookContainer{
+			Name:   cont.Name(),
+			ID:     string(cont.ID()),
+			Image:  cont.ImageName(),
+			Update: cont.IsStale(),
+		})
+	}
+
+	return described
+}
+
+// scanHook returns the configured hook for a phase.
+//
+// Parameters:
+//   - params: Update parameters with the scan hooks.
+//   - phase: Scan hook phase.
+//
+// Returns:
+//   - string: Command or URL, or empty if unset.
+func scanHook(params types.UpdateParams, phase string) string {
+	switch phase {
+	case PreScanPhase:
+		return params.PreScanHook
+	case PreRestartBatchPhase:
+		return params.PreRestartBatchHook
+	case PostRestartBatchPhase:
+		return params.PostRestartBatchHook
+	case PostScanPhase:
+		return params.PostScanHook
+	default:
+		return ""
+	}
+}
+
+// ExecuteScanHook runs the global hook configured for a scan phase.
+//
+// An http:// or https:// hook is called with a POST of the payload and must
+// answer 2xx. Any other hook is run as a command inside the Watchtower
+// container with the payload on stdin, through sh -c when a shell is
+// available, and must exit 0. When ctx carries a Recorder, the run is recorded
+// for every container in the payload.
+//
+// Parameters:
+//   - log: Process logger.
+//   - ctx: Context for cancellation and the parent span.
+//   - params: Update parameters with the scan hooks and timeout.
+//   - payload: Phase, scan ID, and containers passed to the hook.
+//
+// Returns:
+//   - error: Non-nil if the hook fails; nil when no hook is configured.
+func ExecuteScanHook(log *zerolog.Logger, ctx context.Context, params types.UpdateParams, payload ScanHookPayload) error {
+	hook := strings.TrimSpace(scanHook(params, payload.Phase))
+	if hook == "" {
+		return nil
+	}
+
+	clogVal := log.With().
+		Str("phase", payload.Phase).
+		Int("containers", len(payload.Containers)).
+		Logger()
+	clog := &clogVal
+
+	transport := "exec"
+	if isHookURL(hook) {
+		transport = "http"
+	}
+
+	ctx, span := tracing.Start(ctx, "lifecycle.scan_hook",
+		attribute.String("watchtower.lifecycle.phase", payload.Phase),
+		attribute.String("watchtower.lifecycle.transport", transport))
+	defer span.End()
+
+	timeout := params.ScanHookTimeout
+	if timeout <= 0 {
+		timeout = defaultScanHookTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", errScanHookFailed, payload.Phase, err)
+	}
+
+	clog.Debug().Msg("Running " + payload.Phase + " hook")
+
+	run := types.HookRun{Phase: payload.Phase, ExitCode: -1}
+	start := time.Now()
+
+	if transport == "http" {
+		var response webhookResponse
+
+		run.URL = hook
+		response, err = postScanHook(ctx, hook, body)
+		run.StatusCode, run.Output = response.status, response.body
+	} else {
+		run.Command = hook
+		run.ExitCode, run.Output, err = runScanHookCommand(ctx, hook, payload, body)
+	}
+
+	run.Duration = time.Since(start)
+
+	tracing.RecordError(span, err)
+
+	if recorder := RecorderFrom(ctx); recorder != nil {
+		if err != nil {
+			run.Error = err.Error()
+		}
+
+		recorder.recordScan(payload.Containers, run)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", errScanHookFailed, payload.Phase, err)
+	}
+
+	clog.Debug().Msg("Completed " + payload.Phase + " hook")
+
+	return nil
+}
+
+// isHookURL reports whether a scan hook is an HTTP endpoint rather than a command.
+//
+// Parameters:
+//   - hook: Configured hook.
+//
+// Returns:
+//   - bool: True for http:// and https:// hooks.
+func isHookURL(hook string) bool {
+	lower := strings.ToLower(hook)
+
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// postScanHook POSTs the payload to a scan hook endpoint.
+//
+// Parameters:
+//   - ctx: Context carrying the timeout.
+//   - url: Endpoint to call.
+//   - body: JSON payload.
+//
+// Returns:
+//   - webhookResponse: Response status and body; zero when the request failed.
+//   - error: Non-nil if the request fails or does not answer 2xx.
+func postScanHook(ctx context.Context, url string, body []byte) (webhookResponse, error) {
+	response, err := exchangeWebhook(ctx, url, body)
+	if err != nil {
+		return webhookResponse{}, err
+	}
+
+	if !response.ok() {
+		return response, response.statusError()
+	}
+
+	return response, nil
+}
+
+// runScanHookCommand runs a scan hook command with the payload on stdin.
+//
+// The phase and scan ID are also exported as WT_SCAN_PHASE and WT_SCAN_ID.
+// The official image has no shell, so without sh the command is split on
+// whitespace and run directly.
+//
+// Parameters:
+//   - ctx: Context carrying the timeout.
+//   - hook: Command to run.
+//   - payload: Payload whose phase and scan ID are exported.
+//   - body: JSON payload written to stdin.
+//
+// Returns:
+//   - int: Exit code, or -1 if the command did not finish.
+//   - string: Combined stdout and stderr.
+//   - error: Non-nil if the command cannot start or exits non-zero.
+func runScanHookCommand(ctx context.Context, hook string, payload ScanHookPayload, body []byte) (int, string, error) {
+	var cmd *exec.Cmd
+
+	if shell, err := exec.LookPath("sh"); err == nil {
+		cmd = exec.CommandContext(ctx, shell, "-c", hook)
+	} else {
+		// ExecuteScanHook skips blank hooks, so fields is never empty.
+		fields := strings.Fields(hook)
+		cmd = exec.CommandContext(ctx, fields[0], fields[1:]...)
+	}
+
+	output := &cappedBuffer{limit: maxScanHookCapture}
+
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	cmd.Env = append(os.Environ(), "WT_SCAN_PHASE="+payload.Phase, "WT_SCAN_ID="+payload.ScanID)
+	// Do not wait forever for children that keep the output pipes open.
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	exitCode := -1
+
+	if cmd.ProcessState != nil && cmd.ProcessState.Exited() {
+		exitCode = cmd.ProcessState.ExitCode()
+	}
+
+	if err != nil {
+		out, _ := truncateOutput(output.buf.String(), maxScanHookOutput)
+
+		return exitCode, output.buf.String(), fmt.Errorf("%w: %s", err, strings.TrimSpace(out))
+	}
+
+	return exitCode, output.buf.String(), nil
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest,
+// so a chatty hook cannot grow the buffer without bound.
+type cappedBuffer struct {
+	buf   bytes.Buffer
+	limit int
+}
+
+// Write stores what fits under the limit and reports p as fully written.
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,194 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/nicholas-fedor/watchtower/internal/logging"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// requireShell skips tests that run scan hook commands through sh.
+func requireShell(t *testing.T) {
+	t.Helper()
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+}
+
+func TestNewScanHookContainers(t *testing.T) {
+	stale := imageContainer("sha256:old", "sha256:olddigest", nil)
+	stale.SetStale(true)
+
+	described := NewScanHookContainers([]types.Container{stale})
+	require.Len(t, described, 1)
+	assert.Equal(t, ScanHookContainer{
+		Name:   "test-container",
+		ID:     "container_id",
+		Image:  "nginx:latest",
+		Update: true,
+	}, described[0])
+
+	assert.Empty(t, NewScanHookContainers(nil))
+}
+
+func TestExecuteScanHook_Unset(t *testing.T) {
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+
+	err := ExecuteScanHook(log, context.Background(), types.UpdateParams{PostScanHook: "exit 1"},
+		ScanHookPayload{Phase: PreScanPhase})
+	require.NoError(t, err)
+}
+
+func TestExecuteScanHook_Command(t *testing.T) {
+	requireShell(t)
+
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+	dir := t.TempDir()
+	payloadFile := filepath.Join(dir, "payload.json")
+	envFile := filepath.Join(dir, "env")
+
+	params := types.UpdateParams{
+		PreRestartBatchHook: "cat > " + payloadFile + ` && echo "$WT_SCAN_PHASE $WT_SCAN_ID" > ` + envFile,
+		ScanHookTimeout:     time.Minute,
+	}
+	payload := ScanHookPayload{
+		Phase:      PreRestartBatchPhase,
+		ScanID:     "scan-1",
+		Containers: []ScanHookContainer{{Name: "app", ID: "abc", Image: "app:latest", Update: true}},
+	}
+
+	require.NoError(t, ExecuteScanHook(log, context.Background(), params, payload))
+
+	raw, err := os.ReadFile(payloadFile)
+	require.NoError(t, err)
+
+	var received ScanHookPayload
+	require.NoError(t, json.Unmarshal(raw, &received))
+	assert.Equal(t, payload, received)
+
+	env, err := os.ReadFile(envFile)
+	require.NoError(t, err)
+	assert.Equal(t, "pre-restart-batch scan-1\n", string(env))
+}
+
+func TestExecuteScanHook_CommandFails(t *testing.T) {
+	requireShell(t)
+
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+	params := types.UpdateParams{PreScanHook: "echo backups running; exit 3"}
+
+	err := ExecuteScanHook(log, context.Background(), params, ScanHookPayload{Phase: PreScanPhase})
+	require.ErrorIs(t, err, errScanHookFailed)
+	assert.Contains(t, err.Error(), "pre-scan")
+	assert.Contains(t, err.Error(), "backups running")
+}
+
+func TestExecuteScanHook_RecordsRun(t *testing.T) {
+	requireShell(t)
+
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+	recorder := NewRecorder(types.UpdateParams{LifecycleOutputLimit: 64})
+	ctx := WithRecorder(context.Background(), recorder)
+	params := types.UpdateParams{PreRestartBatchHook: "echo password=hunter2; exit 3"}
+	payload := ScanHookPayload{
+		Phase:      PreRestartBatchPhase,
+		Containers: []ScanHookContainer{{Name: "app"}, {Name: "db"}},
+	}
+
+	require.ErrorIs(t, ExecuteScanHook(log, ctx, params, payload), errScanHookFailed)
+
+	for _, name := range []string{"app", "db"} {
+		runs := recorder.Runs(name)
+		require.Len(t, runs, 1, name)
+		assert.Equal(t, PreRestartBatchPhase, runs[0].Phase)
+		assert.Equal(t, params.PreRestartBatchHook, runs[0].Command)
+		assert.Equal(t, 3, runs[0].ExitCode)
+		assert.Equal(t, "password=[redacted]\n", runs[0].Output)
+		assert.NotContains(t, runs[0].Error, "hunter2")
+	}
+}
+
+func TestExecuteScanHook_CommandTimeout(t *testing.T) {
+	requireShell(t)
+
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+	params := types.UpdateParams{PostScanHook: "sleep 10", ScanHookTimeout: 50 * time.Millisecond}
+
+	start := time.Now()
+	err := ExecuteScanHook(log, context.Background(), params, ScanHookPayload{Phase: PostScanPhase})
+	require.ErrorIs(t, err, errScanHookFailed)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestExecuteScanHook_HTTP(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "2xx succeeds", status: http.StatusAccepted},
+		{name: "non-2xx fails", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payloads := make(chan ScanHookPayload, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+
+				var payload ScanHookPayload
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+				payloads <- payload
+
+				w.WriteHeader(tt.status)
+			}))
+			t.Cleanup(server.Close)
+
+			log, _ := logging.NewTestLogger(logging.DebugLevel)
+			params := types.UpdateParams{PostRestartBatchHook: server.URL}
+			payload := ScanHookPayload{
+				Phase:      PostRestartBatchPhase,
+				ScanID:     "scan-2",
+				Containers: []ScanHookContainer{{Name: "app", Error: "start failed"}},
+			}
+
+			err := ExecuteScanHook(log, context.Background(), params, payload)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errScanHookFailed)
+				require.ErrorIs(t, err, errWebhookStatus)
+			} else {
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, payload, <-payloads)
+		})
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	buf := &cappedBuffer{limit: 5}
+
+	n, err := buf.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	n, err = buf.Write([]byte("defgh"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, n)
+
+	_, _ = buf.Write([]byte("ijk"))
+	assert.Equal(t, "abcde", buf.buf.String())
+}
@@ -0,0 +1,390 @@
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+
+	dockerImage "github.com/moby/moby/api/types/image"
+
+	"github.com/nicholas-fedor/watchtower/internal/meta"
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Webhook hook labels configure HTTP endpoints called instead of, or in
+// addition to, in-container commands. They suit images without a shell.
+const (
+	// preCheckURLLabel is the endpoint called before checking for updates.
+	preCheckURLLabel = "com.centurylinklabs.watchtower.lifecycle.pre-check-url"
+	// postCheckURLLabel is the endpoint called after checking for updates.
+	postCheckURLLabel = "com.centurylinklabs.watchtower.lifecycle.post-check-url"
+	// preUpdateURLLabel is the endpoint called before updating the container.
+	preUpdateURLLabel = "com.centurylinklabs.watchtower.lifecycle.pre-update-url"
+	// postUpdateURLLabel is the endpoint called after updating the container.
+	postUpdateURLLabel = "com.centurylinklabs.watchtower.lifecycle.post-update-url"
+	// urlTimeoutLabel sets the request timeout for webhook hooks (e.g., "10s").
+	urlTimeoutLabel = "com.centurylinklabs.watchtower.lifecycle.url-timeout"
+	// urlSkipStatusLabel lists response codes that skip the update, comma-separated (e.g., "409,503").
+	urlSkipStatusLabel = "com.centurylinklabs.watchtower.lifecycle.url-skip-status"
+)
+
+// defaultWebhookTimeout is the request timeout when urlTimeoutLabel is unset or invalid.
+const defaultWebhookTimeout = 30 * time.Second
+
+// maxWebhookErrorBody caps the response body included in webhook errors.
+const maxWebhookErrorBody = 512
+
+// maxWebhookResponseBody caps the response body read for the hook run record (1 MiB, as for command output).
+const maxWebhookResponseBody = 1 << 20
+
+// Errors for webhook hook execution.
+var (
+	// errWebhookFailed indicates a webhook hook that could not be called.
+	errWebhookFailed = errors.New("lifecycle webhook request failed")
+	// errWebhookStatus indicates a webhook hook that answered with an unexpected status.
+	errWebhookStatus = errors.New("lifecycle webhook returned unexpected status")
+)
+
+// webhookClient sends webhook hook requests under the span of each request.
+// Timeouts are applied per request.
+var webhookClient = &http.Client{Transport: tracing.NewTransport(nil)}
+
+// WebhookPayload is the JSON body POSTed to a webhook hook.
+type WebhookPayload struct {
+	Phase       string `json:"phase"`                  // pre-check, post-check, pre-update, or post-update.
+	ScanID      string `json:"scan_id,omitempty"`      // Identifier of the scan that triggered the hook.
+	Container   string `json:"container"`              // Container name.
+	ContainerID string `json:"container_id"`           // Container ID; the new container for post-update.
+	Image       string `json:"image"`                  // Image name with tag.
+	OldImageID  string `json:"old_image_id,omitempty"` // Image ID before the update.
+	NewImageID  string `json:"new_image_id,omitempty"` // Image ID after the update, when known.
+	OldDigest   string `json:"old_digest,omitempty"`   // Repository digest before the update.
+	NewDigest   string `json:"new_digest,omitempty"`   // Repository digest after the update, when known.
+}
+
+// webhookResponse is the status and body of a webhook hook response.
+type webhookResponse struct {
+	status int
+	body   string
+}
+
+// latestImageContainer is implemented by containers that record the newest
+// image found by the staleness check.
+type latestImageContainer interface {
+	LatestImageInfo() *dockerImage.InspectResponse
+}
+
+// webhookURL returns the webhook hook URL for a phase.
+//
+// Parameters:
+//   - cont: Container whose labels are read.
+//   - phase: Hook phase.
+//
+// Returns:
+//   - string: URL, or empty if unset.
+func webhookURL(cont types.Container, phase string) string {
+	label := map[string]string{
+		"pre-check":   preCheckURLLabel,
+		"post-check":  postCheckURLLabel,
+		"pre-update":  preUpdateURLLabel,
+		"post-update": postUpdateURLLabel,
+	}[phase]
+
+	value, _ := cont.GetLabel(label)
+
+	return strings.TrimSpace(value)
+}
+
+// newWebhookPayload builds the payload describing a container for a phase.
+//
+// Parameters:
+//   - phase: Hook phase.
+//   - scanID: Scan identifier.
+//   - cont: Container the hook runs for.
+//
+// Returns:
+//   - WebhookPayload: Payload with the current image as old and the newest known image as new.
+func newWebhookPayload(phase, scanID string, cont types.Container) WebhookPayload {
+	payload := WebhookPayload{
+		Phase:       phase,
+		ScanID:      scanID,
+		Container:   cont.Name(),
+		ContainerID: string(cont.ID()),
+		Image:       cont.ImageName(),
+		OldImageID:  string(cont.ImageID()),
+	}
+
+	if cont.HasImageInfo() {
+		payload.OldDigest = container.ExtractImageDigest(cont.ImageInfo().RepoDigests, cont.ImageName())
+	}
+
+	latest, ok := cont.(latestImageContainer)
+	if ok {
+		info := latest.LatestImageInfo()
+		if info != nil {
+			payload.NewImageID = info.ID
+			payload.NewDigest = container.ExtractImageDigest(info.RepoDigests, cont.ImageName())
+		}
+	}
+
+	return payload
+}
+
+// postUpdatePayload builds the post-update payload from the replaced and new containers.
+//
+// Parameters:
+//   - scanID: Scan identifier.
+//   - source: Container that was replaced.
+//   - updated: Container created from the new image.
+//
+// Returns:
+//   - WebhookPayload: Payload with the source image as old and the updated image as new.
+func postUpdatePayload(scanID string, source, updated types.Container) WebhookPayload {
+	payload := newWebhookPayload("post-update", scanID, updated)
+	payload.NewImageID, payload.NewDigest = payload.OldImageID, payload.OldDigest
+	payload.OldImageID, payload.OldDigest = "", ""
+
+	if source != nil {
+		previous := newWebhookPayload("post-update", scanID, source)
+		payload.OldImageID, payload.OldDigest = previous.OldImageID, previous.OldDigest
+	}
+
+	return payload
+}
+
+// webhookTimeout returns the request timeout for a container's webhook hooks.
+//
+// Parameters:
+//   - clog: Container logger.
+//   - cont: Container whose labels are read.
+//
+// Returns:
+//   - time.Duration: Timeout from urlTimeoutLabel, or defaultWebhookTimeout.
+func webhookTimeout(clog *zerolog.Logger, cont types.Container) time.Duration {
+	value, ok := cont.GetLabel(urlTimeoutLabel)
+	if !ok || value == "" {
+		return defaultWebhookTimeout
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		clog.Warn().
+			Str("label", urlTimeoutLabel).
+			Str("value", value).
+			Msg("Invalid webhook timeout value, using default")
+
+		return defaultWebhookTimeout
+	}
+
+	return timeout
+}
+
+// webhookSkipStatus reports whether a response code is listed in urlSkipStatusLabel.
+//
+// Parameters:
+//   - cont: Container whose labels are read.
+//   - status: Response status code.
+//
+// Returns:
+//   - bool: True if the status skips the update.
+func webhookSkipStatus(cont types.Container, status int) bool {
+	value, _ := cont.GetLabel(urlSkipStatusLabel)
+
+	for field := range strings.SplitSeq(value, ",") {
+		code, err := strconv.Atoi(strings.TrimSpace(field))
+		if err == nil && code == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// callWebhook POSTs the payload to a container's webhook hook.
+//
+// A 2xx response proceeds, a status listed in urlSkipStatusLabel skips the
+// update like exit code 75 does for commands, and anything else fails. When
+// ctx carries a Recorder, the response status, body, and duration are recorded.
+//
+// Parameters:
+//   - log: Container logger.
+//   - ctx: Context for cancellation and the parent span.
+//   - cont: Container whose labels configure the hook.
+//   - url: Endpoint to call.
+//   - payload: Request body.
+//
+// Returns:
+//   - bool: True if the update should be skipped.
+//   - error: Non-nil if the request fails or returns an unexpected status.
+func callWebhook(log *zerolog.Logger, ctx context.Context, cont types.Container, url string, payload WebhookPayload) (bool, error) {
+	ctx, span := tracing.StartContainer(ctx, "lifecycle.hook", cont,
+		attribute.String("watchtower.lifecycle.phase", payload.Phase),
+		attribute.String("watchtower.lifecycle.transport", "http"))
+	defer span.End()
+
+	start := time.Now()
+
+	skip, response, err := postWebhook(ctx, cont, url, payload, webhookTimeout(log, cont))
+	tracing.RecordError(span, err)
+
+	if recorder := RecorderFrom(ctx); recorder != nil {
+		recorder.record(cont, webhookRun(payload.Phase, url, response, time.Since(start), err))
+	}
+
+	return skip, err
+}
+
+// webhookRun describes a webhook call for the hook run record.
+//
+// Parameters:
+//   - phase: Hook phase.
+//   - url: Endpoint called.
+//   - response: Response status and body; zero when no response arrived.
+//   - duration: Time taken by the call.
+//   - err: Call error, or nil.
+//
+// Returns:
+//   - types.HookRun: Run with raw URL and body.
+func webhookRun(phase, url string, response webhookResponse, duration time.Duration, err error) types.HookRun {
+	run := types.HookRun{
+		Phase:      phase,
+		URL:        url,
+		ExitCode:   -1,
+		StatusCode: response.status,
+		Duration:   duration,
+		Output:     response.body,
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	return run
+}
+
+// postWebhook sends the webhook request and maps the response status.
+//
+// Parameters:
+//   - ctx: Context for cancellation.
+//   - cont: Container whose labels list skip statuses.
+//   - url: Endpoint to call.
+//   - payload: Request body.
+//   - timeout: Request timeout.
+//
+// Returns:
+//   - bool: True if the update should be skipped.
+//   - webhookResponse: Response status and body; zero when the request failed.
+//   - error: Non-nil if the request fails or returns an unexpected status.
+func postWebhook(
+	ctx context.Context,
+	cont types.Container,
+	url string,
+	payload WebhookPayload,
+	timeout time.Duration,
+) (bool, webhookResponse, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return false, webhookResponse{}, fmt.Errorf("%w: %w", errWebhookFailed, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response, err := exchangeWebhook(ctx, url, body)
+	if err != nil {
+		return false, webhookResponse{}, err
+	}
+
+	switch {
+	case response.ok():
+		return false, response, nil
+	case webhookSkipStatus(cont, response.status):
+		return true, response, nil
+	default:
+		return false, response, response.statusError()
+	}
+}
+
+// exchangeWebhook POSTs a JSON body and reads the response.
+//
+// Parameters:
+//   - ctx: Context carrying the timeout and the parent span.
+//   - url: Endpoint to call.
+//   - body: JSON request body.
+//
+// Returns:
+//   - webhookResponse: Response status and body, capped at maxWebhookResponseBody.
+//   - error: Non-nil if the request cannot be sent.
+func exchangeWebhook(ctx context.Context, url string, body []byte) (webhookResponse, error) {
+	resp, err := sendWebhook(ctx, url, body)
+	if err != nil {
+		return webhookResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+
+	return webhookResponse{status: resp.StatusCode, body: string(respBody)}, nil
+}
+
+// ok reports whether the response status is 2xx.
+func (r webhookResponse) ok() bool {
+	return r.status >= http.StatusOK && r.status < http.StatusMultipleChoices
+}
+
+// statusError describes an unexpected response with the start of its body.
+func (r webhookResponse) statusError() error {
+	body := r.body
+	if len(body) > maxWebhookErrorBody {
+		body = body[:maxWebhookErrorBody]
+	}
+
+	return fmt.Errorf("%w %d: %s", errWebhookStatus, r.status, strings.TrimSpace(body))
+}
+
+// sendWebhook POSTs a JSON body with the Watchtower user agent and trace headers.
+//
+// Parameters:
+//   - ctx: Context carrying the timeout and the parent span.
+//   - url: Endpoint to call.
+//   - body: JSON request body.
+//
+// Returns:
+//   - *http.Response: Response whose body the caller must close.
+//   - error: Non-nil if the request cannot be built or sent.
+func sendWebhook(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebhookFailed, err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", meta.UserAgent)
+
+	traceParent, traceState := tracing.TraceParent(ctx)
+	if traceParent != "" {
+		req.Header.Set("Traceparent", traceParent)
+
+		if traceState != "" {
+			req.Header.Set("Tracestate", traceState)
+		}
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errWebhookFailed, err)
+	}
+
+	return resp, nil
+}
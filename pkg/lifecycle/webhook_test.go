@@ -0,0 +1,228 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerImage "github.com/moby/moby/api/types/image"
+
+	"github.com/nicholas-fedor/watchtower/internal/logging"
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	mockContainer "github.com/nicholas-fedor/watchtower/pkg/container/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// webhookServer records webhook payloads and answers with status.
+func webhookServer(t *testing.T, status int) (*httptest.Server, <-chan WebhookPayload) {
+	t.Helper()
+
+	payloads := make(chan WebhookPayload, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		var payload WebhookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+
+		payloads <- payload
+
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, payloads
+}
+
+// imageContainer creates a container running nginx:latest from the given image.
+func imageContainer(imageID, digest string, labels map[string]string) *container.Container {
+	return container.NewContainer(nil,
+		&dockerContainer.InspectResponse{
+			ID:         "container_id",
+			Image:      imageID,
+			HostConfig: &dockerContainer.HostConfig{},
+			State:      &dockerContainer.State{},
+			Name:       "/test-container",
+			Config:     &dockerContainer.Config{Image: "nginx:latest", Labels: labels},
+		},
+		&dockerImage.InspectResponse{ID: imageID, RepoDigests: []string{"nginx@" + digest}},
+	)
+}
+
+func TestExecutePreUpdateCommand_Webhook(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		skipStatus string
+		wantSkip   bool
+		wantErr    bool
+	}{
+		{name: "2xx proceeds", status: http.StatusNoContent},
+		{name: "skip status skips", status: http.StatusConflict, skipStatus: "409, 503", wantSkip: true},
+		{name: "unlisted status fails", status: http.StatusServiceUnavailable, skipStatus: "409", wantSkip: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, payloads := webhookServer(t, tt.status)
+			log, _ := logging.NewTestLogger(logging.DebugLevel)
+
+			// Stopped containers still call the webhook; only commands need a shell.
+			cont := imageContainer("sha256:old", "sha256:olddigest", map[string]string{
+				"com.centurylinklabs.watchtower.lifecycle.pre-update-url":  server.URL,
+				"com.centurylinklabs.watchtower.lifecycle.url-skip-status": tt.skipStatus,
+			})
+
+			skip, err := ExecutePreUpdateCommand(log, context.Background(), mockContainer.NewMockClient(t), cont,
+				types.UpdateParams{ScanID: "scan-1"})
+
+			assert.Equal(t, tt.wantSkip, skip)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, errPreUpdateFailed)
+				require.ErrorIs(t, err, errWebhookStatus)
+			} else {
+				require.NoError(t, err)
+			}
+
+			payload := <-payloads
+			assert.Equal(t, "pre-update", payload.Phase)
+			assert.Equal(t, "scan-1", payload.ScanID)
+			assert.Equal(t, "test-container", payload.Container)
+			assert.Equal(t, "container_id", payload.ContainerID)
+			assert.Equal(t, "nginx:latest", payload.Image)
+			assert.Equal(t, "sha256:old", payload.OldImageID)
+			assert.Equal(t, "sha256:olddigest", payload.OldDigest)
+		})
+	}
+}
+
+func TestExecutePreUpdateCommand_CommandSkipBypassesWebhook(t *testing.T) {
+	server, payloads := webhookServer(t, http.StatusOK)
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+
+	cont := mockedContainer(
+		withContainerState(dockerContainer.State{Running: true}),
+		withLabels(map[string]string{
+			"com.centurylinklabs.watchtower.lifecycle.pre-update":     "pre-update",
+			"com.centurylinklabs.watchtower.lifecycle.pre-update-url": server.URL,
+		}),
+	)
+
+	client := mockContainer.NewMockClient(t)
+	client.On("ExecuteCommand", mock.Anything, mock.Anything, "pre-update", 1, 0, 0).Return(true, nil)
+
+	skip, err := ExecutePreUpdateCommand(log, context.Background(), client, cont, types.UpdateParams{})
+	require.NoError(t, err)
+	assert.True(t, skip)
+	assert.Empty(t, payloads)
+}
+
+func TestExecutePostCheckCommand_WebhookOnly(t *testing.T) {
+	server, payloads := webhookServer(t, http.StatusOK)
+	log, logBuf := logging.NewTestLogger(logging.DebugLevel)
+
+	cont := mockedContainer(withLabels(map[string]string{
+		"com.centurylinklabs.watchtower.lifecycle.post-check-url": server.URL,
+	}))
+
+	ExecutePostCheckCommand(log, context.Background(), mockContainer.NewMockClient(t), cont,
+		types.UpdateParams{ScanID: "scan-2"})
+
+	payload := <-payloads
+	assert.Equal(t, "post-check", payload.Phase)
+	assert.Equal(t, "scan-2", payload.ScanID)
+	assert.Contains(t, logBuf.String(), "Calling post-check webhook")
+	assert.NotContains(t, logBuf.String(), "Failed to call")
+}
+
+func TestExecutePostUpdateCommand_WebhookPayload(t *testing.T) {
+	server, payloads := webhookServer(t, http.StatusOK)
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+
+	source := imageContainer("sha256:old", "sha256:olddigest", map[string]string{})
+	updated := imageContainer("sha256:new", "sha256:newdigest", map[string]string{
+		"com.centurylinklabs.watchtower.lifecycle.post-update-url": server.URL,
+	})
+
+	client := mockContainer.NewMockClient(t)
+	client.On("GetContainer", mock.Anything, types.ContainerID("new")).Return(updated, nil)
+
+	ExecutePostUpdateCommand(log, context.Background(), client, source, "new", types.UpdateParams{ScanID: "scan-3"})
+
+	payload := <-payloads
+	assert.Equal(t, "post-update", payload.Phase)
+	assert.Equal(t, "scan-3", payload.ScanID)
+	assert.Equal(t, "sha256:old", payload.OldImageID)
+	assert.Equal(t, "sha256:new", payload.NewImageID)
+	assert.Equal(t, "sha256:olddigest", payload.OldDigest)
+	assert.Equal(t, "sha256:newdigest", payload.NewDigest)
+}
+
+func TestWebhookTimeout(t *testing.T) {
+	log, logBuf := logging.NewTestLogger(logging.DebugLevel)
+
+	assert.Equal(t, defaultWebhookTimeout, webhookTimeout(log, mockedContainer()))
+	assert.Equal(t, 5*time.Second, webhookTimeout(log, mockedContainer(withLabels(map[string]string{
+		"com.centurylinklabs.watchtower.lifecycle.url-timeout": "5s",
+	}))))
+	assert.Equal(t, defaultWebhookTimeout, webhookTimeout(log, mockedContainer(withLabels(map[string]string{
+		"com.centurylinklabs.watchtower.lifecycle.url-timeout": "soon",
+	}))))
+	assert.Contains(t, logBuf.String(), "Invalid webhook timeout value")
+}
+
+func TestCallWebhook_Timeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+	cont := mockedContainer(withLabels(map[string]string{
+		"com.centurylinklabs.watchtower.lifecycle.url-timeout": "50ms",
+	}))
+
+	_, err := callWebhook(log, context.Background(), cont, server.URL, newWebhookPayload("pre-update", "", cont))
+	require.ErrorIs(t, err, errWebhookFailed)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestCallWebhook_RecordsRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("draining, token=abc123"))
+	}))
+	t.Cleanup(server.Close)
+
+	log, _ := logging.NewTestLogger(logging.DebugLevel)
+	recorder := NewRecorder(types.UpdateParams{LifecycleOutputLimit: 12})
+	ctx := WithRecorder(context.Background(), recorder)
+	cont := mockedContainer()
+	url := "http://user:secret@" + server.Listener.Addr().String()
+
+	_, err := callWebhook(log, ctx, cont, url, newWebhookPayload("pre-update", "", cont))
+	require.ErrorIs(t, err, errWebhookStatus)
+
+	runs := recorder.Runs("test-container")
+	require.Len(t, runs, 1)
+	assert.Equal(t, "pre-update", runs[0].Phase)
+	assert.Empty(t, runs[0].Command)
+	assert.Equal(t, "http://user:[redacted]@"+server.Listener.Addr().String(), runs[0].URL)
+	assert.Equal(t, -1, runs[0].ExitCode)
+	assert.Equal(t, http.StatusServiceUnavailable, runs[0].StatusCode)
+	assert.Equal(t, "draining, to", runs[0].Output)
+	assert.True(t, runs[0].Truncated)
+	assert.Contains(t, runs[0].Error, "token=[redacted]")
+}
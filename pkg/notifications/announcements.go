@@ -0,0 +1,254 @@
+package notifications
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// Notice kinds for stale containers, exposed to templates through Data.Notices.
+const (
+	// NoticeNew marks an update whose image digest has not been announced before.
+	NoticeNew = "new"
+	// NoticeReminder marks an already announced update re-announced after the reminder interval.
+	NoticeReminder = "reminder"
+	// NoticeRepeat marks an already announced update that is not yet due a reminder.
+	NoticeRepeat = "repeat"
+)
+
+// announcement is the latest image digest announced as available for a container.
+type announcement struct {
+	Digest      types.ImageID `json:"digest"`
+	AnnouncedAt time.Time     `json:"announced_at"`
+	notice      string
+}
+
+// announcements tracks announced available updates so each image digest is announced once.
+//
+// Containers are keyed by name. A container is forgotten once it is updated or
+// found fresh, so a later update is announced as new again.
+type announcements struct {
+	log      *zerolog.Logger
+	path     string
+	reminder time.Duration
+	now      func() time.Time
+
+	mutex  sync.Mutex
+	byName map[string]*announcement
+}
+
+// newAnnouncements creates a tracker, restoring announcements persisted in path.
+//
+// Parameters:
+//   - log: Logger for persistence diagnostics.
+//   - path: File that keeps announcements across restarts; empty keeps them in memory.
+//   - reminder: Interval after which an announced update is announced again; zero never reminds.
+//
+// Returns:
+//   - *announcements: The tracker.
+func newAnnouncements(log *zerolog.Logger, path string, reminder time.Duration) *announcements {
+	tracker := &announcements{
+		log:      log,
+		path:     path,
+		reminder: reminder,
+		now:      time.Now,
+		byName:   make(map[string]*announcement),
+	}
+
+	if path == "" {
+		return tracker
+	}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return tracker
+	}
+
+	if err == nil {
+		err = json.Unmarshal(content, &tracker.byName)
+	}
+
+	if err != nil || tracker.byName == nil {
+		log.Warn().Err(err).Str("file", path).Msg("Failed to read announced updates, announcing all updates again")
+
+		tracker.byName = make(map[string]*announcement)
+	}
+
+	return tracker
+}
+
+// evaluate classifies the report's stale containers as new, reminder, or repeat.
+//
+// New and reminder updates are recorded as announced. Updated and fresh
+// containers that are not stale are forgotten. A split report only announces
+// or forgets its own container.
+//
+// Parameters:
+//   - report: Scan report.
+//
+// Returns:
+//   - bool: True if the report has stale containers to announce and all of them are repeats.
+func (a *announcements) evaluate(report types.Report) bool {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	now := a.now()
+	changed := false
+
+	stale := make(map[types.ContainerID]bool, len(report.Stale()))
+	for _, container := range report.Stale() {
+		stale[container.ID()] = true
+	}
+
+	for _, containers := range [][]types.ContainerReport{report.Updated(), report.Fresh()} {
+		for _, container := range reportedContainers(report, containers) {
+			if stale[container.ID()] {
+				continue
+			}
+
+			if _, ok := a.byName[container.Name()]; ok {
+				delete(a.byName, container.Name())
+
+				changed = true
+			}
+		}
+	}
+
+	announced := reportedContainers(report, report.Stale())
+	repeatsOnly := len(announced) > 0
+
+	for _, container := range announced {
+		previous, ok := a.byName[container.Name()]
+
+		switch {
+		case !ok || previous.Digest != container.LatestImageID():
+			a.byName[container.Name()] = &announcement{
+				Digest:      container.LatestImageID(),
+				AnnouncedAt: now,
+				notice:      NoticeNew,
+			}
+		case a.reminder > 0 && now.Sub(previous.AnnouncedAt) >= a.reminder:
+			previous.AnnouncedAt = now
+			previous.notice = NoticeReminder
+		default:
+			previous.notice = NoticeRepeat
+
+			continue
+		}
+
+		repeatsOnly = false
+		changed = true
+	}
+
+	if changed {
+		a.saveLocked()
+	}
+
+	return repeatsOnly
+}
+
+// reportedContainers returns the containers of a report category that the report is about.
+//
+// A split report is only about its own container; the other containers it
+// lists are context from the rest of the scan.
+//
+// Parameters:
+//   - report: Scan report.
+//   - containers: One category of the report, such as its stale containers.
+//
+// Returns:
+//   - []types.ContainerReport: containers, or only the split report's own container if listed.
+func reportedContainers(report types.Report, containers []types.ContainerReport) []types.ContainerReport {
+	focus, ok := singleFocusContainer(report)
+	if !ok {
+		return containers
+	}
+
+	for _, container := range containers {
+		if container.ID() == focus.ID() {
+			return []types.ContainerReport{container}
+		}
+	}
+
+	return nil
+}
+
+// notices returns the notice kind of each stale container in report.
+//
+// Parameters:
+//   - report: Report being rendered, or nil.
+//
+// Returns:
+//   - map[string]string: Notice kinds keyed by container name, or nil when there are none.
+func (a *announcements) notices(report types.Report) map[string]string {
+	if report == nil || len(report.Stale()) == 0 {
+		return nil
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	notices := make(map[string]string, len(report.Stale()))
+
+	for _, container := range report.Stale() {
+		if announced, ok := a.byName[container.Name()]; ok && announced.notice != "" {
+			notices[container.Name()] = announced.notice
+		}
+	}
+
+	return notices
+}
+
+// saveLocked writes the announcements to their file, if any. Callers hold mutex.
+func (a *announcements) saveLocked() {
+	if a.path == "" {
+		return
+	}
+
+	err := writeStateFile(a.path, a.byName)
+	if err != nil {
+		a.log.Warn().Err(err).Str("file", a.path).Msg("Failed to save announced updates")
+	}
+}
+
+// dropRepeatEntries removes queued entries about the stale containers the report announces.
+//
+// Called when a scan's notification is suppressed as a repeat so its entries are
+// not flushed with the next scan.
+//
+// Parameters:
+//   - report: Suppressed scan report.
+func (n *shoutrrrTypeNotifier) dropRepeatEntries(report types.Report) {
+	n.entriesMutex.Lock()
+	defer n.entriesMutex.Unlock()
+
+	if n.entries == nil {
+		return
+	}
+
+	kept := make([]*notificationEntry, 0, len(n.entries))
+
+	for _, entry := range n.entries {
+		repeat := false
+
+		for _, container := range reportedContainers(report, report.Stale()) {
+			if entryMatchesFocus(entry, container) {
+				repeat = true
+
+				break
+			}
+		}
+
+		if !repeat {
+			kept = append(kept, entry)
+		}
+	}
+
+	n.entries = kept
+}
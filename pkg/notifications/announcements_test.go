@@ -0,0 +1,194 @@
+package notifications
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// noticeTestTemplate lists stale containers with their notice kind.
+const noticeTestTemplate = `{{range .Report.Stale}}{{.Name}}:{{index $.Notices .Name}} {{end}}`
+
+// staleReport builds a report with one stale container whose latest image is digest.
+func staleReport(name, digest string) types.Report {
+	log := testLogger()
+	progress := session.Progress{}
+
+	c := mockActions.CreateMockContainer(name+"-id", "/"+name, "mock/"+name+":latest", time.Now())
+	progress.AddScanned(log, c, types.ImageID(digest), types.UpdateParams{})
+
+	return progress.Report(log)
+}
+
+// splitReports builds the per-container reports sent for the stale containers of
+// a scan when notifications are split by container, as for monitor-only containers.
+func splitReports(report types.Report) []types.Report {
+	reports := make([]types.Report, 0, len(report.Stale()))
+
+	for _, container := range report.Stale() {
+		reports = append(reports, &session.SingleContainerReport{
+			UpdatedReports: []types.ContainerReport{container},
+			ScannedReports: report.Scanned(),
+			FailedReports:  report.Failed(),
+			SkippedReports: report.Skipped(),
+			StaleReports:   report.Stale(),
+			FreshReports:   report.Fresh(),
+		})
+	}
+
+	return reports
+}
+
+// newAnnouncingNotifier creates a report-mode notifier with repeat suppression and a controllable clock.
+func newAnnouncingNotifier(path string, reminder time.Duration, clock *time.Time) *shoutrrrTypeNotifier {
+	notifier := createNotifier(testLogger(), []string{"logger://"}, zerolog.InfoLevel,
+		noticeTestTemplate, false, StaticData{}, false, 0)
+	notifier.announcements = newAnnouncements(testLogger(), path, reminder)
+	notifier.announcements.now = func() time.Time { return *clock }
+
+	return notifier
+}
+
+var _ = ginkgo.Describe("repeat suppression", func() {
+	var clock time.Time
+
+	ginkgo.BeforeEach(func() {
+		resetTestLogger()
+
+		clock = time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	})
+
+	ginkgo.It("announces an available update once per image digest", func() {
+		notifier := newAnnouncingNotifier("", 0, &clock)
+
+		first := staleReport("db", "sha256:one")
+		gomega.Expect(notifier.ShouldSendNotification(first)).To(gomega.BeTrue())
+		notifier.sendEntries(nil, first)
+		gomega.Expect(queued(notifier)).To(gomega.Equal("db:new "))
+
+		clock = clock.Add(time.Hour)
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeFalse())
+
+		second := staleReport("db", "sha256:two")
+		gomega.Expect(notifier.ShouldSendNotification(second)).To(gomega.BeTrue())
+		notifier.sendEntries(nil, second)
+		gomega.Expect(queued(notifier)).To(gomega.Equal("db:new "))
+	})
+
+	ginkgo.It("reminds about an announced update after the reminder interval", func() {
+		notifier := newAnnouncingNotifier("", 24*time.Hour, &clock)
+
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeTrue())
+
+		clock = clock.Add(23 * time.Hour)
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeFalse())
+
+		clock = clock.Add(time.Hour)
+		reminder := staleReport("db", "sha256:one")
+		gomega.Expect(notifier.ShouldSendNotification(reminder)).To(gomega.BeTrue())
+		notifier.sendEntries(nil, reminder)
+		gomega.Expect(queued(notifier)).To(gomega.Equal("db:reminder "))
+
+		clock = clock.Add(time.Hour)
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("announces the same digest again after the container was updated", func() {
+		notifier := newAnnouncingNotifier("", 0, &clock)
+
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeTrue())
+		gomega.Expect(notifier.ShouldSendNotification(digestTestReport(map[string]string{"db": "updated"}))).
+			To(gomega.BeTrue())
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("still sends repeats alongside failures", func() {
+		notifier := newAnnouncingNotifier("", 0, &clock)
+
+		gomega.Expect(notifier.ShouldSendNotification(digestTestReport(map[string]string{"db": "stale"}))).
+			To(gomega.BeTrue())
+		gomega.Expect(notifier.ShouldSendNotification(digestTestReport(map[string]string{
+			"db": "stale", "cache": "failed",
+		}))).To(gomega.BeTrue())
+	})
+
+	ginkgo.It("drops queued entries about suppressed containers", func() {
+		notifier := newAnnouncingNotifier("", 0, &clock)
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeTrue())
+
+		notifier.entries = routeTestEntries()
+		gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeFalse())
+
+		messages := make([]string, 0, len(notifier.entries))
+		for _, entry := range notifier.entries {
+			messages = append(messages, entry.Message)
+		}
+
+		gomega.Expect(messages).To(gomega.Equal([]string{"web-info", "cache-error", "global-warn"}))
+	})
+
+	ginkgo.It("keeps announced digests across restarts", func() {
+		path := filepath.Join(ginkgo.GinkgoT().TempDir(), "announced.json")
+
+		first := newAnnouncingNotifier(path, 0, &clock)
+		gomega.Expect(first.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeTrue())
+
+		restarted := newAnnouncingNotifier(path, 0, &clock)
+		gomega.Expect(restarted.ShouldSendNotification(staleReport("db", "sha256:one"))).To(gomega.BeFalse())
+		gomega.Expect(restarted.ShouldSendNotification(staleReport("db", "sha256:two"))).To(gomega.BeTrue())
+	})
+
+	ginkgo.When("notifications are split by container", func() {
+		ginkgo.It("announces each monitor-only container once per image digest", func() {
+			notifier := newAnnouncingNotifier("", 0, &clock)
+
+			for _, report := range splitReports(digestTestReport(map[string]string{"db": "stale", "cache": "stale"})) {
+				gomega.Expect(notifier.ShouldSendNotification(report)).To(gomega.BeTrue())
+			}
+
+			clock = clock.Add(time.Hour)
+
+			for _, report := range splitReports(digestTestReport(map[string]string{"db": "stale", "cache": "stale"})) {
+				gomega.Expect(notifier.ShouldSendNotification(report)).To(gomega.BeFalse())
+			}
+		})
+
+		ginkgo.It("only announces the report's own container", func() {
+			notifier := newAnnouncingNotifier("", 0, &clock)
+
+			scan := digestTestReport(map[string]string{"db": "stale", "cache": "stale"})
+			reports := splitReports(scan)
+			gomega.Expect(reports).To(gomega.HaveLen(2))
+
+			gomega.Expect(notifier.ShouldSendNotification(reports[0])).To(gomega.BeTrue())
+
+			own, _ := singleFocusContainer(reports[0])
+			gomega.Expect(notifier.announcements.byName).To(gomega.HaveLen(1))
+			gomega.Expect(notifier.announcements.byName).To(gomega.HaveKey(own.Name()))
+
+			gomega.Expect(notifier.ShouldSendNotification(reports[1])).To(gomega.BeTrue())
+			gomega.Expect(notifier.ShouldSendNotification(scan)).To(gomega.BeFalse())
+		})
+
+		ginkgo.It("sends an updated container's report alongside repeated stale containers", func() {
+			notifier := newAnnouncingNotifier("", 0, &clock)
+			gomega.Expect(notifier.ShouldSendNotification(staleReport("db", "sha256:new-db"))).To(gomega.BeTrue())
+
+			scan := digestTestReport(map[string]string{"db": "stale", "web": "updated"})
+			gomega.Expect(scan.Updated()).To(gomega.HaveLen(1))
+
+			gomega.Expect(notifier.ShouldSendNotification(&session.SingleContainerReport{
+				UpdatedReports: scan.Updated(),
+				StaleReports:   scan.Stale(),
+			})).To(gomega.BeTrue())
+			gomega.Expect(notifier.ShouldSendNotification(splitReports(scan)[0])).To(gomega.BeFalse())
+		})
+	})
+})
@@ -132,4 +132,24 @@ var commonTemplates = map[string]string{
 	// It omits log entries and focuses on the report: name, image, image_id, latest_image_id, state, update_available, and error.
 	// Expects .Report with an All() method returning []ContainerReport.
 	`porcelain.json`: `{{ .Report | ToPorcelainJSON }}`,
+
+	// "digest" template summarizes the scans accumulated by digest mode (--notification-digest-schedule).
+	// It starts with the scan count and period, then lists updated containers with how often they were updated,
+	// containers whose update is still pending with how long they have waited, and containers that failed
+	// with their failure count and last error. Scans that failed outright and cleaned images are totaled.
+	// Expects DigestData with Since, Scans, Updated, Pending, Failures, ScanErrors, LastScanError, CleanedImages.
+	`digest`: `
+{{- .Scans}} scan{{if ne .Scans 1}}s{{end}} since {{.Since.Format "2006-01-02 15:04 MST"}}: {{len .Updated}} Updated, {{len .Pending}} Pending, {{len .Failures}} Failed, {{.CleanedImages}} Images Cleaned
+{{- range .Updated}}
+- {{.Name}} ({{.ImageName}}): updated{{if gt .Count 1}} {{.Count}} times{{end}}
+{{- end -}}
+{{- range .Pending}}
+- {{.Name}} ({{.ImageName}}): update pending for {{.Waiting}}
+{{- end -}}
+{{- range .Failures}}
+- {{.Name}} ({{.ImageName}}): failed {{.Count}} time{{if ne .Count 1}}s{{end}}: {{.Error}}
+{{- end -}}
+{{- if .ScanErrors}}
+{{.ScanErrors}} scan{{if ne .ScanErrors 1}}s{{end}} failed, last: {{.LastScanError}}
+{{- end -}}`,
 }
@@ -0,0 +1,298 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/rs/zerolog"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// notificationTemplateLabel names the template used for a container's part of a notification.
+const notificationTemplateLabel = "com.centurylinklabs.watchtower.notification-template"
+
+// containerTemplateExtension is tried after the bare name when looking up a template file.
+const containerTemplateExtension = ".tmpl"
+
+// containerSectionSeparator joins the sections of a notification rendered with different templates.
+const containerSectionSeparator = "\n\n"
+
+// errUnknownContainerTemplate indicates a label names no template file or defined template.
+var errUnknownContainerTemplate = errors.New("no template file or defined template with that name")
+
+// containerTemplates resolves the templates named by notification-template labels.
+//
+// A name is looked up as a file in the template directory first, then as a
+// template defined with {{define}} in the global template. Results are cached
+// by name, including failures, so each bad name is reported once.
+type containerTemplates struct {
+	log  *zerolog.Logger
+	dir  string
+	base *template.Template
+
+	mutex  sync.Mutex
+	byName map[string]*template.Template
+}
+
+// containerSection is the part of a report rendered with one container template.
+type containerSection struct {
+	name     string
+	template *template.Template
+	ids      map[types.ContainerID]struct{}
+	names    map[string]struct{}
+}
+
+// newContainerTemplates creates a resolver for notification-template labels.
+//
+// Parameters:
+//   - log: Logger for lookup warnings.
+//   - dir: Directory holding template files; empty only uses templates defined in base.
+//   - base: Global notification template.
+//
+// Returns:
+//   - *containerTemplates: The resolver.
+func newContainerTemplates(log *zerolog.Logger, dir string, base *template.Template) *containerTemplates {
+	return &containerTemplates{
+		log:    log,
+		dir:    dir,
+		base:   base,
+		byName: make(map[string]*template.Template),
+	}
+}
+
+// lookup returns the template a label names.
+//
+// Parameters:
+//   - name: Template name from the label.
+//
+// Returns:
+//   - *template.Template: The template, or nil if it is missing or invalid.
+func (c *containerTemplates) lookup(name string) *template.Template {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if tpl, ok := c.byName[name]; ok {
+		return tpl
+	}
+
+	tpl, err := c.resolve(name)
+	if err != nil {
+		c.log.Warn().
+			Err(err).
+			Str("template", name).
+			Msg("Could not use container notification template, falling back to the global template")
+	}
+
+	c.byName[name] = tpl
+
+	return tpl
+}
+
+// resolve loads the template a label names without caching.
+//
+// Parameters:
+//   - name: Template name from the label.
+//
+// Returns:
+//   - *template.Template: The template.
+//   - error: Non-nil if no valid template has that name.
+func (c *containerTemplates) resolve(name string) (*template.Template, error) {
+	if c.dir != "" && name == filepath.Base(name) && name != "." && name != ".." {
+		for _, file := range []string{name, name + containerTemplateExtension} {
+			content, err := os.ReadFile(filepath.Join(c.dir, file))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to read container template file: %w", err)
+			}
+
+			tpl, err := template.New(name).Funcs(Funcs).Parse(string(content))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse container template file %s: %w", file, err)
+			}
+
+			return tpl, nil
+		}
+	}
+
+	if c.base != nil {
+		if tpl := c.base.Lookup(name); tpl != nil {
+			return tpl, nil
+		}
+	}
+
+	return nil, errUnknownContainerTemplate
+}
+
+// sections groups the report's containers by the template their label names.
+//
+// Containers without the label, or whose template cannot be used, are left out.
+//
+// Parameters:
+//   - report: Session report.
+//
+// Returns:
+//   - []*containerSection: Sections in the order their first container appears.
+func (c *containerTemplates) sections(report types.Report) []*containerSection {
+	byName := make(map[string]*containerSection)
+
+	var sections []*containerSection
+
+	for _, container := range report.All() {
+		name := strings.TrimSpace(containerLabels(container)[notificationTemplateLabel])
+		if name == "" {
+			continue
+		}
+
+		section, ok := byName[name]
+		if !ok {
+			tpl := c.lookup(name)
+			if tpl == nil {
+				continue
+			}
+
+			section = &containerSection{
+				name:     name,
+				template: tpl,
+				ids:      make(map[types.ContainerID]struct{}),
+				names:    make(map[string]struct{}),
+			}
+			byName[name] = section
+			sections = append(sections, section)
+		}
+
+		section.ids[container.ID()] = struct{}{}
+		section.names[strings.TrimPrefix(container.Name(), "/")] = struct{}{}
+	}
+
+	return sections
+}
+
+// renderMessage renders a notification, using container templates for labeled containers.
+//
+// A split report is rendered whole with its focus container's template. A
+// combined report is rendered in sections: one per container template, holding
+// those containers and their entries, then the rest with the global template.
+// A container template that fails to execute falls back to the global template.
+//
+// Parameters:
+//   - data: Notification data.
+//
+// Returns:
+//   - string: Rendered message.
+//   - error: Non-nil if the global template fails.
+func (n *shoutrrrTypeNotifier) renderMessage(data Data) (string, error) {
+	if n.containerTemplates == nil || n.legacyTemplate || data.Report == nil {
+		return n.buildMessage(data)
+	}
+
+	if focus, ok := singleFocusContainer(data.Report); ok {
+		name := strings.TrimSpace(containerLabels(focus)[notificationTemplateLabel])
+		if name == "" {
+			return n.buildMessage(data)
+		}
+
+		return n.buildSection(name, n.containerTemplates.lookup(name), data)
+	}
+
+	sections := n.containerTemplates.sections(data.Report)
+	if len(sections) == 0 {
+		return n.buildMessage(data)
+	}
+
+	claimed := make(map[types.ContainerID]struct{})
+	claimedNames := make(map[string]struct{})
+	parts := make([]string, 0, len(sections)+1)
+
+	for _, section := range sections {
+		sectionData := data
+		sectionData.Report = filterReport(data.Report, func(_ string, c types.ContainerReport) bool {
+			_, ok := section.ids[c.ID()]
+
+			return ok
+		})
+		sectionData.Entries = nil
+
+		for _, entry := range data.Entries {
+			if _, ok := section.names[entryContainer(entry)]; ok {
+				sectionData.Entries = append(sectionData.Entries, entry)
+			}
+		}
+
+		for id := range section.ids {
+			claimed[id] = struct{}{}
+		}
+
+		for name := range section.names {
+			claimedNames[name] = struct{}{}
+		}
+
+		msg, err := n.buildSection(section.name, section.template, sectionData)
+		if err == nil && msg != "" {
+			parts = append(parts, msg)
+		}
+	}
+
+	rest := data
+	restReport := filterReport(data.Report, func(_ string, c types.ContainerReport) bool {
+		_, ok := claimed[c.ID()]
+
+		return !ok
+	})
+	rest.Report = restReport
+	rest.Entries = nil
+
+	for _, entry := range data.Entries {
+		if _, ok := claimedNames[entryContainer(entry)]; !ok {
+			rest.Entries = append(rest.Entries, entry)
+		}
+	}
+
+	var err error
+
+	if !restReport.empty() || len(rest.Entries) > 0 {
+		var msg string
+
+		msg, err = n.buildMessage(rest)
+		if msg != "" {
+			parts = append(parts, msg)
+		}
+	}
+
+	return strings.Join(parts, containerSectionSeparator), err
+}
+
+// buildSection renders data with a container template, or the global template
+// if it is missing or fails.
+//
+// Parameters:
+//   - name: Template name from the label.
+//   - tpl: Container template, or nil if it could not be resolved.
+//   - data: Notification data for the section.
+//
+// Returns:
+//   - string: Rendered section.
+//   - error: Non-nil if the global template fails as well.
+func (n *shoutrrrTypeNotifier) buildSection(name string, tpl *template.Template, data Data) (string, error) {
+	if tpl != nil {
+		msg, err := n.buildMessageWith(tpl, data)
+		if err == nil {
+			return msg, nil
+		}
+
+		n.ll().Warn().
+			Err(err).
+			Str("template", name).
+			Msg("Container notification template failed, falling back to the global template")
+	}
+
+	return n.buildMessage(data)
+}
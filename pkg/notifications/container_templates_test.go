@@ -0,0 +1,124 @@
+package notifications
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/rs/zerolog"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// containerTemplateTestGlobal lists updated containers, defines a "terse" template, and lists entries.
+const containerTemplateTestGlobal = `{{define "terse"}}{{range .Report.Updated}}{{.Name}} ok{{end}}{{end}}` +
+	`{{with .Report}}{{range .Updated}}updated:{{.Name}} {{end}}{{end}}` +
+	`{{range .Entries}}entry:{{.Message}} {{end}}`
+
+// templateTestReport builds a report with an updated container per name, labeled
+// with the notification template given for it, if any.
+func templateTestReport(names []string, templates map[string]string) types.Report {
+	log := testLogger()
+	progress := session.Progress{}
+
+	for _, name := range names {
+		c := mockActions.CreateMockContainer(name+"-id", "/"+name, "mock/"+name+":latest", time.Now())
+		if tpl, ok := templates[name]; ok {
+			c.ContainerInfo().Config.Labels[notificationTemplateLabel] = tpl
+		}
+
+		progress.AddScanned(log, c, types.ImageID("sha256:new-"+name), types.UpdateParams{})
+		progress.MarkForUpdate(log, c.ID())
+	}
+
+	return progress.Report(log)
+}
+
+// newTemplatedNotifier creates a report-mode notifier with container templates from dir.
+func newTemplatedNotifier(dir string) *shoutrrrTypeNotifier {
+	notifier := createNotifier(testLogger(), []string{"logger://"}, zerolog.InfoLevel,
+		containerTemplateTestGlobal, false, StaticData{}, false, 0)
+	notifier.containerTemplates = newContainerTemplates(notifier.ll(), dir, notifier.template)
+
+	return notifier
+}
+
+var _ = ginkgo.Describe("per-container notification templates", func() {
+	var dir string
+
+	ginkgo.BeforeEach(func() {
+		resetTestLogger()
+
+		dir = ginkgo.GinkgoT().TempDir()
+		gomega.Expect(os.WriteFile(filepath.Join(dir, "release-notes.tmpl"),
+			[]byte(`{{range .Report.Updated}}{{.Name}} has a new release{{end}}`), 0o600)).To(gomega.Succeed())
+	})
+
+	ginkgo.It("renders a split container report with the template file its label names", func() {
+		notifier := newTemplatedNotifier(dir)
+		full := templateTestReport([]string{"shop"}, map[string]string{"shop": "release-notes"})
+
+		notifier.sendEntries(nil, &session.SingleContainerReport{UpdatedReports: full.Updated()})
+
+		gomega.Expect(queued(notifier)).To(gomega.Equal("shop has a new release"))
+	})
+
+	ginkgo.It("renders labeled containers of a combined report in their own sections", func() {
+		notifier := newTemplatedNotifier(dir)
+		report := templateTestReport([]string{"shop", "dns", "proxy"}, map[string]string{
+			"shop": "release-notes",
+			"dns":  "terse",
+		})
+		entries := []*notificationEntry{
+			{Message: "pulled", Level: "info", Data: map[string]any{"container": "shop"}},
+			{Message: "restarted", Level: "info", Data: map[string]any{"container": "proxy"}},
+		}
+
+		notifier.sendEntries(entries, report)
+
+		gomega.Expect(queued(notifier)).To(gomega.Equal(
+			"dns ok\n\nshop has a new release\n\nupdated:proxy entry:restarted "))
+	})
+
+	ginkgo.It("falls back to the global template with a warning when the template is missing", func() {
+		notifier := newTemplatedNotifier(dir)
+		report := templateTestReport([]string{"shop"}, map[string]string{"shop": "unknown"})
+
+		notifier.sendEntries(nil, report)
+
+		gomega.Expect(queued(notifier)).To(gomega.Equal("updated:shop "))
+		gomega.Expect(testLogBuffer).To(gbytes.Say("Could not use container notification template"))
+	})
+
+	ginkgo.It("falls back to the global template when a template file is invalid or fails", func() {
+		gomega.Expect(os.WriteFile(filepath.Join(dir, "broken.tmpl"), []byte(`{{range}}`), 0o600)).
+			To(gomega.Succeed())
+		gomega.Expect(os.WriteFile(filepath.Join(dir, "failing.tmpl"), []byte(`{{.Missing}}`), 0o600)).
+			To(gomega.Succeed())
+
+		notifier := newTemplatedNotifier(dir)
+
+		notifier.sendEntries(nil, templateTestReport([]string{"shop"}, map[string]string{"shop": "broken"}))
+		gomega.Expect(queued(notifier)).To(gomega.Equal("updated:shop "))
+		gomega.Expect(testLogBuffer).To(gbytes.Say("failed to parse container template file"))
+
+		full := templateTestReport([]string{"shop"}, map[string]string{"shop": "failing"})
+		notifier.sendEntries(nil, &session.SingleContainerReport{UpdatedReports: full.Updated()})
+		gomega.Expect(queued(notifier)).To(gomega.Equal("updated:shop "))
+		gomega.Expect(testLogBuffer).To(gbytes.Say("Container notification template failed"))
+	})
+
+	ginkgo.It("does not read template names outside the template directory", func() {
+		notifier := newTemplatedNotifier(filepath.Join(dir, "nested"))
+		report := templateTestReport([]string{"shop"}, map[string]string{"shop": "../release-notes.tmpl"})
+
+		notifier.sendEntries(nil, report)
+
+		gomega.Expect(queued(notifier)).To(gomega.Equal("updated:shop "))
+	})
+})
@@ -0,0 +1,423 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// digestTemplateName is the builtin template used when no digest template is configured.
+const digestTemplateName = "digest"
+
+// stateFileMode is the permission mode of persisted notification state files.
+const stateFileMode = 0o600
+
+// digestCronParser parses digest schedules with the same syntax as --schedule.
+var digestCronParser = cron.NewParser(
+	cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+var (
+	// errDigestNotifier indicates the notifier cannot render digest messages.
+	errDigestNotifier = errors.New("digest notifications require a shoutrrr notifier")
+	// errInvalidDigestSchedule indicates the digest cron expression could not be parsed.
+	errInvalidDigestSchedule = errors.New("invalid notification digest schedule")
+)
+
+// Digest accumulates scan reports and sends one summary notification on its own schedule.
+//
+// Scans are recorded with Record, which also decides whether the scan's regular
+// notification is still sent. Run sends the digest through the notifier's URLs
+// each time the schedule fires. When a file is configured, the accumulated state
+// is written after every change so a restart does not lose it.
+type Digest struct {
+	log               *zerolog.Logger
+	notifier          *shoutrrrTypeNotifier
+	template          *template.Template
+	schedule          cron.Schedule
+	path              string
+	immediateFailures bool
+	now               func() time.Time
+
+	mutex sync.Mutex
+	state digestState
+}
+
+// digestState is the accumulated digest, persisted as JSON.
+type digestState struct {
+	Since         time.Time                 `json:"since"`
+	Scans         int                       `json:"scans"`
+	Updated       map[string]*DigestUpdate  `json:"updated,omitempty"`
+	Pending       map[string]*DigestPending `json:"pending,omitempty"`
+	Failures      map[string]*DigestFailure `json:"failures,omitempty"`
+	ScanErrors    int                       `json:"scan_errors,omitempty"`
+	LastScanError string                    `json:"last_scan_error,omitempty"`
+	CleanedImages int                       `json:"cleaned_images,omitempty"`
+}
+
+// NewDigest creates a digest that sends through the given notifier.
+//
+// Parameters:
+//   - log: Logger for digest diagnostics; should carry notify=no.
+//   - notifier: Notifier from NewNotifier whose URLs receive the digest.
+//   - cfg: Digest settings from config.Load (Config.Notify.Digest).
+//
+// Returns:
+//   - *Digest: The digest, with any state persisted in cfg.File restored.
+//   - error: Non-nil if the notifier, schedule, or template is unusable.
+func NewDigest(log *zerolog.Logger, notifier types.Notifier, cfg notifyConfig.Digest) (*Digest, error) {
+	shoutrrr, ok := notifier.(*shoutrrrTypeNotifier)
+	if !ok {
+		return nil, errDigestNotifier
+	}
+
+	schedule, err := digestCronParser.Parse(cfg.Schedule)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", errInvalidDigestSchedule, cfg.Schedule, err)
+	}
+
+	tplString := cfg.Template
+	if tplString == "" {
+		tplString = digestTemplateName
+	}
+
+	tpl, err := getShoutrrrTemplate(log, tplString, false)
+	if err != nil {
+		return nil, fmt.Errorf("digest template: %w", err)
+	}
+
+	digest := &Digest{
+		log:               log,
+		notifier:          shoutrrr,
+		template:          tpl,
+		schedule:          schedule,
+		path:              cfg.File,
+		immediateFailures: cfg.ImmediateFailures,
+		now:               time.Now,
+	}
+	digest.state = digest.load()
+
+	return digest, nil
+}
+
+// Record adds a scan to the digest and reports whether its regular notification is still sent.
+//
+// Updated containers are counted and drop out of the pending list, fresh containers
+// drop out of it, and stale containers join it with the time they were first seen.
+//
+// Parameters:
+//   - report: Scan report, or nil when the scan failed.
+//   - cleanedImages: Number of images removed by the scan's cleanup.
+//   - scanErr: Error that aborted the scan, or nil.
+//
+// Returns:
+//   - bool: True if the scan failed and immediate failures are enabled.
+func (d *Digest) Record(report types.Report, cleanedImages int, scanErr error) bool {
+	d.mutex.Lock()
+
+	now := d.now()
+	state := &d.state
+	state.Scans++
+	state.CleanedImages += cleanedImages
+
+	if scanErr != nil {
+		state.ScanErrors++
+		state.LastScanError = scanErr.Error()
+	}
+
+	failed := scanErr != nil
+
+	if report != nil {
+		for _, container := range report.Updated() {
+			update, ok := state.Updated[container.Name()]
+			if !ok {
+				update = &DigestUpdate{Name: container.Name()}
+				state.Updated[container.Name()] = update
+			}
+
+			update.ImageName = container.ImageName()
+			update.Count++
+			update.Last = now
+
+			delete(state.Pending, container.Name())
+		}
+
+		for _, container := range report.Fresh() {
+			delete(state.Pending, container.Name())
+		}
+
+		for _, container := range report.Stale() {
+			pending, ok := state.Pending[container.Name()]
+			if !ok {
+				pending = &DigestPending{Name: container.Name(), Since: now}
+				state.Pending[container.Name()] = pending
+			}
+
+			pending.ImageName = container.ImageName()
+			pending.LatestImageID = container.LatestImageID()
+		}
+
+		for _, container := range report.Failed() {
+			failure, ok := state.Failures[container.Name()]
+			if !ok {
+				failure = &DigestFailure{Name: container.Name(), First: now}
+				state.Failures[container.Name()] = failure
+			}
+
+			failure.ImageName = container.ImageName()
+			failure.Error = container.Error()
+			failure.Count++
+			failure.Last = now
+		}
+
+		failed = failed || len(report.Failed()) > 0
+	}
+
+	d.saveLocked()
+	d.mutex.Unlock()
+
+	return failed && d.immediateFailures
+}
+
+// Discard drops the log entries queued for the current scan so they are not sent.
+//
+// Call it instead of sending the scan's notification when Record holds it for the digest.
+func (d *Digest) Discard() {
+	notifier := d.notifier
+
+	notifier.entriesMutex.Lock()
+	dropped := len(notifier.entries)
+	notifier.entries = nil
+	notifier.entriesMutex.Unlock()
+
+	d.log.Debug().Int("entries_count", dropped).Msg("Holding scan notification for digest")
+}
+
+// Send renders the accumulated digest, queues it for delivery, and starts a new period.
+//
+// Pending updates carry over into the new period. Nothing is sent when no scan was
+// recorded, and a digest due inside quiet hours is held until they end.
+func (d *Digest) Send() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.state.Scans == 0 {
+		d.log.Debug().Msg("No scans recorded since the last digest, skipping send")
+
+		return
+	}
+
+	now := d.now()
+
+	var body bytes.Buffer
+
+	err := d.template.Execute(&body, d.dataLocked(now))
+	if err != nil {
+		d.log.Error().Err(err).Msg("Digest template error")
+
+		return
+	}
+
+	msg := body.String()
+	if strings.TrimSpace(msg) == "" {
+		d.log.Debug().Msg("Digest message empty, skipping send")
+	} else if note := (notification{Message: msg}); !d.notifier.holdForQuietHours(note, false) {
+		d.notifier.enqueue(note, 0)
+	}
+
+	d.state = newDigestState(now, d.state.Pending)
+	d.saveLocked()
+}
+
+// Run sends the digest each time the schedule fires until ctx is canceled.
+//
+// Parameters:
+//   - ctx: Process context; cancellation stops the schedule.
+func (d *Digest) Run(ctx context.Context) {
+	scheduler := cron.New(cron.WithChain(cron.Recover(cron.DefaultLogger)))
+	scheduler.Schedule(d.schedule, cron.FuncJob(d.Send))
+	scheduler.Start()
+
+	d.log.Info().
+		Time("next_digest", d.schedule.Next(d.now())).
+		Msg("Digest notifications scheduled")
+
+	<-ctx.Done()
+	<-scheduler.Stop().Done()
+}
+
+// dataLocked builds the template data for the accumulated digest. Callers hold mutex.
+//
+// Parameters:
+//   - now: End of the digest period.
+//
+// Returns:
+//   - DigestData: Digest template data with containers sorted by name.
+func (d *Digest) dataLocked(now time.Time) DigestData {
+	state := d.state
+
+	data := DigestData{
+		StaticData:    d.notifier.data,
+		Since:         state.Since,
+		Until:         now,
+		Scans:         state.Scans,
+		Updated:       make([]DigestUpdate, 0, len(state.Updated)),
+		Pending:       make([]DigestPending, 0, len(state.Pending)),
+		Failures:      make([]DigestFailure, 0, len(state.Failures)),
+		ScanErrors:    state.ScanErrors,
+		LastScanError: state.LastScanError,
+		CleanedImages: state.CleanedImages,
+	}
+
+	for _, update := range state.Updated {
+		data.Updated = append(data.Updated, *update)
+	}
+
+	for _, pending := range state.Pending {
+		item := *pending
+		item.Waiting = now.Sub(item.Since).Round(time.Minute)
+		data.Pending = append(data.Pending, item)
+	}
+
+	for _, failure := range state.Failures {
+		data.Failures = append(data.Failures, *failure)
+	}
+
+	slices.SortFunc(data.Updated, func(a, b DigestUpdate) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(data.Pending, func(a, b DigestPending) int { return strings.Compare(a.Name, b.Name) })
+	slices.SortFunc(data.Failures, func(a, b DigestFailure) int { return strings.Compare(a.Name, b.Name) })
+
+	return data
+}
+
+// load restores the persisted digest, starting a new one when none can be read.
+//
+// Returns:
+//   - digestState: Restored or new digest state.
+func (d *Digest) load() digestState {
+	fresh := newDigestState(d.now(), nil)
+
+	if d.path == "" {
+		return fresh
+	}
+
+	content, err := os.ReadFile(d.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fresh
+	}
+
+	if err != nil {
+		d.log.Warn().Err(err).Str("file", d.path).Msg("Failed to read digest file, starting a new digest")
+
+		return fresh
+	}
+
+	var state digestState
+
+	err = json.Unmarshal(content, &state)
+	if err != nil {
+		d.log.Warn().Err(err).Str("file", d.path).Msg("Failed to parse digest file, starting a new digest")
+
+		return fresh
+	}
+
+	restored := newDigestState(state.Since, state.Pending)
+	restored.Scans = state.Scans
+	restored.ScanErrors = state.ScanErrors
+	restored.LastScanError = state.LastScanError
+	restored.CleanedImages = state.CleanedImages
+
+	if state.Updated != nil {
+		restored.Updated = state.Updated
+	}
+
+	if state.Failures != nil {
+		restored.Failures = state.Failures
+	}
+
+	if restored.Since.IsZero() {
+		restored.Since = fresh.Since
+	}
+
+	d.log.Debug().Str("file", d.path).Int("scans", restored.Scans).Msg("Restored digest from file")
+
+	return restored
+}
+
+// saveLocked writes the digest to its file, if any. Callers hold mutex.
+func (d *Digest) saveLocked() {
+	if d.path == "" {
+		return
+	}
+
+	err := writeStateFile(d.path, d.state)
+	if err != nil {
+		d.log.Warn().Err(err).Str("file", d.path).Msg("Failed to save digest file")
+	}
+}
+
+// writeStateFile writes v as indented JSON to path.
+//
+// The file is replaced atomically so a crash mid-write keeps the previous contents.
+//
+// Parameters:
+//   - path: Destination file.
+//   - v: Value to encode.
+//
+// Returns:
+//   - error: Non-nil if encoding or writing fails.
+func writeStateFile(path string, v any) error {
+	content, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+
+	err = os.WriteFile(tmp, content, stateFileMode)
+	if err != nil {
+		return fmt.Errorf("write state: %w", err)
+	}
+
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("replace state: %w", err)
+	}
+
+	return nil
+}
+
+// newDigestState returns an empty digest period starting at since.
+//
+// Parameters:
+//   - since: Start of the digest period.
+//   - pending: Pending updates carried over from the previous period, or nil.
+//
+// Returns:
+//   - digestState: Empty digest state.
+func newDigestState(since time.Time, pending map[string]*DigestPending) digestState {
+	if pending == nil {
+		pending = make(map[string]*DigestPending)
+	}
+
+	return digestState{
+		Since:    since,
+		Updated:  make(map[string]*DigestUpdate),
+		Pending:  pending,
+		Failures: make(map[string]*DigestFailure),
+	}
+}
@@ -0,0 +1,192 @@
+package notifications
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/rs/zerolog"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+var errMockDigestScan = errors.New("docker unavailable")
+
+// digestTestReport builds a report where each named container ends up in the given state:
+// "updated", "stale", "fresh", or "failed".
+func digestTestReport(states map[string]string) types.Report {
+	log := testLogger()
+	progress := session.Progress{}
+
+	for name, state := range states {
+		c := mockActions.CreateMockContainer(name+"-id", "/"+name, "mock/"+name+":latest", time.Now())
+
+		newImage := types.ImageID("sha256:new-" + name)
+		if state == "fresh" {
+			newImage = c.ImageID()
+		}
+
+		progress.AddScanned(log, c, newImage, types.UpdateParams{})
+
+		switch state {
+		case "updated":
+			progress.MarkForUpdate(log, c.ID())
+		case "failed":
+			progress.UpdateFailed(log, map[types.ContainerID]error{c.ID(): errMockRoute})
+		}
+	}
+
+	return progress.Report(log)
+}
+
+// newTestDigest creates a digest on a report-mode notifier with a controllable clock.
+func newTestDigest(cfg notifyConfig.Digest, clock *time.Time) *Digest {
+	notifier := createNotifier(testLogger(), []string{"logger://"}, zerolog.InfoLevel,
+		"", false, StaticData{Host: "host"}, false, 0)
+
+	if cfg.Schedule == "" {
+		cfg.Schedule = "@daily"
+	}
+
+	digest, err := NewDigest(testLogger(), notifier, cfg)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	digest.now = func() time.Time { return *clock }
+	digest.state = digest.load()
+
+	return digest
+}
+
+var _ = ginkgo.Describe("notification digest", func() {
+	var clock time.Time
+
+	ginkgo.BeforeEach(func() {
+		resetTestLogger()
+
+		clock = time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	})
+
+	ginkgo.It("summarizes updates, pending updates, failures and cleanup across scans", func() {
+		digest := newTestDigest(notifyConfig.Digest{}, &clock)
+
+		digest.Record(digestTestReport(map[string]string{
+			"web": "updated", "db": "stale", "cache": "failed",
+		}), 2, nil)
+
+		clock = clock.Add(time.Hour)
+		digest.Record(digestTestReport(map[string]string{
+			"web": "updated", "db": "stale", "cache": "failed",
+		}), 1, nil)
+
+		clock = clock.Add(time.Hour)
+		digest.Record(nil, 0, errMockDigestScan)
+		digest.Send()
+
+		gomega.Expect(queued(digest.notifier)).To(gomega.Equal(
+			"3 scans since 2026-03-01 08:00 UTC: 1 Updated, 1 Pending, 1 Failed, 3 Images Cleaned\n" +
+				"- web (mock/web:latest): updated 2 times\n" +
+				"- db (mock/db:latest): update pending for 2h0m0s\n" +
+				"- cache (mock/cache:latest): failed 2 times: pull failed\n" +
+				"1 scan failed, last: docker unavailable"))
+	})
+
+	ginkgo.It("carries pending updates into the next digest until the container is updated", func() {
+		digest := newTestDigest(notifyConfig.Digest{}, &clock)
+
+		digest.Record(digestTestReport(map[string]string{"db": "stale", "web": "fresh"}), 0, nil)
+		digest.Send()
+		gomega.Expect(queued(digest.notifier)).To(gomega.ContainSubstring("db (mock/db:latest): update pending"))
+
+		clock = clock.Add(24 * time.Hour)
+		digest.Record(digestTestReport(map[string]string{"db": "stale"}), 0, nil)
+
+		data := digest.dataLocked(clock)
+		gomega.Expect(data.Scans).To(gomega.Equal(1))
+		gomega.Expect(data.Pending).To(gomega.HaveLen(1))
+		gomega.Expect(data.Pending[0].Waiting).To(gomega.Equal(24 * time.Hour))
+
+		digest.Record(digestTestReport(map[string]string{"db": "updated"}), 0, nil)
+		gomega.Expect(digest.dataLocked(clock).Pending).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("does not send a digest when no scan was recorded", func() {
+		digest := newTestDigest(notifyConfig.Digest{}, &clock)
+
+		digest.Send()
+
+		gomega.Expect(queued(digest.notifier)).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("lets failed scans notify immediately only when configured", func() {
+		failed := digestTestReport(map[string]string{"cache": "failed"})
+		updated := digestTestReport(map[string]string{"web": "updated"})
+
+		holding := newTestDigest(notifyConfig.Digest{}, &clock)
+		gomega.Expect(holding.Record(failed, 0, nil)).To(gomega.BeFalse())
+
+		immediate := newTestDigest(notifyConfig.Digest{ImmediateFailures: true}, &clock)
+		gomega.Expect(immediate.Record(failed, 0, nil)).To(gomega.BeTrue())
+		gomega.Expect(immediate.Record(nil, 0, errMockDigestScan)).To(gomega.BeTrue())
+		gomega.Expect(immediate.Record(updated, 0, nil)).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("discards the queued entries of a held scan", func() {
+		digest := newTestDigest(notifyConfig.Digest{}, &clock)
+		digest.notifier.StartNotification(false)
+		digest.notifier.entries = routeTestEntries()
+
+		digest.Discard()
+
+		gomega.Expect(digest.notifier.entries).To(gomega.BeNil())
+	})
+
+	ginkgo.It("restores the digest from its file after a restart", func() {
+		path := filepath.Join(ginkgo.GinkgoT().TempDir(), "digest.json")
+
+		first := newTestDigest(notifyConfig.Digest{File: path}, &clock)
+		first.Record(digestTestReport(map[string]string{"web": "updated", "db": "stale"}), 1, nil)
+
+		second := newTestDigest(notifyConfig.Digest{File: path}, &clock)
+		data := second.dataLocked(clock)
+		gomega.Expect(data.Scans).To(gomega.Equal(1))
+		gomega.Expect(data.Since).To(gomega.BeTemporally("==", clock))
+		gomega.Expect(data.Updated).To(gomega.HaveLen(1))
+		gomega.Expect(data.Pending).To(gomega.HaveLen(1))
+		gomega.Expect(data.CleanedImages).To(gomega.Equal(1))
+
+		second.Send()
+		gomega.Expect(queued(second.notifier)).NotTo(gomega.BeEmpty())
+
+		third := newTestDigest(notifyConfig.Digest{File: path}, &clock)
+		gomega.Expect(third.dataLocked(clock).Scans).To(gomega.BeZero())
+		gomega.Expect(third.dataLocked(clock).Pending).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("starts a new digest when the file cannot be parsed", func() {
+		path := filepath.Join(ginkgo.GinkgoT().TempDir(), "digest.json")
+		gomega.Expect(os.WriteFile(path, []byte("{broken"), 0o600)).To(gomega.Succeed())
+
+		digest := newTestDigest(notifyConfig.Digest{File: path}, &clock)
+
+		gomega.Expect(digest.dataLocked(clock).Scans).To(gomega.BeZero())
+		gomega.Eventually(testLogBuffer).Should(gbytes.Say("Failed to parse digest file"))
+	})
+
+	ginkgo.It("rejects invalid schedules and non-shoutrrr notifiers", func() {
+		notifier := createNotifier(testLogger(), []string{"logger://"}, zerolog.InfoLevel,
+			"", false, StaticData{}, false, 0)
+
+		_, err := NewDigest(testLogger(), notifier, notifyConfig.Digest{Schedule: "every day"})
+		gomega.Expect(err).To(gomega.MatchError(errInvalidDigestSchedule))
+
+		_, err = NewDigest(testLogger(), nil, notifyConfig.Digest{Schedule: "@daily"})
+		gomega.Expect(err).To(gomega.MatchError(errDigestNotifier))
+	})
+})
@@ -0,0 +1,484 @@
+package notifications
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+
+	"github.com/nicholas-fedor/shoutrrr"
+	"github.com/rs/zerolog"
+
+	shoutrrrTypes "github.com/nicholas-fedor/shoutrrr/pkg/types"
+
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// emailReportName is the file name of the attached JSON report.
+const emailReportName = "watchtower-report.json"
+
+// emailHTMLSeparator joins the HTML parts of notifications combined into one email.
+const emailHTMLSeparator = "\n<hr>\n"
+
+// emailMessage is the HTML email part of a notification.
+//
+// HTML email receives Text and HTML as multipart/alternative parts, with
+// Attachments alongside. Other services receive the notification's message.
+type emailMessage struct {
+	Text        string            `json:"text"`
+	HTML        string            `json:"html,omitempty"`
+	Attachments []emailAttachment `json:"attachments,omitempty"`
+}
+
+// emailAttachment is a file attached to an HTML email.
+type emailAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+}
+
+// emailSection is a report category rendered as one table by HTML email templates.
+type emailSection struct {
+	Title      string
+	State      string
+	Containers []types.ContainerReport
+}
+
+// htmlEmail renders and sends notifications as multipart HTML email to smtp:// URLs.
+//
+// It is shared by the root notifier and its route notifiers. Other service URLs
+// of the same notifier keep receiving the regular rendered message.
+type htmlEmail struct {
+	html         *htmltemplate.Template
+	text         *template.Template // nil uses the notifier's rendered message.
+	attachReport bool
+	senderLog    shoutrrrTypes.StdLogger
+	newSMTP      func(url string) (router, error)
+}
+
+// joinEmails combines the email parts of held notifications into one email.
+//
+// Notifications without email parts contribute their message as text and as
+// preformatted HTML, so the email still has one text and one HTML part, with
+// every attachment.
+//
+// Parameters:
+//   - msgs: Held notifications.
+//
+// Returns:
+//   - *emailMessage: Combined email parts, or nil when no notification has any.
+func joinEmails(msgs []notification) *emailMessage {
+	hasEmail := false
+
+	for _, msg := range msgs {
+		if msg.Email != nil {
+			hasEmail = true
+
+			break
+		}
+	}
+
+	if !hasEmail {
+		return nil
+	}
+
+	combined := &emailMessage{}
+
+	texts := make([]string, 0, len(msgs))
+	htmls := make([]string, 0, len(msgs))
+
+	for _, msg := range msgs {
+		email := emailMessage{Text: msg.Message}
+		if msg.Email != nil {
+			email = *msg.Email
+		}
+
+		if email.HTML == "" {
+			email.HTML = "<pre>" + htmltemplate.HTMLEscapeString(email.Text) + "</pre>"
+		}
+
+		texts = append(texts, email.Text)
+		htmls = append(htmls, email.HTML)
+		combined.Attachments = append(combined.Attachments, email.Attachments...)
+	}
+
+	combined.Text = strings.Join(texts, quietHoursSeparator)
+	combined.HTML = strings.Join(htmls, emailHTMLSeparator)
+
+	return combined
+}
+
+// newHTMLEmail parses the HTML email templates.
+//
+// Parameters:
+//   - log: Logger for template diagnostics.
+//   - cfg: HTML email settings from config.Load (Config.Notify.Email).
+//   - legacy: Whether the text template renders log entries only.
+//   - senderLog: Shoutrrr logger for the non-email service senders.
+//
+// Returns:
+//   - *htmlEmail: The HTML email renderer.
+//   - error: Non-nil if a configured template cannot be parsed.
+func newHTMLEmail(
+	log *zerolog.Logger,
+	cfg notifyConfig.Email,
+	legacy bool,
+	senderLog shoutrrrTypes.StdLogger,
+) (*htmlEmail, error) {
+	htmlString := cfg.HTMLTemplate
+	if htmlString == "" {
+		htmlString = defaultHTMLEmailTemplate
+	}
+
+	html, err := htmltemplate.New("email").Funcs(htmlEmailFuncs).Parse(htmlString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML email template: %w", err)
+	}
+
+	email := &htmlEmail{
+		html:         html,
+		attachReport: cfg.AttachReport,
+		senderLog:    senderLog,
+		newSMTP: func(url string) (router, error) {
+			return newSMTPSender(url, senderLog)
+		},
+	}
+
+	if cfg.TextTemplate != "" {
+		email.text, err = getShoutrrrTemplate(log, cfg.TextTemplate, legacy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email text template: %w", err)
+		}
+	}
+
+	return email, nil
+}
+
+// newSender creates the sender for one service URL.
+//
+// smtp:// URLs get an HTML email sender. Other URLs get a Shoutrrr sender,
+// which receives the regular rendered message.
+//
+// Parameters:
+//   - url: Service URL.
+//
+// Returns:
+//   - router: Sender for the URL.
+//   - error: Non-nil if the URL cannot be used.
+func (e *htmlEmail) newSender(url string) (router, error) {
+	if GetScheme(url) == smtpScheme {
+		return e.newSMTP(url)
+	}
+
+	sender, err := shoutrrr.NewSenderWithOptions(e.senderLog, shoutrrrTypes.SenderOptions{}, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize notification sender: %w", err)
+	}
+
+	return sender, nil
+}
+
+// newRouter creates a router that sends to each URL with its own sender.
+//
+// Parameters:
+//   - urls: Service URLs.
+//
+// Returns:
+//   - router: Router whose errors are indexed like urls.
+//   - error: Non-nil if a URL cannot be used.
+func (e *htmlEmail) newRouter(urls []string) (router, error) {
+	senders := make([]router, 0, len(urls))
+
+	for _, url := range urls {
+		sender, err := e.newSender(url)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", GetScheme(url), err)
+		}
+
+		senders = append(senders, sender)
+	}
+
+	return &emailRouter{senders: senders}, nil
+}
+
+// emailRouter sends a notification to each service URL with that URL's sender.
+type emailRouter struct {
+	senders []router
+}
+
+// Send sends message to every URL.
+//
+// Parameters:
+//   - message: Rendered notification.
+//   - params: Notification parameters.
+//
+// Returns:
+//   - []error: One error per URL, nil where delivery succeeded.
+func (r *emailRouter) Send(message string, params *shoutrrrTypes.Params) []error {
+	return r.sendNotification(notification{Message: message}, params)
+}
+
+// sendNotification sends msg to every URL; only smtp:// URLs receive its email parts.
+//
+// Parameters:
+//   - msg: Rendered notification.
+//   - params: Notification parameters.
+//
+// Returns:
+//   - []error: One error per URL, nil where delivery succeeded.
+func (r *emailRouter) sendNotification(msg notification, params *shoutrrrTypes.Params) []error {
+	errs := make([]error, len(r.senders))
+
+	for i, sender := range r.senders {
+		errs[i] = errors.Join(sendTo(sender, msg, params)...)
+	}
+
+	return errs
+}
+
+// useHTMLEmail renders this notifier's messages for HTML email and sends them
+// with a router that gives smtp:// URLs the email parts.
+//
+// Parameters:
+//   - email: Shared HTML email renderer.
+//
+// Returns:
+//   - error: Non-nil if a URL cannot be used.
+func (n *shoutrrrTypeNotifier) useHTMLEmail(email *htmlEmail) error {
+	router, err := email.newRouter(n.Urls)
+	if err != nil {
+		return err
+	}
+
+	n.Router = router
+	n.email = email
+
+	return nil
+}
+
+// composeEmail adds the HTML email parts to a rendered notification.
+//
+// A failing HTML or text template is logged and that part falls back to the
+// rendered message, so the email is still sent.
+//
+// Parameters:
+//   - data: Notification data.
+//   - msg: Message rendered with the notifier's template.
+//
+// Returns:
+//   - *emailMessage: The email parts.
+func (n *shoutrrrTypeNotifier) composeEmail(data Data, msg string) *emailMessage {
+	log := n.ll()
+	email := &emailMessage{Text: msg}
+
+	if n.email.text != nil {
+		text, err := n.buildMessageWith(n.email.text, data)
+		if err != nil {
+			log.Error().Err(err).Msg("Email text template failed, using the notification message")
+		} else {
+			email.Text = text
+		}
+	}
+
+	var body bytes.Buffer
+
+	err := n.email.html.Execute(&body, data)
+	if err != nil {
+		log.Error().Err(err).Msg("HTML email template failed, sending plain text only")
+	} else if content := strings.TrimSpace(body.String()); content != "" {
+		email.HTML = htmlEmailDocument(data.Title, content)
+	}
+
+	if n.email.attachReport {
+		report, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to attach notification report to email")
+		} else {
+			email.Attachments = []emailAttachment{{
+				Name:        emailReportName,
+				ContentType: "application/json",
+				Content:     string(report),
+			}}
+		}
+	}
+
+	return email
+}
+
+// messageFor returns the part of a rendered notification a service URL receives.
+//
+// Parameters:
+//   - url: Service URL.
+//   - msg: Rendered notification.
+//
+// Returns:
+//   - notification: msg for smtp:// URLs, otherwise msg without its email parts.
+func (n *shoutrrrTypeNotifier) messageFor(url string, msg notification) notification {
+	if GetScheme(url) != smtpScheme {
+		msg.Email = nil
+	}
+
+	return msg
+}
+
+// reportSections returns the non-empty report categories in the order HTML email shows them.
+//
+// Parameters:
+//   - report: Session report, or nil.
+//
+// Returns:
+//   - []emailSection: Sections with at least one container.
+func reportSections(report types.Report) []emailSection {
+	if report == nil {
+		return nil
+	}
+
+	var sections []emailSection
+
+	for _, section := range []emailSection{
+		{Title: "Failed", State: categoryFailed, Containers: report.Failed()},
+		{Title: "Updated", State: categoryUpdated, Containers: report.Updated()},
+		{Title: "Restarted", State: categoryRestarted, Containers: report.Restarted()},
+		{Title: "Update available", State: categoryStale, Containers: report.Stale()},
+		{Title: "Skipped", State: categorySkipped, Containers: report.Skipped()},
+		{Title: "Up to date", State: categoryFresh, Containers: report.Fresh()},
+	} {
+		if len(section.Containers) > 0 {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+// htmlEmailFuncs are the template functions of HTML email templates: Funcs plus ReportSections.
+var htmlEmailFuncs = func() htmltemplate.FuncMap {
+	funcs := htmltemplate.FuncMap{"ReportSections": reportSections}
+	for name, fn := range Funcs {
+		funcs[name] = fn
+	}
+
+	return funcs
+}()
+
+// htmlEmailDocument wraps the rendered HTML email template in a document with the shared styles.
+//
+// Parameters:
+//   - title: Notification title.
+//   - content: Rendered HTML email template.
+//
+// Returns:
+//   - string: Complete HTML document.
+func htmlEmailDocument(title, content string) string {
+	if strings.HasPrefix(strings.ToLower(content), "<!doctype") || strings.HasPrefix(strings.ToLower(content), "<html") {
+		return content
+	}
+
+	return `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>` + htmltemplate.HTMLEscapeString(title) + `</title>
+<style>` + htmlEmailStyles + `</style>
+</head>
+<body>
+` + content + `
+</body>
+</html>
+`
+}
+
+// htmlEmailStyles are the styles available to HTML email templates.
+//
+// Tables collapse into stacked rows on narrow screens. The state-* classes color
+// report categories and the level-* classes color log entries.
+const htmlEmailStyles = `
+body { margin: 0; padding: 16px; background: #f4f6f8; color: #1f2933;
+  font-family: -apple-system, "Segoe UI", Roboto, Helvetica, Arial, sans-serif; font-size: 14px; }
+.wt { max-width: 720px; margin: 0 auto; background: #ffffff; border-radius: 6px; padding: 20px; }
+h1 { font-size: 20px; margin: 0 0 8px; color: ` + ColorHex + `; }
+h2 { font-size: 16px; margin: 24px 0 8px; padding-left: 8px; border-left: 4px solid #9aa5b1; }
+.summary { margin: 0 0 8px; color: #52606d; }
+table { width: 100%; border-collapse: collapse; }
+th, td { text-align: left; padding: 6px 8px; border-bottom: 1px solid #e4e7eb; vertical-align: top; }
+th { background: #f5f7fa; font-weight: 600; }
+code { font-family: Menlo, Consolas, monospace; font-size: 12px; }
+.badge { display: inline-block; padding: 1px 8px; border-radius: 10px; color: #ffffff;
+  font-size: 12px; background: #9aa5b1; }
+.state-failed { border-color: #d64545; } .badge.state-failed { background: #d64545; }
+.state-updated { border-color: #3f9142; } .badge.state-updated { background: #3f9142; }
+.state-restarted { border-color: #2680c2; } .badge.state-restarted { background: #2680c2; }
+.state-stale { border-color: #de911d; } .badge.state-stale { background: #de911d; }
+.state-skipped { border-color: #7b8794; } .badge.state-skipped { background: #7b8794; }
+.state-fresh { border-color: #9aa5b1; } .badge.state-fresh { background: #9aa5b1; }
+.level-error, .level-fatal, .level-panic { color: #d64545; }
+.level-warning { color: #cb6e17; }
+details { margin-top: 24px; }
+summary { cursor: pointer; font-weight: 600; }
+@media (max-width: 600px) {
+  body { padding: 0; }
+  .wt { border-radius: 0; padding: 12px; }
+  thead { display: none; }
+  tr, td { display: block; width: auto; }
+  tr { border-bottom: 1px solid #e4e7eb; padding: 4px 0; }
+  td { border: none; padding: 2px 0; }
+}
+`
+
+// defaultHTMLEmailTemplate renders a table per report category with color-coded
+// states, followed by the log entries in a collapsible section.
+//
+// Without a report, for example when --notification-report is off, only the log
+// entries are shown, expanded.
+const defaultHTMLEmailTemplate = `
+<div class="wt">
+<h1>{{with .Title}}{{.}}{{else}}Watchtower updates{{end}}</h1>
+{{- with .Report}}
+<p class="summary">{{len .Scanned}} scanned, {{len .Updated}} updated, {{len .Failed}} failed
+{{- with .Stale}}, {{len .}} with an update available{{end}}</p>
+{{- range ReportSections .}}
+{{- $state := .State}}
+<h2 class="state-{{$state}}">{{.Title}} ({{len .Containers}})</h2>
+<table>
+<thead><tr><th>Container</th><th>Image</th><th>Status</th><th>Details</th></tr></thead>
+<tbody>
+{{- range .Containers}}
+<tr>
+<td>{{.Name}}</td>
+<td><code>{{.ImageName}}</code></td>
+<td><span class="badge state-{{$state}}">{{.State}}</span></td>
+<td>
+{{- if .Error}}{{.Error}}
+{{- else if eq $state "updated" "stale"}}
+{{- with ImageChange .}}{{.}}<br>{{end}}<code>{{.CurrentImageID.ShortID}} → {{.LatestImageID.ShortID}}</code>
+{{- end -}}
+</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- end}}
+{{- if .Entries}}
+<details{{if not .Report}} open{{end}}>
+<summary>Log entries ({{len .Entries}})</summary>
+<table>
+<tbody>
+{{- range .Entries}}
+<tr class="level-{{.Level}}">
+<td><code>{{.Time.Format "15:04:05"}}</code></td>
+<td>{{.Level}}</td>
+<td>{{.Message}}</td>
+</tr>
+{{- end}}
+</tbody>
+</table>
+</details>
+{{- end}}
+</div>
+`
@@ -0,0 +1,355 @@
+package notifications
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+
+	shoutrrrTypes "github.com/nicholas-fedor/shoutrrr/pkg/types"
+
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+)
+
+// emailTestURL is an smtp:// URL with two recipients.
+const emailTestURL = "smtp://mail.example.com:2525/?fromaddress=watchtower@example.com" +
+	"&fromname=Watchtower&toaddresses=ops@example.com,dev@example.com&encryption=None"
+
+// emailSender records the notifications sent to an smtp:// URL.
+type emailSender struct {
+	outboxSender
+
+	notifications []notification
+}
+
+func (s *emailSender) sendNotification(msg notification, params *shoutrrrTypes.Params) []error {
+	s.mutex.Lock()
+	s.notifications = append(s.notifications, msg)
+	s.mutex.Unlock()
+
+	return s.Send(msg.Message, params)
+}
+
+// queuedNotification returns the notification queued on a notifier, or an empty one if none.
+func queuedNotification(notifier *shoutrrrTypeNotifier) notification {
+	select {
+	case msg := <-notifier.messages:
+		return msg
+	default:
+		return notification{}
+	}
+}
+
+// newHTMLEmailNotifier creates a report-mode notifier sending to logger:// and an
+// smtp:// URL whose email sender is recorded.
+func newHTMLEmailNotifier(cfg notifyConfig.Email) (*shoutrrrTypeNotifier, *emailSender) {
+	notifier := createNotifier(testLogger(), []string{"logger://", emailTestURL}, zerolog.InfoLevel,
+		routeTestTemplate, false, StaticData{Title: "Watchtower updates on host"}, false, 0)
+
+	email, err := newHTMLEmail(notifier.ll(), cfg, false, notifier.senderLog)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+	sender := &emailSender{}
+	email.newSMTP = func(string) (router, error) { return sender, nil }
+
+	gomega.Expect(notifier.useHTMLEmail(email)).To(gomega.Succeed())
+
+	return notifier, sender
+}
+
+// emailParts reads the decoded parts of a multipart body by media type.
+func emailParts(body io.Reader, contentType string) map[string]string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	gomega.Expect(err).NotTo(gomega.HaveOccurred())
+	gomega.Expect(mediaType).To(gomega.HavePrefix("multipart/"))
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(body, params["boundary"])
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts
+		}
+
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if strings.HasPrefix(partType, "multipart/") {
+			for key, value := range emailParts(part, part.Header.Get("Content-Type")) {
+				parts[key] = value
+			}
+
+			continue
+		}
+
+		var content io.Reader = part
+		if part.Header.Get("Content-Transfer-Encoding") == "base64" {
+			content = base64.NewDecoder(base64.StdEncoding, part)
+		}
+
+		decoded, err := io.ReadAll(content)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		parts[partType] = string(decoded)
+	}
+}
+
+var _ = ginkgo.Describe("HTML email notifications", func() {
+	ginkgo.BeforeEach(func() {
+		resetTestLogger()
+	})
+
+	ginkgo.It("renders a report as color-coded tables with collapsible log entries", func() {
+		notifier, _ := newHTMLEmailNotifier(notifyConfig.Email{HTML: true})
+
+		notifier.sendEntries(routeTestEntries(), routeTestReport())
+
+		note := queuedNotification(notifier)
+		gomega.Expect(note.Message).To(gomega.ContainSubstring("updated:web"))
+		gomega.Expect(note.Email).NotTo(gomega.BeNil())
+
+		msg := note.Email
+		gomega.Expect(msg.Text).To(gomega.Equal(note.Message))
+		gomega.Expect(msg.HTML).To(gomega.HavePrefix("<!DOCTYPE html>"))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring(`<h1>Watchtower updates on host</h1>`))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring(`<h2 class="state-failed">Failed (1)</h2>`))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring(`<h2 class="state-updated">Updated (2)</h2>`))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring(`<span class="badge state-updated">Updated</span>`))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring("<summary>Log entries (4)</summary>"))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring(`<tr class="level-error">`))
+		gomega.Expect(msg.HTML).NotTo(gomega.ContainSubstring("<details open>"))
+		gomega.Expect(msg.Attachments).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("escapes values in the HTML part and expands entries without a report", func() {
+		notifier, _ := newHTMLEmailNotifier(notifyConfig.Email{HTML: true})
+
+		notifier.sendEntries(infoEntry("<script>alert(1)</script>"), nil)
+
+		msg := queuedNotification(notifier).Email
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring("<details open>"))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring("&lt;script&gt;alert(1)&lt;/script&gt;"))
+		gomega.Expect(msg.HTML).NotTo(gomega.ContainSubstring("<script>"))
+	})
+
+	ginkgo.It("uses separately configured text and HTML templates and attaches the report", func() {
+		notifier, _ := newHTMLEmailNotifier(notifyConfig.Email{
+			HTML:         true,
+			HTMLTemplate: `<p>{{len .Report.Updated}} updated</p>`,
+			TextTemplate: `{{len .Report.Updated}} containers updated`,
+			AttachReport: true,
+		})
+
+		notifier.sendEntries(nil, routeTestReport())
+
+		note := queuedNotification(notifier)
+		gomega.Expect(note.Message).To(gomega.ContainSubstring("failed:cache"))
+
+		msg := note.Email
+		gomega.Expect(msg.Text).To(gomega.Equal("2 containers updated"))
+		gomega.Expect(msg.HTML).To(gomega.ContainSubstring("<body>\n<p>2 updated</p>\n</body>"))
+		gomega.Expect(msg.Attachments).To(gomega.HaveLen(1))
+		gomega.Expect(msg.Attachments[0].Name).To(gomega.Equal(emailReportName))
+		gomega.Expect(msg.Attachments[0].Content).To(gomega.ContainSubstring(`"updated": [`))
+	})
+
+	ginkgo.It("sends the email parts to smtp URLs and the regular message to other services", func() {
+		notifier, email := newHTMLEmailNotifier(notifyConfig.Email{HTML: true})
+		other := &outboxSender{}
+		notifier.Router.(*emailRouter).senders[0] = other
+
+		notifier.sendEntries(infoEntry("web"), nil)
+		errs := sendTo(notifier.Router, queuedNotification(notifier), notifier.params)
+
+		gomega.Expect(errs).To(gomega.Equal([]error{nil, nil}))
+		gomega.Expect(other.messages()).To(gomega.Equal([]string{"entry:web "}))
+		gomega.Expect(email.notifications).To(gomega.HaveLen(1))
+		gomega.Expect(email.notifications[0].Email.HTML).To(gomega.ContainSubstring("web"))
+		gomega.Expect(notifier.messageFor("logger://", email.notifications[0])).
+			To(gomega.Equal(notification{Message: "entry:web "}))
+		gomega.Expect(notifier.messageFor(emailTestURL, email.notifications[0])).To(gomega.Equal(email.notifications[0]))
+	})
+
+	ginkgo.It("replays held and undelivered email notifications as plain messages to other services", func() {
+		notifier, _ := newHTMLEmailNotifier(notifyConfig.Email{HTML: true})
+		other := &outboxSender{}
+
+		notifier.sendEntries(infoEntry("web"), nil)
+		notifier.sendEntries(infoEntry("db"), nil)
+
+		held := joinMessages([]notification{queuedNotification(notifier), queuedNotification(notifier)})
+		gomega.Expect(held.Email).NotTo(gomega.BeNil())
+
+		gomega.Expect(sendTo(other, notifier.messageFor("logger://", held), notifier.params)).
+			To(gomega.Equal([]error{nil}))
+		gomega.Expect(other.messages()).To(gomega.Equal([]string{"entry:web \n\nentry:db "}))
+	})
+
+	ginkgo.It("combines held email notifications into one text and one HTML part", func() {
+		first := notification{Message: "web", Email: &emailMessage{
+			Text:        "web",
+			HTML:        "<p>web</p>",
+			Attachments: []emailAttachment{{Name: emailReportName}},
+		}}
+
+		joined := joinMessages([]notification{first, {Message: "db & cache"}})
+		gomega.Expect(joined.Message).To(gomega.Equal("web\n\ndb & cache"))
+
+		msg := joined.Email
+		gomega.Expect(msg.Text).To(gomega.Equal("web\n\ndb & cache"))
+		gomega.Expect(msg.HTML).To(gomega.Equal("<p>web</p>\n<hr>\n<pre>db &amp; cache</pre>"))
+		gomega.Expect(msg.Attachments).To(gomega.HaveLen(1))
+		gomega.Expect(joinMessages([]notification{{Message: "web"}, {Message: "db"}})).
+			To(gomega.Equal(notification{Message: "web\n\ndb"}))
+	})
+
+	ginkgo.It("composes multipart/alternative email with a JSON attachment", func() {
+		sender, err := newSMTPSender(emailTestURL, nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		config := sender.service.Config.Clone()
+		config.Subject = "Watchtower updates on hôst"
+
+		content, err := sender.compose(&config, emailMessage{
+			Text:        "2 updated — done",
+			HTML:        "<p>2 updated</p>",
+			Attachments: []emailAttachment{{Name: emailReportName, ContentType: "application/json", Content: `{"a":1}`}},
+		}, "ops@example.com")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		parsed, err := mail.ReadMessage(strings.NewReader(string(content)))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		gomega.Expect(subject).To(gomega.Equal("Watchtower updates on hôst"))
+		gomega.Expect(parsed.Header.Get("From")).To(gomega.Equal(`"Watchtower" <watchtower@example.com>`))
+		gomega.Expect(parsed.Header.Get("To")).To(gomega.Equal("ops@example.com"))
+		gomega.Expect(parsed.Header.Get("Content-Type")).To(gomega.HavePrefix("multipart/mixed"))
+
+		parts := emailParts(parsed.Body, parsed.Header.Get("Content-Type"))
+		gomega.Expect(parts).To(gomega.HaveKeyWithValue("text/plain", "2 updated — done"))
+		gomega.Expect(parts).To(gomega.HaveKeyWithValue("text/html", "<p>2 updated</p>"))
+		gomega.Expect(parts).To(gomega.HaveKeyWithValue("application/json", `{"a":1}`))
+	})
+
+	ginkgo.It("rejects smtp URLs without addresses or with invalid options", func() {
+		for _, url := range []string{
+			"smtp://mail.example.com/?to=ops@example.com",
+			"smtp://mail.example.com/?from=watchtower@example.com",
+			"smtp://mail.example.com/?from=a@example.com&to=b@example.com&encryption=Sometimes",
+		} {
+			_, err := newSMTPSender(url, nil)
+			gomega.Expect(err).To(gomega.HaveOccurred(), url)
+		}
+	})
+
+	ginkgo.It("accepts every option of Shoutrrr's smtp service", func() {
+		sender, err := newSMTPSender(emailTestURL+
+			"&auth=OAuth2&clienthost=mail-client&requirestarttls=yes&skiptlsverify=yes&timeout=5s&subject=Updates", nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		config := sender.service.Config
+		gomega.Expect(smtpAuth(config)).NotTo(gomega.BeNil())
+		gomega.Expect(config.ClientHost).To(gomega.Equal("mail-client"))
+		gomega.Expect(config.RequireStartTLS).To(gomega.BeTrue())
+		gomega.Expect(config.SkipTLSVerify).To(gomega.BeTrue())
+		gomega.Expect(config.Timeout).To(gomega.Equal(5 * time.Second))
+		gomega.Expect(config.Subject).To(gomega.Equal("Updates"))
+	})
+
+	ginkgo.It("delivers the email to every recipient over SMTP", func() {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+		ginkgo.DeferCleanup(listener.Close)
+
+		received := make(chan []string, 1)
+
+		go serveTestSMTP(listener, received)
+
+		url := "smtp://" + listener.Addr().String() +
+			"/?from=watchtower@example.com&to=ops@example.com,dev@example.com&encryption=None&auth=None"
+		sender, err := newSMTPSender(url, nil)
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		params := &shoutrrrTypes.Params{}
+		params.SetTitle("Watchtower updates")
+
+		errs := sender.sendNotification(
+			notification{Message: "ok", Email: &emailMessage{Text: "ok", HTML: "<p>ok</p>"}},
+			params,
+		)
+		gomega.Expect(errs).To(gomega.Equal([]error{nil}))
+
+		var commands []string
+		gomega.Eventually(received).WithTimeout(5 * time.Second).Should(gomega.Receive(&commands))
+		gomega.Expect(commands).To(gomega.ContainElements(
+			"MAIL FROM:<watchtower@example.com>",
+			"RCPT TO:<ops@example.com>",
+			"RCPT TO:<dev@example.com>",
+			"DATA",
+		))
+	})
+})
+
+// serveTestSMTP accepts one SMTP session and reports the commands it received.
+func serveTestSMTP(listener net.Listener, received chan<- []string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	var commands []string
+
+	reply("220 test ESMTP")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			received <- commands
+
+			return
+		}
+
+		command := strings.TrimRight(line, "\r\n")
+		commands = append(commands, command)
+
+		switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 test")
+		case "DATA":
+			reply("354 go ahead")
+
+			for {
+				data, err := reader.ReadString('\n')
+				if err != nil || data == ".\r\n" {
+					break
+				}
+			}
+
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+
+			received <- commands
+
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
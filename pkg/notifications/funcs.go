@@ -9,8 +9,16 @@ import (
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+
+	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
 
+// shortRevisionLength is the number of revision characters shown by ImageChange.
+const shortRevisionLength = 7
+
+// unknownImageValue stands in for metadata one of the images lacks in ImageChange.
+const unknownImageValue = "?"
+
 // Funcs defines utility functions for notification templates.
 var Funcs = template.FuncMap{
 	"ToUpper":         strings.ToUpper,
@@ -19,6 +27,7 @@ var Funcs = template.FuncMap{
 	"ToPorcelainJSON": ToPorcelainJSON,
 	"Title":           cases.Title(language.AmericanEnglish).String,
 	"RFC1123":         formatRFC1123,
+	"ImageChange":     imageChange,
 }
 
 // toJSON marshals a value to a formatted JSON string for use in templates.
@@ -42,3 +51,68 @@ func formatRFC1123(value string) string {
 
 	return timestamp.Format(time.RFC1123)
 }
+
+// imageChange describes how a container's image metadata changed, such as
+// "1.4.2 → 1.5.0 (abc1234 → def5678)".
+//
+// Revisions are shortened to seven characters. Missing values on one side are
+// shown as "?"; a part missing on both sides is left out.
+//
+// Parameters:
+//   - report: Container report.
+//
+// Returns:
+//   - string: Description of the change, or empty if neither image has version or revision labels.
+func imageChange(report types.ContainerReport) string {
+	if report == nil {
+		return ""
+	}
+
+	current := report.CurrentImageMetadata()
+	latest := report.LatestImageMetadata()
+
+	versions := changePart(current.Version, latest.Version)
+	revisions := changePart(shortRevision(current.Revision), shortRevision(latest.Revision))
+
+	switch {
+	case versions != "" && revisions != "":
+		return versions + " (" + revisions + ")"
+	case versions != "":
+		return versions
+	default:
+		return revisions
+	}
+}
+
+// changePart formats an old and new value as "old → new".
+//
+// Parameters:
+//   - from: Value of the current image.
+//   - to: Value of the latest image.
+//
+// Returns:
+//   - string: The change, or empty if both values are empty.
+func changePart(from, to string) string {
+	if from == "" && to == "" {
+		return ""
+	}
+
+	if from == "" {
+		from = unknownImageValue
+	}
+
+	if to == "" {
+		to = unknownImageValue
+	}
+
+	return from + " → " + to
+}
+
+// shortRevision shortens a source revision such as a commit hash for display.
+func shortRevision(revision string) string {
+	if len(revision) > shortRevisionLength {
+		return revision[:shortRevisionLength]
+	}
+
+	return revision
+}
@@ -0,0 +1,85 @@
+package notifications
+
+import (
+	"time"
+
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	dockerImage "github.com/moby/moby/api/types/image"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	"github.com/nicholas-fedor/watchtower/pkg/container"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// labeledImage returns image inspect data with the given ID and config labels.
+func labeledImage(id string, labels map[string]string) *dockerImage.InspectResponse {
+	return &dockerImage.InspectResponse{
+		ID:     id,
+		Config: &dockerspec.DockerOCIImageConfig{ImageConfig: ocispec.ImageConfig{Labels: labels}},
+	}
+}
+
+// imageMetadataReport builds a report with one updated container whose current and
+// latest images carry the given labels.
+func imageMetadataReport(current, latest map[string]string) types.Report {
+	log := testLogger()
+	progress := session.Progress{}
+
+	c := mockActions.CreateMockContainerWithImageInfoP("app-id", "/app", "example/app:latest",
+		time.Now(), labeledImage("sha256:old", current))
+	c.(*container.Container).SetLatestImageInfo(labeledImage("sha256:new", latest))
+
+	progress.AddScanned(log, c, "sha256:new", types.UpdateParams{})
+	progress.MarkForUpdate(log, c.ID())
+
+	return progress.Report(log)
+}
+
+var _ = ginkgo.Describe("template functions", func() {
+	ginkgo.Describe("ImageChange", func() {
+		ginkgo.It("shows version and shortened revision changes", func() {
+			report := imageMetadataReport(
+				map[string]string{types.ImageVersionLabel: "1.4.2", types.ImageRevisionLabel: "abc1234ffff"},
+				map[string]string{types.ImageVersionLabel: "1.5.0", types.ImageRevisionLabel: "def5678eeee"},
+			)
+
+			gomega.Expect(imageChange(report.Updated()[0])).To(gomega.Equal("1.4.2 → 1.5.0 (abc1234 → def5678)"))
+		})
+
+		ginkgo.It("marks values only one image has and leaves out missing parts", func() {
+			report := imageMetadataReport(
+				map[string]string{types.ImageRevisionLabel: "abc1234"},
+				map[string]string{types.ImageVersionLabel: "1.5.0", types.ImageRevisionLabel: "def5678"},
+			)
+			gomega.Expect(imageChange(report.Updated()[0])).To(gomega.Equal("? → 1.5.0 (abc1234 → def5678)"))
+
+			report = imageMetadataReport(
+				map[string]string{types.ImageVersionLabel: "1.4.2"},
+				map[string]string{types.ImageVersionLabel: "1.5.0"},
+			)
+			gomega.Expect(imageChange(report.Updated()[0])).To(gomega.Equal("1.4.2 → 1.5.0"))
+		})
+
+		ginkgo.It("is empty for images without version or revision labels", func() {
+			report := imageMetadataReport(nil, map[string]string{types.ImageSourceLabel: "https://example.com"})
+
+			gomega.Expect(imageChange(report.Updated()[0])).To(gomega.BeEmpty())
+			gomega.Expect(imageChange(nil)).To(gomega.BeEmpty())
+		})
+
+		ginkgo.It("is available to notification templates", func() {
+			data := Data{Report: imageMetadataReport(
+				map[string]string{types.ImageVersionLabel: "1.4.2"},
+				map[string]string{types.ImageVersionLabel: "1.5.0"},
+			)}
+
+			result := getTemplatedResult(`{{range .Report.Updated}}{{.Name}}: {{ImageChange .}}{{end}}`, false, data)
+
+			gomega.Expect(result).To(gomega.Equal("app: 1.4.2 → 1.5.0"))
+		})
+	})
+})
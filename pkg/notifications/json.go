@@ -46,7 +46,7 @@ func (d Data) MarshalJSON() ([]byte, error) {
 			"restarted": marshalReports(d.Report.Restarted()),
 			"failed":    marshalReports(d.Report.Failed()),
 			"skipped":   marshalReports(d.Report.Skipped()),
-			"stale":     marshalReports(d.Report.Stale()),
+			"stale":     marshalStaleReports(d.Report.Stale(), d.Notices),
 			"fresh":     marshalReports(d.Report.Fresh()),
 		}
 	}
@@ -68,6 +68,26 @@ func (d Data) MarshalJSON() ([]byte, error) {
 	return bytes, nil
 }
 
+// marshalStaleReports converts stale ContainerReports to JSON maps with their notice kind.
+//
+// Parameters:
+//   - reports: List of stale container reports.
+//   - notices: Notice kinds keyed by container name, or nil.
+//
+// Returns:
+//   - []jsonMap: JSON maps of report data, with "notice" set when known.
+func marshalStaleReports(reports []types.ContainerReport, notices map[string]string) []jsonMap {
+	jsonReports := marshalReports(reports)
+
+	for i, report := range reports {
+		if notice := notices[report.Name()]; notice != "" {
+			jsonReports[i]["notice"] = notice
+		}
+	}
+
+	return jsonReports
+}
+
 // marshalReports converts ContainerReport slice to JSON-compatible maps.
 //
 // Parameters:
@@ -90,6 +110,30 @@ func marshalReports(reports []types.ContainerReport) []jsonMap {
 			"state":          report.State(),
 		}
 
+		// Add OCI image metadata when the images carry it.
+		if current := report.CurrentImageMetadata(); !current.IsZero() {
+			jsonReports[i]["currentImage"] = current
+		}
+
+		if latest := report.LatestImageMetadata(); !latest.IsZero() {
+			jsonReports[i]["latestImage"] = latest
+		}
+
+		// Add lifecycle hook runs when hooks ran.
+		if runs := report.HookRuns(); len(runs) > 0 {
+			jsonReports[i]["hookRuns"] = runs
+		}
+
+		// Add the volume backup taken before the update.
+		if backupPath := report.BackupPath(); backupPath != "" {
+			jsonReports[i]["backupPath"] = backupPath
+		}
+
+		// Add the logs of a container that crashed after the update.
+		if crashLogs := report.CrashLogs(); len(crashLogs) > 0 {
+			jsonReports[i]["crashLogs"] = crashLogs
+		}
+
 		// Add error if present.
 		errorMessage := report.Error()
 		if errorMessage != "" {
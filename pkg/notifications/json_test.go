@@ -1,10 +1,14 @@
 package notifications
 
 import (
+	"encoding/json"
+	"time"
+
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
 
 	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
 
 var _ = ginkgo.Describe("JSON template", func() {
@@ -216,6 +220,81 @@ var _ = ginkgo.Describe("JSON template", func() {
 				gomega.Expect(result).To(gomega.ContainSubstring(`"host": "Test Host"`))
 			})
 
+			ginkgo.It("should include OCI image metadata when the images carry it", func() {
+				data := Data{Report: imageMetadataReport(
+					map[string]string{types.ImageVersionLabel: "1.4.2"},
+					map[string]string{
+						types.ImageVersionLabel:  "1.5.0",
+						types.ImageRevisionLabel: "def5678",
+						types.ImageSourceLabel:   "https://github.com/example/app",
+					},
+				)}
+				result := getTemplatedResult(`json.v1`, false, data)
+
+				var decoded struct {
+					Report struct {
+						Updated []struct {
+							CurrentImage types.ImageMetadata `json:"currentImage"`
+							LatestImage  types.ImageMetadata `json:"latestImage"`
+						} `json:"updated"`
+					} `json:"report"`
+				}
+				gomega.Expect(json.Unmarshal([]byte(result), &decoded)).To(gomega.Succeed())
+				gomega.Expect(decoded.Report.Updated).To(gomega.HaveLen(1))
+				gomega.Expect(decoded.Report.Updated[0].CurrentImage).
+					To(gomega.Equal(types.ImageMetadata{Version: "1.4.2"}))
+				gomega.Expect(decoded.Report.Updated[0].LatestImage).To(gomega.Equal(types.ImageMetadata{
+					Version:  "1.5.0",
+					Revision: "def5678",
+					Source:   "https://github.com/example/app",
+				}))
+			})
+
+			ginkgo.It("should omit OCI image metadata when the images lack it", func() {
+				result := getTemplatedResult(`json.v1`, false, mockDataFromStates(session.UpdatedState))
+
+				gomega.Expect(result).NotTo(gomega.ContainSubstring(`"currentImage"`))
+				gomega.Expect(result).NotTo(gomega.ContainSubstring(`"latestImage"`))
+			})
+
+			ginkgo.It("should include lifecycle hook runs", func() {
+				report := imageMetadataReport(nil, nil)
+				report.Updated()[0].(*session.ContainerStatus).SetHookRuns([]types.HookRun{{
+					Phase:    "pre-update",
+					Command:  "/backup.sh",
+					Duration: 1500 * time.Millisecond,
+					Output:   "done",
+				}})
+				result := getTemplatedResult(`json.v1`, false, Data{Report: report})
+
+				gomega.Expect(result).To(gomega.ContainSubstring(`"hookRuns"`))
+				gomega.Expect(result).To(gomega.ContainSubstring(`"command": "/backup.sh"`))
+				gomega.Expect(result).To(gomega.ContainSubstring(`"duration_ms": 1500`))
+				gomega.Expect(getTemplatedResult(`json.v1`, false, mockDataFromStates(session.UpdatedState))).
+					NotTo(gomega.ContainSubstring(`"hookRuns"`))
+			})
+
+			ginkgo.It("should include the volume backup path", func() {
+				report := imageMetadataReport(nil, nil)
+				report.Updated()[0].(*session.ContainerStatus).SetBackupPath("/srv/backups/db/20240101T120000Z")
+				result := getTemplatedResult(`json.v1`, false, Data{Report: report})
+
+				gomega.Expect(result).To(gomega.ContainSubstring(`"backupPath": "/srv/backups/db/20240101T120000Z"`))
+				gomega.Expect(getTemplatedResult(`json.v1`, false, mockDataFromStates(session.UpdatedState))).
+					NotTo(gomega.ContainSubstring(`"backupPath"`))
+			})
+
+			ginkgo.It("should include the logs of a container that crashed after the update", func() {
+				report := imageMetadataReport(nil, nil)
+				report.Updated()[0].(*session.ContainerStatus).SetCrashLogs([]string{"panic: missing DATABASE_URL"})
+				result := getTemplatedResult(`json.v1`, false, Data{Report: report})
+
+				gomega.Expect(result).To(gomega.ContainSubstring(`"crashLogs": [`))
+				gomega.Expect(result).To(gomega.ContainSubstring(`"panic: missing DATABASE_URL"`))
+				gomega.Expect(getTemplatedResult(`json.v1`, false, mockDataFromStates(session.UpdatedState))).
+					NotTo(gomega.ContainSubstring(`"crashLogs"`))
+			})
+
 			ginkgo.It("should validate notification formatting", func() {
 				data := mockDataFromStates(session.RestartedState)
 				result := getTemplatedResult(`json.v1`, false, data)
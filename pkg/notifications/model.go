@@ -29,6 +29,9 @@ type Data struct {
 
 	Entries []*notificationEntry
 	Report  types.Report
+	// Notices maps stale container names to NoticeNew, NoticeReminder, or NoticeRepeat
+	// when --notification-suppress-repeats is enabled.
+	Notices map[string]string
 }
 
 // levelToString converts a zerolog level to the legacy template string.
@@ -42,3 +45,49 @@ func levelToString(level zerolog.Level) string {
 
 	return level.String()
 }
+
+// DigestData is the digest notification template data model.
+//
+// It summarizes every scan recorded between Since and Until.
+type DigestData struct {
+	StaticData
+
+	Since         time.Time
+	Until         time.Time
+	Scans         int
+	Updated       []DigestUpdate
+	Pending       []DigestPending
+	Failures      []DigestFailure
+	ScanErrors    int
+	LastScanError string
+	CleanedImages int
+}
+
+// DigestUpdate is a container updated during the digest period.
+type DigestUpdate struct {
+	Name      string    `json:"name"`
+	ImageName string    `json:"image_name"`
+	Count     int       `json:"count"`
+	Last      time.Time `json:"last"`
+}
+
+// DigestPending is a container with an available update that has not been applied.
+//
+// Pending containers carry over between digests until they are updated or found fresh.
+type DigestPending struct {
+	Name          string        `json:"name"`
+	ImageName     string        `json:"image_name"`
+	LatestImageID types.ImageID `json:"latest_image_id"`
+	Since         time.Time     `json:"since"`
+	Waiting       time.Duration `json:"-"`
+}
+
+// DigestFailure is a container whose update failed during the digest period.
+type DigestFailure struct {
+	Name      string    `json:"name"`
+	ImageName string    `json:"image_name"`
+	Error     string    `json:"error"`
+	Count     int       `json:"count"`
+	First     time.Time `json:"first"`
+	Last      time.Time `json:"last"`
+}
@@ -105,7 +105,7 @@ func NewNotifier(log *zerolog.Logger, cfg notifyConfig.Notify) types.Notifier {
 			Msg("Notifier Shoutrrr URLs loaded")
 	}
 
-	return createNotifier(
+	notifier := createNotifier(
 		log,
 		urls,
 		logLevel,
@@ -115,6 +115,37 @@ func NewNotifier(log *zerolog.Logger, cfg notifyConfig.Notify) types.Notifier {
 		cfg.LogStdout,
 		delay,
 	)
+	if cfg.Email.HTML {
+		email, emailErr := newHTMLEmail(notifier.ll(), cfg.Email, legacyTemplate, notifier.senderLog)
+		if emailErr == nil {
+			emailErr = notifier.useHTMLEmail(email)
+		}
+
+		if emailErr != nil {
+			clog.Error().Err(emailErr).Msg("Failed to initialize HTML email notifications, sending plain-text email")
+		}
+	}
+
+	if cfg.SuppressRepeats {
+		notifier.announcements = newAnnouncements(notifier.ll(), cfg.SuppressRepeatsFile, cfg.ReminderInterval)
+	}
+
+	if cfg.Outbox.Dir != "" {
+		notifier.outbox = newOutbox(notifier.ll(), cfg.Outbox, notifier.senderLog, notifier.params)
+		if notifier.email != nil {
+			notifier.outbox.newSender = notifier.email.newSender
+		}
+
+		notifier.outbox.track(notifier.Urls)
+	}
+
+	notifier.containerTemplates = newContainerTemplates(notifier.ll(), cfg.TemplateDir, notifier.template)
+
+	notifier.addRoutes(cfg.Routes)
+	notifier.applyQuietHours(cfg.QuietHours)
+	notifier.restoreQuietHours()
+
+	return notifier
 }
 
 // NewNotifierFromFlags creates a notification client from Cobra flags.
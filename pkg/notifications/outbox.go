@@ -0,0 +1,592 @@
+package notifications
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/nicholas-fedor/shoutrrr"
+	"github.com/rs/zerolog"
+
+	shoutrrrTypes "github.com/nicholas-fedor/shoutrrr/pkg/types"
+
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+	"github.com/nicholas-fedor/watchtower/internal/metrics"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// outboxMaxBackoff caps the delay between retries of a service URL's backlog.
+const outboxMaxBackoff = time.Hour
+
+// outboxFlushTimeout bounds the best-effort outbox flush during Close.
+const outboxFlushTimeout = 10 * time.Second
+
+// outboxDirMode is the permission mode of a created outbox directory.
+const outboxDirMode = 0o700
+
+// outboxFileNameLength is the number of hex digits of the URL hash used as the backlog file name.
+const outboxFileNameLength = 16
+
+// OutboxMessage is an undelivered notification waiting in the outbox for a retry.
+type OutboxMessage struct {
+	// Service is the scheme of the service URL.
+	Service string `json:"service"`
+	// URL is the service URL without credentials, path, or query.
+	URL string `json:"url"`
+	// Message is the rendered notification.
+	Message string `json:"message"`
+	// Queued is when the first delivery attempt failed.
+	Queued time.Time `json:"queued"`
+	// Attempts counts failed deliveries of the service URL's oldest message.
+	Attempts int `json:"attempts"`
+	// NextAttempt is when the service URL's backlog is retried next.
+	NextAttempt time.Time `json:"next_attempt"`
+	// LastError is the most recent delivery error of the service URL.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// outboxEntry is a persisted undelivered message.
+type outboxEntry struct {
+	notification
+
+	Queued time.Time `json:"queued"`
+}
+
+// outboxQueue is the persisted backlog of one service URL.
+//
+// Messages are retried oldest first. Attempts and NextAttempt describe the
+// oldest message and reset whenever it is delivered or dropped.
+type outboxQueue struct {
+	Messages    []outboxEntry `json:"messages"`
+	Attempts    int           `json:"attempts"`
+	NextAttempt time.Time     `json:"next_attempt"`
+	LastError   string        `json:"last_error,omitempty"`
+}
+
+// outbox holds messages that service URLs failed to accept and retries them.
+//
+// Each service URL has its own backlog file in dir, named after a hash of the
+// URL so credentials never appear in file names. Backlogs are retried with
+// exponential backoff starting at retryInterval, and messages older than
+// maxAge are dropped. The outbox is shared by the root notifier and its route
+// notifiers.
+type outbox struct {
+	log           *zerolog.Logger
+	dir           string
+	maxAge        time.Duration
+	retryInterval time.Duration
+	params        *shoutrrrTypes.Params
+	now           func() time.Time
+	newSender     func(url string) (router, error)
+	wake          chan struct{}
+
+	// passMutex serializes retry passes so a backlog is never sent twice concurrently.
+	passMutex sync.Mutex
+
+	mutex   sync.Mutex
+	queues  map[string]*outboxQueue
+	senders map[string]router
+}
+
+// newOutbox creates an outbox that persists backlogs in cfg.Dir.
+//
+// Parameters:
+//   - log: Logger for outbox diagnostics; should carry notify=no.
+//   - cfg: Outbox settings from config.Load (Config.Notify.Outbox).
+//   - senderLog: Shoutrrr logger for the per-URL retry senders.
+//   - params: Notification parameters used for retries.
+//
+// Returns:
+//   - *outbox: The outbox.
+func newOutbox(
+	log *zerolog.Logger,
+	cfg notifyConfig.Outbox,
+	senderLog shoutrrrTypes.StdLogger,
+	params *shoutrrrTypes.Params,
+) *outbox {
+	err := os.MkdirAll(cfg.Dir, outboxDirMode)
+	if err != nil {
+		log.Warn().Err(err).Str("dir", cfg.Dir).Msg("Failed to create notification outbox directory")
+	}
+
+	return &outbox{
+		log:           log,
+		dir:           cfg.Dir,
+		maxAge:        cfg.MaxAge,
+		retryInterval: cfg.RetryInterval,
+		params:        params,
+		now:           time.Now,
+		newSender: func(url string) (router, error) {
+			return shoutrrr.NewSenderWithOptions(senderLog, shoutrrrTypes.SenderOptions{}, url)
+		},
+		wake:    make(chan struct{}, 1),
+		queues:  make(map[string]*outboxQueue),
+		senders: make(map[string]router),
+	}
+}
+
+// track restores the persisted backlogs of urls so they are retried.
+//
+// Parameters:
+//   - urls: Service URLs whose backlog files are read.
+func (o *outbox) track(urls []string) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for _, url := range urls {
+		queue := o.queueLocked(url)
+		if len(queue.Messages) > 0 {
+			o.log.Info().
+				Str("service", GetScheme(url)).
+				Int("messages", len(queue.Messages)).
+				Msg("Restored undelivered notifications from outbox")
+		}
+	}
+
+	o.reportDepthLocked()
+}
+
+// add holds a message that url failed to accept.
+//
+// Parameters:
+//   - url: Service URL that failed.
+//   - msg: Rendered message.
+//   - sendErr: Delivery error.
+func (o *outbox) add(url string, msg notification, sendErr error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	now := o.now()
+	queue := o.queueLocked(url)
+
+	if len(queue.Messages) == 0 {
+		queue.Attempts = 1
+		queue.NextAttempt = now.Add(o.backoff(queue.Attempts))
+	}
+
+	queue.Messages = append(queue.Messages, outboxEntry{notification: msg, Queued: now})
+	queue.LastError = sendErr.Error()
+
+	o.saveLocked(url, queue)
+	o.reportDepthLocked()
+
+	o.log.Warn().
+		Str("service", GetScheme(url)).
+		Str("url", sanitizeURLForLogging(url)).
+		Int("messages", len(queue.Messages)).
+		Time("next_attempt", queue.NextAttempt).
+		Msg("Holding undelivered notification in outbox for retry")
+}
+
+// delivered notes that url accepted a message, retrying its backlog right away.
+//
+// Parameters:
+//   - url: Service URL that accepted a message.
+func (o *outbox) delivered(url string) {
+	o.mutex.Lock()
+
+	queue, ok := o.queues[url]
+	pending := ok && len(queue.Messages) > 0
+
+	if pending {
+		queue.NextAttempt = o.now()
+	}
+
+	o.mutex.Unlock()
+
+	if pending {
+		select {
+		case o.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// retry attempts to deliver the held messages of each service URL, oldest first.
+//
+// A URL's backlog stops at its first failure, which doubles the delay before
+// its next attempt. Messages older than maxAge are dropped.
+//
+// Parameters:
+//   - ctx: Bounds the pass; a canceled send leaves its message queued.
+//   - force: Retry backlogs that are not yet due.
+func (o *outbox) retry(ctx context.Context, force bool) {
+	o.passMutex.Lock()
+	defer o.passMutex.Unlock()
+
+	o.mutex.Lock()
+	urls := make([]string, 0, len(o.queues))
+
+	for url, queue := range o.queues {
+		if len(queue.Messages) > 0 {
+			urls = append(urls, url)
+		}
+	}
+	o.mutex.Unlock()
+
+	slices.Sort(urls)
+
+	for _, url := range urls {
+		for ctx.Err() == nil {
+			if !o.retryNext(ctx, url, force) {
+				break
+			}
+		}
+	}
+}
+
+// retryNext attempts to deliver the oldest held message of url.
+//
+// Parameters:
+//   - ctx: Bounds the send.
+//   - url: Service URL whose backlog is retried.
+//   - force: Retry even when the backlog is not yet due.
+//
+// Returns:
+//   - bool: True if the message was delivered or dropped and the next one may be tried.
+func (o *outbox) retryNext(ctx context.Context, url string, force bool) bool {
+	o.mutex.Lock()
+
+	queue := o.queues[url]
+	if o.expireLocked(url, queue) {
+		o.saveLocked(url, queue)
+		o.reportDepthLocked()
+	}
+
+	if len(queue.Messages) == 0 || (!force && o.now().Before(queue.NextAttempt)) {
+		o.mutex.Unlock()
+
+		return false
+	}
+
+	msg := queue.Messages[0].notification
+	o.mutex.Unlock()
+
+	sendErr := o.deliver(ctx, url, msg)
+	if ctx.Err() != nil {
+		return false
+	}
+
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	// Only retry passes remove messages and passes are serialized, so the
+	// oldest message is still the one that was sent.
+	if sendErr != nil {
+		queue.Attempts++
+		queue.NextAttempt = o.now().Add(o.backoff(queue.Attempts))
+		queue.LastError = sendErr.Error()
+		o.saveLocked(url, queue)
+
+		o.log.Debug().
+			Err(sendErr).
+			Str("service", GetScheme(url)).
+			Int("attempts", queue.Attempts).
+			Time("next_attempt", queue.NextAttempt).
+			Msg("Outbox retry failed")
+
+		return false
+	}
+
+	queue.Messages = queue.Messages[1:]
+	queue.Attempts = 0
+	queue.LastError = ""
+	o.saveLocked(url, queue)
+	o.reportDepthLocked()
+
+	o.log.Info().
+		Str("service", GetScheme(url)).
+		Int("remaining", len(queue.Messages)).
+		Msg("Delivered notification from outbox")
+
+	return true
+}
+
+// deliver sends msg to url alone.
+//
+// Parameters:
+//   - ctx: Abandons the send when canceled.
+//   - url: Service URL.
+//   - msg: Message to send.
+//
+// Returns:
+//   - error: Non-nil if the sender could not be created or the service rejected the message.
+func (o *outbox) deliver(ctx context.Context, url string, msg notification) error {
+	o.mutex.Lock()
+
+	sender, ok := o.senders[url]
+	if !ok {
+		var err error
+
+		sender, err = o.newSender(url)
+		if err != nil {
+			o.mutex.Unlock()
+
+			return err
+		}
+
+		o.senders[url] = sender
+	}
+	o.mutex.Unlock()
+
+	errsCh := make(chan error, 1)
+
+	go func() {
+		errsCh <- errors.Join(sendTo(sender, msg, o.params)...)
+	}()
+
+	select {
+	case err := <-errsCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run retries due backlogs every retry interval, and right away when a
+// service URL with a backlog accepts a new message, until ctx is canceled.
+//
+// Parameters:
+//   - ctx: Notifier context.
+func (o *outbox) run(ctx context.Context) {
+	ticker := time.NewTicker(o.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		o.retry(ctx, false)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// messages returns every held message ordered by service URL and age.
+//
+// Returns:
+//   - []OutboxMessage: Held messages.
+func (o *outbox) messages() []OutboxMessage {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	urls := make([]string, 0, len(o.queues))
+	for url := range o.queues {
+		urls = append(urls, url)
+	}
+
+	slices.Sort(urls)
+
+	messages := []OutboxMessage{}
+
+	for _, url := range urls {
+		queue := o.queues[url]
+
+		for _, entry := range queue.Messages {
+			messages = append(messages, OutboxMessage{
+				Service:     GetScheme(url),
+				URL:         sanitizeURLForLogging(url),
+				Message:     entry.Message,
+				Queued:      entry.Queued,
+				Attempts:    queue.Attempts,
+				NextAttempt: queue.NextAttempt,
+				LastError:   queue.LastError,
+			})
+		}
+	}
+
+	return messages
+}
+
+// backoff returns the delay before the next retry after attempts failed deliveries.
+//
+// Parameters:
+//   - attempts: Failed deliveries of the oldest message, at least one.
+//
+// Returns:
+//   - time.Duration: retryInterval doubled per additional failure, capped at outboxMaxBackoff.
+func (o *outbox) backoff(attempts int) time.Duration {
+	delay := o.retryInterval
+
+	for range attempts - 1 {
+		if delay >= outboxMaxBackoff/2 {
+			return outboxMaxBackoff
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, outboxMaxBackoff)
+}
+
+// expireLocked drops the messages of queue older than maxAge. Callers hold mutex.
+//
+// Parameters:
+//   - url: Service URL of queue.
+//   - queue: Backlog to expire.
+//
+// Returns:
+//   - bool: True if any message was dropped.
+func (o *outbox) expireLocked(url string, queue *outboxQueue) bool {
+	if o.maxAge <= 0 {
+		return false
+	}
+
+	cutoff := o.now().Add(-o.maxAge)
+	expired := 0
+
+	for expired < len(queue.Messages) && queue.Messages[expired].Queued.Before(cutoff) {
+		expired++
+	}
+
+	if expired == 0 {
+		return false
+	}
+
+	queue.Messages = queue.Messages[expired:]
+	queue.Attempts = 0
+	queue.NextAttempt = o.now()
+
+	o.log.Warn().
+		Str("service", GetScheme(url)).
+		Int("dropped", expired).
+		Dur("max_age", o.maxAge).
+		Msg("Dropped undelivered notifications older than the outbox max age")
+
+	return true
+}
+
+// queueLocked returns the backlog of url, reading it from disk on first use. Callers hold mutex.
+//
+// Parameters:
+//   - url: Service URL.
+//
+// Returns:
+//   - *outboxQueue: The backlog.
+func (o *outbox) queueLocked(url string) *outboxQueue {
+	if queue, ok := o.queues[url]; ok {
+		return queue
+	}
+
+	queue := &outboxQueue{}
+	path := o.path(url)
+
+	content, err := os.ReadFile(path)
+	if err == nil {
+		err = json.Unmarshal(content, queue)
+	}
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		o.log.Warn().Err(err).Str("file", path).Msg("Failed to read notification outbox file, starting empty")
+
+		queue = &outboxQueue{}
+	}
+
+	o.queues[url] = queue
+
+	return queue
+}
+
+// saveLocked writes the backlog of url, removing the file once it is empty. Callers hold mutex.
+//
+// Parameters:
+//   - url: Service URL.
+//   - queue: Backlog to write.
+func (o *outbox) saveLocked(url string, queue *outboxQueue) {
+	path := o.path(url)
+
+	var err error
+	if len(queue.Messages) == 0 {
+		err = os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			err = nil
+		}
+	} else {
+		err = writeStateFile(path, queue)
+	}
+
+	if err != nil {
+		o.log.Warn().Err(err).Str("file", path).Msg("Failed to save notification outbox file")
+	}
+}
+
+// reportDepthLocked publishes the number of held messages as a metric. Callers hold mutex.
+func (o *outbox) reportDepthLocked() {
+	depth := 0
+	for _, queue := range o.queues {
+		depth += len(queue.Messages)
+	}
+
+	metrics.Default().SetNotificationOutboxDepth(depth)
+}
+
+// path returns the backlog file of url.
+//
+// Parameters:
+//   - url: Service URL.
+//
+// Returns:
+//   - string: File path named after a hash of url.
+func (o *outbox) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+
+	return filepath.Join(o.dir, hex.EncodeToString(sum[:])[:outboxFileNameLength]+".json")
+}
+
+// holdUndelivered hands the service URLs that rejected msg to the outbox.
+//
+// Parameters:
+//   - msg: Message that was sent.
+//   - errs: Router errors, indexed like n.Urls.
+func (n *shoutrrrTypeNotifier) holdUndelivered(msg notification, errs []error) {
+	if n.outbox == nil {
+		return
+	}
+
+	for i, url := range n.Urls {
+		if i < len(errs) && errs[i] != nil {
+			n.outbox.add(url, n.messageFor(url, msg), errs[i])
+		} else {
+			n.outbox.delivered(url)
+		}
+	}
+}
+
+// flushOutbox makes one best-effort attempt to deliver every held message.
+//
+// Route notifiers share the root notifier's outbox, so only the root flushes it.
+func (n *shoutrrrTypeNotifier) flushOutbox() {
+	if n.outbox == nil || !n.routing {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), outboxFlushTimeout)
+	defer cancel()
+
+	n.outbox.retry(ctx, true)
+}
+
+// OutboxMessages returns the notifications held in the notifier's outbox for a retry.
+//
+// Parameters:
+//   - notifier: Notifier from NewNotifier.
+//
+// Returns:
+//   - []OutboxMessage: Held messages, empty when the outbox is disabled.
+func OutboxMessages(notifier types.Notifier) []OutboxMessage {
+	n, ok := notifier.(*shoutrrrTypeNotifier)
+	if !ok || n.outbox == nil {
+		return []OutboxMessage{}
+	}
+
+	return n.outbox.messages()
+}
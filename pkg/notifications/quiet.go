@@ -0,0 +1,496 @@
+package notifications
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// quietHoursSeparator joins messages held during quiet hours into one notification.
+const quietHoursSeparator = "\n\n"
+
+// quietHours holds a notifier's messages produced inside a daily quiet window.
+//
+// Each notifier has its own quietHours so routes keep separate held messages.
+// With a store, held messages are persisted under key so a restart inside the
+// window keeps them.
+type quietHours struct {
+	windows       []notifyConfig.QuietWindow
+	location      *time.Location
+	allowFailures bool
+	now           func() time.Time
+	store         *quietStore
+	key           string
+
+	mutex sync.Mutex
+	held  []notification
+	timer *time.Timer
+}
+
+// newQuietHours creates quiet hours for windows read in the configured time zone.
+//
+// Parameters:
+//   - windows: Daily quiet windows.
+//   - cfg: Global quiet hours settings providing the time zone and failure handling.
+//
+// Returns:
+//   - *quietHours: The quiet hours, or nil when windows is empty.
+func newQuietHours(windows []notifyConfig.QuietWindow, cfg notifyConfig.QuietHours) *quietHours {
+	if len(windows) == 0 {
+		return nil
+	}
+
+	location := cfg.Location
+	if location == nil {
+		location = time.Local
+	}
+
+	return &quietHours{
+		windows:       windows,
+		location:      location,
+		allowFailures: cfg.AllowFailures,
+		now:           time.Now,
+	}
+}
+
+// clone returns quiet hours with the same windows and no held messages or store.
+//
+// Returns:
+//   - *quietHours: The copy, or nil when q is nil.
+func (q *quietHours) clone() *quietHours {
+	if q == nil {
+		return nil
+	}
+
+	return &quietHours{
+		windows:       q.windows,
+		location:      q.location,
+		allowFailures: q.allowFailures,
+		now:           q.now,
+	}
+}
+
+// active reports whether now falls inside a quiet window.
+//
+// Parameters:
+//   - now: Time to check.
+//
+// Returns:
+//   - bool: True inside a quiet window.
+//   - time.Time: End of the window containing now; for overlapping windows, the earliest end.
+func (q *quietHours) active(now time.Time) (bool, time.Time) {
+	local := now.In(q.location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, q.location)
+	offset := local.Sub(midnight)
+
+	var end time.Time
+
+	for _, window := range q.windows {
+		var windowEnd time.Time
+
+		switch {
+		case window.Start < window.End && offset >= window.Start && offset < window.End:
+			windowEnd = atOffset(midnight, window.End)
+		case window.Start >= window.End && offset >= window.Start:
+			windowEnd = atOffset(midnight.AddDate(0, 0, 1), window.End)
+		case window.Start >= window.End && offset < window.End:
+			windowEnd = atOffset(midnight, window.End)
+		default:
+			continue
+		}
+
+		if end.IsZero() || windowEnd.Before(end) {
+			end = windowEnd
+		}
+	}
+
+	return !end.IsZero(), end
+}
+
+// atOffset returns the wall clock time offset from midnight on the same day.
+//
+// The hour and minute are set through time.Date so daylight saving changes do not
+// shift the window.
+func atOffset(midnight time.Time, offset time.Duration) time.Time {
+	return time.Date(
+		midnight.Year(), midnight.Month(), midnight.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0,
+		midnight.Location(),
+	)
+}
+
+// take removes and returns the held messages combined into one message.
+//
+// Returns:
+//   - notification: Held messages combined by joinMessages, empty when none are held.
+func (q *quietHours) take() notification {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.timer != nil {
+		q.timer.Stop()
+		q.timer = nil
+	}
+
+	msg := joinMessages(q.held)
+	q.held = nil
+	q.store.save(q.key, nil)
+
+	return msg
+}
+
+// joinMessages combines held notifications into one, separated by blank lines.
+//
+// Email parts are combined by joinEmails.
+//
+// Parameters:
+//   - msgs: Held notifications.
+//
+// Returns:
+//   - notification: Combined notification, empty when msgs is empty.
+func joinMessages(msgs []notification) notification {
+	messages := make([]string, 0, len(msgs))
+	for _, msg := range msgs {
+		messages = append(messages, msg.Message)
+	}
+
+	return notification{
+		Message: strings.Join(messages, quietHoursSeparator),
+		Email:   joinEmails(msgs),
+	}
+}
+
+// isFailureNotification reports whether a notification is about a failure.
+//
+// Parameters:
+//   - entries: Log entries rendered into the notification.
+//   - report: Optional scan report.
+//
+// Returns:
+//   - bool: True if the report has failed containers or an entry is logged at error level or above.
+func isFailureNotification(entries []*notificationEntry, report types.Report) bool {
+	if report != nil && len(report.Failed()) > 0 {
+		return true
+	}
+
+	for _, entry := range entries {
+		switch entry.Level {
+		case zerolog.ErrorLevel.String(), zerolog.FatalLevel.String(), zerolog.PanicLevel.String():
+			return true
+		}
+	}
+
+	return false
+}
+
+// applyQuietHours sets up quiet hours on this notifier and its routes.
+//
+// Routes with their own quiet_hours use those windows, or none for "off"; other
+// routes use the global windows. Each notifier holds its own messages, persisted
+// in cfg.File when set; restoreQuietHours holds them again after a restart.
+//
+// Parameters:
+//   - cfg: Global quiet hours settings.
+func (n *shoutrrrTypeNotifier) applyQuietHours(cfg notifyConfig.QuietHours) {
+	var store *quietStore
+	if cfg.File != "" {
+		store = newQuietStore(n.ll(), cfg.File)
+	}
+
+	n.quiet = newQuietHours(cfg.Windows, cfg)
+	n.useQuietStore(store)
+
+	for _, route := range n.routes {
+		windows := cfg.Windows
+		if route.rule.QuietHours != "" {
+			windows = route.rule.QuietWindows
+		}
+
+		route.notifier.quiet = newQuietHours(windows, cfg)
+		route.notifier.useQuietStore(store)
+	}
+}
+
+// useQuietStore persists the notifier's held messages in store, keyed by its URLs.
+//
+// Parameters:
+//   - store: Quiet hours file, or nil to keep held messages in memory only.
+func (n *shoutrrrTypeNotifier) useQuietStore(store *quietStore) {
+	if n.quiet == nil || store == nil {
+		return
+	}
+
+	n.quiet.store = store
+	n.quiet.key = quietKey(n.Urls)
+}
+
+// restoreQuietHours holds the messages persisted before a restart again, on this notifier and its routes.
+func (n *shoutrrrTypeNotifier) restoreQuietHours() {
+	n.restoreHeld()
+
+	for _, route := range n.routes {
+		route.notifier.restoreHeld()
+	}
+}
+
+// restoreHeld holds the messages this notifier persisted before a restart again.
+//
+// Restored messages are released at the end of the current window, or right
+// away when no window is active.
+func (n *shoutrrrTypeNotifier) restoreHeld() {
+	quiet := n.quiet
+	if quiet == nil || quiet.store == nil {
+		return
+	}
+
+	held := quiet.store.load(quiet.key)
+	if len(held) == 0 {
+		return
+	}
+
+	now := quiet.now()
+
+	var delay time.Duration
+	if active, end := quiet.active(now); active {
+		delay = end.Sub(now)
+	}
+
+	quiet.mutex.Lock()
+	defer quiet.mutex.Unlock()
+
+	quiet.held = held
+
+	if quiet.timer == nil {
+		quiet.timer = time.AfterFunc(delay, n.releaseQuietHours)
+	}
+
+	n.ll().Debug().
+		Int("held", len(held)).
+		Dur("release_in", delay).
+		Msg("Restored notifications held for quiet hours")
+}
+
+// holdForQuietHours holds msg when it is produced inside a quiet window.
+//
+// The first held message schedules the release at the end of the window.
+// Failures are not held when quiet hours allow them through.
+//
+// Parameters:
+//   - msg: Rendered notification message.
+//   - failure: Whether the notification is about a failure.
+//
+// Returns:
+//   - bool: True if msg was held and must not be sent now.
+func (n *shoutrrrTypeNotifier) holdForQuietHours(msg notification, failure bool) bool {
+	quiet := n.quiet
+	if quiet == nil || (failure && quiet.allowFailures) {
+		return false
+	}
+
+	now := quiet.now()
+
+	active, end := quiet.active(now)
+	if !active {
+		return false
+	}
+
+	quiet.mutex.Lock()
+	defer quiet.mutex.Unlock()
+
+	quiet.held = append(quiet.held, msg)
+	quiet.store.save(quiet.key, quiet.held)
+
+	if quiet.timer == nil {
+		quiet.timer = time.AfterFunc(end.Sub(now), n.releaseQuietHours)
+	}
+
+	n.ll().Debug().
+		Int("held", len(quiet.held)).
+		Time("until", end).
+		Msg("Holding notification until quiet hours end")
+
+	return true
+}
+
+// releaseQuietHours sends the messages held during quiet hours as one notification.
+//
+// When a later quiet window has already begun, the release moves to its end.
+func (n *shoutrrrTypeNotifier) releaseQuietHours() {
+	quiet := n.quiet
+	now := quiet.now()
+
+	if active, end := quiet.active(now); active {
+		quiet.mutex.Lock()
+		quiet.timer = time.AfterFunc(end.Sub(now), n.releaseQuietHours)
+		quiet.mutex.Unlock()
+
+		return
+	}
+
+	msg := quiet.take()
+	if msg.Message == "" {
+		return
+	}
+
+	n.ll().Debug().Msg("Quiet hours ended, sending held notifications")
+	n.enqueue(msg, 0)
+}
+
+// flushQuietHours handles messages still held during quiet hours before the notifier shuts down.
+//
+// Called from Close after the send worker has exited. Inside a quiet window the
+// messages stay in the quiet hours file for the next start, or are dropped
+// without one; once the window has ended they are sent so they are not lost.
+func (n *shoutrrrTypeNotifier) flushQuietHours() {
+	quiet := n.quiet
+	if quiet == nil {
+		return
+	}
+
+	if active, end := quiet.active(quiet.now()); active {
+		quiet.mutex.Lock()
+		defer quiet.mutex.Unlock()
+
+		if quiet.timer != nil {
+			quiet.timer.Stop()
+			quiet.timer = nil
+		}
+
+		if len(quiet.held) == 0 {
+			return
+		}
+
+		if quiet.store != nil {
+			n.ll().Info().
+				Int("held", len(quiet.held)).
+				Time("until", end).
+				Msg("Keeping notifications held for quiet hours until after restart")
+		} else {
+			n.ll().Warn().
+				Int("held", len(quiet.held)).
+				Msg("Dropping notifications held for quiet hours at shutdown")
+		}
+
+		quiet.held = nil
+
+		return
+	}
+
+	msg := quiet.take()
+	if msg.Message == "" {
+		return
+	}
+
+	n.ll().Info().Msg("Sending notifications held for quiet hours before shutdown")
+	n.send(msg)
+}
+
+// quietStore persists the messages held during quiet hours in a JSON file.
+//
+// Held messages are keyed by a hash of the holding notifier's URLs so credentials
+// never appear in the file's keys. The store is shared by the root notifier and
+// its route notifiers.
+type quietStore struct {
+	log  *zerolog.Logger
+	path string
+
+	mutex sync.Mutex
+	held  map[string][]notification
+}
+
+// newQuietStore creates a store backed by path and reads the messages persisted in it.
+//
+// Parameters:
+//   - log: Logger for file errors.
+//   - path: JSON file holding the messages.
+//
+// Returns:
+//   - *quietStore: The store; empty if the file is missing or unreadable.
+func newQuietStore(log *zerolog.Logger, path string) *quietStore {
+	store := &quietStore{log: log, path: path, held: make(map[string][]notification)}
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store
+	}
+
+	if err == nil {
+		err = json.Unmarshal(content, &store.held)
+	}
+
+	if err != nil || store.held == nil {
+		log.Warn().Err(err).Str("file", path).Msg("Failed to read quiet hours file, starting without held notifications")
+
+		store.held = make(map[string][]notification)
+	}
+
+	return store
+}
+
+// load returns the messages persisted under key.
+//
+// Parameters:
+//   - key: Notifier key from quietKey.
+//
+// Returns:
+//   - []notification: Held messages, oldest first.
+func (s *quietStore) load(key string) []notification {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]notification(nil), s.held[key]...)
+}
+
+// save replaces the messages persisted under key and writes the file.
+//
+// A nil store ignores the call.
+//
+// Parameters:
+//   - key: Notifier key from quietKey.
+//   - held: Held messages; empty removes the key.
+func (s *quietStore) save(key string, held []notification) {
+	if s == nil {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(held) == 0 {
+		if _, ok := s.held[key]; !ok {
+			return
+		}
+
+		delete(s.held, key)
+	} else {
+		s.held[key] = append([]notification(nil), held...)
+	}
+
+	err := writeStateFile(s.path, s.held)
+	if err != nil {
+		s.log.Warn().Err(err).Str("file", s.path).Msg("Failed to save quiet hours file")
+	}
+}
+
+// quietKey returns the store key of a notifier with urls.
+//
+// Parameters:
+//   - urls: Service URLs of the notifier.
+//
+// Returns:
+//   - string: Hex hash of the URLs.
+func quietKey(urls []string) string {
+	sum := sha256.Sum256([]byte(strings.Join(urls, "\n")))
+
+	return hex.EncodeToString(sum[:])[:outboxFileNameLength]
+}
@@ -0,0 +1,222 @@
+package notifications
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+)
+
+// overnightQuietHours is a 22:00-07:00 quiet window read in UTC.
+func overnightQuietHours(allowFailures bool) notifyConfig.QuietHours {
+	return notifyConfig.QuietHours{
+		Windows:       []notifyConfig.QuietWindow{{Start: 22 * time.Hour, End: 7 * time.Hour}},
+		Location:      time.UTC,
+		AllowFailures: allowFailures,
+	}
+}
+
+// newQuietNotifier creates a notifier with quiet hours, the given routes, and a controllable clock.
+func newQuietNotifier(cfg notifyConfig.QuietHours, clock *time.Time, routes ...notifyConfig.Route) *shoutrrrTypeNotifier {
+	notifier := newRoutedNotifier(routes...)
+	notifier.applyQuietHours(cfg)
+
+	for _, quiet := range []*quietHours{notifier.quiet, routeQuietHours(notifier)} {
+		if quiet != nil {
+			quiet.now = func() time.Time { return *clock }
+		}
+	}
+
+	notifier.restoreQuietHours()
+
+	return notifier
+}
+
+// routeQuietHours returns the quiet hours of the notifier's first route, if any.
+func routeQuietHours(notifier *shoutrrrTypeNotifier) *quietHours {
+	if len(notifier.routes) == 0 {
+		return nil
+	}
+
+	return notifier.routes[0].notifier.quiet
+}
+
+// infoEntry returns a non-failure entry with message.
+func infoEntry(message string) []*notificationEntry {
+	return []*notificationEntry{{Message: message, Level: "info", Data: map[string]any{}}}
+}
+
+var _ = ginkgo.Describe("quiet hours", func() {
+	var clock time.Time
+
+	ginkgo.BeforeEach(func() {
+		resetTestLogger()
+
+		clock = time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	})
+
+	ginkgo.It("holds notifications inside the window and sends them combined when it ends", func() {
+		notifier := newQuietNotifier(overnightQuietHours(false), &clock)
+
+		notifier.sendEntries(infoEntry("web"), nil)
+		notifier.sendEntries(infoEntry("db"), nil)
+		gomega.Expect(queued(notifier)).To(gomega.BeEmpty())
+
+		clock = time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+		notifier.releaseQuietHours()
+		gomega.Expect(queued(notifier)).To(gomega.Equal("entry:web \n\nentry:db "))
+		gomega.Expect(notifier.quiet.timer).To(gomega.BeNil())
+
+		notifier.sendEntries(infoEntry("cache"), nil)
+		gomega.Expect(queued(notifier)).To(gomega.Equal("entry:cache "))
+	})
+
+	ginkgo.It("lets failures through only when allowed", func() {
+		failure := []*notificationEntry{{Message: "pull failed", Level: "error", Data: map[string]any{}}}
+
+		held := newQuietNotifier(overnightQuietHours(false), &clock)
+		held.sendEntries(failure, nil)
+		gomega.Expect(queued(held)).To(gomega.BeEmpty())
+
+		allowed := newQuietNotifier(overnightQuietHours(true), &clock)
+		allowed.sendEntries(failure, nil)
+		gomega.Expect(queued(allowed)).To(gomega.Equal("entry:pull failed "))
+
+		allowed.sendEntries(nil, routeTestReport())
+		gomega.Expect(queued(allowed)).To(gomega.ContainSubstring("failed:cache"))
+
+		allowed.sendEntries(infoEntry("web"), nil)
+		gomega.Expect(queued(allowed)).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("reads windows that span midnight in the configured time zone", func() {
+		zone := time.FixedZone("UTC+2", 2*60*60)
+		cfg := overnightQuietHours(false)
+		cfg.Location = zone
+		quiet := newQuietHours(cfg.Windows, cfg)
+
+		active, end := quiet.active(time.Date(2026, 3, 1, 20, 30, 0, 0, time.UTC))
+		gomega.Expect(active).To(gomega.BeTrue())
+		gomega.Expect(end).To(gomega.BeTemporally("==", time.Date(2026, 3, 2, 7, 0, 0, 0, zone)))
+
+		active, end = quiet.active(time.Date(2026, 3, 2, 4, 59, 0, 0, time.UTC))
+		gomega.Expect(active).To(gomega.BeTrue())
+		gomega.Expect(end).To(gomega.BeTemporally("==", time.Date(2026, 3, 2, 7, 0, 0, 0, zone)))
+
+		active, _ = quiet.active(time.Date(2026, 3, 2, 5, 0, 0, 0, time.UTC))
+		gomega.Expect(active).To(gomega.BeFalse())
+	})
+
+	ginkgo.It("moves the release to the end of a window that has already begun", func() {
+		cfg := overnightQuietHours(false)
+		cfg.Windows = append(cfg.Windows, notifyConfig.QuietWindow{Start: 6 * time.Hour, End: 9 * time.Hour})
+		notifier := newQuietNotifier(cfg, &clock)
+
+		notifier.sendEntries(infoEntry("web"), nil)
+
+		clock = time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+		notifier.releaseQuietHours()
+		gomega.Expect(queued(notifier)).To(gomega.BeEmpty())
+
+		clock = time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+		notifier.releaseQuietHours()
+		gomega.Expect(queued(notifier)).To(gomega.Equal("entry:web "))
+	})
+
+	ginkgo.It("applies a route's own quiet hours", func() {
+		notifier := newQuietNotifier(overnightQuietHours(false), &clock, notifyConfig.Route{
+			Name:       "web",
+			Containers: []string{"web"},
+			URLs:       []string{"logger://"},
+			QuietHours: "off",
+		})
+
+		notifier.sendEntries(routeTestEntries(), nil)
+
+		gomega.Expect(routeQuietHours(notifier)).To(gomega.BeNil())
+		gomega.Expect(queued(notifier.routes[0].notifier)).To(gomega.Equal("entry:web-info "))
+		gomega.Expect(queued(notifier)).To(gomega.BeEmpty())
+		gomega.Expect(notifier.quiet.held).To(gomega.HaveLen(1))
+	})
+
+	ginkgo.It("drops held notifications when the notifier closes inside the window", func() {
+		notifier := newQuietNotifier(overnightQuietHours(false), &clock)
+		sender := &outboxSender{}
+		notifier.Router = sender
+
+		notifier.sendEntries(infoEntry("web"), nil)
+		notifier.Close()
+
+		gomega.Expect(sender.messages()).To(gomega.BeEmpty())
+		gomega.Expect(notifier.quiet.held).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("sends held notifications on close once the window has ended", func() {
+		notifier := newQuietNotifier(overnightQuietHours(false), &clock)
+		sender := &outboxSender{}
+		notifier.Router = sender
+
+		notifier.sendEntries(infoEntry("web"), nil)
+
+		clock = time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+		notifier.Close()
+
+		gomega.Expect(sender.messages()).To(gomega.Equal([]string{"entry:web "}))
+	})
+
+	ginkgo.It("keeps held notifications in the quiet hours file across a restart", func() {
+		cfg := overnightQuietHours(false)
+		cfg.File = filepath.Join(ginkgo.GinkgoT().TempDir(), "quiet.json")
+
+		first := newQuietNotifier(cfg, &clock)
+		sender := &outboxSender{}
+		first.Router = sender
+
+		first.sendEntries(infoEntry("web"), nil)
+		first.Close()
+		gomega.Expect(sender.messages()).To(gomega.BeEmpty())
+
+		clock = time.Date(2026, 3, 1, 4, 0, 0, 0, time.UTC)
+		second := newQuietNotifier(cfg, &clock)
+		gomega.Expect(second.quiet.held).To(gomega.Equal([]notification{{Message: "entry:web "}}))
+		gomega.Expect(second.quiet.timer).NotTo(gomega.BeNil())
+
+		clock = time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+		second.releaseQuietHours()
+		gomega.Expect(queued(second)).To(gomega.Equal("entry:web "))
+
+		third := newQuietNotifier(cfg, &clock)
+		gomega.Expect(third.quiet.held).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("holds digests inside the window", func() {
+		notifier := newQuietNotifier(overnightQuietHours(true), &clock)
+
+		digest, err := NewDigest(testLogger(), notifier, notifyConfig.Digest{Schedule: "@daily"})
+		gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+		digest.Record(digestTestReport(map[string]string{"web": "updated"}), 0, nil)
+		digest.Send()
+		gomega.Expect(queued(notifier)).To(gomega.BeEmpty())
+		gomega.Expect(notifier.quiet.held).To(gomega.HaveLen(1))
+
+		clock = time.Date(2026, 3, 1, 7, 0, 0, 0, time.UTC)
+		notifier.releaseQuietHours()
+		gomega.Expect(queued(notifier)).To(gomega.ContainSubstring("web"))
+	})
+
+	ginkgo.It("leaves notifiers without quiet hours alone", func() {
+		notifier := createNotifier(testLogger(), []string{"logger://"}, zerolog.InfoLevel,
+			routeTestTemplate, false, StaticData{}, false, 0)
+		notifier.applyQuietHours(notifyConfig.QuietHours{})
+
+		notifier.sendEntries(infoEntry("web"), nil)
+
+		gomega.Expect(notifier.quiet).To(gomega.BeNil())
+		gomega.Expect(queued(notifier)).To(gomega.Equal("entry:web "))
+	})
+})
@@ -0,0 +1,730 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/nicholas-fedor/shoutrrr"
+	"github.com/rs/zerolog"
+
+	shoutrrrTypes "github.com/nicholas-fedor/shoutrrr/pkg/types"
+
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+	"github.com/nicholas-fedor/watchtower/internal/logging"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// notificationURLLabel holds space-separated Shoutrrr URLs that receive a container's notifications.
+const notificationURLLabel = "com.centurylinklabs.watchtower.notification-url"
+
+// composeProjectLabel is the Docker Compose project label matched by route projects.
+const composeProjectLabel = "com.docker.compose.project"
+
+// Report categories matched by route categories.
+const (
+	categoryScanned   = "scanned"
+	categoryUpdated   = "updated"
+	categoryFailed    = "failed"
+	categorySkipped   = "skipped"
+	categoryStale     = "stale"
+	categoryFresh     = "fresh"
+	categoryRestarted = "restarted"
+)
+
+// labeledContainerReport is implemented by container reports that carry container labels.
+type labeledContainerReport interface {
+	Labels() map[string]string
+}
+
+// notificationRoute is a configured routing rule and the notifier that delivers its messages.
+type notificationRoute struct {
+	rule     notifyConfig.Route    // Matching criteria and destination.
+	level    zerolog.Level         // Minimum level of routed log entries.
+	notifier *shoutrrrTypeNotifier // Delivers the route's messages.
+}
+
+// routedReport is a subset of a session report delivered to one route.
+type routedReport struct {
+	scanned   []types.ContainerReport
+	updated   []types.ContainerReport
+	failed    []types.ContainerReport
+	skipped   []types.ContainerReport
+	stale     []types.ContainerReport
+	fresh     []types.ContainerReport
+	restarted []types.ContainerReport
+}
+
+// Scanned returns scanned containers.
+func (r *routedReport) Scanned() []types.ContainerReport { return r.scanned }
+
+// Updated returns updated containers.
+func (r *routedReport) Updated() []types.ContainerReport { return r.updated }
+
+// Failed returns failed containers.
+func (r *routedReport) Failed() []types.ContainerReport { return r.failed }
+
+// Skipped returns skipped containers.
+func (r *routedReport) Skipped() []types.ContainerReport { return r.skipped }
+
+// Stale returns stale containers.
+func (r *routedReport) Stale() []types.ContainerReport { return r.stale }
+
+// Fresh returns fresh containers.
+func (r *routedReport) Fresh() []types.ContainerReport { return r.fresh }
+
+// Restarted returns restarted containers.
+func (r *routedReport) Restarted() []types.ContainerReport { return r.restarted }
+
+// All returns unique containers, keeping each in its most significant category
+// in the same priority order as the session report.
+//
+// Returns:
+//   - []types.ContainerReport: Deduplicated containers.
+func (r *routedReport) All() []types.ContainerReport {
+	all := make([]types.ContainerReport, 0, reportCategoryCount(r))
+	seen := make(map[types.ContainerID]struct{})
+
+	for _, category := range [][]types.ContainerReport{
+		r.updated, r.restarted, r.failed, r.skipped, r.stale, r.fresh, r.scanned,
+	} {
+		for _, c := range category {
+			if _, ok := seen[c.ID()]; ok {
+				continue
+			}
+
+			seen[c.ID()] = struct{}{}
+			all = append(all, c)
+		}
+	}
+
+	return all
+}
+
+// empty reports whether no category holds a container.
+//
+// Returns:
+//   - bool: True if the report has no containers.
+func (r *routedReport) empty() bool {
+	return reportCategoryCount(r) == 0
+}
+
+// filterReport returns the containers of report accepted by keep.
+//
+// Parameters:
+//   - report: Source report, or nil.
+//   - keep: Decides per category and container whether it is included.
+//
+// Returns:
+//   - *routedReport: Filtered report, or nil if report is nil.
+func filterReport(report types.Report, keep func(category string, c types.ContainerReport) bool) *routedReport {
+	if report == nil {
+		return nil
+	}
+
+	filter := func(category string, reports []types.ContainerReport) []types.ContainerReport {
+		var kept []types.ContainerReport
+
+		for _, c := range reports {
+			if keep(category, c) {
+				kept = append(kept, c)
+			}
+		}
+
+		return kept
+	}
+
+	return &routedReport{
+		scanned:   filter(categoryScanned, report.Scanned()),
+		updated:   filter(categoryUpdated, report.Updated()),
+		failed:    filter(categoryFailed, report.Failed()),
+		skipped:   filter(categorySkipped, report.Skipped()),
+		stale:     filter(categoryStale, report.Stale()),
+		fresh:     filter(categoryFresh, report.Fresh()),
+		restarted: filter(categoryRestarted, report.Restarted()),
+	}
+}
+
+// containerLabels returns a container report's labels when available.
+//
+// Parameters:
+//   - c: Container report.
+//
+// Returns:
+//   - map[string]string: Labels, or nil if the report carries none.
+func containerLabels(c types.ContainerReport) map[string]string {
+	labeled, ok := c.(labeledContainerReport)
+	if !ok {
+		return nil
+	}
+
+	return labeled.Labels()
+}
+
+// entryContainer returns the container name a log entry refers to.
+//
+// Parameters:
+//   - entry: Queued log entry.
+//
+// Returns:
+//   - string: Container name without a leading slash, or empty if none.
+func entryContainer(entry *notificationEntry) string {
+	name, _ := entry.Data["container"].(string)
+	if name == "" {
+		name, _ = entry.Data["container_name"].(string)
+	}
+
+	return strings.TrimPrefix(name, "/")
+}
+
+// entryLevel parses a queued entry's level.
+//
+// Parameters:
+//   - entry: Queued log entry.
+//
+// Returns:
+//   - zerolog.Level: Parsed level, or NoLevel if unknown.
+func entryLevel(entry *notificationEntry) zerolog.Level {
+	if entry.Level == levelToString(zerolog.WarnLevel) {
+		return zerolog.WarnLevel
+	}
+
+	level, err := zerolog.ParseLevel(entry.Level)
+	if err != nil {
+		return zerolog.NoLevel
+	}
+
+	return level
+}
+
+// hasContainerCriteria reports whether the route matches on container properties.
+//
+// Returns:
+//   - bool: True if containers, labels, or projects are set.
+func (r *notificationRoute) hasContainerCriteria() bool {
+	return len(r.rule.Containers) > 0 || len(r.rule.Labels) > 0 || len(r.rule.Projects) > 0
+}
+
+// matchesName reports whether a container name matches one of the route's patterns.
+//
+// Parameters:
+//   - name: Container name without a leading slash.
+//
+// Returns:
+//   - bool: True if a pattern matches.
+func (r *notificationRoute) matchesName(name string) bool {
+	for _, pattern := range r.rule.Containers {
+		pattern = strings.TrimPrefix(pattern, "/")
+
+		matched, err := path.Match(pattern, name)
+		if err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesContainer reports whether a container satisfies every container criterion.
+//
+// Parameters:
+//   - c: Container report.
+//
+// Returns:
+//   - bool: True if the container matches.
+func (r *notificationRoute) matchesContainer(c types.ContainerReport) bool {
+	if len(r.rule.Containers) > 0 && !r.matchesName(strings.TrimPrefix(c.Name(), "/")) {
+		return false
+	}
+
+	labels := containerLabels(c)
+
+	for key, want := range r.rule.Labels {
+		value, ok := labels[key]
+		if !ok || (want != "" && value != want) {
+			return false
+		}
+	}
+
+	if len(r.rule.Projects) > 0 && !slices.Contains(r.rule.Projects, labels[composeProjectLabel]) {
+		return false
+	}
+
+	return true
+}
+
+// matchesEntry reports whether a log entry refers to a container matched by the route.
+//
+// Parameters:
+//   - entry: Queued log entry.
+//   - matched: Names of report containers that match the route.
+//
+// Returns:
+//   - bool: True if the entry's container was matched in the report, or by
+//     name when the route has no label or project criteria.
+func (r *notificationRoute) matchesEntry(entry *notificationEntry, matched map[string]struct{}) bool {
+	name := entryContainer(entry)
+	if name == "" {
+		return false
+	}
+
+	if _, ok := matched[name]; ok {
+		return true
+	}
+
+	return len(r.rule.Labels) == 0 && len(r.rule.Projects) == 0 && r.matchesName(name)
+}
+
+// matchesCategory reports whether the route accepts a report category.
+//
+// Parameters:
+//   - category: Report category.
+//
+// Returns:
+//   - bool: True if the route has no categories or lists this one.
+func (r *notificationRoute) matchesCategory(category string) bool {
+	return len(r.rule.Categories) == 0 || slices.Contains(r.rule.Categories, category)
+}
+
+// matchesCategoryEntry reports whether a log entry belongs to the route's categories.
+//
+// A route for failures only takes entries logged at error level or above.
+// Otherwise the entry must refer to a container in the routed report.
+//
+// Parameters:
+//   - entry: Queued log entry.
+//   - routed: Names of containers in the routed report.
+//
+// Returns:
+//   - bool: True if the route has no categories or the entry belongs to one of them.
+func (r *notificationRoute) matchesCategoryEntry(entry *notificationEntry, routed map[string]struct{}) bool {
+	if len(r.rule.Categories) == 0 {
+		return true
+	}
+
+	if len(r.rule.Categories) == 1 && r.rule.Categories[0] == categoryFailed && entryLevel(entry) >= zerolog.ErrorLevel {
+		return true
+	}
+
+	_, ok := routed[entryContainer(entry)]
+
+	return ok
+}
+
+// partition selects the part of a notification that belongs to the route.
+//
+// Containers must match the container criteria and category. Log entries must
+// meet the route level and, when container criteria are set, refer to a
+// container matched in the report or by name. With categories set, entries
+// must also belong to one of them; see matchesCategoryEntry.
+//
+// Parameters:
+//   - entries: Queued log entries.
+//   - report: Session report, or nil.
+//
+// Returns:
+//   - []*notificationEntry: Routed entries.
+//   - *routedReport: Routed report, or nil if report is nil.
+func (r *notificationRoute) partition(
+	entries []*notificationEntry,
+	report types.Report,
+) ([]*notificationEntry, *routedReport) {
+	routed := filterReport(report, func(category string, c types.ContainerReport) bool {
+		return r.matchesCategory(category) && r.matchesContainer(c)
+	})
+
+	names := make(map[string]struct{})
+	routedNames := make(map[string]struct{})
+
+	if routed != nil {
+		for _, c := range routed.All() {
+			routedNames[strings.TrimPrefix(c.Name(), "/")] = struct{}{}
+		}
+	}
+
+	if report != nil && r.hasContainerCriteria() {
+		for _, c := range report.All() {
+			if r.matchesContainer(c) {
+				names[strings.TrimPrefix(c.Name(), "/")] = struct{}{}
+			}
+		}
+	}
+
+	var routedEntries []*notificationEntry
+
+	for _, entry := range entries {
+		if entryLevel(entry) < r.level {
+			continue
+		}
+
+		if r.hasContainerCriteria() && !r.matchesEntry(entry, names) {
+			continue
+		}
+
+		if !r.matchesCategoryEntry(entry, routedNames) {
+			continue
+		}
+
+		routedEntries = append(routedEntries, entry)
+	}
+
+	return routedEntries, routed
+}
+
+// addRoutes creates a notifier for each routing rule and for per-container
+// notification-url labels.
+//
+// Rules are validated by config.Load. An unparsable route level or URL is fatal,
+// matching the handling of the main notification settings.
+//
+// Parameters:
+//   - rules: Routing rules from configuration.
+func (n *shoutrrrTypeNotifier) addRoutes(rules []notifyConfig.Route) {
+	log := n.ll()
+
+	for _, rule := range rules {
+		level := n.logLevel
+
+		if rule.Level != "" {
+			parsed, err := logging.ParseLevel(rule.Level)
+			if err != nil {
+				log.Fatal().Err(err).Str("route", rule.Name).Msg("Invalid notification route level")
+			}
+
+			level = parsed
+		}
+
+		notifier, err := n.newRouteNotifier(rule.URLs, rule.Template)
+		if err != nil {
+			log.Fatal().Err(err).Str("route", rule.Name).Msg("Failed to initialize notification route")
+		}
+
+		n.routes = append(n.routes, &notificationRoute{rule: rule, level: level, notifier: notifier})
+
+		log.Debug().
+			Str("route", rule.Name).
+			Strs("urls", redactServiceURLs(rule.URLs)).
+			Msg("Added notification route")
+	}
+}
+
+// newRouteNotifier creates a notifier that shares this notifier's settings but
+// sends to its own URLs with its own template.
+//
+// The route notifier is not hooked into the logger. Its worker starts now if
+// this notifier is already receiving, or otherwise in RegisterHook.
+//
+// Parameters:
+//   - urls: Shoutrrr URLs of the route.
+//   - tplString: Route template; empty uses this notifier's template.
+//
+// Returns:
+//   - *shoutrrrTypeNotifier: Route notifier.
+//   - error: Non-nil if a URL cannot be used.
+func (n *shoutrrrTypeNotifier) newRouteNotifier(urls []string, tplString string) (*shoutrrrTypeNotifier, error) {
+	tpl := n.template
+	labelTemplates := n.containerTemplates
+
+	if tplString != "" {
+		labelTemplates = nil
+
+		parsed, err := getShoutrrrTemplate(n.ll(), tplString, n.legacyTemplate)
+		if err != nil {
+			n.ll().Error().Err(err).
+				Msg("Could not use notification route template, falling back to default")
+		} else {
+			tpl = parsed
+		}
+	}
+
+	router, err := shoutrrr.NewSenderWithOptions(n.senderLog, shoutrrrTypes.SenderOptions{}, urls...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize notification route sender: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	route := &shoutrrrTypeNotifier{
+		Urls:           urls,
+		Router:         router,
+		messages:       make(chan notification, messageChannelBufferSize),
+		done:           make(chan struct{}, 1),
+		stop:           make(chan struct{}),
+		logLevel:       n.logLevel,
+		template:       tpl,
+		legacyTemplate: n.legacyTemplate,
+		data:           n.data,
+		params:         n.params,
+		ctx:            ctx,
+		cancel:         cancel,
+		delay:          n.delay,
+		entries:        make([]*notificationEntry, 0, initialEntriesCapacity),
+		senderLog:      n.senderLog,
+		localLog:       n.localLog,
+		announcements:  n.announcements,
+		outbox:         n.outbox,
+		quiet:          n.quiet.clone(),
+
+		containerTemplates: labelTemplates,
+	}
+
+	if n.email != nil {
+		err = route.useHTMLEmail(n.email)
+		if err != nil {
+			route.ll().Error().Err(err).Msg("Failed to initialize HTML email for notification route, sending plain-text email")
+		}
+	}
+
+	if n.outbox != nil {
+		n.outbox.track(urls)
+	}
+
+	if n.quiet != nil {
+		route.useQuietStore(n.quiet.store)
+		route.restoreHeld()
+	}
+
+	if spanContext := n.traceParent.Load(); spanContext != nil {
+		route.traceParent.Store(spanContext)
+	}
+
+	if n.receiving.Load() {
+		route.startWorker()
+	}
+
+	return route, nil
+}
+
+// startWorker marks a route notifier as receiving and starts its send goroutine.
+func (n *shoutrrrTypeNotifier) startWorker() {
+	n.receiving.Store(true)
+
+	go sendNotifications(n)
+}
+
+// routeNotifiers returns every route notifier, including per-label notifiers.
+//
+// Returns:
+//   - []*shoutrrrTypeNotifier: Route notifiers.
+func (n *shoutrrrTypeNotifier) routeNotifiers() []*shoutrrrTypeNotifier {
+	n.routesMutex.Lock()
+	defer n.routesMutex.Unlock()
+
+	notifiers := make([]*shoutrrrTypeNotifier, 0, len(n.routes)+len(n.labelRoutes))
+	for _, route := range n.routes {
+		notifiers = append(notifiers, route.notifier)
+	}
+
+	for _, notifier := range n.labelRoutes {
+		notifiers = append(notifiers, notifier)
+	}
+
+	return notifiers
+}
+
+// labelRouteNotifier returns the notifier for a notification-url label value,
+// creating it on first use.
+//
+// Parameters:
+//   - value: Space-separated Shoutrrr URLs from the label.
+//
+// Returns:
+//   - *shoutrrrTypeNotifier: Label notifier, or nil if the URLs are invalid.
+func (n *shoutrrrTypeNotifier) labelRouteNotifier(value string) *shoutrrrTypeNotifier {
+	n.routesMutex.Lock()
+	defer n.routesMutex.Unlock()
+
+	if notifier, ok := n.labelRoutes[value]; ok {
+		return notifier
+	}
+
+	notifier, err := n.newRouteNotifier(strings.Fields(value), "")
+	if err != nil {
+		n.ll().Warn().Err(err).Msg("Ignoring invalid notification URL container label")
+	}
+
+	if n.labelRoutes == nil {
+		n.labelRoutes = make(map[string]*shoutrrrTypeNotifier)
+	}
+
+	// Cache failures as nil so an invalid label is reported once.
+	n.labelRoutes[value] = notifier
+
+	return notifier
+}
+
+// dispatchRoutes sends each route its part of a notification and returns the
+// part left for the default URLs.
+//
+// Parameters:
+//   - entries: Queued log entries.
+//   - report: Session report, or nil.
+//
+// Returns:
+//   - []*notificationEntry: Entries for the default notification.
+//   - types.Report: Report for the default notification.
+func (n *shoutrrrTypeNotifier) dispatchRoutes(
+	entries []*notificationEntry,
+	report types.Report,
+) ([]*notificationEntry, types.Report) {
+	claimedEntries := make(map[*notificationEntry]struct{})
+	claimedContainers := make(map[string]map[types.ContainerID]struct{})
+
+	for _, route := range n.routes {
+		routedEntries, routed := route.partition(entries, report)
+
+		if route.rule.Exclusive {
+			for _, entry := range routedEntries {
+				claimedEntries[entry] = struct{}{}
+			}
+
+			if routed != nil {
+				claimCategory(claimedContainers, categoryScanned, routed.scanned)
+				claimCategory(claimedContainers, categoryUpdated, routed.updated)
+				claimCategory(claimedContainers, categoryFailed, routed.failed)
+				claimCategory(claimedContainers, categorySkipped, routed.skipped)
+				claimCategory(claimedContainers, categoryStale, routed.stale)
+				claimCategory(claimedContainers, categoryFresh, routed.fresh)
+				claimCategory(claimedContainers, categoryRestarted, routed.restarted)
+			}
+		}
+
+		n.sendRouted(route.notifier, route.rule.Name, routedEntries, routed)
+	}
+
+	n.dispatchLabelRoutes(entries, report)
+
+	if len(claimedEntries) == 0 && len(claimedContainers) == 0 {
+		return entries, report
+	}
+
+	remaining := make([]*notificationEntry, 0, len(entries))
+
+	for _, entry := range entries {
+		if _, ok := claimedEntries[entry]; !ok {
+			remaining = append(remaining, entry)
+		}
+	}
+
+	var remainingReport types.Report
+	if report != nil {
+		remainingReport = filterReport(report, func(category string, c types.ContainerReport) bool {
+			_, claimed := claimedContainers[category][c.ID()]
+
+			return !claimed
+		})
+	}
+
+	return remaining, remainingReport
+}
+
+// claimCategory records containers taken from the default notification by an exclusive route.
+//
+// Parameters:
+//   - claimed: Claimed container IDs per category.
+//   - category: Report category.
+//   - reports: Containers claimed in that category.
+func claimCategory(claimed map[string]map[types.ContainerID]struct{}, category string, reports []types.ContainerReport) {
+	if len(reports) == 0 {
+		return
+	}
+
+	if claimed[category] == nil {
+		claimed[category] = make(map[types.ContainerID]struct{})
+	}
+
+	for _, c := range reports {
+		claimed[category][c.ID()] = struct{}{}
+	}
+}
+
+// dispatchLabelRoutes sends containers with a notification-url label their part
+// of a notification, grouped by label value.
+//
+// Parameters:
+//   - entries: Queued log entries.
+//   - report: Session report, or nil.
+func (n *shoutrrrTypeNotifier) dispatchLabelRoutes(entries []*notificationEntry, report types.Report) {
+	if report == nil {
+		return
+	}
+
+	groups := make(map[string]map[types.ContainerID]struct{})
+	names := make(map[string]map[string]struct{})
+
+	var order []string
+
+	for _, c := range report.All() {
+		value := strings.TrimSpace(containerLabels(c)[notificationURLLabel])
+		if value == "" {
+			continue
+		}
+
+		if groups[value] == nil {
+			groups[value] = make(map[types.ContainerID]struct{})
+			names[value] = make(map[string]struct{})
+			order = append(order, value)
+		}
+
+		groups[value][c.ID()] = struct{}{}
+		names[value][strings.TrimPrefix(c.Name(), "/")] = struct{}{}
+	}
+
+	for _, value := range order {
+		notifier := n.labelRouteNotifier(value)
+		if notifier == nil {
+			continue
+		}
+
+		routed := filterReport(report, func(_ string, c types.ContainerReport) bool {
+			_, ok := groups[value][c.ID()]
+
+			return ok
+		})
+
+		var routedEntries []*notificationEntry
+
+		for _, entry := range entries {
+			if _, ok := names[value][entryContainer(entry)]; ok {
+				routedEntries = append(routedEntries, entry)
+			}
+		}
+
+		n.sendRouted(notifier, notificationURLLabel, routedEntries, routed)
+	}
+}
+
+// sendRouted queues a route's part of a notification unless it is empty.
+//
+// Parameters:
+//   - notifier: Route notifier.
+//   - name: Route name for logging.
+//   - entries: Routed entries.
+//   - report: Routed report, or nil.
+func (n *shoutrrrTypeNotifier) sendRouted(
+	notifier *shoutrrrTypeNotifier,
+	name string,
+	entries []*notificationEntry,
+	report *routedReport,
+) {
+	if len(entries) == 0 && (report == nil || report.empty()) {
+		return
+	}
+
+	// Keep a nil *routedReport out of the interface so templates see a nil report.
+	var routed types.Report
+
+	if report != nil {
+		routed = report
+	}
+
+	n.ll().Debug().
+		Str("route", name).
+		Int("entries_count", len(entries)).
+		Int("container_count", reportCategoryCount(routed)).
+		Msg("Routing notification")
+
+	notifier.sendEntries(entries, routed)
+}
@@ -0,0 +1,191 @@
+package notifications
+
+import (
+	"errors"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/rs/zerolog"
+
+	mockActions "github.com/nicholas-fedor/watchtower/internal/actions/mocks"
+	notifyConfig "github.com/nicholas-fedor/watchtower/internal/config/notify"
+	"github.com/nicholas-fedor/watchtower/pkg/session"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
+)
+
+// routeTestTemplate lists report containers by category, then entry messages.
+const routeTestTemplate = `{{with .Report}}{{range .Updated}}updated:{{.Name}} {{end}}` +
+	`{{range .Failed}}failed:{{.Name}} {{end}}{{end}}` +
+	`{{range .Entries}}entry:{{.Message}} {{end}}`
+
+// routeTestReport builds a report with an updated web container in project
+// "shop", an updated db container labeled tier=data, and a failed cache
+// container that carries its own notification-url label.
+func routeTestReport() types.Report {
+	log := testLogger()
+	progress := session.Progress{}
+
+	add := func(id, name string, labels map[string]string) types.Container {
+		c := mockActions.CreateMockContainer(id, name, "mock/"+name+":latest", time.Now())
+		for key, value := range labels {
+			c.ContainerInfo().Config.Labels[key] = value
+		}
+
+		progress.AddScanned(log, c, types.ImageID("sha256:new-"+id), types.UpdateParams{})
+
+		return c
+	}
+
+	web := add("web-id", "/web", map[string]string{composeProjectLabel: "shop"})
+	db := add("db-id", "/db", map[string]string{"tier": "data"})
+	cache := add("cache-id", "/cache", map[string]string{notificationURLLabel: "logger://"})
+
+	progress.MarkForUpdate(log, web.ID())
+	progress.MarkForUpdate(log, db.ID())
+	progress.UpdateFailed(log, map[types.ContainerID]error{cache.ID(): errMockRoute})
+
+	return progress.Report(log)
+}
+
+var errMockRoute = errors.New("pull failed")
+
+// routeTestEntries returns queued entries for each container plus one without a container.
+func routeTestEntries() []*notificationEntry {
+	return []*notificationEntry{
+		{Message: "web-info", Level: "info", Data: map[string]any{"container": "web"}},
+		{Message: "db-info", Level: "info", Data: map[string]any{"container": "db"}},
+		{Message: "cache-error", Level: "error", Data: map[string]any{"container": "cache"}},
+		{Message: "global-warn", Level: "warning", Data: map[string]any{}},
+	}
+}
+
+// newRoutedNotifier creates a report-mode notifier with the given routes.
+func newRoutedNotifier(routes ...notifyConfig.Route) *shoutrrrTypeNotifier {
+	notifier := createNotifier(testLogger(), []string{"logger://"}, zerolog.InfoLevel,
+		routeTestTemplate, false, StaticData{}, false, 0)
+	notifier.addRoutes(routes)
+
+	return notifier
+}
+
+// queued returns the message queued on a notifier, or empty if none.
+func queued(notifier *shoutrrrTypeNotifier) string {
+	select {
+	case msg := <-notifier.messages:
+		return msg.Message
+	default:
+		return ""
+	}
+}
+
+var _ = ginkgo.Describe("notification routes", func() {
+	ginkgo.BeforeEach(func() {
+		resetTestLogger()
+	})
+
+	ginkgo.It("sends failures only to an exclusive route", func() {
+		notifier := newRoutedNotifier(notifyConfig.Route{
+			Name:       "on-call",
+			Categories: []string{"failed"},
+			Level:      "error",
+			URLs:       []string{"logger://"},
+			Template:   routeTestTemplate,
+			Exclusive:  true,
+		})
+
+		notifier.sendEntries(routeTestEntries(), routeTestReport())
+
+		gomega.Expect(queued(notifier.routes[0].notifier)).
+			To(gomega.Equal("failed:cache entry:cache-error "))
+		gomega.Expect(queued(notifier)).To(gomega.Equal(
+			"updated:db updated:web entry:web-info entry:db-info entry:global-warn "))
+	})
+
+	ginkgo.It("routes only the entries of an exclusive route's categories at the default level", func() {
+		notifier := newRoutedNotifier(
+			notifyConfig.Route{
+				Name:       "on-call",
+				Categories: []string{"failed"},
+				URLs:       []string{"logger://"},
+				Exclusive:  true,
+			},
+			notifyConfig.Route{
+				Name:       "changes",
+				Categories: []string{"updated"},
+				URLs:       []string{"logger://"},
+			},
+		)
+
+		notifier.sendEntries(routeTestEntries(), routeTestReport())
+
+		gomega.Expect(queued(notifier.routes[0].notifier)).
+			To(gomega.Equal("failed:cache entry:cache-error "))
+		gomega.Expect(queued(notifier.routes[1].notifier)).
+			To(gomega.Equal("updated:db updated:web entry:web-info entry:db-info "))
+		gomega.Expect(queued(notifier)).To(gomega.Equal(
+			"updated:db updated:web entry:web-info entry:db-info entry:global-warn "))
+	})
+
+	ginkgo.It("matches containers by project and label without removing them from the default", func() {
+		notifier := newRoutedNotifier(
+			notifyConfig.Route{Name: "shop", Projects: []string{"shop"}, URLs: []string{"logger://"}},
+			notifyConfig.Route{Name: "data", Labels: map[string]string{"tier": ""}, URLs: []string{"logger://"}},
+		)
+
+		notifier.sendEntries(routeTestEntries(), routeTestReport())
+
+		gomega.Expect(queued(notifier.routes[0].notifier)).To(gomega.Equal("updated:web entry:web-info "))
+		gomega.Expect(queued(notifier.routes[1].notifier)).To(gomega.Equal("updated:db entry:db-info "))
+		gomega.Expect(queued(notifier)).To(gomega.Equal(
+			"updated:db updated:web failed:cache entry:web-info entry:db-info entry:cache-error entry:global-warn "))
+	})
+
+	ginkgo.It("matches log entries by container name glob without a report", func() {
+		notifier := newRoutedNotifier(notifyConfig.Route{
+			Name:       "web",
+			Containers: []string{"/we*"},
+			URLs:       []string{"logger://"},
+		})
+
+		notifier.sendEntries(routeTestEntries(), nil)
+
+		gomega.Expect(queued(notifier.routes[0].notifier)).To(gomega.Equal("entry:web-info "))
+	})
+
+	ginkgo.It("skips routes with nothing to send", func() {
+		notifier := newRoutedNotifier(notifyConfig.Route{
+			Name:       "stale",
+			Categories: []string{"stale"},
+			Level:      "error",
+			Containers: []string{"web"},
+			URLs:       []string{"logger://"},
+		})
+
+		notifier.sendEntries(routeTestEntries(), routeTestReport())
+
+		gomega.Expect(queued(notifier.routes[0].notifier)).To(gomega.BeEmpty())
+	})
+
+	ginkgo.It("sends a container's part to the URLs in its notification-url label", func() {
+		notifier := newRoutedNotifier()
+
+		notifier.sendEntries(routeTestEntries(), routeTestReport())
+
+		gomega.Expect(notifier.labelRoutes).To(gomega.HaveKey("logger://"))
+		gomega.Expect(queued(notifier.labelRoutes["logger://"])).
+			To(gomega.Equal("failed:cache entry:cache-error "))
+	})
+
+	ginkgo.It("starts and stops route workers with the notifier", func() {
+		notifier := newRoutedNotifier(notifyConfig.Route{Name: "all", URLs: []string{"logger://"}})
+		route := notifier.routes[0].notifier
+
+		log := testLogger()
+		notifier.RegisterHook(log)
+		gomega.Expect(route.receiving.Load()).To(gomega.BeTrue())
+
+		notifier.Close()
+		gomega.Expect(route.closed.Load()).To(gomega.BeTrue())
+	})
+})
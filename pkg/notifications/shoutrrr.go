@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
@@ -17,10 +18,13 @@ import (
 
 	"github.com/nicholas-fedor/shoutrrr"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	shoutrrrTypes "github.com/nicholas-fedor/shoutrrr/pkg/types"
 	stdlog "log"
 
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/session"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
@@ -65,6 +69,37 @@ type router interface {
 	Send(message string, params *shoutrrrTypes.Params) []error
 }
 
+// notification is a rendered message on its way to the service URLs.
+//
+// Every service receives Message. With HTML email enabled, Email holds the
+// parts smtp:// URLs receive instead.
+type notification struct {
+	Message string        `json:"message"`
+	Email   *emailMessage `json:"email,omitempty"`
+}
+
+// notificationRouter is a router that can deliver the email parts of a notification.
+type notificationRouter interface {
+	sendNotification(msg notification, params *shoutrrrTypes.Params) []error
+}
+
+// sendTo sends msg with r, passing the email parts only to a router that can deliver them.
+//
+// Parameters:
+//   - r: Router or single-URL sender.
+//   - msg: Rendered notification.
+//   - params: Notification parameters.
+//
+// Returns:
+//   - []error: Errors from r.
+func sendTo(r router, msg notification, params *shoutrrrTypes.Params) []error {
+	if email, ok := r.(notificationRouter); ok {
+		return email.sendNotification(msg, params)
+	}
+
+	return r.Send(msg.Message, params)
+}
+
 // shoutrrrTypeNotifier manages Shoutrrr notifications.
 //
 // It handles queuing, templating, and sending with delay.
@@ -77,7 +112,7 @@ type shoutrrrTypeNotifier struct {
 	entriesMutex   sync.RWMutex          // Mutex for thread-safe access to entries.
 	logLevel       zerolog.Level         // Minimum log level for notifications.
 	template       *template.Template    // Template for message formatting.
-	messages       chan string           // Channel for message queuing.
+	messages       chan notification     // Channel for message queuing.
 	done           chan struct{}         // Signal for send completion.
 	stop           chan struct{}         // Channel for stopping the notifier.
 	legacyTemplate bool                  // Use legacy log-only template if true.
@@ -95,6 +130,8 @@ type shoutrrrTypeNotifier struct {
 	extractWarnOnce sync.Once // Guards the first extraction failure warning.
 	// These fields must only be accessed via sync/atomic (e.g., atomic.Load/atomic.Store) to avoid data races.
 	closed atomic.Bool // Tracks if the notifier is closed.
+	// traceParent is the scan span that parents notification delivery spans.
+	traceParent atomic.Pointer[trace.SpanContext]
 
 	// localLog is a child logger with notify=no for internal logging (loop prevention).
 	// Initialized at create time from the process logger and rebound in RegisterHook
@@ -103,6 +140,31 @@ type shoutrrrTypeNotifier struct {
 	// before taking entriesMutex, so internal logs never re-enter the queue or deadlock.
 	// Concurrent application Logs are serialized only by entriesMutex on queue access.
 	localLog *zerolog.Logger
+
+	// senderLog is the Shoutrrr logger shared with route notifiers.
+	senderLog shoutrrrTypes.StdLogger
+	// routing enables per-route partitioning in sendEntries. Set on the root notifier only.
+	routing bool
+	// routes are the configured routing rules.
+	routes []*notificationRoute
+	// labelRoutes are notifiers for notification-url container labels, keyed by label value.
+	labelRoutes map[string]*shoutrrrTypeNotifier
+	// routesMutex guards labelRoutes.
+	routesMutex sync.Mutex
+	// announcements tracks announced available updates when repeat suppression is enabled.
+	// Shared with route notifiers so their templates see the same notices.
+	announcements *announcements
+	// outbox holds messages that service URLs failed to accept when the outbox is enabled.
+	// Shared with route notifiers and retried by the root notifier.
+	outbox *outbox
+	// quiet holds messages produced during quiet hours when they are configured for this notifier.
+	quiet *quietHours
+	// containerTemplates resolves notification-template container labels.
+	// Shared with route notifiers that use the global template.
+	containerTemplates *containerTemplates
+	// email renders and sends smtp:// notifications as HTML email when HTML email is enabled.
+	// Shared with route notifiers that have smtp:// URLs.
+	email *htmlEmail
 }
 
 // GetScheme extracts the scheme from a Shoutrrr URL.
@@ -172,6 +234,14 @@ func (n *shoutrrrTypeNotifier) RegisterHook(log *zerolog.Logger) {
 
 		// Send using a separate goroutine to avoid blocking the main process.
 		go sendNotifications(n)
+
+		if n.outbox != nil {
+			go n.outbox.run(n.ctx)
+		}
+
+		for _, route := range n.routeNotifiers() {
+			route.startWorker()
+		}
 	})
 }
 
@@ -238,7 +308,7 @@ func createNotifier(
 		Urls:   urls,   // Notification service URLs.
 		Router: router, // Router for sending messages.
 		messages: make(
-			chan string,
+			chan notification,
 			messageChannelBufferSize,
 		), // Channel buffer size for notification messages
 		done: make(
@@ -258,6 +328,8 @@ func createNotifier(
 		delay:          delay,                                                 // Delay between sends.
 		entries:        make([]*notificationEntry, 0, initialEntriesCapacity), // Queued log entries.
 		localLog:       localLog,                                              // Loop-safe internal logger.
+		senderLog:      logger,                                                // Shoutrrr logger for route notifiers.
+		routing:        true,                                                  // Partition notifications across routes.
 	}
 }
 
@@ -377,12 +449,12 @@ func sendNotifications(notifier *shoutrrrTypeNotifier) {
 		case msg := <-notifier.messages:
 			// Log goroutine receipt of message
 			log.Trace().
-				Int("msg_length", len(msg)).
+				Int("msg_length", len(msg.Message)).
 				Str("notification_type", shoutrrrType).
 				Int("total_urls", len(notifier.Urls)).
 				Msg("Notification goroutine received message from channel")
 
-			log.Debug().Str("message", msg).Msg("Sending notification")
+			log.Debug().Str("message", msg.Message).Msg("Sending notification")
 
 			// Only delay if a positive delay is configured.
 			// Use a context-aware select to allow interruption when the context is canceled.
@@ -406,12 +478,12 @@ func sendNotifications(notifier *shoutrrrTypeNotifier) {
 			log.Trace().
 				Int("total_urls", len(notifier.Urls)).
 				Str("delay", notifier.delay.String()).
-				Int("msg_length", len(msg)).
+				Int("msg_length", len(msg.Message)).
 				Msg("Attempting to send notification to configured services")
 
 			// Log before calling Router.Send
 			log.Trace().
-				Int("msg_length", len(msg)).
+				Int("msg_length", len(msg.Message)).
 				Int("total_urls", len(notifier.Urls)).
 				Str("notification_type", shoutrrrType).
 				Msg("Calling Router.Send with message")
@@ -426,13 +498,13 @@ func sendNotifications(notifier *shoutrrrTypeNotifier) {
 				case msg := <-notifier.messages:
 					// Log goroutine receipt of message during shutdown
 					log.Trace().
-						Int("msg_length", len(msg)).
+						Int("msg_length", len(msg.Message)).
 						Str("notification_type", shoutrrrType).
 						Int("total_urls", len(notifier.Urls)).
 						Bool("shutdown_mode", true).
 						Msg("Processing remaining notification message during shutdown")
 
-					log.Debug().Str("message", msg).Msg("Sending notification during shutdown")
+					log.Debug().Str("message", msg.Message).Msg("Sending notification during shutdown")
 
 					// Skip delay during shutdown to expedite processing
 
@@ -440,12 +512,12 @@ func sendNotifications(notifier *shoutrrrTypeNotifier) {
 					log.Trace().
 						Int("total_urls", len(notifier.Urls)).
 						Str("delay", notifier.delay.String()).
-						Int("msg_length", len(msg)).
+						Int("msg_length", len(msg.Message)).
 						Msg("Attempting to send notification to configured services during shutdown")
 
 					// Log before calling Router.Send
 					log.Trace().
-						Int("msg_length", len(msg)).
+						Int("msg_length", len(msg.Message)).
 						Int("total_urls", len(notifier.Urls)).
 						Str("notification_type", shoutrrrType).
 						Msg("Calling Router.Send with message during shutdown")
@@ -620,9 +692,15 @@ func (n *shoutrrrTypeNotifier) Close() {
 	n.closeOnce.Do(func() {
 		n.closed.Store(true)
 
+		for _, route := range n.routeNotifiers() {
+			route.Close()
+		}
+
 		// If no worker goroutine exists, skip waiting and cancel immediately.
 		if !n.receiving.Load() {
 			log.Debug().Msg("No notification worker running, canceling context immediately")
+			n.flushQuietHours()
+			n.flushOutbox()
 			n.cancel()
 
 			return
@@ -642,6 +720,10 @@ func (n *shoutrrrTypeNotifier) Close() {
 		log.Debug().Msg("Closing messages channel after worker exit")
 		close(n.messages)
 
+		// Give held messages one last chance before the retry loop stops with the context.
+		n.flushQuietHours()
+		n.flushOutbox()
+
 		// Cancel context to unblock any pending operations.
 		log.Debug().Msg("Canceling notification context to unblock pending sends")
 		n.cancel()
@@ -777,16 +859,36 @@ func deduplicateEntries(entries []*notificationEntry) []*notificationEntry {
 
 // ShouldSendNotification checks if a notification should be sent for the given report based on the notifier's log level.
 //
+// With repeat suppression enabled, it also records the announced image digest of
+// each stale container. A report whose only news is stale containers that were
+// already announced is not sent, and its queued entries about them are dropped.
+//
 // Parameters:
 //   - report: The report to check.
 //
 // Returns:
 //   - bool: True if notification should be sent, false otherwise.
 func (n *shoutrrrTypeNotifier) ShouldSendNotification(report types.Report) bool {
-	if report != nil && n.logLevel == zerolog.ErrorLevel {
-		if len(report.Failed()) == 0 {
-			return false
-		}
+	if report == nil {
+		return true
+	}
+
+	repeatsOnly := n.announcements != nil && n.announcements.evaluate(report)
+
+	if n.logLevel == zerolog.ErrorLevel && len(report.Failed()) == 0 {
+		return false
+	}
+
+	// A split report is only about its own container, so other updates and failures it lists do not count.
+	_, split := singleFocusContainer(report)
+
+	if repeatsOnly && (split || len(report.Updated())+len(report.Failed())+len(report.Restarted()) == 0) {
+		n.ll().Debug().
+			Int("stale_count", len(report.Stale())).
+			Msg("Suppressing notification for already announced updates")
+		n.dropRepeatEntries(report)
+
+		return false
 	}
 
 	return true
@@ -1023,7 +1125,7 @@ func eventFieldMap(event *zerolog.Event) (map[string]any, time.Time, bool) {
 //
 // Parameters:
 //   - msg: Message to send.
-func (n *shoutrrrTypeNotifier) send(msg string) {
+func (n *shoutrrrTypeNotifier) send(msg notification) {
 	// Skip delivery when Close already canceled the worker.
 	select {
 	case <-n.ctx.Done():
@@ -1033,15 +1135,29 @@ func (n *shoutrrrTypeNotifier) send(msg string) {
 	default:
 	}
 
+	parent := context.Background()
+	if spanContext := n.traceParent.Load(); spanContext != nil {
+		parent = trace.ContextWithSpanContext(parent, *spanContext)
+	}
+
+	_, span := tracing.Start(parent, "notification.send",
+		attribute.Int("watchtower.notification.services", len(n.Urls)),
+	)
+	defer span.End()
+
 	errsCh := make(chan []error, 1)
 
 	go func() {
-		errsCh <- n.Router.Send(msg, n.params)
+		// The router does not accept a context, so its requests are covered
+		// by this span rather than parented on it.
+		errsCh <- sendTo(n.Router, msg, n.params)
 	}()
 
 	select {
 	case errs := <-errsCh:
 		processSendErrors(n, errs)
+		n.holdUndelivered(msg, errs)
+		tracing.RecordError(span, errors.Join(errs...))
 	case <-n.ctx.Done():
 		timer := time.NewTimer(shutdownGracePeriod)
 		defer timer.Stop()
@@ -1049,13 +1165,29 @@ func (n *shoutrrrTypeNotifier) send(msg string) {
 		select {
 		case errs := <-errsCh:
 			processSendErrors(n, errs)
+			n.holdUndelivered(msg, errs)
+			tracing.RecordError(span, errors.Join(errs...))
 		case <-timer.C:
 			n.ll().Debug().Err(n.ctx.Err()).Msg("Notification send canceled")
 		}
 	}
 }
 
-// buildMessage constructs a notification message from data.
+// SetTraceContext records the span in ctx as the parent of subsequent
+// notification delivery spans.
+//
+// Parameters:
+//   - ctx: Context holding the scan span.
+func (n *shoutrrrTypeNotifier) SetTraceContext(ctx context.Context) {
+	spanContext := trace.SpanContextFromContext(ctx)
+	n.traceParent.Store(&spanContext)
+
+	for _, route := range n.routeNotifiers() {
+		route.traceParent.Store(&spanContext)
+	}
+}
+
+// buildMessage constructs a notification message from data with the notifier's template.
 //
 // Parameters:
 //   - data: Notification data.
@@ -1064,6 +1196,19 @@ func (n *shoutrrrTypeNotifier) send(msg string) {
 //   - string: Rendered message.
 //   - error: Non-nil if templating fails, nil on success.
 func (n *shoutrrrTypeNotifier) buildMessage(data Data) (string, error) {
+	return n.buildMessageWith(n.template, data)
+}
+
+// buildMessageWith constructs a notification message from data with tpl.
+//
+// Parameters:
+//   - tpl: Template to execute.
+//   - data: Notification data.
+//
+// Returns:
+//   - string: Rendered message.
+//   - error: Non-nil if templating fails, nil on success.
+func (n *shoutrrrTypeNotifier) buildMessageWith(tpl *template.Template, data Data) (string, error) {
 	log := n.ll()
 
 	var body bytes.Buffer
@@ -1086,12 +1231,12 @@ func (n *shoutrrrTypeNotifier) buildMessage(data Data) (string, error) {
 	}
 
 	// Execute template with data.
-	err := n.template.Execute(&body, dataSource)
+	err := tpl.Execute(&body, dataSource)
 	if err != nil {
 		log.Debug().
 			Err(err).
 			Bool("legacy_template", n.legacyTemplate).
-			Str("template_name", n.template.Name()).
+			Str("template_name", tpl.Name()).
 			Msg("Template execution failed")
 
 		return "", fmt.Errorf("failed to execute template: %w", err)
@@ -1103,7 +1248,7 @@ func (n *shoutrrrTypeNotifier) buildMessage(data Data) (string, error) {
 	log.Debug().
 		Int("msg_length", len(msg)).
 		Bool("legacy_template", n.legacyTemplate).
-		Str("template_name", n.template.Name()).
+		Str("template_name", tpl.Name()).
 		Int("entries_count", len(data.Entries)).
 		Msg("Template processing completed successfully")
 
@@ -1143,7 +1288,16 @@ func reportCategoryCount(report types.Report) int {
 func (n *shoutrrrTypeNotifier) sendEntries(entries []*notificationEntry, report types.Report) {
 	log := n.ll()
 
-	msg, err := n.buildMessage(Data{n.data, entries, report})
+	if n.routing {
+		entries, report = n.dispatchRoutes(entries, report)
+	}
+
+	data := Data{StaticData: n.data, Entries: entries, Report: report}
+	if n.announcements != nil {
+		data.Notices = n.announcements.notices(report)
+	}
+
+	msg, err := n.renderMessage(data)
 	if err != nil {
 		log.Debug().
 			Err(err).
@@ -1167,24 +1321,44 @@ func (n *shoutrrrTypeNotifier) sendEntries(entries []*notificationEntry, report
 		return
 	}
 
+	note := notification{Message: msg}
+	if n.email != nil {
+		note.Email = n.composeEmail(data, msg)
+	}
+
+	if n.holdForQuietHours(note, isFailureNotification(entries, report)) {
+		return
+	}
+
+	n.enqueue(note, len(entries))
+}
+
+// enqueue hands a rendered message to the send worker without blocking.
+//
+// Parameters:
+//   - msg: Rendered notification message.
+//   - entriesCount: Number of log entries the message was rendered from, for diagnostics.
+func (n *shoutrrrTypeNotifier) enqueue(msg notification, entriesCount int) {
+	log := n.ll()
+
 	// Use select with non-blocking send to coordinate with shutdown.
 	// This ensures we can't enqueue messages after shutdown has begun.
 	select {
 	case n.messages <- msg:
 		// Message sent successfully to channel
 		log.Debug().
-			Int("entries_count", len(entries)).
-			Int("msg_length", len(msg)).
+			Int("entries_count", entriesCount).
+			Int("msg_length", len(msg.Message)).
 			Str("channel_status", "sent").
 			Msg("Successfully sent message to notification channel")
 	default:
 		// Non-blocking send failed - check if closed or done before returning.
 		// This check is done AFTER the send attempt to catch the race condition
-		// where Close() signaled stop but sendEntries was already in progress.
+		// where Close() signaled stop but a send was already in progress.
 		if n.closed.Load() {
 			log.Debug().
-				Int("entries_count", len(entries)).
-				Int("msg_length", len(msg)).
+				Int("entries_count", entriesCount).
+				Int("msg_length", len(msg.Message)).
 				Str("channel_status", "closed").
 				Msg("Notifier closed, skipping send")
 
@@ -1195,8 +1369,8 @@ func (n *shoutrrrTypeNotifier) sendEntries(entries []*notificationEntry, report
 		select {
 		case <-n.done:
 			log.Debug().
-				Int("entries_count", len(entries)).
-				Int("msg_length", len(msg)).
+				Int("entries_count", entriesCount).
+				Int("msg_length", len(msg.Message)).
 				Str("channel_status", "worker_done").
 				Msg("Worker goroutine done, skipping send")
 
@@ -1204,8 +1378,8 @@ func (n *shoutrrrTypeNotifier) sendEntries(entries []*notificationEntry, report
 		default:
 			// Channel is full (not closed, not done), apply backpressure
 			log.Debug().
-				Int("entries_count", len(entries)).
-				Int("msg_length", len(msg)).
+				Int("entries_count", entriesCount).
+				Int("msg_length", len(msg.Message)).
 				Str("channel_status", "full").
 				Msg("Channel full, skipping send (backpressure)")
 		}
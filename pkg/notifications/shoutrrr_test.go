@@ -1182,7 +1182,7 @@ func sendNotificationsWithBlockingRouter() (*shoutrrrTypeNotifier, *blockingRout
 
 	shoutrrr := &shoutrrrTypeNotifier{
 		template:       tpl,
-		messages:       make(chan string, 1),
+		messages:       make(chan notification, 1),
 		done:           make(chan struct{}),
 		Router:         router,
 		legacyTemplate: legacy,
@@ -1366,7 +1366,7 @@ func TestGracePeriodAllowsInFlightMessages(t *testing.T) {
 
 		shoutrrr := &shoutrrrTypeNotifier{
 			template:       tpl,
-			messages:       make(chan string, 1),
+			messages:       make(chan notification, 1),
 			done:           make(chan struct{}),
 			stop:           make(chan struct{}),
 			Router:         controlledR,
@@ -1383,7 +1383,7 @@ func TestGracePeriodAllowsInFlightMessages(t *testing.T) {
 		go sendNotifications(shoutrrr)
 
 		// Queue a message
-		shoutrrr.messages <- "test message"
+		shoutrrr.messages <- notification{Message: "test message"}
 
 		// Wait for goroutine to be blocked in router.Send
 		synctest.Wait()
@@ -1798,7 +1798,7 @@ func TestSend_CanceledBeforeSend(t *testing.T) {
 		cancel: cancel,
 	}
 
-	notifier.send("should not send")
+	notifier.send(notification{Message: "should not send"})
 	assert.Equal(t, int32(0), router.sends.Load())
 }
 
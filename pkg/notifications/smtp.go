@@ -0,0 +1,532 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nicholas-fedor/shoutrrr/pkg/format"
+
+	shoutrrrSMTP "github.com/nicholas-fedor/shoutrrr/pkg/services/email/smtp"
+	shoutrrrTypes "github.com/nicholas-fedor/shoutrrr/pkg/types"
+)
+
+// smtpScheme is the Shoutrrr URL scheme of email notifications.
+const smtpScheme = "smtp"
+
+// smtpDefaultSubject is the subject of HTML email when neither the URL nor the notification sets one.
+const smtpDefaultSubject = "Watchtower updates"
+
+// smtpClientHostAuto is the clienthost value that sends the local hostname in EHLO.
+const smtpClientHostAuto = "auto"
+
+// smtpLineLength is the maximum length of base64 lines in attachments.
+const smtpLineLength = 76
+
+// smtpMessageIDBytes is the number of random bytes in a generated Message-ID.
+const smtpMessageIDBytes = 16
+
+var (
+	// errSMTPParams indicates the notification parameters do not fit the smtp URL's options.
+	errSMTPParams = errors.New("failed to apply notification parameters to smtp URL")
+	// errSMTPStartTLS indicates the server does not offer STARTTLS although the URL requires it.
+	errSMTPStartTLS = errors.New("smtp server does not support STARTTLS")
+)
+
+// smtpSender delivers notifications to one smtp:// URL.
+//
+// The URL is parsed by Shoutrrr's smtp service, so it takes all of that
+// service's query parameters. Plain messages are sent by the service itself.
+// Notifications with email parts are sent as multipart email over a
+// connection set up from the same options: encryption, STARTTLS, TLS
+// verification, authentication including OAuth2, client host, and timeout.
+// Like the service, each recipient gets a separate email.
+type smtpSender struct {
+	service *shoutrrrSMTP.Service
+	now     func() time.Time
+}
+
+// newSMTPSender creates a sender for an smtp:// URL.
+//
+// Parameters:
+//   - rawURL: Shoutrrr smtp:// URL.
+//   - logger: Shoutrrr logger for delivery diagnostics.
+//
+// Returns:
+//   - *smtpSender: Sender for the URL.
+//   - error: Non-nil if Shoutrrr's smtp service rejects the URL.
+func newSMTPSender(rawURL string, logger shoutrrrTypes.StdLogger) (*smtpSender, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse smtp URL: %w", err)
+	}
+
+	service := &shoutrrrSMTP.Service{}
+
+	err = service.Initialize(parsed, logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp URL: %w", err)
+	}
+
+	return &smtpSender{service: service, now: time.Now}, nil
+}
+
+// Send sends message as a plain-text email through Shoutrrr's smtp service.
+//
+// Parameters:
+//   - message: Rendered notification.
+//   - params: Notification parameters.
+//
+// Returns:
+//   - []error: One error, nil on success.
+func (s *smtpSender) Send(message string, params *shoutrrrTypes.Params) []error {
+	return []error{s.service.Send(message, params)}
+}
+
+// sendNotification sends msg as multipart email, or as plain text when it has no email parts.
+//
+// Parameters:
+//   - msg: Rendered notification.
+//   - params: Notification parameters, applied to the URL's options like Shoutrrr does; the title is the subject.
+//
+// Returns:
+//   - []error: One error, nil on success.
+func (s *smtpSender) sendNotification(msg notification, params *shoutrrrTypes.Params) []error {
+	if msg.Email == nil {
+		return s.Send(msg.Message, params)
+	}
+
+	config := s.service.Config.Clone()
+	resolver := format.NewPropKeyResolver(&config)
+
+	err := resolver.UpdateConfigFromParams(&config, params)
+	if err != nil {
+		return []error{fmt.Errorf("%w: %w", errSMTPParams, err)}
+	}
+
+	config.FixEmailTags()
+
+	if config.Subject == "" {
+		config.Subject = smtpDefaultSubject
+	}
+
+	return []error{s.deliver(&config, *msg.Email)}
+}
+
+// compose builds the RFC 5322 message for one recipient.
+//
+// Parameters:
+//   - config: URL options with the sender and subject.
+//   - msg: Email parts.
+//   - recipient: Address of the To header.
+//
+// Returns:
+//   - []byte: Message with CRLF line endings.
+//   - error: Non-nil if a MIME part cannot be written.
+func (s *smtpSender) compose(config *shoutrrrSMTP.Config, msg emailMessage, recipient string) ([]byte, error) {
+	header, body, err := emailBody(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	from := mail.Address{Name: config.FromName, Address: config.FromAddress}
+
+	var buf bytes.Buffer
+
+	writeHeader(&buf, "From", from.String())
+	writeHeader(&buf, "To", recipient)
+	writeHeader(&buf, "Subject", mime.QEncoding.Encode("utf-8", config.Subject))
+	writeHeader(&buf, "Date", s.now().Format(time.RFC1123Z))
+	writeHeader(&buf, "Message-ID", messageID(config.FromAddress))
+	writeHeader(&buf, "MIME-Version", "1.0")
+
+	for _, key := range []string{"Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			writeHeader(&buf, key, value)
+		}
+	}
+
+	buf.WriteString("\r\n")
+	buf.Write(body)
+
+	return buf.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID in the domain of the from address.
+func messageID(from string) string {
+	id := make([]byte, smtpMessageIDBytes)
+	_, _ = rand.Read(id)
+
+	domain := from[strings.LastIndex(from, "@")+1:]
+
+	return "<" + hex.EncodeToString(id) + "@" + domain + ">"
+}
+
+// deliver sends msg to every recipient over one SMTP connection.
+//
+// Failures for single recipients do not stop delivery to the others and are returned together.
+//
+// Parameters:
+//   - config: URL options with notification parameters applied.
+//   - msg: Email parts.
+//
+// Returns:
+//   - error: Non-nil if the SMTP session fails or any recipient is rejected.
+func (s *smtpSender) deliver(config *shoutrrrSMTP.Config, msg emailMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	defer cancel()
+
+	client, implicit, err := dialSMTP(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	err = client.Hello(s.clientHost(config))
+	if err != nil {
+		return fmt.Errorf("smtp EHLO failed: %w", err)
+	}
+
+	if config.UseStartTLS && !implicit {
+		if supported, _ := client.Extension("STARTTLS"); supported {
+			err = client.StartTLS(smtpTLSConfig(config))
+			if err != nil {
+				return fmt.Errorf("smtp STARTTLS failed: %w", err)
+			}
+		} else if config.RequireStartTLS {
+			return errSMTPStartTLS
+		} else {
+			s.service.Logf("Warning: StartTLS enabled, but server does not support it. Connection is unencrypted")
+		}
+	}
+
+	if auth := smtpAuth(config); auth != nil {
+		err = client.Auth(auth)
+		if err != nil {
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	var errs []error
+
+	for _, recipient := range config.ToAddresses {
+		err = s.sendToRecipient(client, config, msg, recipient)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send email to %s: %w", recipient, err))
+		}
+	}
+
+	err = client.Quit()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("smtp QUIT failed: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// sendToRecipient sends one email within the SMTP session.
+//
+// Parameters:
+//   - client: Authenticated SMTP client.
+//   - config: URL options.
+//   - msg: Email parts.
+//   - recipient: Recipient address.
+//
+// Returns:
+//   - error: Non-nil if the server rejects the email.
+func (s *smtpSender) sendToRecipient(
+	client *smtp.Client,
+	config *shoutrrrSMTP.Config,
+	msg emailMessage,
+	recipient string,
+) error {
+	content, err := s.compose(config, msg, recipient)
+	if err != nil {
+		return err
+	}
+
+	err = client.Mail(config.FromAddress)
+	if err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+
+	err = client.Rcpt(recipient)
+	if err != nil {
+		return fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %w", err)
+	}
+
+	_, err = writer.Write(content)
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+
+	return nil
+}
+
+// clientHost returns the name sent in EHLO, resolving "auto" to the local hostname.
+func (s *smtpSender) clientHost(config *shoutrrrSMTP.Config) string {
+	if config.ClientHost != smtpClientHostAuto {
+		return config.ClientHost
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil {
+		s.service.Logf("Failed to get hostname, falling back to localhost: %v", err)
+
+		return "localhost"
+	}
+
+	return hostname
+}
+
+// dialSMTP connects to the server of config and starts an SMTP session.
+//
+// Parameters:
+//   - ctx: Bounds the connection and the whole session.
+//   - config: URL options.
+//
+// Returns:
+//   - *smtp.Client: The session.
+//   - bool: Whether the connection uses implicit TLS.
+//   - error: Non-nil if the server cannot be reached.
+func dialSMTP(ctx context.Context, config *shoutrrrSMTP.Config) (*smtp.Client, bool, error) {
+	address := net.JoinHostPort(config.Host, strconv.FormatUint(uint64(config.Port), 10))
+
+	implicit := config.Encryption == shoutrrrSMTP.EncMethods.ImplicitTLS ||
+		(config.Encryption == shoutrrrSMTP.EncMethods.Auto && config.Port == shoutrrrSMTP.ImplicitTLSPort)
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	if implicit {
+		dialer := &tls.Dialer{Config: smtpTLSConfig(config)}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, false, fmt.Errorf("failed to start smtp session: %w", err)
+	}
+
+	return client, implicit, nil
+}
+
+// smtpTLSConfig returns the TLS settings of config.
+func smtpTLSConfig(config *shoutrrrSMTP.Config) *tls.Config {
+	return &tls.Config{
+		ServerName:         config.Host,
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.SkipTLSVerify, //nolint:gosec // Opt-in through skiptlsverify, as in Shoutrrr.
+	}
+}
+
+// smtpAuth returns the authentication of config.
+//
+// Shoutrrr resolves an unset auth type to Plain with a username and None without one.
+func smtpAuth(config *shoutrrrSMTP.Config) smtp.Auth {
+	switch config.Auth {
+	case shoutrrrSMTP.AuthTypes.Plain:
+		return smtp.PlainAuth("", config.Username, config.Password, config.Host)
+	case shoutrrrSMTP.AuthTypes.CRAMMD5:
+		return smtp.CRAMMD5Auth(config.Username, config.Password)
+	case shoutrrrSMTP.AuthTypes.OAuth2:
+		return shoutrrrSMTP.OAuth2Auth(config.Username, config.Password)
+	default:
+		return nil
+	}
+}
+
+// writeHeader writes one header field with a CRLF line ending.
+func writeHeader(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	buf.WriteString(": ")
+	buf.WriteString(value)
+	buf.WriteString("\r\n")
+}
+
+// emailBody renders the MIME body of msg.
+//
+// A message with only text is a single text/plain part. An HTML part makes it
+// multipart/alternative, and attachments wrap that in multipart/mixed.
+//
+// Parameters:
+//   - msg: Email parts.
+//
+// Returns:
+//   - textproto.MIMEHeader: Content headers of the body.
+//   - []byte: Encoded body.
+//   - error: Non-nil if a part cannot be written.
+func emailBody(msg emailMessage) (textproto.MIMEHeader, []byte, error) {
+	header, body, err := alternativeBody(msg)
+	if err != nil || len(msg.Attachments) == 0 {
+		return header, body, err
+	}
+
+	var buf bytes.Buffer
+
+	mixed := multipart.NewWriter(&buf)
+
+	part, err := mixed.CreatePart(header)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create email part: %w", err)
+	}
+
+	_, err = part.Write(body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to write email part: %w", err)
+	}
+
+	for _, attachment := range msg.Attachments {
+		err = writeAttachment(mixed, attachment)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	err = mixed.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to finish email: %w", err)
+	}
+
+	return textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mixed.Boundary()})},
+	}, buf.Bytes(), nil
+}
+
+// alternativeBody renders the text part, and the HTML part when msg has one.
+func alternativeBody(msg emailMessage) (textproto.MIMEHeader, []byte, error) {
+	if msg.HTML == "" {
+		body, err := quotedPrintable(msg.Text)
+
+		return textPartHeader("text/plain"), body, err
+	}
+
+	var buf bytes.Buffer
+
+	alternative := multipart.NewWriter(&buf)
+
+	for _, content := range []struct{ mediaType, value string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		part, err := alternative.CreatePart(textPartHeader(content.mediaType))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create email part: %w", err)
+		}
+
+		body, err := quotedPrintable(content.value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		_, err = part.Write(body)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to write email part: %w", err)
+		}
+	}
+
+	err := alternative.Close()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to finish email: %w", err)
+	}
+
+	return textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": alternative.Boundary()})},
+	}, buf.Bytes(), nil
+}
+
+// textPartHeader returns the headers of a quoted-printable UTF-8 text part.
+func textPartHeader(mediaType string) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(mediaType, map[string]string{"charset": "utf-8"})},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	}
+}
+
+// quotedPrintable encodes text with CRLF line endings.
+func quotedPrintable(text string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := quotedprintable.NewWriter(&buf)
+
+	_, err := io.WriteString(writer, strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n"))
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode email text: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeAttachment adds a base64-encoded attachment part.
+func writeAttachment(mixed *multipart.Writer, attachment emailAttachment) error {
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Name})},
+		"Content-Disposition": {
+			mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name}),
+		},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create email attachment: %w", err)
+	}
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(attachment.Content))
+
+	for len(encoded) > 0 {
+		line := encoded[:min(smtpLineLength, len(encoded))]
+		encoded = encoded[len(line):]
+
+		_, err = io.WriteString(part, line+"\r\n")
+		if err != nil {
+			return fmt.Errorf("failed to write email attachment: %w", err)
+		}
+	}
+
+	return nil
+}
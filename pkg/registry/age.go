@@ -89,6 +89,9 @@ type imageManifest struct {
 // imageConfig represents the relevant fields from an image config blob.
 type imageConfig struct {
 	Created *time.Time `json:"created"`
+	Config  struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
 }
 
 // FetchImageCreationTime retrieves the image creation timestamp from the registry
@@ -117,6 +120,80 @@ func FetchImageCreationTime(log *zerolog.Logger,
 	container types.Container,
 	registryAuth string,
 ) (time.Time, error) {
+	config, fields, err := fetchImageConfig(log, ctx, container, registryAuth)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Check if the created field is present.
+	if config.Created == nil {
+		log.Debug().
+			Fields(fields).
+			Msg("Image config does not contain creation timestamp")
+
+		return time.Time{}, errImageCreationTimeMissing
+	}
+
+	log.Debug().
+		Fields(fields).
+		Time("created", *config.Created).
+		Msg("Fetched image creation time from registry")
+
+	return *config.Created, nil
+}
+
+// FetchImageMetadata retrieves the OCI metadata labels of the image a container's
+// tag currently points to in the registry, without pulling the image.
+//
+// Like FetchImageCreationTime, only the manifest and config blob are fetched and
+// the same platform selection applies.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control.
+//   - container: Container whose image metadata to fetch.
+//   - registryAuth: Base64-encoded registry credentials.
+//
+// Returns:
+//   - types.ImageMetadata: Metadata from the registry config labels.
+//   - error: Non-nil if any step fails.
+func FetchImageMetadata(log *zerolog.Logger,
+	ctx context.Context,
+	container types.Container,
+	registryAuth string,
+) (types.ImageMetadata, error) {
+	config, fields, err := fetchImageConfig(log, ctx, container, registryAuth)
+	if err != nil {
+		return types.ImageMetadata{}, err
+	}
+
+	metadata := types.ImageMetadataFromLabels(config.Config.Labels)
+
+	log.Debug().
+		Fields(fields).
+		Str("version", metadata.Version).
+		Str("revision", metadata.Revision).
+		Msg("Fetched image metadata from registry")
+
+	return metadata, nil
+}
+
+// fetchImageConfig fetches and parses the config blob of the image a container's
+// tag points to in the registry.
+//
+// Parameters:
+//   - ctx: Context for cancellation and timeout control.
+//   - container: Container whose image config to fetch.
+//   - registryAuth: Base64-encoded registry credentials.
+//
+// Returns:
+//   - imageConfig: Parsed config blob.
+//   - map[string]any: Log fields identifying the container and image.
+//   - error: Non-nil if any step fails.
+func fetchImageConfig(log *zerolog.Logger,
+	ctx context.Context,
+	container types.Container,
+	registryAuth string,
+) (imageConfig, map[string]any, error) {
 	fields := map[string]any{
 		"container": container.Name(),
 		"image":     container.ImageName(),
@@ -157,7 +234,7 @@ func FetchImageCreationTime(log *zerolog.Logger,
 			Fields(fields).
 			Msg("Failed to get auth token for image age check")
 
-		return time.Time{},
+		return imageConfig{}, fields,
 			fmt.Errorf("%w: %w", errFetchManifestFailed, err)
 	}
 
@@ -177,7 +254,7 @@ func FetchImageCreationTime(log *zerolog.Logger,
 			Fields(fields).
 			Msg("Failed to build manifest URL")
 
-		return time.Time{}, fmt.Errorf("%w: %w", errFetchManifestFailed, err)
+		return imageConfig{}, fields, fmt.Errorf("%w: %w", errFetchManifestFailed, err)
 	}
 
 	// Determine the primary manifest host based on auth redirect.
@@ -193,7 +270,7 @@ func FetchImageCreationTime(log *zerolog.Logger,
 				Fields(fields).
 				Msg("Failed to build manifest URL with redirect host")
 
-			return time.Time{},
+			return imageConfig{}, fields,
 				fmt.Errorf("%w: %w", errFetchManifestFailed, err)
 		}
 	}
@@ -214,7 +291,7 @@ func FetchImageCreationTime(log *zerolog.Logger,
 		fields,
 	)
 	if err != nil {
-		return time.Time{}, err
+		return imageConfig{}, fields, err
 	}
 
 	log.Debug().
@@ -236,7 +313,7 @@ func FetchImageCreationTime(log *zerolog.Logger,
 		fields,
 	)
 	if err != nil {
-		return time.Time{}, err
+		return imageConfig{}, fields, err
 	}
 	defer configBody.Close()
 
@@ -250,25 +327,11 @@ func FetchImageCreationTime(log *zerolog.Logger,
 			Fields(fields).
 			Msg("Failed to parse image config JSON")
 
-		return time.Time{},
+		return imageConfig{}, fields,
 			fmt.Errorf("%w: %w", errParseConfigFailed, err)
 	}
 
-	// Check if the created field is present.
-	if config.Created == nil {
-		log.Debug().
-			Fields(fields).
-			Msg("Image config does not contain creation timestamp")
-
-		return time.Time{}, errImageCreationTimeMissing
-	}
-
-	log.Debug().
-		Fields(fields).
-		Time("created", *config.Created).
-		Msg("Fetched image creation time from registry")
-
-	return *config.Created, nil
+	return config, fields, nil
 }
 
 // buildManifestURLForAge constructs the manifest URL for image age checking.
@@ -21,6 +21,7 @@ import (
 
 	"github.com/nicholas-fedor/watchtower/internal/logging"
 	"github.com/nicholas-fedor/watchtower/pkg/registry/ratelimit"
+	"github.com/nicholas-fedor/watchtower/pkg/types"
 	mockTypes "github.com/nicholas-fedor/watchtower/pkg/types/mocks"
 )
 
@@ -952,6 +953,55 @@ func TestPipeline_MissingCreationTimestamp(t *testing.T) {
 	assert.Nil(t, config.Created, "created field should be nil when absent")
 }
 
+func TestPipeline_ConfigLabels_ReturnImageMetadata(t *testing.T) {
+	t.Parallel()
+
+	server := ghttp.NewServer()
+	t.Cleanup(server.Close)
+
+	configDigest := "sha256:configlabels0abcdef1234567890abcdef1234567890abcdef1234567890abcd"
+
+	server.AppendHandlers(
+		ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/v2/library/alpine/manifests/3.19"),
+			ghttp.RespondWith(http.StatusOK, validManifestJSON(configDigest),
+				http.Header{"Content-Type": {"application/vnd.docker.distribution.manifest.v2+json"}},
+			),
+		),
+		ghttp.CombineHandlers(
+			ghttp.VerifyRequest("GET", "/v2/library/alpine/blobs/"+configDigest),
+			ghttp.RespondWith(http.StatusOK, `{"created":"`+testCreatedTimestamp+`","config":{"Labels":{`+
+				`"org.opencontainers.image.version":"3.19.1",`+
+				`"org.opencontainers.image.revision":"def5678",`+
+				`"org.opencontainers.image.source":"https://github.com/alpinelinux/docker-alpine"}}}`),
+		),
+	)
+
+	parsedURL, err := url.Parse(server.URL() + "/v2/library/alpine/manifests/3.19")
+	require.NoError(t, err)
+
+	client := server.HTTPTestServer.Client()
+	ctx := context.Background()
+	fields := map[string]any{"test": "pipeline_labels"}
+
+	digest, _, err := fetchManifestForAge(testLog(), ctx, client, parsedURL.String(), "", parsedURL, "", "", "", "", parsedURL.Host, fields)
+	require.NoError(t, err)
+
+	body, err := fetchConfigBlob(testLog(), ctx, client, parsedURL, digest, "", fields)
+	require.NoError(t, err)
+	t.Cleanup(func() { body.Close() })
+
+	var config imageConfig
+
+	err = json.NewDecoder(body).Decode(&config)
+	require.NoError(t, err)
+	assert.Equal(t, types.ImageMetadata{
+		Version:  "3.19.1",
+		Revision: "def5678",
+		Source:   "https://github.com/alpinelinux/docker-alpine",
+	}, types.ImageMetadataFromLabels(config.Config.Labels))
+}
+
 func TestPipeline_ConfigBlobNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -17,6 +17,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/registry/ratelimit"
 	"github.com/nicholas-fedor/watchtower/pkg/types"
 )
@@ -115,6 +116,9 @@ func GetBearerToken(log *zerolog.Logger,
 	registryAuth string,
 	client Client,
 ) (string, error) {
+	ctx, span := tracing.Start(ctx, "registry.get_bearer_token", tracing.ImageNameKey.String(imageRef.Name()))
+	defer span.End()
+
 	clog := log.With().Str("image", imageRef.Name()).Logger()
 	clog.Debug().Msg("Fetching bearer token from challenge")
 
@@ -125,7 +129,10 @@ func GetBearerToken(log *zerolog.Logger,
 		registryAuth,
 	)
 	if err != nil {
-		return "", fmt.Errorf("%w: %w", errFailedConstructBearerAuthURL, err)
+		err = fmt.Errorf("%w: %w", errFailedConstructBearerAuthURL, err)
+		tracing.RecordError(span, err)
+
+		return "", err
 	}
 
 	token, err := executeBearerTokenRequest(&clog,
@@ -136,6 +143,8 @@ func GetBearerToken(log *zerolog.Logger,
 		client,
 	)
 	if err != nil {
+		tracing.RecordError(span, err)
+
 		return "", err
 	}
 
@@ -11,6 +11,9 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
+
+	"github.com/nicholas-fedor/watchtower/internal/metrics"
+	"github.com/nicholas-fedor/watchtower/pkg/registry/ratelimit"
 )
 
 // Constants for HTTP client configuration.
@@ -68,7 +71,8 @@ type registryClient struct {
 // Do executes an HTTP request using the underlying HTTP client.
 //
 // This method satisfies the Client interface, delegating the request execution
-// to the embedded HTTP client.
+// to the embedded HTTP client and recording request duration and rate-limit
+// budget metrics per registry host.
 //
 // Parameters:
 //   - request: The HTTP request to execute.
@@ -77,7 +81,10 @@ type registryClient struct {
 //   - *http.Response: The HTTP response from the registry, if successful.
 //   - error: Non-nil if the request fails, nil otherwise.
 func (r *registryClient) Do(request *http.Request) (*http.Response, error) {
+	start := time.Now()
 	response, err := r.client.Do(request)
+	observeRegistryRequest(request, response, time.Since(start))
+
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute HTTP request: %w", err)
 	}
@@ -85,6 +92,31 @@ func (r *registryClient) Do(request *http.Request) (*http.Response, error) {
 	return response, nil
 }
 
+// observeRegistryRequest records registry request metrics.
+//
+// Parameters:
+//   - request: The executed request.
+//   - response: The response, or nil when the request failed.
+//   - duration: Time taken by the request.
+func observeRegistryRequest(request *http.Request, response *http.Response, duration time.Duration) {
+	if request == nil || request.URL == nil {
+		return
+	}
+
+	host := request.URL.Host
+	status := 0
+
+	if response != nil {
+		status = response.StatusCode
+
+		if remaining, ok := ratelimit.ParseRemaining(response.Header); ok {
+			metrics.Default().SetRegistryRateLimitRemaining(host, remaining)
+		}
+	}
+
+	metrics.Default().ObserveRegistryRequest(host, request.Method, status, duration)
+}
+
 // ConfigureTLS builds a TLS configuration from Viper settings.
 //
 // Parameters:
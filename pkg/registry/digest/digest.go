@@ -19,8 +19,10 @@ import (
 	"github.com/distribution/reference"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/nicholas-fedor/watchtower/internal/meta"
+	"github.com/nicholas-fedor/watchtower/internal/tracing"
 	"github.com/nicholas-fedor/watchtower/pkg/registry/auth"
 	"github.com/nicholas-fedor/watchtower/pkg/registry/manifest"
 	"github.com/nicholas-fedor/watchtower/pkg/registry/ratelimit"
@@ -525,7 +527,37 @@ func BuildManifestURL(log *zerolog.Logger,
 	return manifestURLStr, originalHost, parsedURL, nil
 }
 
-// fetchDigest retrieves an image digest using the specified HTTP method.
+// fetchDigest retrieves an image digest using the specified HTTP method inside
+// a registry.fetch_digest span.
+//
+// Parameters:
+//   - ctx: Context for request lifecycle control.
+//   - container: Container whose digest is being retrieved.
+//   - registryAuth: Base64-encoded auth string.
+//   - method: HTTP method ("HEAD" or "GET").
+//   - endpoints: Optional list of registry mirror host overrides to try before the canonical host.
+//
+// Returns:
+//   - string: Normalized digest.
+//   - error: Non-nil if operation fails, nil on success.
+func fetchDigest(log *zerolog.Logger,
+	ctx context.Context,
+	container types.Container,
+	registryAuth string,
+	method string,
+	endpoints ...string,
+) (string, error) {
+	ctx, span := tracing.StartContainer(ctx, "registry.fetch_digest", container,
+		attribute.String("http.request.method", method))
+	defer span.End()
+
+	digest, err := fetchDigestFromEndpoints(log, ctx, container, registryAuth, method, endpoints...)
+	tracing.RecordError(span, err)
+
+	return digest, err
+}
+
+// fetchDigestFromEndpoints retrieves an image digest using the specified HTTP method.
 //
 // When endpoints are provided, each mirror host is tried in order. An empty string
 // endpoint means use the canonical registry host. If all endpoints fail, the last
@@ -541,7 +573,7 @@ func BuildManifestURL(log *zerolog.Logger,
 // Returns:
 //   - string: Normalized digest.
 //   - error: Non-nil if operation fails, nil on success.
-func fetchDigest(log *zerolog.Logger,
+func fetchDigestFromEndpoints(log *zerolog.Logger,
 	ctx context.Context,
 	container types.Container,
 	registryAuth string,
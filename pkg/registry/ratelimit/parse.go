@@ -148,6 +148,38 @@ func FromResponse(resp *http.Response, body []byte) *Error {
 	return info
 }
 
+// ParseRemaining reads the remaining request budget from response headers.
+//
+// It checks RateLimit-Remaining and then X-RateLimit-Remaining. Docker Hub
+// sends values such as "76;w=21600".
+//
+// Parameters:
+//   - header: Response headers. May be nil.
+//
+// Returns:
+//   - int: Remaining requests in the current window.
+//   - bool: True when a usable header was present.
+func ParseRemaining(header http.Header) (int, bool) {
+	for _, name := range []string{"Ratelimit-Remaining", "X-Ratelimit-Remaining"} {
+		value := strings.TrimSpace(header.Get(name))
+		if value == "" {
+			continue
+		}
+
+		match := rateLimitLimit.FindStringSubmatch(value)
+		if match == nil {
+			continue
+		}
+
+		remaining, err := strconv.Atoi(match[1])
+		if err == nil && remaining >= 0 {
+			return remaining, true
+		}
+	}
+
+	return 0, false
+}
+
 // FromErrorMessage builds a rate-limit error from Docker pull-stream text.
 //
 // Parameters:
@@ -445,3 +445,57 @@ func (failingReadCloser) Close() error {
 }
 
 var errReadFailed = io.ErrUnexpectedEOF
+
+func TestParseRemaining(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+		wantOK bool
+	}{
+		{
+			name:   "docker hub window",
+			header: http.Header{"Ratelimit-Remaining": []string{"76;w=21600"}},
+			want:   76,
+			wantOK: true,
+		},
+		{
+			name:   "x-prefixed header",
+			header: http.Header{"X-Ratelimit-Remaining": []string{"4999"}},
+			want:   4999,
+			wantOK: true,
+		},
+		{
+			name:   "zero remaining",
+			header: http.Header{"Ratelimit-Remaining": []string{"0"}},
+			want:   0,
+			wantOK: true,
+		},
+		{
+			name:   "missing header",
+			header: http.Header{},
+			wantOK: false,
+		},
+		{
+			name:   "unparseable header",
+			header: http.Header{"Ratelimit-Remaining": []string{"soon"}},
+			wantOK: false,
+		},
+		{
+			name:   "nil header",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, ok := ParseRemaining(tt.header)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -37,20 +37,26 @@ const (
 //
 //nolint:errname // ContainerStatus is not an error type, it contains an error field.
 type ContainerStatus struct {
-	containerID        types.ContainerID // Container ID.
-	oldImage           types.ImageID     // Original image ID.
-	newImage           types.ImageID     // Latest image ID.
-	containerName      string            // Container name.
-	imageName          string            // Image name with tag.
-	containerError     error             // Error encountered, if any.
-	state              State             // Current state.
-	monitorOnly        bool              // Monitor-only flag.
-	newContainerID     types.ContainerID // New container ID after update.
-	cooldownPassed     bool              // True if image passed cooldown check.
-	cooldownAge        string            // Human-readable image age (e.g., "47 days, 11 hours").
-	cooldownDelay      string            // Human-readable cooldown duration (e.g., "24 hours").
-	cooldownRemaining  string            // Human-readable remaining time (empty if passed).
-	cooldownEligibleAt time.Time         // Time when the container becomes eligible for update.
+	containerID        types.ContainerID   // Container ID.
+	oldImage           types.ImageID       // Original image ID.
+	newImage           types.ImageID       // Latest image ID.
+	oldImageMetadata   types.ImageMetadata // Original image OCI metadata.
+	newImageMetadata   types.ImageMetadata // Latest image OCI metadata.
+	containerName      string              // Container name.
+	imageName          string              // Image name with tag.
+	containerError     error               // Error encountered, if any.
+	state              State               // Current state.
+	monitorOnly        bool                // Monitor-only flag.
+	newContainerID     types.ContainerID   // New container ID after update.
+	labels             map[string]string   // Container labels at scan time.
+	cooldownPassed     bool                // True if image passed cooldown check.
+	cooldownAge        string              // Human-readable image age (e.g., "47 days, 11 hours").
+	cooldownDelay      string              // Human-readable cooldown duration (e.g., "24 hours").
+	cooldownRemaining  string              // Human-readable remaining time (empty if passed).
+	cooldownEligibleAt time.Time           // Time when the container becomes eligible for update.
+	hookRuns           []types.HookRun     // Lifecycle hook runs, in execution order.
+	backupPath         string              // Volume backup taken before the update.
+	crashLogs          []string            // Last log lines of a container that crashed after the update.
 }
 
 // ID returns the container ID.
@@ -85,6 +91,22 @@ func (u *ContainerStatus) LatestImageID() types.ImageID {
 	return u.newImage
 }
 
+// CurrentImageMetadata returns the original image's OCI metadata.
+//
+// Returns:
+//   - types.ImageMetadata: Metadata of the image at session start.
+func (u *ContainerStatus) CurrentImageMetadata() types.ImageMetadata {
+	return u.oldImageMetadata
+}
+
+// LatestImageMetadata returns the latest image's OCI metadata.
+//
+// Returns:
+//   - types.ImageMetadata: Metadata of the newest image, or empty if it was not inspected.
+func (u *ContainerStatus) LatestImageMetadata() types.ImageMetadata {
+	return u.newImageMetadata
+}
+
 // ImageName returns the image name with tag.
 //
 // Returns:
@@ -148,6 +170,62 @@ func (u *ContainerStatus) NewContainerID() types.ContainerID {
 	return u.newContainerID
 }
 
+// Labels returns the container's labels at scan time.
+//
+// Returns:
+//   - map[string]string: Label map, or nil if unavailable.
+func (u *ContainerStatus) Labels() map[string]string {
+	return u.labels
+}
+
+// HookRuns returns the lifecycle hook runs recorded for this container.
+//
+// Returns:
+//   - []types.HookRun: Runs in execution order, or nil if no hooks ran.
+func (u *ContainerStatus) HookRuns() []types.HookRun {
+	return u.hookRuns
+}
+
+// SetHookRuns sets the lifecycle hook runs recorded for this container.
+//
+// Parameters:
+//   - runs: Runs in execution order.
+func (u *ContainerStatus) SetHookRuns(runs []types.HookRun) {
+	u.hookRuns = runs
+}
+
+// BackupPath returns the volume backup taken before this container was updated.
+//
+// Returns:
+//   - string: Backup directory, or empty if no backup was taken.
+func (u *ContainerStatus) BackupPath() string {
+	return u.backupPath
+}
+
+// SetBackupPath sets the volume backup taken before this container was updated.
+//
+// Parameters:
+//   - path: Backup directory.
+func (u *ContainerStatus) SetBackupPath(path string) {
+	u.backupPath = path
+}
+
+// CrashLogs returns the last log lines of this container if it crashed after the update.
+//
+// Returns:
+//   - []string: Log lines, oldest first, or nil if the container did not crash.
+func (u *ContainerStatus) CrashLogs() []string {
+	return u.crashLogs
+}
+
+// SetCrashLogs sets the last log lines of this container after it crashed following the update.
+//
+// Parameters:
+//   - lines: Log lines, oldest first.
+func (u *ContainerStatus) SetCrashLogs(lines []string) {
+	u.crashLogs = lines
+}
+
 // SetNewContainerID sets the new container ID after update.
 //
 // Parameters:
@@ -3,6 +3,7 @@ package session
 import (
 	"time"
 
+	dockerImage "github.com/moby/moby/api/types/image"
 	"github.com/rs/zerolog"
 
 	"github.com/nicholas-fedor/watchtower/pkg/types"
@@ -27,16 +28,26 @@ func UpdateFromContainer(log *zerolog.Logger,
 	state State,
 	params types.UpdateParams,
 ) *ContainerStatus {
+	oldMetadata := imageMetadata(container.ImageInfo())
+
+	newMetadata := oldMetadata
+	if newImage != container.ImageID() {
+		newMetadata = latestImageMetadata(container, newImage)
+	}
+
 	update := &ContainerStatus{
-		containerID:    container.ID(),
-		oldImage:       container.ImageID(),
-		newImage:       newImage,
-		containerName:  container.Name(),
-		imageName:      container.ImageName(),
-		containerError: nil,
-		state:          state,
-		monitorOnly:    container.IsMonitorOnly(params),
-		newContainerID: "",
+		containerID:      container.ID(),
+		oldImage:         container.ImageID(),
+		newImage:         newImage,
+		oldImageMetadata: oldMetadata,
+		newImageMetadata: newMetadata,
+		containerName:    container.Name(),
+		imageName:        container.ImageName(),
+		containerError:   nil,
+		state:            state,
+		monitorOnly:      container.IsMonitorOnly(params),
+		newContainerID:   "",
+		labels:           containerLabels(container),
 	}
 	log.Debug().
 		Str("container_id", container.ID().ShortID()).
@@ -47,6 +58,65 @@ func UpdateFromContainer(log *zerolog.Logger,
 	return update
 }
 
+// containerLabels returns the labels from a container's inspect data.
+//
+// Parameters:
+//   - container: Container to read labels from.
+//
+// Returns:
+//   - map[string]string: Label map, or nil if the container has no config.
+func containerLabels(container types.Container) map[string]string {
+	info := container.ContainerInfo()
+	if info == nil || info.Config == nil {
+		return nil
+	}
+
+	return info.Config.Labels
+}
+
+// latestImageContainer is implemented by containers that remember the newest image
+// found by the staleness check.
+type latestImageContainer interface {
+	LatestImageInfo() *dockerImage.InspectResponse
+}
+
+// imageMetadata reads OCI metadata from an image's config labels.
+//
+// Parameters:
+//   - info: Image inspect data, or nil.
+//
+// Returns:
+//   - types.ImageMetadata: Metadata, or empty if the image has no config.
+func imageMetadata(info *dockerImage.InspectResponse) types.ImageMetadata {
+	if info == nil || info.Config == nil {
+		return types.ImageMetadata{}
+	}
+
+	return types.ImageMetadataFromLabels(info.Config.Labels)
+}
+
+// latestImageMetadata reads OCI metadata from the newest image found for a container.
+//
+// Parameters:
+//   - container: Container whose staleness check found newImage.
+//   - newImage: Latest image ID.
+//
+// Returns:
+//   - types.ImageMetadata: Metadata, or empty if the newest image was not inspected.
+func latestImageMetadata(container types.Container, newImage types.ImageID) types.ImageMetadata {
+	latest, ok := container.(latestImageContainer)
+	if !ok {
+		return types.ImageMetadata{}
+	}
+
+	info := latest.LatestImageInfo()
+	if info == nil || types.ImageID(info.ID) != newImage {
+		return types.ImageMetadata{}
+	}
+
+	return imageMetadata(info)
+}
+
 // AddSkipped adds a container as skipped with an error.
 //
 // Parameters:
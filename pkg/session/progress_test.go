@@ -9,6 +9,11 @@ import (
 
 	"github.com/rs/zerolog"
 
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	dockerContainer "github.com/moby/moby/api/types/container"
+	dockerImage "github.com/moby/moby/api/types/image"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
 	testifyMock "github.com/stretchr/testify/mock"
 
 	"github.com/nicholas-fedor/watchtower/pkg/types"
@@ -43,6 +48,8 @@ func TestUpdateFromContainer(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img1"))
 					mock.EXPECT().Name().Return("container1")
 					mock.EXPECT().ImageName().Return("image1:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(false)
@@ -73,6 +80,8 @@ func TestUpdateFromContainer(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID(""))
 					mock.EXPECT().Name().Return("")
 					mock.EXPECT().ImageName().Return("")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(false)
@@ -103,6 +112,8 @@ func TestUpdateFromContainer(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img3"))
 					mock.EXPECT().Name().Return("container3")
 					mock.EXPECT().ImageName().Return("image3:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(true)
@@ -133,6 +144,8 @@ func TestUpdateFromContainer(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID(""))
 					mock.EXPECT().Name().Return("")
 					mock.EXPECT().ImageName().Return("")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(true)
@@ -190,6 +203,115 @@ func TestUpdateFromContainer(t *testing.T) {
 	}
 }
 
+func TestUpdateFromContainer_Labels(t *testing.T) {
+	labels := map[string]string{"com.docker.compose.project": "billing"}
+
+	mock := mockTypes.NewMockContainer(t)
+	mock.EXPECT().ID().Return(types.ContainerID("cont1"))
+	mock.EXPECT().ImageID().Return(types.ImageID("img1"))
+	mock.EXPECT().Name().Return("container1")
+	mock.EXPECT().ImageName().Return("image1:latest")
+	mock.EXPECT().ContainerInfo().Return(&dockerContainer.InspectResponse{
+		Config: &dockerContainer.Config{Labels: labels},
+	})
+	mock.EXPECT().ImageInfo().Return(nil)
+	mock.EXPECT().
+		IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
+		Return(false)
+
+	got := UpdateFromContainer(testLog(), mock, "img2", ScannedState, types.UpdateParams{})
+	if !reflect.DeepEqual(got.Labels(), labels) {
+		t.Errorf("Labels() = %v, want %v", got.Labels(), labels)
+	}
+}
+
+// latestImageMock is a container mock that remembers the newest image found for it.
+type latestImageMock struct {
+	*mockTypes.MockContainer
+
+	latest *dockerImage.InspectResponse
+}
+
+func (m latestImageMock) LatestImageInfo() *dockerImage.InspectResponse {
+	return m.latest
+}
+
+func TestUpdateFromContainer_ImageMetadata(t *testing.T) {
+	imageWithLabels := func(id string, labels map[string]string) *dockerImage.InspectResponse {
+		return &dockerImage.InspectResponse{ID: id, Config: &dockerspec.DockerOCIImageConfig{
+			ImageConfig: ocispec.ImageConfig{Labels: labels},
+		}}
+	}
+
+	current := imageWithLabels("img1", map[string]string{
+		types.ImageVersionLabel:  "1.4.2",
+		types.ImageRevisionLabel: "abc1234",
+	})
+	latest := imageWithLabels("img2", map[string]string{
+		types.ImageVersionLabel:  "1.5.0",
+		types.ImageRevisionLabel: "def5678",
+		types.ImageSourceLabel:   "https://github.com/example/app",
+	})
+
+	tests := []struct {
+		name       string
+		newImage   types.ImageID
+		latest     *dockerImage.InspectResponse
+		wantLatest types.ImageMetadata
+	}{
+		{
+			name:     "newer image inspected",
+			newImage: "img2",
+			latest:   latest,
+			wantLatest: types.ImageMetadata{
+				Version:  "1.5.0",
+				Revision: "def5678",
+				Source:   "https://github.com/example/app",
+			},
+		},
+		{
+			name:       "image unchanged",
+			newImage:   "img1",
+			wantLatest: types.ImageMetadata{Version: "1.4.2", Revision: "abc1234"},
+		},
+		{
+			name:     "inspected image is not the latest",
+			newImage: "img3",
+			latest:   latest,
+		},
+		{
+			name:     "newer image not inspected",
+			newImage: "img2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := mockTypes.NewMockContainer(t)
+			mock.EXPECT().ID().Return(types.ContainerID("cont1"))
+			mock.EXPECT().ImageID().Return(types.ImageID("img1"))
+			mock.EXPECT().Name().Return("container1")
+			mock.EXPECT().ImageName().Return("image1:latest")
+			mock.EXPECT().ContainerInfo().Return(nil)
+			mock.EXPECT().ImageInfo().Return(current)
+			mock.EXPECT().
+				IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
+				Return(false)
+
+			got := UpdateFromContainer(testLog(), latestImageMock{MockContainer: mock, latest: tt.latest},
+				tt.newImage, ScannedState, types.UpdateParams{})
+
+			wantCurrent := types.ImageMetadata{Version: "1.4.2", Revision: "abc1234"}
+			if got.CurrentImageMetadata() != wantCurrent {
+				t.Errorf("CurrentImageMetadata() = %+v, want %+v", got.CurrentImageMetadata(), wantCurrent)
+			}
+
+			if got.LatestImageMetadata() != tt.wantLatest {
+				t.Errorf("LatestImageMetadata() = %+v, want %+v", got.LatestImageMetadata(), tt.wantLatest)
+			}
+		})
+	}
+}
+
 func TestProgress_AddSkipped(t *testing.T) {
 	type args struct {
 		container types.Container
@@ -213,6 +335,8 @@ func TestProgress_AddSkipped(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img1"))
 					mock.EXPECT().Name().Return("container1")
 					mock.EXPECT().ImageName().Return("image1:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(false)
@@ -245,6 +369,8 @@ func TestProgress_AddSkipped(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img2"))
 					mock.EXPECT().Name().Return("container2")
 					mock.EXPECT().ImageName().Return("image2:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(false)
@@ -277,6 +403,8 @@ func TestProgress_AddSkipped(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img3"))
 					mock.EXPECT().Name().Return("container3")
 					mock.EXPECT().ImageName().Return("image3:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(true)
@@ -363,6 +491,8 @@ func TestProgress_AddScanned(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img1"))
 					mock.EXPECT().Name().Return("container1")
 					mock.EXPECT().ImageName().Return("image1:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(false)
@@ -395,6 +525,8 @@ func TestProgress_AddScanned(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img2"))
 					mock.EXPECT().Name().Return("container2")
 					mock.EXPECT().ImageName().Return("image2:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(false)
@@ -427,6 +559,8 @@ func TestProgress_AddScanned(t *testing.T) {
 					mock.EXPECT().ImageID().Return(types.ImageID("img3"))
 					mock.EXPECT().Name().Return("container3")
 					mock.EXPECT().ImageName().Return("image3:latest")
+					mock.EXPECT().ContainerInfo().Return(nil)
+					mock.EXPECT().ImageInfo().Return(nil)
 					mock.EXPECT().
 						IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 						Return(true)
@@ -1044,6 +1178,8 @@ func TestProgress_MarkRestarted_AddSkipped_Integration(t *testing.T) {
 	mock.EXPECT().ImageID().Return(types.ImageID("img1"))
 	mock.EXPECT().Name().Return("container1")
 	mock.EXPECT().ImageName().Return("image1:latest")
+	mock.EXPECT().ContainerInfo().Return(nil)
+	mock.EXPECT().ImageInfo().Return(nil)
 	mock.EXPECT().
 		IsMonitorOnly(testifyMock.MatchedBy(func(_ types.UpdateParams) bool { return true })).
 		Return(false)
@@ -180,6 +180,102 @@ func sortByDependencies(log *zerolog.Logger, containers []types.Container, useCo
 	return sorted, nil
 }
 
+// groupByDependencies splits containers into the connected components of their dependency graph.
+//
+// Containers linked directly or through other containers share a group, so a
+// group can be stopped and restarted without touching the containers of another.
+// Watchtower containers form one group placed last, matching Sort. When the
+// graph cannot be built, all containers form a single group.
+//
+// Parameters:
+//   - containers: Containers in dependency order.
+//   - useComposeDependsOn: Whether to include Docker Compose depends_on label in dependency resolution.
+//
+// Returns:
+//   - [][]types.Container: Groups in the order of their first container, each keeping the given order.
+func groupByDependencies(log *zerolog.Logger, containers []types.Container, useComposeDependsOn bool) [][]types.Container {
+	if len(containers) == 0 {
+		return nil
+	}
+
+	var (
+		nonWatchtowerContainers []types.Container
+		watchtowerContainers    []types.Container
+	)
+
+	for _, c := range containers {
+		if c.IsWatchtower() {
+			watchtowerContainers = append(watchtowerContainers, c)
+		} else {
+			nonWatchtowerContainers = append(nonWatchtowerContainers, c)
+		}
+	}
+
+	_, _, adjacency, normalizedMap, err := buildDependencyGraph(log, nonWatchtowerContainers, useComposeDependsOn)
+	if err != nil {
+		log.Debug().
+			Err(err).
+			Msg("Failed to build dependency graph, keeping containers in one group")
+
+		return [][]types.Container{containers}
+	}
+
+	// Union the identifiers joined by a dependency edge.
+	parent := make(map[string]string, len(normalizedMap))
+
+	var find func(id string) string
+
+	find = func(id string) string {
+		root, ok := parent[id]
+		if !ok || root == id {
+			return id
+		}
+
+		root = find(root)
+		parent[id] = root
+
+		return root
+	}
+
+	for dependency, dependents := range adjacency {
+		for _, dependent := range dependents {
+			a, b := find(dependency), find(dependent)
+			if a != b {
+				parent[b] = a
+			}
+		}
+	}
+
+	var groups [][]types.Container
+
+	groupIndex := make(map[string]int)
+
+	for _, c := range nonWatchtowerContainers {
+		root := find(normalizedMap[c])
+
+		index, ok := groupIndex[root]
+		if !ok {
+			index = len(groups)
+			groupIndex[root] = index
+
+			groups = append(groups, nil)
+		}
+
+		groups[index] = append(groups[index], c)
+	}
+
+	if len(watchtowerContainers) > 0 {
+		groups = append(groups, watchtowerContainers)
+	}
+
+	log.Debug().
+		Int("container_count", len(containers)).
+		Int("group_count", len(groups)).
+		Msg("Grouped containers by dependencies")
+
+	return groups
+}
+
 // buildDependencyGraph constructs the dependency graph data structures for topological sorting.
 //
 // This function builds three key data structures:
@@ -2424,3 +2424,33 @@ var _ = ginkgo.Describe("dependency link form permutations", func() {
 			To(gomega.ConsistOf("web", "other-proxy"))
 	})
 })
+
+var _ = ginkgo.Describe("GroupByDependencies", func() {
+	ginkgo.It("splits unrelated dependency chains and places Watchtower last", func() {
+		db := mockLinkedContainer("db", "db-id", "", "", nil, false)
+		web := mockLinkedContainer("web", "web-id", "", "", []string{"db"}, false)
+		worker := mockLinkedContainer("worker", "worker-id", "", "", []string{"db"}, false)
+		cache := mockLinkedContainer("cache", "cache-id", "", "", nil, false)
+
+		watchtower := mockTypes.NewMockContainer(ginkgo.GinkgoT())
+		watchtower.EXPECT().IsWatchtower().Return(true)
+
+		groups := GroupByDependencies(testLog(),
+			[]types.Container{db, cache, web, worker, watchtower}, false)
+
+		gomega.Expect(groups).To(gomega.Equal([][]types.Container{
+			{db, web, worker},
+			{cache},
+			{watchtower},
+		}))
+	})
+
+	ginkgo.It("keeps containers in one group when the graph cannot be built", func() {
+		first := mockLinkedContainer("db", "db-1", "", "", nil, false)
+		second := mockLinkedContainer("db", "db-2", "", "", nil, false)
+
+		groups := GroupByDependencies(testLog(), []types.Container{first, second}, false)
+
+		gomega.Expect(groups).To(gomega.Equal([][]types.Container{{first, second}}))
+	})
+})
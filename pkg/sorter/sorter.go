@@ -39,3 +39,16 @@ func SortByDependencies(log *zerolog.Logger, containers []types.Container, useCo
 
 	return sorter.Sort(log, containers, useComposeDependsOn)
 }
+
+// GroupByDependencies splits containers into groups that do not depend on each other.
+//
+// Parameters:
+//   - log: Process logger.
+//   - containers: Containers sorted by SortByDependencies.
+//   - useComposeDependsOn: Whether to include Docker Compose depends_on label in dependency resolution.
+//
+// Returns:
+//   - [][]types.Container: Groups in the order of their first container, each keeping the given order.
+func GroupByDependencies(log *zerolog.Logger, containers []types.Container, useComposeDependsOn bool) [][]types.Container {
+	return groupByDependencies(log, containers, useComposeDependsOn)
+}
@@ -11,4 +11,32 @@ type RemovedImageInfo struct {
 	ImageName string `json:"image_name"`
 	// ContainerName is the name of the container that was using this image before the update.
 	ContainerName string `json:"container_name"`
+	// Size is the size of the image in bytes, or 0 if unknown.
+	Size int64 `json:"size,omitempty"`
+}
+
+// ImageSummary describes an image present on the Docker host.
+type ImageSummary struct {
+	// ID is the image ID.
+	ID ImageID `json:"id"`
+	// RepoTags are the tags referencing the image; empty for dangling images.
+	RepoTags []string `json:"repo_tags"`
+	// RepoDigests are the registry digests the image was pulled by.
+	RepoDigests []string `json:"repo_digests"`
+	// Size is the size of the image in bytes, including layers shared with other images.
+	Size int64 `json:"size"`
+}
+
+// Dangling reports whether no tag references the image.
+//
+// Returns:
+//   - bool: True if the image is untagged.
+func (s ImageSummary) Dangling() bool {
+	for _, tag := range s.RepoTags {
+		if tag != "<none>:<none>" {
+			return false
+		}
+	}
+
+	return true
 }
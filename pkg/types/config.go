@@ -36,6 +36,10 @@ type RunConfig struct {
 	EnableImagesAPI bool
 	// EnableMetricsAPI enables the metrics API endpoint.
 	EnableMetricsAPI bool
+	// EnableOutboxAPI enables the notification outbox API endpoint.
+	EnableOutboxAPI bool
+	// EnableQuarantineAPI enables the image quarantine API endpoints.
+	EnableQuarantineAPI bool
 	// EnableSwaggerAPI enables Swagger UI endpoint.
 	EnableSwaggerAPI bool
 	// EnableUpdateAPI enables the update API endpoint.
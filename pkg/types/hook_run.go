@@ -0,0 +1,36 @@
+package types
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// HookRun records one execution of a lifecycle hook command or webhook.
+//
+// Output has already been redacted and truncated to the configured limit.
+type HookRun struct {
+	Phase      string        `json:"phase"`                 // Hook phase, e.g. "pre-update".
+	Command    string        `json:"command,omitempty"`     // Command, empty for webhooks.
+	URL        string        `json:"url,omitempty"`         // Webhook endpoint, empty for commands.
+	ExitCode   int           `json:"exit_code"`             // Exit code, or -1 for webhooks and commands that did not finish.
+	StatusCode int           `json:"status_code,omitempty"` // Webhook response status, 0 without a response.
+	Duration   time.Duration `json:"-"`                     // Time taken by the hook.
+	Output     string        `json:"output,omitempty"`      // Captured stdout/stderr or response body.
+	Truncated  bool          `json:"truncated,omitempty"`   // True if Output was cut at the limit.
+	Error      string        `json:"error,omitempty"`       // Execution error, if any.
+}
+
+// MarshalJSON encodes the run with its duration in milliseconds.
+//
+// Returns:
+//   - []byte: JSON encoding of the run.
+//   - error: Non-nil if encoding fails.
+func (r HookRun) MarshalJSON() ([]byte, error) {
+	type hookRun HookRun
+
+	return json.Marshal(struct { //nolint:wrapcheck // Plain JSON encoding of a value type.
+		hookRun
+
+		DurationMS int64 `json:"duration_ms"`
+	}{hookRun(r), r.Duration.Milliseconds()})
+}
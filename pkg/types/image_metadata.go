@@ -0,0 +1,43 @@
+package types
+
+// OCI image annotation keys read from image config labels.
+const (
+	ImageVersionLabel  = "org.opencontainers.image.version"  // Image version, e.g. "1.5.0".
+	ImageRevisionLabel = "org.opencontainers.image.revision" // Source revision, e.g. a commit hash.
+	ImageSourceLabel   = "org.opencontainers.image.source"   // URL of the image source.
+	ImageCreatedLabel  = "org.opencontainers.image.created"  // RFC 3339 image build time.
+)
+
+// ImageMetadata describes what an image contains, from its OCI annotation labels.
+//
+// Fields are empty when the image does not carry the corresponding label.
+type ImageMetadata struct {
+	Version  string `json:"version,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Created  string `json:"created,omitempty"`
+}
+
+// ImageMetadataFromLabels reads image metadata from image config labels.
+//
+// Parameters:
+//   - labels: Image config labels, or nil.
+//
+// Returns:
+//   - ImageMetadata: Metadata with the OCI annotation values found in labels.
+func ImageMetadataFromLabels(labels map[string]string) ImageMetadata {
+	return ImageMetadata{
+		Version:  labels[ImageVersionLabel],
+		Revision: labels[ImageRevisionLabel],
+		Source:   labels[ImageSourceLabel],
+		Created:  labels[ImageCreatedLabel],
+	}
+}
+
+// IsZero reports whether no metadata is known.
+//
+// Returns:
+//   - bool: True if every field is empty.
+func (m ImageMetadata) IsZero() bool {
+	return m == ImageMetadata{}
+}
@@ -36,6 +36,96 @@ func (_m *MockContainerReport) EXPECT() *MockContainerReport_Expecter {
 	return &MockContainerReport_Expecter{mock: &_m.Mock}
 }
 
+// BackupPath provides a mock function for the type MockContainerReport
+func (_mock *MockContainerReport) BackupPath() string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackupPath")
+	}
+
+	var r0 string
+	if returnFunc, ok := ret.Get(0).(func() string); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+	return r0
+}
+
+// MockContainerReport_BackupPath_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BackupPath'
+type MockContainerReport_BackupPath_Call struct {
+	*mock.Call
+}
+
+// BackupPath is a helper method to define mock.On call
+func (_e *MockContainerReport_Expecter) BackupPath() *MockContainerReport_BackupPath_Call {
+	return &MockContainerReport_BackupPath_Call{Call: _e.mock.On("BackupPath")}
+}
+
+func (_c *MockContainerReport_BackupPath_Call) Run(run func()) *MockContainerReport_BackupPath_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContainerReport_BackupPath_Call) Return(s string) *MockContainerReport_BackupPath_Call {
+	_c.Call.Return(s)
+	return _c
+}
+
+func (_c *MockContainerReport_BackupPath_Call) RunAndReturn(run func() string) *MockContainerReport_BackupPath_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CrashLogs provides a mock function for the type MockContainerReport
+func (_mock *MockContainerReport) CrashLogs() []string {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CrashLogs")
+	}
+
+	var r0 []string
+	if returnFunc, ok := ret.Get(0).(func() []string); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	return r0
+}
+
+// MockContainerReport_CrashLogs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CrashLogs'
+type MockContainerReport_CrashLogs_Call struct {
+	*mock.Call
+}
+
+// CrashLogs is a helper method to define mock.On call
+func (_e *MockContainerReport_Expecter) CrashLogs() *MockContainerReport_CrashLogs_Call {
+	return &MockContainerReport_CrashLogs_Call{Call: _e.mock.On("CrashLogs")}
+}
+
+func (_c *MockContainerReport_CrashLogs_Call) Run(run func()) *MockContainerReport_CrashLogs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContainerReport_CrashLogs_Call) Return(crashLogs []string) *MockContainerReport_CrashLogs_Call {
+	_c.Call.Return(crashLogs)
+	return _c
+}
+
+func (_c *MockContainerReport_CrashLogs_Call) RunAndReturn(run func() []string) *MockContainerReport_CrashLogs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CurrentImageID provides a mock function for the type MockContainerReport
 func (_mock *MockContainerReport) CurrentImageID() types.ImageID {
 	ret := _mock.Called()
@@ -80,6 +170,50 @@ func (_c *MockContainerReport_CurrentImageID_Call) RunAndReturn(run func() types
 	return _c
 }
 
+// CurrentImageMetadata provides a mock function for the type MockContainerReport
+func (_mock *MockContainerReport) CurrentImageMetadata() types.ImageMetadata {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CurrentImageMetadata")
+	}
+
+	var r0 types.ImageMetadata
+	if returnFunc, ok := ret.Get(0).(func() types.ImageMetadata); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(types.ImageMetadata)
+	}
+	return r0
+}
+
+// MockContainerReport_CurrentImageMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CurrentImageMetadata'
+type MockContainerReport_CurrentImageMetadata_Call struct {
+	*mock.Call
+}
+
+// CurrentImageMetadata is a helper method to define mock.On call
+func (_e *MockContainerReport_Expecter) CurrentImageMetadata() *MockContainerReport_CurrentImageMetadata_Call {
+	return &MockContainerReport_CurrentImageMetadata_Call{Call: _e.mock.On("CurrentImageMetadata")}
+}
+
+func (_c *MockContainerReport_CurrentImageMetadata_Call) Run(run func()) *MockContainerReport_CurrentImageMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContainerReport_CurrentImageMetadata_Call) Return(imageMetadata types.ImageMetadata) *MockContainerReport_CurrentImageMetadata_Call {
+	_c.Call.Return(imageMetadata)
+	return _c
+}
+
+func (_c *MockContainerReport_CurrentImageMetadata_Call) RunAndReturn(run func() types.ImageMetadata) *MockContainerReport_CurrentImageMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Error provides a mock function for the type MockContainerReport
 func (_mock *MockContainerReport) Error() string {
 	ret := _mock.Called()
@@ -124,6 +258,52 @@ func (_c *MockContainerReport_Error_Call) RunAndReturn(run func() string) *MockC
 	return _c
 }
 
+// HookRuns provides a mock function for the type MockContainerReport
+func (_mock *MockContainerReport) HookRuns() []types.HookRun {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for HookRuns")
+	}
+
+	var r0 []types.HookRun
+	if returnFunc, ok := ret.Get(0).(func() []types.HookRun); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]types.HookRun)
+		}
+	}
+	return r0
+}
+
+// MockContainerReport_HookRuns_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HookRuns'
+type MockContainerReport_HookRuns_Call struct {
+	*mock.Call
+}
+
+// HookRuns is a helper method to define mock.On call
+func (_e *MockContainerReport_Expecter) HookRuns() *MockContainerReport_HookRuns_Call {
+	return &MockContainerReport_HookRuns_Call{Call: _e.mock.On("HookRuns")}
+}
+
+func (_c *MockContainerReport_HookRuns_Call) Run(run func()) *MockContainerReport_HookRuns_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContainerReport_HookRuns_Call) Return(hookRuns []types.HookRun) *MockContainerReport_HookRuns_Call {
+	_c.Call.Return(hookRuns)
+	return _c
+}
+
+func (_c *MockContainerReport_HookRuns_Call) RunAndReturn(run func() []types.HookRun) *MockContainerReport_HookRuns_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ID provides a mock function for the type MockContainerReport
 func (_mock *MockContainerReport) ID() types.ContainerID {
 	ret := _mock.Called()
@@ -300,6 +480,50 @@ func (_c *MockContainerReport_LatestImageID_Call) RunAndReturn(run func() types.
 	return _c
 }
 
+// LatestImageMetadata provides a mock function for the type MockContainerReport
+func (_mock *MockContainerReport) LatestImageMetadata() types.ImageMetadata {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for LatestImageMetadata")
+	}
+
+	var r0 types.ImageMetadata
+	if returnFunc, ok := ret.Get(0).(func() types.ImageMetadata); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(types.ImageMetadata)
+	}
+	return r0
+}
+
+// MockContainerReport_LatestImageMetadata_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LatestImageMetadata'
+type MockContainerReport_LatestImageMetadata_Call struct {
+	*mock.Call
+}
+
+// LatestImageMetadata is a helper method to define mock.On call
+func (_e *MockContainerReport_Expecter) LatestImageMetadata() *MockContainerReport_LatestImageMetadata_Call {
+	return &MockContainerReport_LatestImageMetadata_Call{Call: _e.mock.On("LatestImageMetadata")}
+}
+
+func (_c *MockContainerReport_LatestImageMetadata_Call) Run(run func()) *MockContainerReport_LatestImageMetadata_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockContainerReport_LatestImageMetadata_Call) Return(imageMetadata types.ImageMetadata) *MockContainerReport_LatestImageMetadata_Call {
+	_c.Call.Return(imageMetadata)
+	return _c
+}
+
+func (_c *MockContainerReport_LatestImageMetadata_Call) RunAndReturn(run func() types.ImageMetadata) *MockContainerReport_LatestImageMetadata_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Name provides a mock function for the type MockContainerReport
 func (_mock *MockContainerReport) Name() string {
 	ret := _mock.Called()
@@ -14,13 +14,18 @@ type Report interface {
 
 // ContainerReport defines a container's session status.
 type ContainerReport interface {
-	ID() ContainerID             // Container ID.
-	Name() string                // Container name.
-	CurrentImageID() ImageID     // Original image ID.
-	LatestImageID() ImageID      // Latest image ID.
-	ImageName() string           // Image name with tag.
-	Error() string               // Error message, if any.
-	State() string               // Human-readable state.
-	IsMonitorOnly() bool         // Monitor-only status.
-	NewContainerID() ContainerID // New container ID after update.
+	ID() ContainerID                     // Container ID.
+	Name() string                        // Container name.
+	CurrentImageID() ImageID             // Original image ID.
+	LatestImageID() ImageID              // Latest image ID.
+	CurrentImageMetadata() ImageMetadata // Original image OCI metadata.
+	LatestImageMetadata() ImageMetadata  // Latest image OCI metadata.
+	ImageName() string                   // Image name with tag.
+	Error() string                       // Error message, if any.
+	State() string                       // Human-readable state.
+	IsMonitorOnly() bool                 // Monitor-only status.
+	NewContainerID() ContainerID         // New container ID after update.
+	HookRuns() []HookRun                 // Lifecycle hook runs, in execution order.
+	BackupPath() string                  // Volume backup taken before the update, if any.
+	CrashLogs() []string                 // Last log lines of a container that crashed after the update.
 }
@@ -6,25 +6,44 @@ import (
 
 // UpdateParams defines options for the Update function.
 type UpdateParams struct {
-	Filter              Filter        `json:"-"`                      // Container filter.
-	Cleanup             bool          `json:"cleanup"`                // Remove old images if true.
-	NoRestart           bool          `json:"no_restart"`             // Skip restarts if true.
-	ReviveStopped       bool          `json:"revive_stopped"`         // Start stopped containers after update if true.
-	Timeout             time.Duration `json:"timeout"`                // Update timeout.
-	MonitorOnly         bool          `json:"monitor_only"`           // Monitor without updating if true.
-	NoPull              bool          `json:"no_pull"`                // Skip image pulls if true.
-	LifecycleHooks      bool          `json:"lifecycle_hooks"`        // Enable lifecycle hooks if true.
-	RollingRestart      bool          `json:"rolling_restart"`        // Use rolling restart if true.
-	LabelPrecedence     bool          `json:"label_precedence"`       // Prioritize labels if true.
-	PullFailureDelay    time.Duration `json:"pull_failure_delay"`     // Delay after failed self-update pull.
-	LifecycleUID        int           `json:"lifecycle_uid"`          // Default UID for lifecycle hooks.
-	LifecycleGID        int           `json:"lifecycle_gid"`          // Default GID for lifecycle hooks.
-	CPUCopyMode         string        `json:"cpu_copy_mode"`          // CPU copy mode for container recreation.
-	RunOnce             bool          `json:"run_once"`               // Run once mode if true.
-	CurrentContainerID  ContainerID   `json:"current_container_id"`   // ID of the current container being updated.
-	UseComposeDependsOn bool          `json:"use_compose_depends_on"` // Enable Docker Compose depends_on label processing.
-	SkipSelfUpdate      bool          `json:"skip_self_update"`       // Skip Watchtower self-update if true.
-	EphemeralSelfUpdate bool          `json:"ephemeral_self_update"`  // Use ephemeral container for self-update if true.
-	CooldownDelay       time.Duration `json:"cooldown_delay"`         // Minimum time since image creation before allowing updates.
-	LabelEnable         bool          `json:"label_enable"`           // Require enable label for monitoring.
+	Filter               Filter        `json:"-"`                       // Container filter.
+	Cleanup              bool          `json:"cleanup"`                 // Remove old images if true.
+	NoRestart            bool          `json:"no_restart"`              // Skip restarts if true.
+	ReviveStopped        bool          `json:"revive_stopped"`          // Start stopped containers after update if true.
+	Timeout              time.Duration `json:"timeout"`                 // Update timeout.
+	MonitorOnly          bool          `json:"monitor_only"`            // Monitor without updating if true.
+	NoPull               bool          `json:"no_pull"`                 // Skip image pulls if true.
+	LifecycleHooks       bool          `json:"lifecycle_hooks"`         // Enable lifecycle hooks if true.
+	RollingRestart       bool          `json:"rolling_restart"`         // Use rolling restart if true.
+	LabelPrecedence      bool          `json:"label_precedence"`        // Prioritize labels if true.
+	PullFailureDelay     time.Duration `json:"pull_failure_delay"`      // Delay after failed self-update pull.
+	LifecycleUID         int           `json:"lifecycle_uid"`           // Default UID for lifecycle hooks.
+	LifecycleGID         int           `json:"lifecycle_gid"`           // Default GID for lifecycle hooks.
+	LifecycleOutputLimit int           `json:"lifecycle_output_limit"`  // Maximum bytes of hook output kept in reports.
+	LifecycleRedact      string        `json:"lifecycle_redact"`        // Extra pattern redacted from hook output.
+	CPUCopyMode          string        `json:"cpu_copy_mode"`           // CPU copy mode for container recreation.
+	RunOnce              bool          `json:"run_once"`                // Run once mode if true.
+	Targeted             bool          `json:"targeted"`                // Scan narrowed to named containers or images if true.
+	CurrentContainerID   ContainerID   `json:"current_container_id"`    // ID of the current container being updated.
+	UseComposeDependsOn  bool          `json:"use_compose_depends_on"`  // Enable Docker Compose depends_on label processing.
+	SkipSelfUpdate       bool          `json:"skip_self_update"`        // Skip Watchtower self-update if true.
+	EphemeralSelfUpdate  bool          `json:"ephemeral_self_update"`   // Use ephemeral container for self-update if true.
+	CooldownDelay        time.Duration `json:"cooldown_delay"`          // Minimum time since image creation before allowing updates.
+	LabelEnable          bool          `json:"label_enable"`            // Require enable label for monitoring.
+	ScanID               string        `json:"scan_id"`                 // Identifier of the scan, sent to lifecycle webhooks.
+	PreScanHook          string        `json:"pre_scan_hook"`           // Command or URL run before the scan.
+	PreRestartBatchHook  string        `json:"pre_restart_batch_hook"`  // Command or URL run before the first stop.
+	PostRestartBatchHook string        `json:"post_restart_batch_hook"` // Command or URL run after the last start.
+	PostScanHook         string        `json:"post_scan_hook"`          // Command or URL run after the scan.
+	ScanHookTimeout      time.Duration `json:"scan_hook_timeout"`       // Timeout for each scan hook.
+	VolumeBackupDir      string        `json:"volume_backup_dir"`       // Host path or volume receiving volume backups.
+	VolumeBackupImage    string        `json:"volume_backup_image"`     // Image of the volume backup helper container.
+	VolumeBackupKeep     int           `json:"volume_backup_keep"`      // Backup generations kept per container; 0 keeps all.
+	MaxUpdates           int           `json:"max_updates"`             // Containers updated per scan, deferring the rest; 0 updates all.
+	BreakerFailures      int           `json:"breaker_failures"`        // Consecutive failures halting the remaining updates; 0 disables.
+	BreakerPercent       int           `json:"breaker_percent"`         // Percentage of planned updates failing before the rest halt; 0 disables.
+	ObservePeriod        time.Duration `json:"observe_period"`          // How long updated containers are watched for crashes; 0 disables.
+	ObserveMaxRestarts   int           `json:"observe_max_restarts"`    // Restarts tolerated while a container is observed.
+	ObserveLogLines      int           `json:"observe_log_lines"`       // Log lines of a crashed container attached to the report.
+	RevertOnCrash        bool          `json:"revert_on_crash"`         // Recreate crashed containers from their previous image if true.
 }
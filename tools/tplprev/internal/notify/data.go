@@ -27,4 +27,6 @@ type Data struct {
 
 	Entries []*Entry
 	Report  report.Report
+	// Notices maps stale container names to "new", "reminder", or "repeat".
+	Notices map[string]string
 }
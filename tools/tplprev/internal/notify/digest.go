@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/nicholas-fedor/tplprev/internal/report"
+)
+
+// DigestData is the digest notification template data model.
+//
+// It summarizes every scan recorded between Since and Until.
+type DigestData struct {
+	StaticData
+
+	Since         time.Time
+	Until         time.Time
+	Scans         int
+	Updated       []DigestUpdate
+	Pending       []DigestPending
+	Failures      []DigestFailure
+	ScanErrors    int
+	LastScanError string
+	CleanedImages int
+}
+
+// DigestUpdate is a container updated during the digest period.
+type DigestUpdate struct {
+	Name      string    `json:"name"`
+	ImageName string    `json:"image_name"`
+	Count     int       `json:"count"`
+	Last      time.Time `json:"last"`
+}
+
+// DigestPending is a container with an available update that has not been applied.
+type DigestPending struct {
+	Name          string         `json:"name"`
+	ImageName     string         `json:"image_name"`
+	LatestImageID report.ImageID `json:"latest_image_id"`
+	Since         time.Time      `json:"since"`
+	Waiting       time.Duration  `json:"-"`
+}
+
+// DigestFailure is a container whose update failed during the digest period.
+type DigestFailure struct {
+	Name      string    `json:"name"`
+	ImageName string    `json:"image_name"`
+	Error     string    `json:"error"`
+	Count     int       `json:"count"`
+	First     time.Time `json:"first"`
+	Last      time.Time `json:"last"`
+}
@@ -40,7 +40,7 @@ func (d Data) MarshalJSON() ([]byte, error) {
 			"restarted": marshalReports(d.Report.Restarted()),
 			"failed":    marshalReports(d.Report.Failed()),
 			"skipped":   marshalReports(d.Report.Skipped()),
-			"stale":     marshalReports(d.Report.Stale()),
+			"stale":     marshalStaleReports(d.Report.Stale(), d.Notices),
 			"fresh":     marshalReports(d.Report.Fresh()),
 		}
 	}
@@ -60,6 +60,26 @@ func (d Data) MarshalJSON() ([]byte, error) {
 	return bytes, nil
 }
 
+// marshalStaleReports converts stale ContainerReports to JSON maps with their notice kind.
+//
+// Parameters:
+//   - reports: List of stale container reports.
+//   - notices: Notice kinds keyed by container name, or nil.
+//
+// Returns:
+//   - []jsonMap: JSON maps of report data, with "notice" set when known.
+func marshalStaleReports(reports []report.ContainerReport, notices map[string]string) []jsonMap {
+	jsonReports := marshalReports(reports)
+
+	for i, report := range reports {
+		if notice := notices[report.Name()]; notice != "" {
+			jsonReports[i]["notice"] = notice
+		}
+	}
+
+	return jsonReports
+}
+
 // marshalReports converts a ContainerReport slice to JSON-compatible maps.
 //
 // Parameters:
@@ -79,6 +99,26 @@ func marshalReports(reports []report.ContainerReport) []jsonMap {
 			"state":          report.State(),
 		}
 
+		if current := report.CurrentImageMetadata(); !current.IsZero() {
+			jsonReports[i]["currentImage"] = current
+		}
+
+		if latest := report.LatestImageMetadata(); !latest.IsZero() {
+			jsonReports[i]["latestImage"] = latest
+		}
+
+		if runs := report.HookRuns(); len(runs) > 0 {
+			jsonReports[i]["hookRuns"] = runs
+		}
+
+		if backupPath := report.BackupPath(); backupPath != "" {
+			jsonReports[i]["backupPath"] = backupPath
+		}
+
+		if crashLogs := report.CrashLogs(); len(crashLogs) > 0 {
+			jsonReports[i]["crashLogs"] = crashLogs
+		}
+
 		errorMessage := report.Error()
 		if errorMessage != "" {
 			jsonReports[i]["error"] = errorMessage
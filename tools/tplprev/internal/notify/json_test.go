@@ -25,6 +25,7 @@ type stubContainerError struct {
 	image string
 	state string
 	err   string
+	newMD report.ImageMetadata
 }
 
 func (r stubReport) Scanned() []report.ContainerReport   { return r.scanned }
@@ -46,6 +47,16 @@ func (c stubContainerError) State() string                      { return c.state
 func (c stubContainerError) IsMonitorOnly() bool                { return false }
 func (c stubContainerError) NewContainerID() report.ContainerID { return "" }
 
+func (c stubContainerError) CurrentImageMetadata() report.ImageMetadata {
+	return report.ImageMetadata{}
+}
+
+func (c stubContainerError) LatestImageMetadata() report.ImageMetadata { return c.newMD }
+
+func (c stubContainerError) HookRuns() []report.HookRun { return nil }
+func (c stubContainerError) BackupPath() string         { return "" }
+func (c stubContainerError) CrashLogs() []string        { return nil }
+
 func TestDataMarshalJSON(t *testing.T) {
 	t.Parallel()
 
@@ -56,6 +67,7 @@ func TestDataMarshalJSON(t *testing.T) {
 		newID: "sha256:d0a110000000aaaa",
 		image: "mock/updt1:latest",
 		state: "updated",
+		newMD: report.ImageMetadata{Version: "1.5.0"},
 	}
 	failed := stubContainerError{
 		id:    "sha256:c79210000000aaaa",
@@ -105,6 +117,14 @@ func TestDataMarshalJSON(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, "execution failed", failedEntry["error"])
 	assert.Equal(t, "c79210000000", failedEntry["id"])
+	assert.NotContains(t, failedEntry, "latestImage")
+
+	updatedJSON, ok := report["updated"].([]any)
+	require.True(t, ok)
+	updatedEntry, ok := updatedJSON[0].(map[string]any)
+	require.True(t, ok)
+	assert.NotContains(t, updatedEntry, "currentImage")
+	assert.Equal(t, map[string]any{"version": "1.5.0"}, updatedEntry["latestImage"])
 
 	entries, ok := decoded["entries"].([]any)
 	require.True(t, ok)
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nicholas-fedor/tplprev/internal/notify"
@@ -31,12 +32,19 @@ const (
 	previewCooldown         = "24h"
 	previewEligibleIn       = "6h"
 	previewEligibleAfter    = 6 * time.Hour
+	previewRevisionLength   = 40
+	previewHookDuration     = 1200 * time.Millisecond
+	previewBackupDir        = "/srv/backups"
+	previewBackupStamp      = "20240101T120000Z"
 )
 
 var (
 	errExecutionFailed = errors.New("execution failed")
 	errSkipped         = errors.New("container skipped")
 
+	// previewNotices are the notice kinds assigned to stale containers in turn.
+	previewNotices = []string{"new", "reminder"}
+
 	// previewStartTime is the fixed session start so generated timestamps stay stable.
 	previewStartTime = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
 )
@@ -86,6 +94,10 @@ func (p *PreviewData) AddFromState(state State) {
 		imageName:      image,
 		containerError: err,
 		state:          state,
+		oldMetadata:    p.generateImageMetadata(name, oldImageID, 0),
+		newMetadata:    p.generateImageMetadata(name, newImageID, 1),
+		hookRuns:       generateHookRuns(state),
+		backupPath:     generateBackupPath(state, name),
 	}
 
 	switch state {
@@ -153,6 +165,15 @@ func (p *PreviewData) NotificationData() notify.Data {
 		payload.Report = p.report
 	}
 
+	// Alternate new and reminder notices so templates can preview both.
+	if len(p.report.stale) > 0 {
+		payload.Notices = make(map[string]string, len(p.report.stale))
+
+		for i, status := range p.report.stale {
+			payload.Notices[status.Name()] = previewNotices[i%len(previewNotices)]
+		}
+	}
+
 	return payload
 }
 
@@ -191,6 +212,74 @@ func (p *PreviewData) generateImageName(name string) string {
 	return organizationNames[index] + "/" + name + ":latest"
 }
 
+// generateImageMetadata derives OCI image metadata for a preview container without
+// consuming random numbers, so the rest of the preview data stays unchanged.
+//
+// Parameters:
+//   - name: Container name.
+//   - imageID: Image ID whose prefix becomes the revision.
+//   - release: 0 for the current image, 1 for the latest.
+//
+// Returns:
+//   - report.ImageMetadata: Metadata with a version, revision, and source.
+func (p *PreviewData) generateImageMetadata(name string, imageID report.ImageID, release int) report.ImageMetadata {
+	return report.ImageMetadata{
+		Version:  fmt.Sprintf("1.%d.%d", p.containerCount, release),
+		Revision: string(imageID)[:previewRevisionLength],
+		Source:   "https://github.com/" + strings.TrimSuffix(p.generateImageName(name), ":latest"),
+	}
+}
+
+// generateHookRuns returns the lifecycle hook runs shown for a preview container
+// without consuming random numbers. Updated containers ran their hooks, and
+// failed containers have a failing pre-update hook.
+//
+// Parameters:
+//   - state: Container report state.
+//
+// Returns:
+//   - []report.HookRun: Runs for the state, or nil.
+func generateHookRuns(state State) []report.HookRun {
+	//nolint:exhaustive // Only updated and failed containers ran hooks.
+	switch state {
+	case UpdatedState:
+		return []report.HookRun{
+			{Phase: "pre-update", Command: "/scripts/backup.sh", Duration: previewHookDuration, Output: "backup complete"},
+			{Phase: "post-update", Command: "/scripts/notify.sh", Duration: previewHookDuration / 4, Output: "ok"},
+		}
+	case FailedState:
+		return []report.HookRun{
+			{
+				Phase:    "pre-update",
+				Command:  "/scripts/backup.sh",
+				ExitCode: 1,
+				Duration: previewHookDuration,
+				Output:   "pg_dump: connection to server failed: password=[redacted]",
+				Error:    "command execution failed with exit code 1",
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// generateBackupPath returns the volume backup shown for a preview container.
+// Only updated containers were backed up.
+//
+// Parameters:
+//   - state: Container report state.
+//   - name: Container name.
+//
+// Returns:
+//   - string: Backup directory, or empty.
+func generateBackupPath(state State, name string) string {
+	if state != UpdatedState {
+		return ""
+	}
+
+	return previewBackupDir + "/" + name + "/" + previewBackupStamp
+}
+
 func (p *PreviewData) logSubject() (string, string) {
 	index := len(p.entries)
 	name := containerNames[index%len(containerNames)]
@@ -0,0 +1,80 @@
+package preview
+
+import (
+	"time"
+
+	"github.com/nicholas-fedor/tplprev/internal/notify"
+)
+
+const (
+	// previewDigestScans is the number of scans a preview digest covers (hourly for a day).
+	previewDigestScans = 24
+	// previewDigestPeriod is the length of a preview digest period.
+	previewDigestPeriod = 24 * time.Hour
+	// previewDigestMaxCount is the upper bound for simulated update and failure counts.
+	previewDigestMaxCount = 3
+)
+
+// DigestData returns digest template data built from the generated containers.
+//
+// Updated containers become digest updates, stale containers become pending
+// updates, and failed containers become failures. Other states are only counted
+// as scanned.
+//
+// Returns:
+//   - notify.DigestData: Simulated digest data covering one day of hourly scans.
+func (p *PreviewData) DigestData() notify.DigestData {
+	until := previewStartTime.Add(previewDigestPeriod)
+
+	digest := notify.DigestData{
+		StaticData:    p.static,
+		Since:         previewStartTime,
+		Until:         until,
+		Scans:         previewDigestScans,
+		Updated:       []notify.DigestUpdate{},
+		Pending:       []notify.DigestPending{},
+		Failures:      []notify.DigestFailure{},
+		CleanedImages: len(p.report.updated),
+	}
+
+	for _, status := range p.report.updated {
+		digest.Updated = append(digest.Updated, notify.DigestUpdate{
+			Name:      status.Name(),
+			ImageName: status.ImageName(),
+			Count:     1 + p.rand.Intn(previewDigestMaxCount),
+			Last:      p.digestTime(),
+		})
+	}
+
+	for _, status := range p.report.stale {
+		since := p.digestTime().Add(-previewDigestPeriod)
+
+		digest.Pending = append(digest.Pending, notify.DigestPending{
+			Name:          status.Name(),
+			ImageName:     status.ImageName(),
+			LatestImageID: status.LatestImageID(),
+			Since:         since,
+			Waiting:       until.Sub(since).Round(time.Minute),
+		})
+	}
+
+	for _, status := range p.report.failed {
+		first := p.digestTime()
+
+		digest.Failures = append(digest.Failures, notify.DigestFailure{
+			Name:      status.Name(),
+			ImageName: status.ImageName(),
+			Error:     status.Error(),
+			Count:     1 + p.rand.Intn(previewDigestMaxCount),
+			First:     first,
+			Last:      first.Add(time.Duration(p.rand.Intn(previewDigestScans)) * time.Hour),
+		})
+	}
+
+	return digest
+}
+
+// digestTime returns a whole hour within the preview digest period.
+func (p *PreviewData) digestTime() time.Time {
+	return previewStartTime.Add(time.Duration(p.rand.Intn(previewDigestScans)) * time.Hour)
+}
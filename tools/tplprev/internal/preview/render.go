@@ -37,6 +37,40 @@ func Render(input string, states []State, logLevels []LogLevel) (string, error)
 	return Execute(input, generator.NotificationData(), len(states) > 0)
 }
 
+// RenderDigest generates a digest preview string from a template and states.
+//
+// Updated, stale, and failed states become digest updates, pending updates,
+// and failures. The template executes against a notify.DigestData value.
+//
+// Parameters:
+//   - input: Template string to render.
+//   - states: List of container states to include.
+//
+// Returns:
+//   - string: Rendered preview string.
+//   - error: Non-nil if parsing or execution fails, nil on success.
+func RenderDigest(input string, states []State) (string, error) {
+	generator := New()
+
+	for _, state := range states {
+		generator.AddFromState(state)
+	}
+
+	tpl, err := template.New("").Funcs(templates.Funcs).Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	var buf strings.Builder
+
+	err = tpl.Execute(&buf, generator.DigestData())
+	if err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
 // Execute renders a template against notification data.
 //
 // When reportMode is true, the template executes against payload. When it is
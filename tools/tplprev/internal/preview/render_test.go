@@ -28,6 +28,45 @@ func TestRenderDefaultReport(t *testing.T) {
 	assert.Contains(t, result, "updated to")
 }
 
+func TestRenderStaleNotices(t *testing.T) {
+	t.Parallel()
+
+	result, err := Render(
+		`{{range .Report.Stale}}{{index $.Notices .Name}} {{end}}`,
+		[]State{StaleState, StaleState, StaleState},
+		nil,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "new reminder new ", result)
+}
+
+func TestRenderDigest(t *testing.T) {
+	t.Parallel()
+
+	result, err := RenderDigest(
+		templates.Templates["digest"],
+		[]State{UpdatedState, StaleState, FailedState, FreshState},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, result, "24 scans since 2026-01-02 03:04 UTC: 1 Updated, 1 Pending, 1 Failed, 1 Images Cleaned")
+	assert.Contains(t, result, "update pending for")
+	assert.Contains(t, result, "execution failed")
+
+	again, err := RenderDigest(
+		templates.Templates["digest"],
+		[]State{UpdatedState, StaleState, FailedState, FreshState},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, result, again)
+}
+
+func TestRenderDigestRejectsReportTemplate(t *testing.T) {
+	t.Parallel()
+
+	_, err := RenderDigest(templates.Templates["default"], []State{UpdatedState})
+	require.Error(t, err)
+}
+
 func TestRenderDefaultLegacyEntriesRoot(t *testing.T) {
 	t.Parallel()
 
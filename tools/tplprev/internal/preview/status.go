@@ -15,6 +15,11 @@ type containerStatus struct {
 	state          State
 	monitorOnly    bool
 	newContainerID report.ContainerID
+	oldMetadata    report.ImageMetadata
+	newMetadata    report.ImageMetadata
+	hookRuns       []report.HookRun
+	backupPath     string
+	crashLogs      []string
 }
 
 func (u *containerStatus) ID() report.ContainerID {
@@ -56,3 +61,23 @@ func (u *containerStatus) IsMonitorOnly() bool {
 func (u *containerStatus) NewContainerID() report.ContainerID {
 	return u.newContainerID
 }
+
+func (u *containerStatus) CurrentImageMetadata() report.ImageMetadata {
+	return u.oldMetadata
+}
+
+func (u *containerStatus) LatestImageMetadata() report.ImageMetadata {
+	return u.newMetadata
+}
+
+func (u *containerStatus) HookRuns() []report.HookRun {
+	return u.hookRuns
+}
+
+func (u *containerStatus) BackupPath() string {
+	return u.backupPath
+}
+
+func (u *containerStatus) CrashLogs() []string {
+	return u.crashLogs
+}
@@ -0,0 +1,34 @@
+package report
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// HookRun records one execution of a lifecycle hook command or webhook.
+type HookRun struct {
+	Phase      string        `json:"phase"`
+	Command    string        `json:"command,omitempty"`
+	URL        string        `json:"url,omitempty"`
+	ExitCode   int           `json:"exit_code"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Duration   time.Duration `json:"-"`
+	Output     string        `json:"output,omitempty"`
+	Truncated  bool          `json:"truncated,omitempty"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// MarshalJSON encodes the run with its duration in milliseconds.
+//
+// Returns:
+//   - []byte: JSON encoding of the run.
+//   - error: Non-nil if encoding fails.
+func (r HookRun) MarshalJSON() ([]byte, error) {
+	type hookRun HookRun
+
+	return json.Marshal(struct { //nolint:wrapcheck // Plain JSON encoding of a value type.
+		hookRun
+
+		DurationMS int64 `json:"duration_ms"`
+	}{hookRun(r), r.Duration.Milliseconds()})
+}
@@ -0,0 +1,19 @@
+package report
+
+// ImageMetadata describes what an image contains, from its OCI annotation labels.
+//
+// Fields are empty when the image does not carry the corresponding label.
+type ImageMetadata struct {
+	Version  string `json:"version,omitempty"`
+	Revision string `json:"revision,omitempty"`
+	Source   string `json:"source,omitempty"`
+	Created  string `json:"created,omitempty"`
+}
+
+// IsZero reports whether no metadata is known.
+//
+// Returns:
+//   - bool: True if every field is empty.
+func (m ImageMetadata) IsZero() bool {
+	return m == ImageMetadata{}
+}
@@ -23,4 +23,9 @@ type ContainerReport interface {
 	State() string
 	IsMonitorOnly() bool
 	NewContainerID() ContainerID
+	CurrentImageMetadata() ImageMetadata
+	LatestImageMetadata() ImageMetadata
+	HookRuns() []HookRun
+	BackupPath() string
+	CrashLogs() []string
 }
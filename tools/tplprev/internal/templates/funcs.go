@@ -22,8 +22,15 @@ var Funcs = template.FuncMap{
 	"ToPorcelainJSON": toPorcelainJSON,
 	"Title":           cases.Title(language.AmericanEnglish).String,
 	"RFC1123":         formatRFC1123,
+	"ImageChange":     imageChange,
 }
 
+// shortRevisionLength is the number of revision characters shown by ImageChange.
+const shortRevisionLength = 7
+
+// unknownImageValue stands in for metadata one of the images lacks in ImageChange.
+const unknownImageValue = "?"
+
 // toJSON marshals a value to a formatted JSON string for use in templates.
 // If marshaling fails, it returns an error message as the string.
 func toJSON(v any) string {
@@ -101,3 +108,55 @@ func formatRFC1123(value string) string {
 
 	return timestamp.Format(time.RFC1123)
 }
+
+// imageChange describes how a container's image metadata changed, such as
+// "1.4.2 → 1.5.0 (abc1234 → def5678)".
+//
+// Revisions are shortened to seven characters. Missing values on one side are
+// shown as "?"; a part missing on both sides is left out.
+func imageChange(containerReport report.ContainerReport) string {
+	if containerReport == nil {
+		return ""
+	}
+
+	current := containerReport.CurrentImageMetadata()
+	latest := containerReport.LatestImageMetadata()
+
+	versions := changePart(current.Version, latest.Version)
+	revisions := changePart(shortRevision(current.Revision), shortRevision(latest.Revision))
+
+	switch {
+	case versions != "" && revisions != "":
+		return versions + " (" + revisions + ")"
+	case versions != "":
+		return versions
+	default:
+		return revisions
+	}
+}
+
+// changePart formats an old and new value as "old → new", or empty if both are empty.
+func changePart(from, to string) string {
+	if from == "" && to == "" {
+		return ""
+	}
+
+	if from == "" {
+		from = unknownImageValue
+	}
+
+	if to == "" {
+		to = unknownImageValue
+	}
+
+	return from + " → " + to
+}
+
+// shortRevision shortens a source revision such as a commit hash for display.
+func shortRevision(revision string) string {
+	if len(revision) > shortRevisionLength {
+		return revision[:shortRevisionLength]
+	}
+
+	return revision
+}
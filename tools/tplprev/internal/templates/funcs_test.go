@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/nicholas-fedor/tplprev/internal/report"
 )
 
 func TestToJSON(t *testing.T) {
@@ -32,3 +34,53 @@ func TestFormatRFC1123(t *testing.T) {
 		})
 	}
 }
+
+// metadataReport is a container report that only provides image metadata.
+type metadataReport struct {
+	report.ContainerReport
+
+	current report.ImageMetadata
+	latest  report.ImageMetadata
+}
+
+func (r metadataReport) CurrentImageMetadata() report.ImageMetadata { return r.current }
+func (r metadataReport) LatestImageMetadata() report.ImageMetadata  { return r.latest }
+func (metadataReport) HookRuns() []report.HookRun                   { return nil }
+func (metadataReport) BackupPath() string                           { return "" }
+func (metadataReport) CrashLogs() []string                          { return nil }
+
+func TestImageChange(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		current report.ImageMetadata
+		latest  report.ImageMetadata
+		want    string
+	}{
+		{
+			name:    "version and revision",
+			current: report.ImageMetadata{Version: "1.4.2", Revision: "abc1234ffff"},
+			latest:  report.ImageMetadata{Version: "1.5.0", Revision: "def5678eeee"},
+			want:    "1.4.2 → 1.5.0 (abc1234 → def5678)",
+		},
+		{
+			name:    "version only on the latest image",
+			current: report.ImageMetadata{Revision: "abc1234"},
+			latest:  report.ImageMetadata{Version: "1.5.0", Revision: "def5678"},
+			want:    "? → 1.5.0 (abc1234 → def5678)",
+		},
+		{
+			name: "no metadata",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, imageChange(metadataReport{current: tt.current, latest: tt.latest}))
+		})
+	}
+}
@@ -103,6 +103,21 @@ var Templates = map[string]string{
 	"json.v1": `{{ . | ToJSON }}`,
 
 	"porcelain.json": `{{ .Report | ToPorcelainJSON }}`,
+
+	"digest": `
+{{- .Scans}} scan{{if ne .Scans 1}}s{{end}} since {{.Since.Format "2006-01-02 15:04 MST"}}: {{len .Updated}} Updated, {{len .Pending}} Pending, {{len .Failures}} Failed, {{.CleanedImages}} Images Cleaned
+{{- range .Updated}}
+- {{.Name}} ({{.ImageName}}): updated{{if gt .Count 1}} {{.Count}} times{{end}}
+{{- end -}}
+{{- range .Pending}}
+- {{.Name}} ({{.ImageName}}): update pending for {{.Waiting}}
+{{- end -}}
+{{- range .Failures}}
+- {{.Name}} ({{.ImageName}}): failed {{.Count}} time{{if ne .Count 1}}s{{end}}: {{.Error}}
+{{- end -}}
+{{- if .ScanErrors}}
+{{.ScanErrors}} scan{{if ne .ScanErrors 1}}s{{end}} failed, last: {{.LastScanError}}
+{{- end -}}`,
 }
 
 // Lookup returns a named builtin template.
@@ -16,6 +16,7 @@ func TestLookup(t *testing.T) {
 	}{
 		{give: "default", wantFound: true},
 		{give: "default-legacy", wantFound: true},
+		{give: "digest", wantFound: true},
 		{give: "json.v1", wantFound: true},
 		{give: "porcelain.v1.summary-no-log", wantFound: true},
 		{give: "porcelain.json", wantFound: true},
@@ -46,6 +47,7 @@ func TestNames(t *testing.T) {
 	assert.Equal(t, []string{
 		"default",
 		"default-legacy",
+		"digest",
 		"json.v1",
 		"porcelain.json",
 		"porcelain.v1.summary-no-log",
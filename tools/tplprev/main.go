@@ -19,6 +19,8 @@ func main() {
 
 	var entries string
 
+	var digest bool
+
 	flag.StringVar(
 		&states,
 		"states",
@@ -26,6 +28,7 @@ func main() {
 		"sCanned, Updated, failEd, sKipped, restaRted, sTale, Fresh",
 	)
 	flag.StringVar(&entries, "entries", "ewwiiidddd", "Panic,Fatal,Error,Warn,Info,Debug,Trace")
+	flag.BoolVar(&digest, "digest", false, "Render TEMPLATE as a digest template (updated, stale, and failed states)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: tplprev [flags] TEMPLATE\n\n")
@@ -57,11 +60,18 @@ func main() {
 		return
 	}
 
-	result, err := preview.Render(
-		input,
-		preview.StatesFromString(states),
-		preview.LevelsFromString(entries),
-	)
+	var result string
+
+	if digest {
+		result, err = preview.RenderDigest(input, preview.StatesFromString(states))
+	} else {
+		result, err = preview.Render(
+			input,
+			preview.StatesFromString(states),
+			preview.LevelsFromString(entries),
+		)
+	}
+
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to render template %q: %v\n", flag.Arg(0), err)
 		os.Exit(1)
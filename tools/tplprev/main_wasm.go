@@ -18,7 +18,8 @@ func main() {
 	fmt.Println("tplprev " + metadata.String())
 
 	js.Global().Set("WATCHTOWER", js.ValueOf(map[string]any{
-		"tplprev": js.FuncOf(jsTplPrev),
+		"tplprev":       js.FuncOf(jsTplPrev),
+		"tplprevDigest": js.FuncOf(jsTplPrevDigest),
 	}))
 	<-make(chan bool)
 }
@@ -48,6 +49,24 @@ func jsTplPrev(_ js.Value, args []js.Value) any {
 	return result
 }
 
+func jsTplPrevDigest(_ js.Value, args []js.Value) any {
+	if len(args) < 2 {
+		return "Requires 2 arguments passed"
+	}
+
+	states, err := statesFromJS(args[1])
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	result, err := preview.RenderDigest(args[0].String(), states)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	return result
+}
+
 func statesFromJS(arg js.Value) ([]preview.State, error) {
 	isArray, isTypedArray := jsCollectionFlags(arg)
 	switch classifyJSType(arg.Type().String(), isArray, isTypedArray) {